@@ -124,6 +124,22 @@ func (sc *ServiceContext) changeVersion(node int, v *clusterupgrade.Version) err
 	return nil
 }
 
+// relabelNodes renames the nodes tracked by this context according to
+// the given mapping, which must be a permutation of the nodes of the
+// service. Nodes that are not in the mapping keep their name.
+func (sc *ServiceContext) relabelNodes(relabel map[int]int) {
+	for v, nodes := range sc.nodesByVersion {
+		newSet := intSetP()
+		for _, n := range nodes.Ordered() {
+			if to, ok := relabel[n]; ok {
+				n = to
+			}
+			newSet.Add(n)
+		}
+		sc.nodesByVersion[v] = newSet
+	}
+}
+
 // NodeVersion returns the release version the given `node` is
 // currently running. Returns an error if the node is not valid (i.e.,
 // the underlying service is not deployed on the node passed).
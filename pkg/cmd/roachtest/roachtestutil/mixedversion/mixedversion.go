@@ -265,6 +265,7 @@ type (
 		settings                       []install.ClusterSettingOption
 		enabledDeploymentModes         []DeploymentMode
 		overriddenMutatorProbabilities map[string]float64
+		nodeLocalities                 map[int]string
 	}
 
 	CustomOption func(*testOptions)
@@ -421,6 +422,16 @@ func WithMutatorProbability(name string, probability float64) CustomOption {
 	}
 }
 
+// NodeLocalities declares the locality of each cockroach node in the
+// test (typically, the region it runs in), keyed by node ID. This
+// allows the planner to correlate version skew with localities; see
+// the `LocalityOrderedUpgrade` mutator.
+func NodeLocalities(localities map[int]string) CustomOption {
+	return func(opts *testOptions) {
+		opts.nodeLocalities = localities
+	}
+}
+
 // DisableMutators disables all mutators with the names passed.
 func DisableMutators(names ...string) CustomOption {
 	return func(opts *testOptions) {
@@ -14,7 +14,9 @@ import (
 	"fmt"
 	"math/rand"
 	"sort"
+	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/cmd/roachtest/option"
 	"github.com/cockroachdb/cockroach/pkg/cmd/roachtest/roachtestutil/clusterupgrade"
 	"github.com/cockroachdb/cockroach/pkg/roachprod/install"
 	"golang.org/x/exp/maps"
@@ -36,6 +38,150 @@ const (
 	// the cluster version since the auto upgrades feature has been
 	// broken for tenants in several published releases (see #121858).
 	PreserveDowngradeOptionRandomizer = "preserve_downgrade_option_randomizer"
+
+	// DiskPressure is a mutator that fills up the store directory of a
+	// random node for a bounded window of the upgrade, leaving only a
+	// small percentage of the disk available. This simulates a node
+	// that is close to running out of disk space while the cluster is
+	// in a mixed-version state, exercising the allocator's
+	// disk-fullness heuristics and admission control concurrently with
+	// binary version changes.
+	DiskPressure = "disk_pressure"
+
+	// StaleDescriptorLease is a mutator that runs a schema change and
+	// immediately restarts a random node while the cluster is in a
+	// mixed-binary state. The restarted node loses every descriptor
+	// lease it held, and has to acquire leases on the new descriptor
+	// versions while its peers may be running a different binary.
+	StaleDescriptorLease = "stale_descriptor_lease"
+
+	// DescriptorVersionBurst is a mutator that runs a burst of small
+	// schema changes back to back while the cluster is in a
+	// mixed-binary state. Each of them creates a new descriptor
+	// version, stressing lease propagation while nodes are restarted
+	// into a different binary.
+	DescriptorVersionBurst = "descriptor_version_burst"
+
+	// Import is a mutator that runs an IMPORT INTO a new table while
+	// the cluster is in a mixed-binary state, and checks that the
+	// imported data is intact once the upgrade is finalized.
+	Import = "import"
+
+	// CertRotation is a mutator that rotates the TLS certificates of a
+	// subset of nodes while the cluster is in a mixed-binary state.
+	CertRotation = "cert_rotation"
+
+	// QuorumLoss is a mutator that stops a majority of nodes for a
+	// short period of time while the cluster is in a mixed-binary
+	// state, making some ranges unavailable until the nodes are
+	// restarted. Since any workload running in the background fails
+	// while quorum is lost, this mutator is disabled by default, and
+	// tests opt into it with `WithMutatorProbability`.
+	QuorumLoss = "quorum_loss"
+
+	// ReplicationCutover is a mutator that starts a physical
+	// replication stream between two virtual clusters while the
+	// cluster is in a mixed-binary state, and cuts over to the standby
+	// virtual cluster later in the same upgrade. Nodes may be restarted
+	// into a different binary while the stream is running.
+	ReplicationCutover = "replication_cutover"
+
+	// ProtectedTimestampGC is a mutator that protects a table with a
+	// protected timestamp and drops it while the cluster is in a
+	// mixed-binary state. It checks that the data in the dropped table
+	// is not garbage collected while the protected timestamp is in
+	// place, even though its GC TTL expired long before.
+	ProtectedTimestampGC = "protected_timestamp_gc"
+
+	// NodePause is a mutator that pauses the cockroach process of a
+	// random node with SIGSTOP while upgrade migrations are running,
+	// resuming it after a bounded amount of time. Unlike a node that
+	// is shut down, a paused node keeps its connections open but stops
+	// responding to them.
+	NodePause = "node_pause"
+
+	// SurvivalGoal is a mutator that changes the survival goal of a
+	// multi-region database between zone and region failure while the
+	// cluster is in a mixed-binary state, and checks that the new goal
+	// is in place once the upgrade is finalized. It has no effect on
+	// clusters with fewer than three regions.
+	SurvivalGoal = "survival_goal"
+
+	// InflightJob is a mutator that creates a schema change job while
+	// the cluster is in a mixed-binary state and leaves it paused
+	// until the upgrade is finalized, at which point the job is
+	// resumed and must succeed. This checks that jobs that are in
+	// flight survive the migrations to the jobs system.
+	InflightJob = "inflight_job"
+
+	// SchemaChangePauseResume is a mutator that starts a long running
+	// schema change job while the cluster is in a mixed-binary state
+	// and pauses it with PAUSE JOB halfway through its backfill. The
+	// job is resumed after the upgrade is finalized, and must pick up
+	// the progress it made on the previous binary. Unlike
+	// `InflightJob`, the job is paused while running rather than
+	// before it starts executing.
+	SchemaChangePauseResume = "schema_change_pause_resume"
+
+	// CPULimit is a mutator that caps the CPU available to the
+	// cockroach process on a subset of nodes for a bounded window of
+	// the upgrade while the cluster is in a mixed-binary state. This
+	// puts the scheduler and admission control under CPU pressure
+	// while nodes run different binaries.
+	CPULimit = "cpu_limit"
+
+	// DecommissionRejoin is a mutator that decommissions a node while
+	// the cluster is in a mixed-binary state, wipes its data, and
+	// starts it again with the same binary so that it rejoins the
+	// cluster as a new node. This exercises replacing a node while
+	// nodes disagree on the version.
+	DecommissionRejoin = "decommission_rejoin"
+
+	// TenantCapabilities is a mutator that grants and revokes
+	// capabilities of a virtual cluster while the cluster is in a
+	// mixed-binary state, and waits for every node to observe the
+	// change. The capabilities are checked again once the upgrade is
+	// finalized.
+	TenantCapabilities = "tenant_capabilities"
+
+	// RollingRestart is a mutator that restarts every node in the
+	// cluster, one at a time and with the binary it is already
+	// running, while the cluster is in a mixed-binary state. This
+	// checks that a mixed-version cluster tolerates a full bounce.
+	RollingRestart = "rolling_restart"
+
+	// LocalityOrderedUpgrade is a mutator that changes the order in
+	// which nodes are restarted into a different binary so that every
+	// node in one locality is upgraded before any node in the next.
+	// This mirrors geo-distributed rollouts, where version skew is
+	// correlated with regions instead of being spread randomly across
+	// the cluster. It only applies to tests that declare the locality
+	// of their nodes with the `NodeLocalities` option.
+	LocalityOrderedUpgrade = "locality_ordered_upgrade"
+
+	// PointInTimeRestore is a mutator that takes a backup with
+	// revision history while the cluster is in a mixed-binary state,
+	// and later restores it into a new database as of a random time
+	// covered by that history. Both happen before the upgrade is
+	// finalized, so the backup is always restored by a cluster at the
+	// version that produced it, while its nodes run different binaries.
+	PointInTimeRestore = "point_in_time_restore"
+
+	// RPCCompression is a mutator that restarts every node in the
+	// cluster, with the binary it is already running, alternately
+	// enabling and disabling compression of the RPCs it sends, while
+	// the cluster is in a mixed-binary state. Nodes then dial each
+	// other with and without compression while running different
+	// binaries, on secure and insecure clusters alike.
+	RPCCompression = "rpc_compression"
+
+	// SQLConnectionChurn is a mutator that rapidly opens and closes
+	// SQL connections to the cluster while a node is being restarted
+	// into a different binary, changing session settings on each of
+	// them. This exercises the connection handler and the lifecycle
+	// of SQL sessions under version skew, at the point in the upgrade
+	// where clients are most likely to reconnect.
+	SQLConnectionChurn = "sql_connection_churn"
 )
 
 type preserveDowngradeOptionRandomizerMutator struct{}
@@ -107,6 +253,1237 @@ func (m preserveDowngradeOptionRandomizerMutator) Generate(
 	return mutations
 }
 
+// maxDiskPressureWindow is the maximum number of sequential steps
+// that run while a node's store is filled by the `diskPressureMutator`.
+const maxDiskPressureWindow = 4
+
+type diskPressureMutator struct{}
+
+func (m diskPressureMutator) Name() string {
+	return DiskPressure
+}
+
+// Disk pressure affects the performance of the node being filled and
+// causes replicas to be moved away from it, so we only enable this
+// mutator in a small fraction of runs.
+func (m diskPressureMutator) Probability() float64 {
+	return 0.1
+}
+
+// Generate returns mutations that fill up the store of a random node
+// and later restore it, for a random subset of upgrades in the
+// plan. The fill step is always inserted before, and the restore step
+// after, steps that run sequentially, so that at most
+// `maxDiskPressureWindow` sequential steps run while the disk is
+// under pressure. The length of the returned mutations is always
+// even.
+func (m diskPressureMutator) Generate(rng *rand.Rand, plan *TestPlan) []mutation {
+	freePercentages := []int{5, 10, 15}
+	index := newStepIndex(plan)
+
+	var mutations []mutation
+	for _, upgradeSelector := range randomUpgrades(rng, plan) {
+		sequentialSteps := upgradeSelector.Filter(func(s *singleStep) bool {
+			// The store directory is only guaranteed to exist once the
+			// cluster has been set up.
+			return s.context.System.Stage >= OnStartupStage && !index.IsConcurrent(s)
+		})
+		if len(sequentialSteps) == 0 {
+			continue
+		}
+
+		start := rng.Intn(len(sequentialSteps))
+		end := start + rng.Intn(min(maxDiskPressureWindow, len(sequentialSteps)-start))
+		nodes := sequentialSteps[start].context.System.Descriptor.Nodes
+		node := nodes[rng.Intn(len(nodes))]
+
+		fill := sequentialSteps[start : start+1].InsertBefore(fillStoreStep{
+			node:        node,
+			freePercent: freePercentages[rng.Intn(len(freePercentages))],
+		})
+		restore := sequentialSteps[end : end+1].InsertAfter(restoreStoreStep{node: node})
+
+		mutations = append(mutations, fill...)
+		mutations = append(mutations, restore...)
+	}
+
+	return mutations
+}
+
+type staleDescriptorLeaseMutator struct{}
+
+func (m staleDescriptorLeaseMutator) Name() string {
+	return StaleDescriptorLease
+}
+
+// Each application of this mutator adds an extra node restart to the
+// test, so it is only enabled in a fraction of runs.
+func (m staleDescriptorLeaseMutator) Probability() float64 {
+	return 0.2
+}
+
+// Generate returns mutations that insert a schema change immediately
+// followed by a restart of a random node, for a random subset of
+// upgrades in the plan. Both steps are inserted before the same
+// sequential step in a mixed-binary state, which guarantees that they
+// run back to back. The node is restarted with the binary it is
+// already running, so the rest of the plan is unaffected.
+func (m staleDescriptorLeaseMutator) Generate(rng *rand.Rand, plan *TestPlan) []mutation {
+	// We take the test handle and settings used to restart nodes from
+	// the restarts already planned for the upgrade.
+	var restartTemplate *restartWithNewBinaryStep
+	for _, s := range plan.newStepSelector() {
+		if step, ok := s.impl.(restartWithNewBinaryStep); ok {
+			restartTemplate = &step
+			break
+		}
+	}
+	if restartTemplate == nil {
+		return nil
+	}
+
+	index := newStepIndex(plan)
+
+	var mutations []mutation
+	for _, upgradeSelector := range randomUpgrades(rng, plan) {
+		chosenStep := upgradeSelector.
+			Filter(func(s *singleStep) bool {
+				numUpgraded := len(s.context.System.NodesInNextVersion())
+				return numUpgraded > 0 &&
+					numUpgraded < len(s.context.System.Descriptor.Nodes) &&
+					!index.IsConcurrent(s)
+			}).
+			RandomStep(rng)
+		if len(chosenStep) == 0 {
+			continue
+		}
+
+		stepContext := chosenStep[0].context
+		nodes := stepContext.System.Descriptor.Nodes
+		node := nodes[rng.Intn(len(nodes))]
+		nodeVersion, err := stepContext.System.NodeVersion(node)
+		handleInternalError(err)
+
+		mutations = append(mutations, chosenStep.InsertBefore(leaseSchemaChangeStep{})...)
+		mutations = append(mutations, chosenStep.InsertBefore(restartWithNewBinaryStep{
+			version:  nodeVersion,
+			rt:       restartTemplate.rt,
+			node:     node,
+			settings: restartTemplate.settings,
+		})...)
+	}
+
+	return mutations
+}
+
+// minDescriptorVersionBurst and maxDescriptorVersionBurst bound the
+// number of schema changes inserted by each application of the
+// `descriptorVersionBurstMutator`.
+const (
+	minDescriptorVersionBurst = 3
+	maxDescriptorVersionBurst = 10
+)
+
+type descriptorVersionBurstMutator struct{}
+
+func (m descriptorVersionBurstMutator) Name() string {
+	return DescriptorVersionBurst
+}
+
+func (m descriptorVersionBurstMutator) Probability() float64 {
+	return 0.2
+}
+
+// Generate returns mutations that insert a sequence of schema changes
+// before a random sequential step in a mixed-binary state, for a
+// random subset of upgrades in the plan.
+func (m descriptorVersionBurstMutator) Generate(rng *rand.Rand, plan *TestPlan) []mutation {
+	index := newStepIndex(plan)
+
+	var mutations []mutation
+	for _, upgradeSelector := range randomUpgrades(rng, plan) {
+		chosenStep := upgradeSelector.
+			Filter(func(s *singleStep) bool {
+				numUpgraded := len(s.context.System.NodesInNextVersion())
+				return numUpgraded > 0 &&
+					numUpgraded < len(s.context.System.Descriptor.Nodes) &&
+					!index.IsConcurrent(s)
+			}).
+			RandomStep(rng)
+		if len(chosenStep) == 0 {
+			continue
+		}
+
+		numChanges := minDescriptorVersionBurst +
+			rng.Intn(maxDescriptorVersionBurst-minDescriptorVersionBurst+1)
+		excludeFromBackup := rng.Float64() < 0.5
+		for j := 0; j < numChanges; j++ {
+			// Flip the storage parameter on every step so that each of
+			// them actually changes the descriptor.
+			mutations = append(mutations, chosenStep.InsertBefore(
+				toggleExcludeFromBackupStep{exclude: excludeFromBackup},
+			)...)
+			excludeFromBackup = !excludeFromBackup
+		}
+	}
+
+	return mutations
+}
+
+type importMutator struct{}
+
+func (m importMutator) Name() string {
+	return Import
+}
+
+func (m importMutator) Probability() float64 {
+	return 0.2
+}
+
+// Generate returns mutations that insert an import step at a random
+// sequential step in a mixed-binary state, and a step that verifies
+// the imported data after the cluster version is finalized, for a
+// random subset of upgrades in the plan. The length of the returned
+// mutations is always even.
+func (m importMutator) Generate(rng *rand.Rand, plan *TestPlan) []mutation {
+	index := newStepIndex(plan)
+
+	var mutations []mutation
+	for j, upgradeSelector := range randomUpgrades(rng, plan) {
+		chosenStep := upgradeSelector.
+			Filter(func(s *singleStep) bool {
+				numUpgraded := len(s.context.System.NodesInNextVersion())
+				return numUpgraded > 0 &&
+					numUpgraded < len(s.context.System.Descriptor.Nodes) &&
+					!index.IsConcurrent(s)
+			}).
+			RandomStep(rng)
+		finalizedStep := upgradeSelector.Filter(func(s *singleStep) bool {
+			_, ok := s.impl.(waitForStableClusterVersionStep)
+			return ok && s.context.System.Stage == RunningUpgradeMigrationsStage
+		})
+		if len(chosenStep) == 0 || len(finalizedStep) == 0 {
+			continue
+		}
+
+		nodes := chosenStep[0].context.System.Descriptor.Nodes
+		table := fmt.Sprintf("%s_%d", importTablePrefix, j)
+
+		mutations = append(mutations, chosenStep.InsertBefore(importStep{
+			node:  nodes[rng.Intn(len(nodes))],
+			table: table,
+		})...)
+		mutations = append(mutations, finalizedStep[len(finalizedStep)-1:].InsertAfter(
+			verifyImportStep{table: table},
+		)...)
+	}
+
+	return mutations
+}
+
+type certRotationMutator struct{}
+
+func (m certRotationMutator) Name() string {
+	return CertRotation
+}
+
+func (m certRotationMutator) Probability() float64 {
+	return 0.2
+}
+
+// Generate returns mutations that rotate the certificates of a random
+// subset of nodes at a random sequential step in a mixed-binary
+// state, followed by a check that every node can still reach every
+// other node. The length of the returned mutations is always even.
+func (m certRotationMutator) Generate(rng *rand.Rand, plan *TestPlan) []mutation {
+	index := newStepIndex(plan)
+
+	var mutations []mutation
+	for _, upgradeSelector := range randomUpgrades(rng, plan) {
+		chosenStep := upgradeSelector.
+			Filter(func(s *singleStep) bool {
+				numUpgraded := len(s.context.System.NodesInNextVersion())
+				return numUpgraded > 0 &&
+					numUpgraded < len(s.context.System.Descriptor.Nodes) &&
+					!index.IsConcurrent(s)
+			}).
+			RandomStep(rng)
+		if len(chosenStep) == 0 {
+			continue
+		}
+
+		nodes := append(option.NodeListOption{}, chosenStep[0].context.System.Descriptor.Nodes...)
+		rng.Shuffle(len(nodes), func(i, j int) {
+			nodes[i], nodes[j] = nodes[j], nodes[i]
+		})
+		nodes = nodes[:1+rng.Intn(len(nodes))]
+		sort.Ints(nodes)
+
+		mutations = append(mutations, chosenStep.InsertBefore(rotateNodeCertsStep{nodes: nodes})...)
+		mutations = append(mutations, chosenStep.InsertBefore(checkNodeConnectivityStep{})...)
+	}
+
+	return mutations
+}
+
+// minQuorumLossOutage and maxQuorumLossOutage bound the amount of
+// time nodes are kept down by the `quorumLossMutator`.
+const (
+	minQuorumLossOutage = 10 * time.Second
+	maxQuorumLossOutage = time.Minute
+)
+
+type quorumLossMutator struct{}
+
+func (m quorumLossMutator) Name() string {
+	return QuorumLoss
+}
+
+// Losing quorum makes the cluster unavailable to any workload running
+// in the background, which most tests do not expect, so this mutator
+// is only enabled in tests that opt into it.
+func (m quorumLossMutator) Probability() float64 {
+	return 0
+}
+
+// Generate returns mutations that stop a majority of the nodes at a
+// random sequential step in a mixed-binary state, wait for a bounded
+// amount of time, and then restart the stopped nodes with the binary
+// they were running, before waiting for every range to become
+// available again.
+func (m quorumLossMutator) Generate(rng *rand.Rand, plan *TestPlan) []mutation {
+	// We take the test handle and settings used to restart nodes from
+	// the restarts already planned for the upgrade.
+	var restartTemplate *restartWithNewBinaryStep
+	for _, s := range plan.newStepSelector() {
+		if step, ok := s.impl.(restartWithNewBinaryStep); ok {
+			restartTemplate = &step
+			break
+		}
+	}
+	if restartTemplate == nil {
+		return nil
+	}
+
+	index := newStepIndex(plan)
+
+	var mutations []mutation
+	for _, upgradeSelector := range randomUpgrades(rng, plan) {
+		chosenStep := upgradeSelector.
+			Filter(func(s *singleStep) bool {
+				numUpgraded := len(s.context.System.NodesInNextVersion())
+				return numUpgraded > 0 &&
+					numUpgraded < len(s.context.System.Descriptor.Nodes) &&
+					len(s.context.System.Descriptor.Nodes) >= 3 &&
+					!index.IsConcurrent(s)
+			}).
+			RandomStep(rng)
+		if len(chosenStep) == 0 {
+			continue
+		}
+
+		stepContext := chosenStep[0].context
+		nodes := append(option.NodeListOption{}, stepContext.System.Descriptor.Nodes...)
+		rng.Shuffle(len(nodes), func(i, j int) {
+			nodes[i], nodes[j] = nodes[j], nodes[i]
+		})
+		nodes = nodes[:len(nodes)/2+1]
+		sort.Ints(nodes)
+
+		outage := minQuorumLossOutage +
+			time.Duration(rng.Int63n(int64(maxQuorumLossOutage-minQuorumLossOutage)+1))
+
+		mutations = append(mutations, chosenStep.InsertBefore(stopNodesStep{nodes: nodes})...)
+		mutations = append(mutations, chosenStep.InsertBefore(waitStep{dur: outage})...)
+		for _, node := range nodes {
+			nodeVersion, err := stepContext.System.NodeVersion(node)
+			handleInternalError(err)
+
+			mutations = append(mutations, chosenStep.InsertBefore(restartWithNewBinaryStep{
+				version:  nodeVersion,
+				rt:       restartTemplate.rt,
+				node:     node,
+				settings: restartTemplate.settings,
+			})...)
+		}
+		mutations = append(mutations, chosenStep.InsertBefore(waitForRangeAvailabilityStep{})...)
+	}
+
+	return mutations
+}
+
+// maxReplicationCutoverLag is the maximum amount of time, relative to
+// the replicated time of the standby, that the cutover timestamp
+// chosen by the `replicationCutoverMutator` lags behind.
+const maxReplicationCutoverLag = 30 * time.Second
+
+type replicationCutoverMutator struct{}
+
+func (m replicationCutoverMutator) Name() string {
+	return ReplicationCutover
+}
+
+func (m replicationCutoverMutator) Probability() float64 {
+	return 0.2
+}
+
+// Generate returns mutations that start a replication stream into a
+// standby virtual cluster at a random sequential step in a
+// mixed-binary state, and cut over to the standby at the same or a
+// later step of the same upgrade. Only upgrades from a release that
+// supports cutting over to a standby virtual cluster are considered,
+// so that every node involved in the stream is able to serve it. The
+// length of the returned mutations is always even.
+func (m replicationCutoverMutator) Generate(rng *rand.Rand, plan *TestPlan) []mutation {
+	index := newStepIndex(plan)
+
+	var mutations []mutation
+	for j, upgradeSelector := range randomUpgrades(rng, plan) {
+		candidates := upgradeSelector.Filter(func(s *singleStep) bool {
+			numUpgraded := len(s.context.System.NodesInNextVersion())
+			return numUpgraded > 0 &&
+				numUpgraded < len(s.context.System.Descriptor.Nodes) &&
+				s.context.System.FromVersion.AtLeast(minReplicationCutoverVersion) &&
+				!index.IsConcurrent(s)
+		})
+		if len(candidates) == 0 {
+			continue
+		}
+
+		startIdx := rng.Intn(len(candidates))
+		cutoverIdx := startIdx + rng.Intn(len(candidates)-startIdx)
+		nodes := candidates[startIdx].context.System.Descriptor.Nodes
+		source := fmt.Sprintf("%s-%d", replicationSourcePrefix, j)
+		standby := fmt.Sprintf("%s-%d", replicationStandbyPrefix, j)
+
+		mutations = append(mutations, candidates[startIdx:startIdx+1].InsertBefore(startReplicationStep{
+			node:    nodes[rng.Intn(len(nodes))],
+			source:  source,
+			standby: standby,
+		})...)
+		mutations = append(mutations, candidates[cutoverIdx:cutoverIdx+1].InsertBefore(replicationCutoverStep{
+			standby: standby,
+			lag:     time.Duration(rng.Int63n(int64(maxReplicationCutoverLag) + 1)),
+		})...)
+	}
+
+	return mutations
+}
+
+type protectedTimestampGCMutator struct{}
+
+func (m protectedTimestampGCMutator) Name() string {
+	return ProtectedTimestampGC
+}
+
+func (m protectedTimestampGCMutator) Probability() float64 {
+	return 0.2
+}
+
+// Generate returns mutations that protect a new table and drop it at
+// the same or a later sequential step in a mixed-binary state, and a
+// step that verifies the contents of the dropped table after the
+// cluster version is finalized, for a random subset of upgrades in
+// the plan. The length of the returned mutations is always a multiple
+// of 3.
+func (m protectedTimestampGCMutator) Generate(rng *rand.Rand, plan *TestPlan) []mutation {
+	index := newStepIndex(plan)
+
+	var mutations []mutation
+	for j, upgradeSelector := range randomUpgrades(rng, plan) {
+		candidates := upgradeSelector.Filter(func(s *singleStep) bool {
+			numUpgraded := len(s.context.System.NodesInNextVersion())
+			return numUpgraded > 0 &&
+				numUpgraded < len(s.context.System.Descriptor.Nodes) &&
+				!index.IsConcurrent(s)
+		})
+		finalizedStep := upgradeSelector.Filter(func(s *singleStep) bool {
+			_, ok := s.impl.(waitForStableClusterVersionStep)
+			return ok && s.context.System.Stage == RunningUpgradeMigrationsStage
+		})
+		if len(candidates) == 0 || len(finalizedStep) == 0 {
+			continue
+		}
+
+		protectIdx := rng.Intn(len(candidates))
+		dropIdx := protectIdx + rng.Intn(len(candidates)-protectIdx)
+		table := fmt.Sprintf("%s_%d", protectedTablePrefix, j)
+
+		mutations = append(mutations, candidates[protectIdx:protectIdx+1].InsertBefore(
+			protectTableStep{table: table},
+		)...)
+		mutations = append(mutations, candidates[dropIdx:dropIdx+1].InsertBefore(
+			dropProtectedTableStep{table: table},
+		)...)
+		mutations = append(mutations, finalizedStep[len(finalizedStep)-1:].InsertAfter(
+			verifyProtectedTableStep{table: table},
+		)...)
+	}
+
+	return mutations
+}
+
+// minNodePause and maxNodePause bound the amount of time a node is
+// paused by the `nodePauseMutator`.
+const (
+	minNodePause = 5 * time.Second
+	maxNodePause = 30 * time.Second
+)
+
+type nodePauseMutator struct{}
+
+func (m nodePauseMutator) Name() string {
+	return NodePause
+}
+
+func (m nodePauseMutator) Probability() float64 {
+	return 0.2
+}
+
+// Generate returns mutations that pause a random node concurrently
+// with the step that waits for upgrade migrations to finish, for a
+// random subset of upgrades in the plan, followed by a check that the
+// node is reachable again once the migrations are done. The length of
+// the returned mutations is always even.
+func (m nodePauseMutator) Generate(rng *rand.Rand, plan *TestPlan) []mutation {
+	var mutations []mutation
+	for _, upgradeSelector := range randomUpgrades(rng, plan) {
+		finalizedStep := upgradeSelector.Filter(func(s *singleStep) bool {
+			_, ok := s.impl.(waitForStableClusterVersionStep)
+			return ok && s.context.System.Stage == RunningUpgradeMigrationsStage
+		})
+		if len(finalizedStep) == 0 {
+			continue
+		}
+
+		finalizedStep = finalizedStep[len(finalizedStep)-1:]
+		nodes := finalizedStep[0].context.System.Descriptor.Nodes
+		pause := minNodePause + time.Duration(rng.Int63n(int64(maxNodePause-minNodePause)+1))
+
+		// The connectivity check is inserted first so that it ends up
+		// after the concurrent step created for the pause.
+		mutations = append(mutations, finalizedStep.InsertAfter(checkNodeConnectivityStep{})...)
+		mutations = append(mutations, finalizedStep.InsertConcurrent(pauseNodeStep{
+			node: nodes[rng.Intn(len(nodes))],
+			dur:  pause,
+		})...)
+	}
+
+	return mutations
+}
+
+type survivalGoalMutator struct{}
+
+func (m survivalGoalMutator) Name() string {
+	return SurvivalGoal
+}
+
+func (m survivalGoalMutator) Probability() float64 {
+	return 0.2
+}
+
+// Generate returns mutations that change the survival goal of a new
+// multi-region database at a random sequential step in a mixed-binary
+// state, and a step that verifies the survival goal after the cluster
+// version is finalized, for a random subset of upgrades in the plan.
+// The length of the returned mutations is always even.
+func (m survivalGoalMutator) Generate(rng *rand.Rand, plan *TestPlan) []mutation {
+	index := newStepIndex(plan)
+
+	var mutations []mutation
+	for j, upgradeSelector := range randomUpgrades(rng, plan) {
+		chosenStep := upgradeSelector.
+			Filter(func(s *singleStep) bool {
+				numUpgraded := len(s.context.System.NodesInNextVersion())
+				return numUpgraded > 0 &&
+					numUpgraded < len(s.context.System.Descriptor.Nodes) &&
+					!index.IsConcurrent(s)
+			}).
+			RandomStep(rng)
+		finalizedStep := upgradeSelector.Filter(func(s *singleStep) bool {
+			_, ok := s.impl.(waitForStableClusterVersionStep)
+			return ok && s.context.System.Stage == RunningUpgradeMigrationsStage
+		})
+		if len(chosenStep) == 0 || len(finalizedStep) == 0 {
+			continue
+		}
+
+		database := fmt.Sprintf("%s_%d", survivalGoalDatabasePrefix, j)
+		goal := survivalGoals[rng.Intn(len(survivalGoals))]
+
+		mutations = append(mutations, chosenStep.InsertBefore(changeSurvivalGoalStep{
+			database: database,
+			goal:     goal,
+		})...)
+		mutations = append(mutations, finalizedStep[len(finalizedStep)-1:].InsertAfter(
+			verifySurvivalGoalStep{database: database, goal: goal},
+		)...)
+	}
+
+	return mutations
+}
+
+type inflightJobMutator struct{}
+
+func (m inflightJobMutator) Name() string {
+	return InflightJob
+}
+
+func (m inflightJobMutator) Probability() float64 {
+	return 0.3
+}
+
+// Generate returns mutations that create a paused schema change job
+// in a mixed-binary state, and resume it after the cluster version is
+// finalized, for a random subset of upgrades in the plan. The length
+// of the returned mutations is always even.
+func (m inflightJobMutator) Generate(rng *rand.Rand, plan *TestPlan) []mutation {
+	index := newStepIndex(plan)
+
+	var mutations []mutation
+	for j, upgradeSelector := range randomUpgrades(rng, plan) {
+		candidates := upgradeSelector.Filter(func(s *singleStep) bool {
+			numUpgraded := len(s.context.System.NodesInNextVersion())
+			return numUpgraded > 0 &&
+				numUpgraded < len(s.context.System.Descriptor.Nodes) &&
+				!index.IsConcurrent(s)
+		})
+		finalizedStep := upgradeSelector.Filter(func(s *singleStep) bool {
+			_, ok := s.impl.(waitForStableClusterVersionStep)
+			return ok && s.context.System.Stage == RunningUpgradeMigrationsStage
+		})
+		if len(candidates) == 0 || len(finalizedStep) == 0 {
+			continue
+		}
+
+		job := inflightJob{
+			table: fmt.Sprintf("%s_%d", inflightJobTablePrefix, j),
+			index: fmt.Sprintf("%s_idx_%d", inflightJobIndexPrefix, j),
+			rows:  inflightJobRows,
+		}
+		mutations = append(mutations, candidates.RandomStep(rng).InsertBefore(
+			pauseSchemaChangeJobStep{job: job},
+		)...)
+		mutations = append(mutations, finalizedStep[len(finalizedStep)-1:].InsertAfter(
+			resumeSchemaChangeJobStep{job: job},
+		)...)
+	}
+
+	return mutations
+}
+
+type schemaChangePauseResumeMutator struct{}
+
+func (m schemaChangePauseResumeMutator) Name() string {
+	return SchemaChangePauseResume
+}
+
+func (m schemaChangePauseResumeMutator) Probability() float64 {
+	return 0.2
+}
+
+// Generate returns mutations that start a schema change job and pause
+// it while it is running in a mixed-binary state, resuming it after
+// the cluster version is finalized, for a random subset of upgrades
+// in the plan. The length of the returned mutations is always even.
+func (m schemaChangePauseResumeMutator) Generate(rng *rand.Rand, plan *TestPlan) []mutation {
+	index := newStepIndex(plan)
+
+	var mutations []mutation
+	for j, upgradeSelector := range randomUpgrades(rng, plan) {
+		candidates := upgradeSelector.Filter(func(s *singleStep) bool {
+			numUpgraded := len(s.context.System.NodesInNextVersion())
+			return numUpgraded > 0 &&
+				numUpgraded < len(s.context.System.Descriptor.Nodes) &&
+				!index.IsConcurrent(s)
+		})
+		finalizedStep := upgradeSelector.Filter(func(s *singleStep) bool {
+			_, ok := s.impl.(waitForStableClusterVersionStep)
+			return ok && s.context.System.Stage == RunningUpgradeMigrationsStage
+		})
+		if len(candidates) == 0 || len(finalizedStep) == 0 {
+			continue
+		}
+
+		job := inflightJob{
+			table: fmt.Sprintf("%s_%d", pauseResumeJobTablePrefix, j),
+			index: fmt.Sprintf("%s_idx_%d", pauseResumeJobIndexPrefix, j),
+			rows:  pauseResumeJobRows,
+		}
+		mutations = append(mutations, candidates.RandomStep(rng).InsertBefore(
+			pauseRunningSchemaChangeJobStep{job: job},
+		)...)
+		mutations = append(mutations, finalizedStep[len(finalizedStep)-1:].InsertAfter(
+			resumeSchemaChangeJobStep{job: job},
+		)...)
+	}
+
+	return mutations
+}
+
+// minDecommissionRejoinNodes is the minimum number of nodes a cluster
+// needs for the `decommissionRejoinMutator` to decommission one of
+// them: the remaining nodes must be able to hold three replicas of
+// every range.
+const minDecommissionRejoinNodes = 4
+
+type decommissionRejoinMutator struct{}
+
+func (m decommissionRejoinMutator) Name() string {
+	return DecommissionRejoin
+}
+
+// Decommissioning a node moves all of its replicas, which can take a
+// while, so we only enable this mutator in a small fraction of runs.
+func (m decommissionRejoinMutator) Probability() float64 {
+	return 0.1
+}
+
+// Generate returns mutations that decommission, stop, and wipe a
+// random node at a random sequential step in a mixed-binary state,
+// and then start it with the binary it was running, for a random
+// subset of upgrades in the plan. The first node is never chosen, as
+// it is the node other nodes join through when started.
+func (m decommissionRejoinMutator) Generate(rng *rand.Rand, plan *TestPlan) []mutation {
+	// We take the test handle and settings used to restart nodes from
+	// the restarts already planned for the upgrade.
+	var restartTemplate *restartWithNewBinaryStep
+	for _, s := range plan.newStepSelector() {
+		if step, ok := s.impl.(restartWithNewBinaryStep); ok {
+			restartTemplate = &step
+			break
+		}
+	}
+	if restartTemplate == nil {
+		return nil
+	}
+
+	index := newStepIndex(plan)
+
+	var mutations []mutation
+	for _, upgradeSelector := range randomUpgrades(rng, plan) {
+		chosenStep := upgradeSelector.
+			Filter(func(s *singleStep) bool {
+				numUpgraded := len(s.context.System.NodesInNextVersion())
+				return numUpgraded > 0 &&
+					numUpgraded < len(s.context.System.Descriptor.Nodes) &&
+					len(s.context.System.Descriptor.Nodes) >= minDecommissionRejoinNodes &&
+					s.context.Tenant == nil &&
+					!index.IsConcurrent(s)
+			}).
+			RandomStep(rng)
+		if len(chosenStep) == 0 {
+			continue
+		}
+
+		stepContext := chosenStep[0].context
+		nodes := stepContext.System.Descriptor.Nodes
+		node := nodes[1+rng.Intn(len(nodes)-1)]
+		nodeVersion, err := stepContext.System.NodeVersion(node)
+		handleInternalError(err)
+
+		mutations = append(mutations, chosenStep.InsertBefore(decommissionNodeStep{
+			rt:      restartTemplate.rt,
+			node:    node,
+			version: nodeVersion,
+		})...)
+		mutations = append(mutations, chosenStep.InsertBefore(stopNodesStep{nodes: option.NodeListOption{node}})...)
+		mutations = append(mutations, chosenStep.InsertBefore(wipeNodesStep{nodes: option.NodeListOption{node}})...)
+		mutations = append(mutations, chosenStep.InsertBefore(restartWithNewBinaryStep{
+			version:  nodeVersion,
+			rt:       restartTemplate.rt,
+			node:     node,
+			settings: restartTemplate.settings,
+		})...)
+		mutations = append(mutations, chosenStep.InsertBefore(waitForRangeAvailabilityStep{})...)
+	}
+
+	return mutations
+}
+
+// minCPULimitPercent and maxCPULimitPercent bound the share of each
+// CPU that the `cpuLimitMutator` leaves to the cockroach process.
+const (
+	minCPULimitPercent = 25
+	maxCPULimitPercent = 75
+)
+
+type cpuLimitMutator struct{}
+
+func (m cpuLimitMutator) Name() string {
+	return CPULimit
+}
+
+func (m cpuLimitMutator) Probability() float64 {
+	return 0.2
+}
+
+// Generate returns mutations that limit the CPU of a random subset of
+// nodes before a step in a mixed-binary state, and lift the limit
+// after the same or a later step in that state, for a random subset
+// of upgrades in the plan. The length of the returned mutations is
+// always even.
+func (m cpuLimitMutator) Generate(rng *rand.Rand, plan *TestPlan) []mutation {
+	index := newStepIndex(plan)
+
+	var mutations []mutation
+	for _, upgradeSelector := range randomUpgrades(rng, plan) {
+		candidates := upgradeSelector.Filter(func(s *singleStep) bool {
+			numUpgraded := len(s.context.System.NodesInNextVersion())
+			return numUpgraded > 0 &&
+				numUpgraded < len(s.context.System.Descriptor.Nodes) &&
+				!index.IsConcurrent(s)
+		})
+		if len(candidates) == 0 {
+			continue
+		}
+
+		limitIdx := rng.Intn(len(candidates))
+		releaseIdx := limitIdx + rng.Intn(len(candidates)-limitIdx)
+
+		allNodes := candidates[limitIdx].context.System.Descriptor.Nodes
+		numLimited := 1
+		if len(allNodes) > 2 {
+			numLimited += rng.Intn(len(allNodes) - 1)
+		}
+		var nodes option.NodeListOption
+		for _, j := range rng.Perm(len(allNodes))[:numLimited] {
+			nodes = append(nodes, allNodes[j])
+		}
+		sort.Ints(nodes)
+
+		percent := minCPULimitPercent + rng.Intn(maxCPULimitPercent-minCPULimitPercent+1)
+		mutations = append(mutations, candidates[limitIdx:limitIdx+1].InsertBefore(
+			limitCPUStep{nodes: nodes, percent: percent},
+		)...)
+		mutations = append(mutations, candidates[releaseIdx:releaseIdx+1].InsertAfter(
+			releaseCPUStep{nodes: nodes},
+		)...)
+	}
+
+	return mutations
+}
+
+// tenantCapabilityNames are the boolean capabilities that the
+// `tenantCapabilitiesMutator` may grant or revoke. All of them are
+// available in every release that supports tenant capabilities.
+var tenantCapabilityNames = []string{
+	"can_admin_split",
+	"can_admin_unsplit",
+	"can_view_node_info",
+	"can_view_tsdb_metrics",
+}
+
+type tenantCapabilitiesMutator struct{}
+
+func (m tenantCapabilitiesMutator) Name() string {
+	return TenantCapabilities
+}
+
+func (m tenantCapabilitiesMutator) Probability() float64 {
+	return 0.3
+}
+
+// Generate returns mutations that change the capabilities of a new
+// virtual cluster at a random sequential step in a mixed-binary
+// state, and a step that verifies the capabilities after the cluster
+// version is finalized, for a random subset of upgrades in the plan.
+// The virtual cluster is only created to hold the capabilities, and
+// is never started. The length of the returned mutations is always
+// even.
+func (m tenantCapabilitiesMutator) Generate(rng *rand.Rand, plan *TestPlan) []mutation {
+	index := newStepIndex(plan)
+
+	var mutations []mutation
+	for j, upgradeSelector := range randomUpgrades(rng, plan) {
+		chosenStep := upgradeSelector.
+			Filter(func(s *singleStep) bool {
+				numUpgraded := len(s.context.System.NodesInNextVersion())
+				return numUpgraded > 0 &&
+					numUpgraded < len(s.context.System.Descriptor.Nodes) &&
+					s.context.System.FromVersion.AtLeast(minTenantCapabilitiesVersion) &&
+					!index.IsConcurrent(s)
+			}).
+			RandomStep(rng)
+		finalizedStep := upgradeSelector.Filter(func(s *singleStep) bool {
+			_, ok := s.impl.(waitForStableClusterVersionStep)
+			return ok && s.context.System.Stage == RunningUpgradeMigrationsStage
+		})
+		if len(chosenStep) == 0 || len(finalizedStep) == 0 {
+			continue
+		}
+
+		tenant := fmt.Sprintf("%s-%d", tenantCapabilitiesPrefix, j)
+		numCapabilities := 1 + rng.Intn(len(tenantCapabilityNames))
+		var capabilities []tenantCapability
+		for _, k := range rng.Perm(len(tenantCapabilityNames))[:numCapabilities] {
+			capabilities = append(capabilities, tenantCapability{
+				name:    tenantCapabilityNames[k],
+				granted: rng.Float64() < 0.5,
+			})
+		}
+		sort.Slice(capabilities, func(i, j int) bool {
+			return capabilities[i].name < capabilities[j].name
+		})
+
+		mutations = append(mutations, chosenStep.InsertBefore(setTenantCapabilitiesStep{
+			tenant:       tenant,
+			capabilities: capabilities,
+		})...)
+		mutations = append(mutations, finalizedStep[len(finalizedStep)-1:].InsertAfter(
+			verifyTenantCapabilitiesStep{tenant: tenant, capabilities: capabilities},
+		)...)
+	}
+
+	return mutations
+}
+
+type rollingRestartMutator struct{}
+
+func (m rollingRestartMutator) Name() string {
+	return RollingRestart
+}
+
+func (m rollingRestartMutator) Probability() float64 {
+	return 0.2
+}
+
+// Generate returns mutations that restart every node, in random
+// order and with the binary it is running, before a random sequential
+// step in a mixed-binary state, for a random subset of upgrades in
+// the plan. The number of mutations generated for each upgrade is the
+// number of nodes in the cluster.
+func (m rollingRestartMutator) Generate(rng *rand.Rand, plan *TestPlan) []mutation {
+	// We take the test handle and settings used to restart nodes from
+	// the restarts already planned for the upgrade.
+	var restartTemplate *restartWithNewBinaryStep
+	for _, s := range plan.newStepSelector() {
+		if step, ok := s.impl.(restartWithNewBinaryStep); ok {
+			restartTemplate = &step
+			break
+		}
+	}
+	if restartTemplate == nil {
+		return nil
+	}
+
+	index := newStepIndex(plan)
+
+	var mutations []mutation
+	for _, upgradeSelector := range randomUpgrades(rng, plan) {
+		chosenStep := upgradeSelector.
+			Filter(func(s *singleStep) bool {
+				numUpgraded := len(s.context.System.NodesInNextVersion())
+				return numUpgraded > 0 &&
+					numUpgraded < len(s.context.System.Descriptor.Nodes) &&
+					s.context.Tenant == nil &&
+					!index.IsConcurrent(s)
+			}).
+			RandomStep(rng)
+		if len(chosenStep) == 0 {
+			continue
+		}
+
+		stepContext := chosenStep[0].context
+		nodes := stepContext.System.Descriptor.Nodes
+		for _, j := range rng.Perm(len(nodes)) {
+			nodeVersion, err := stepContext.System.NodeVersion(nodes[j])
+			handleInternalError(err)
+
+			mutations = append(mutations, chosenStep.InsertBefore(restartWithNewBinaryStep{
+				version:  nodeVersion,
+				rt:       restartTemplate.rt,
+				node:     nodes[j],
+				settings: restartTemplate.settings,
+			})...)
+		}
+	}
+
+	return mutations
+}
+
+// rpcCompressionEnvVar is read by cockroach on startup to decide
+// whether the RPCs it sends are compressed. Servers accept either, so
+// compression is negotiated per connection by the dialing node. It is
+// honored by every release supported by this framework.
+const rpcCompressionEnvVar = "COCKROACH_ENABLE_RPC_COMPRESSION"
+
+type rpcCompressionMutator struct{}
+
+func (m rpcCompressionMutator) Name() string {
+	return RPCCompression
+}
+
+func (m rpcCompressionMutator) Probability() float64 {
+	return 0.2
+}
+
+// Generate returns mutations that restart every node, in random
+// order and with the binary it is running, before a random sequential
+// step in a mixed-binary state, for a random subset of upgrades in
+// the plan. Nodes alternate between enabling and disabling RPC
+// compression as they are restarted, starting from a random choice,
+// and the restarts are followed by a check that every node can still
+// reach every other node. Nodes go back to the default (compression
+// enabled) the next time they are restarted by the plan.
+func (m rpcCompressionMutator) Generate(rng *rand.Rand, plan *TestPlan) []mutation {
+	// We take the test handle and settings used to restart nodes from
+	// the restarts already planned for the upgrade.
+	var restartTemplate *restartWithNewBinaryStep
+	for _, s := range plan.newStepSelector() {
+		if step, ok := s.impl.(restartWithNewBinaryStep); ok {
+			restartTemplate = &step
+			break
+		}
+	}
+	if restartTemplate == nil {
+		return nil
+	}
+
+	index := newStepIndex(plan)
+
+	var mutations []mutation
+	for _, upgradeSelector := range randomUpgrades(rng, plan) {
+		chosenStep := upgradeSelector.
+			Filter(func(s *singleStep) bool {
+				numUpgraded := len(s.context.System.NodesInNextVersion())
+				return numUpgraded > 0 &&
+					numUpgraded < len(s.context.System.Descriptor.Nodes) &&
+					s.context.Tenant == nil &&
+					!index.IsConcurrent(s)
+			}).
+			RandomStep(rng)
+		if len(chosenStep) == 0 {
+			continue
+		}
+
+		stepContext := chosenStep[0].context
+		nodes := stepContext.System.Descriptor.Nodes
+		compress := rng.Float64() < 0.5
+		for _, j := range rng.Perm(len(nodes)) {
+			nodeVersion, err := stepContext.System.NodeVersion(nodes[j])
+			handleInternalError(err)
+
+			settings := append([]install.ClusterSettingOption{}, restartTemplate.settings...)
+			settings = append(settings, install.EnvOption{
+				fmt.Sprintf("%s=%t", rpcCompressionEnvVar, compress),
+			})
+			mutations = append(mutations, chosenStep.InsertBefore(restartWithNewBinaryStep{
+				version:  nodeVersion,
+				rt:       restartTemplate.rt,
+				node:     nodes[j],
+				settings: settings,
+			})...)
+			compress = !compress
+		}
+		mutations = append(mutations, chosenStep.InsertBefore(checkNodeConnectivityStep{})...)
+	}
+
+	return mutations
+}
+
+// minSQLConnectionChurn and maxSQLConnectionChurn bound the number of
+// connections opened by each application of the
+// `sqlConnectionChurnMutator`.
+const (
+	minSQLConnectionChurn = 20
+	maxSQLConnectionChurn = 100
+)
+
+type sqlConnectionChurnMutator struct{}
+
+func (m sqlConnectionChurnMutator) Name() string {
+	return SQLConnectionChurn
+}
+
+func (m sqlConnectionChurnMutator) Probability() float64 {
+	return 0.2
+}
+
+// Generate returns mutations that open and close SQL connections
+// concurrently with a random node restart, for a random subset of
+// upgrades in the plan, followed by a check that every node can reach
+// every other node once both are done. Connections are only opened
+// to the nodes that are not being restarted. The length of the
+// returned mutations is always even.
+func (m sqlConnectionChurnMutator) Generate(rng *rand.Rand, plan *TestPlan) []mutation {
+	index := newStepIndex(plan)
+
+	var mutations []mutation
+	for _, upgradeSelector := range randomUpgrades(rng, plan) {
+		restartStep := upgradeSelector.
+			Filter(func(s *singleStep) bool {
+				_, ok := s.impl.(restartWithNewBinaryStep)
+				return ok && s.context.Tenant == nil && !index.IsConcurrent(s)
+			}).
+			RandomStep(rng)
+		if len(restartStep) == 0 {
+			continue
+		}
+
+		restart := restartStep[0].impl.(restartWithNewBinaryStep)
+		var nodes option.NodeListOption
+		for _, node := range restartStep[0].context.System.Descriptor.Nodes {
+			if node != restart.node {
+				nodes = append(nodes, node)
+			}
+		}
+		if len(nodes) == 0 {
+			continue
+		}
+
+		// The connectivity check is inserted first so that it ends up
+		// after the concurrent step created for the churn.
+		mutations = append(mutations, restartStep.InsertAfter(checkNodeConnectivityStep{})...)
+		mutations = append(mutations, restartStep.InsertConcurrent(sqlConnectionChurnStep{
+			nodes: nodes,
+			connections: minSQLConnectionChurn +
+				rng.Intn(maxSQLConnectionChurn-minSQLConnectionChurn+1),
+		})...)
+	}
+
+	return mutations
+}
+
+type localityOrderedUpgradeMutator struct{}
+
+func (m localityOrderedUpgradeMutator) Name() string {
+	return LocalityOrderedUpgrade
+}
+
+func (m localityOrderedUpgradeMutator) Probability() float64 {
+	return 0.3
+}
+
+// Generate reorders the restarts of every stage that changes the
+// binary of the nodes, for a random subset of upgrades in the plan,
+// so that nodes change binary one locality at a time. Localities,
+// and nodes within a locality, are visited in random order. Instead
+// of moving steps around, nodes are relabeled: the i-th restart of a
+// stage is assigned the i-th node of the new order, and the context
+// of every step in the stage is rewritten accordingly, so that hooks
+// keep observing the nodes actually running each version. As the
+// plan is changed in place, no mutations are returned.
+func (m localityOrderedUpgradeMutator) Generate(rng *rand.Rand, plan *TestPlan) []mutation {
+	if len(plan.nodeLocalities) == 0 {
+		return nil
+	}
+
+	for _, upgradeSelector := range randomUpgrades(rng, plan) {
+		for _, stage := range []UpgradeStage{
+			TemporaryUpgradeStage, RollbackUpgradeStage, LastUpgradeStage,
+		} {
+			stageSteps := upgradeSelector.Filter(func(s *singleStep) bool {
+				return s.context.System.Stage == stage
+			})
+
+			var restarted option.NodeListOption
+			for _, s := range stageSteps {
+				if step, ok := s.impl.(restartWithNewBinaryStep); ok {
+					restarted = append(restarted, step.node)
+				}
+			}
+			if len(restarted) == 0 {
+				continue
+			}
+
+			relabel := make(map[int]int)
+			for j, node := range localityOrder(rng, restarted, plan.nodeLocalities) {
+				relabel[restarted[j]] = node
+			}
+			for _, s := range stageSteps {
+				if step, ok := s.impl.(restartWithNewBinaryStep); ok {
+					step.node = relabel[step.node]
+					s.impl = step
+				}
+				s.context.System.relabelNodes(relabel)
+			}
+		}
+	}
+
+	return nil
+}
+
+// localityOrder returns the nodes passed grouped by locality, i.e.,
+// the nodes of each locality are contiguous in the result. Nodes
+// without a declared locality are grouped together. Both the order
+// of localities and the order of nodes within a locality are random.
+func localityOrder(
+	rng *rand.Rand, nodes option.NodeListOption, localities map[int]string,
+) option.NodeListOption {
+	byLocality := make(map[string]option.NodeListOption)
+	for _, n := range nodes {
+		byLocality[localities[n]] = append(byLocality[localities[n]], n)
+	}
+
+	names := maps.Keys(byLocality)
+	sort.Strings(names)
+
+	var result option.NodeListOption
+	for _, j := range rng.Perm(len(names)) {
+		group := byLocality[names[j]]
+		rng.Shuffle(len(group), func(a, b int) {
+			group[a], group[b] = group[b], group[a]
+		})
+		result = append(result, group...)
+	}
+
+	return result
+}
+
+type pointInTimeRestoreMutator struct{}
+
+func (m pointInTimeRestoreMutator) Name() string {
+	return PointInTimeRestore
+}
+
+func (m pointInTimeRestoreMutator) Probability() float64 {
+	return 0.2
+}
+
+// Generate returns mutations that back up a new table with revision
+// history, and restore it as of a point in that history at the same
+// or a later sequential step in a mixed-binary state, for a random
+// subset of upgrades in the plan. The length of the returned
+// mutations is always even.
+func (m pointInTimeRestoreMutator) Generate(rng *rand.Rand, plan *TestPlan) []mutation {
+	index := newStepIndex(plan)
+
+	var mutations []mutation
+	for j, upgradeSelector := range randomUpgrades(rng, plan) {
+		candidates := upgradeSelector.Filter(func(s *singleStep) bool {
+			numUpgraded := len(s.context.System.NodesInNextVersion())
+			return numUpgraded > 0 &&
+				numUpgraded < len(s.context.System.Descriptor.Nodes) &&
+				!index.IsConcurrent(s)
+		})
+		if len(candidates) == 0 {
+			continue
+		}
+
+		backupIdx := rng.Intn(len(candidates))
+		restoreIdx := backupIdx + rng.Intn(len(candidates)-backupIdx)
+		nodes := candidates[backupIdx].context.System.Descriptor.Nodes
+		backup := pitrBackup{
+			node:  nodes[rng.Intn(len(nodes))],
+			table: fmt.Sprintf("%s_%d", pitrTablePrefix, j),
+		}
+
+		mutations = append(mutations, candidates[backupIdx:backupIdx+1].InsertBefore(
+			pitrBackupStep{backup: backup},
+		)...)
+		mutations = append(mutations, candidates[restoreIdx:restoreIdx+1].InsertBefore(
+			pitrRestoreStep{
+				backup:   backup,
+				database: fmt.Sprintf("%s_%d", pitrRestoreDatabasePrefix, j),
+				offset:   rng.Float64(),
+			},
+		)...)
+	}
+
+	return mutations
+}
+
 // randomUpgrades returns selectors for the steps of a random subset
 // of upgrades in the plan. The last upgrade is always returned, as
 // that is the most critical upgrade being tested.
@@ -160,6 +1537,8 @@ type clusterSettingMutator struct {
 	possibleValues []interface{}
 	// The version the cluster setting was introduced.
 	minVersion *clusterupgrade.Version
+	// The version the cluster setting was removed, if any.
+	maxVersion *clusterupgrade.Version
 	// The maximum number of changes (set or reset) we will perform.
 	maxChanges int
 }
@@ -182,6 +1561,15 @@ func clusterSettingMinimumVersion(v string) clusterSettingMutatorOption {
 	}
 }
 
+// clusterSettingMaximumVersion indicates that the cluster setting was
+// retired in version `v`, so nodes running that version or later
+// reject any changes to it.
+func clusterSettingMaximumVersion(v string) clusterSettingMutatorOption {
+	return func(csm *clusterSettingMutator) {
+		csm.maxVersion = clusterupgrade.MustParseVersion(v)
+	}
+}
+
 //lint:ignore U1000 currently unused // TODO(renato): remove when used.
 func clusterSettingMaxChanges(n int) clusterSettingMutatorOption {
 	return func(csm *clusterSettingMutator) {
@@ -251,6 +1639,27 @@ func (m clusterSettingMutator) Generate(rng *rand.Rand, plan *TestPlan) []mutati
 				}
 			}
 
+			if m.maxVersion != nil {
+				// Once every node runs a version that no longer knows
+				// about the cluster setting, it can no longer be changed.
+				if s.context.System.FromVersion.AtLeast(m.maxVersion) {
+					return false
+				}
+
+				// If we are upgrading to a version where the cluster
+				// setting was removed, only nodes still running the
+				// previous version are able to service the change. Those
+				// also need to satisfy the minimum version, if any.
+				if s.context.System.ToVersion.AtLeast(m.maxVersion) {
+					if len(s.context.System.NodesInPreviousVersion()) == 0 {
+						return false
+					}
+					if m.minVersion != nil && !s.context.System.FromVersion.AtLeast(m.minVersion) {
+						return false
+					}
+				}
+			}
+
 			// We skip restart steps as we might insert the cluster setting
 			// change step concurrently with the selected step.
 			_, isRestartNode := s.impl.(restartWithNewBinaryStep)
@@ -332,6 +1741,7 @@ func (m clusterSettingMutator) changeSteps(
 		steps = append(steps, clusterSettingChangeStep{
 			impl: setClusterSettingStep{
 				minVersion:         m.minVersion,
+				maxVersion:         m.maxVersion,
 				name:               m.name,
 				value:              newValue,
 				virtualClusterName: install.SystemInterfaceName,
@@ -348,6 +1758,7 @@ func (m clusterSettingMutator) changeSteps(
 		steps = append(steps, clusterSettingChangeStep{
 			impl: resetClusterSettingStep{
 				minVersion:         m.minVersion,
+				maxVersion:         m.maxVersion,
 				name:               m.name,
 				virtualClusterName: install.SystemInterfaceName,
 			},
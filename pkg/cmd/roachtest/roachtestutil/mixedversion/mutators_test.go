@@ -13,11 +13,18 @@ package mixedversion
 import (
 	"math/rand"
 	"reflect"
+	"slices"
+	"strings"
 	"testing"
 	"testing/quick"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/cmd/roachtest/option"
 	"github.com/cockroachdb/cockroach/pkg/cmd/roachtest/roachtestutil/clusterupgrade"
+	"github.com/cockroachdb/cockroach/pkg/roachprod/install"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
 	"github.com/stretchr/testify/require"
 )
@@ -54,6 +61,919 @@ func TestPreserveDowngradeOptionRandomizerMutator(t *testing.T) {
 	}
 }
 
+// TestDiskPressureMutator verifies that every store filled by the
+// mutator is eventually restored, and that the window during which
+// a node is under disk pressure is bounded.
+func TestDiskPressureMutator(t *testing.T) {
+	mvt := newBasicUpgradeTest(NumUpgrades(3))
+	plan, err := mvt.plan()
+	require.NoError(t, err)
+
+	var mut diskPressureMutator
+	rng := newRand()
+	mutations := mut.Generate(rng, plan)
+	require.NotEmpty(t, mutations)
+	require.True(t, len(mutations)%2 == 0, "should produce even number of mutations") // one fill and one restore per upgrade
+
+	for j := 0; j < len(mutations); j += 2 {
+		require.Equal(t, mutationInsertBefore, mutations[j].op)
+		fill, ok := mutations[j].impl.(fillStoreStep)
+		require.True(t, ok, "expected fillStoreStep, found %T", mutations[j].impl)
+
+		require.Equal(t, mutationInsertAfter, mutations[j+1].op)
+		restore, ok := mutations[j+1].impl.(restoreStoreStep)
+		require.True(t, ok, "expected restoreStoreStep, found %T", mutations[j+1].impl)
+		require.Equal(t, fill.node, restore.node)
+	}
+
+	plan.applyMutations(rng, mutations)
+
+	// Count the sequential steps that run while a store is filled.
+	filledNode := -1
+	var stepsUnderPressure int
+	index := newStepIndex(plan)
+	for _, ss := range plan.singleSteps() {
+		switch s := ss.impl.(type) {
+		case fillStoreStep:
+			require.Equal(t, -1, filledNode, "store filled while another node was under pressure")
+			filledNode, stepsUnderPressure = s.node, 0
+		case restoreStoreStep:
+			require.Equal(t, filledNode, s.node)
+			require.LessOrEqual(t, stepsUnderPressure, maxDiskPressureWindow)
+			filledNode = -1
+		default:
+			if filledNode != -1 && !index.IsConcurrent(ss) {
+				stepsUnderPressure++
+			}
+		}
+	}
+	require.Equal(t, -1, filledNode, "store was never restored:\n%s", plan.PrettyPrint())
+}
+
+func TestStaleDescriptorLeaseMutator(t *testing.T) {
+	mvt := newBasicUpgradeTest(NumUpgrades(3))
+	plan, err := mvt.plan()
+	require.NoError(t, err)
+
+	var mut staleDescriptorLeaseMutator
+	rng := newRand()
+	mutations := mut.Generate(rng, plan)
+	require.NotEmpty(t, mutations)
+	require.True(t, len(mutations)%2 == 0, "should produce even number of mutations") // one schema change and one restart per upgrade
+
+	for j := 0; j < len(mutations); j += 2 {
+		require.IsType(t, leaseSchemaChangeStep{}, mutations[j].impl)
+		require.IsType(t, restartWithNewBinaryStep{}, mutations[j+1].impl)
+		require.Equal(t, mutations[j].reference, mutations[j+1].reference)
+	}
+
+	plan.applyMutations(rng, mutations)
+
+	// Every schema change must be immediately followed by the restart
+	// of a node, using the binary that node is already running.
+	steps := plan.singleSteps()
+	var numSchemaChanges int
+	for j, ss := range steps {
+		if _, ok := ss.impl.(leaseSchemaChangeStep); !ok {
+			continue
+		}
+		numSchemaChanges++
+
+		require.Less(t, j+1, len(steps), "schema change is the last step:\n%s", plan.PrettyPrint())
+		restart, ok := steps[j+1].impl.(restartWithNewBinaryStep)
+		require.True(
+			t, ok, "expected restart after schema change, found %T:\n%s", steps[j+1].impl, plan.PrettyPrint(),
+		)
+
+		nodeVersion, err := steps[j+1].context.System.NodeVersion(restart.node)
+		require.NoError(t, err)
+		require.True(t, nodeVersion.Equal(restart.version))
+	}
+	require.Equal(t, len(mutations)/2, numSchemaChanges)
+}
+
+func TestDescriptorVersionBurstMutator(t *testing.T) {
+	mvt := newBasicUpgradeTest(NumUpgrades(3))
+	plan, err := mvt.plan()
+	require.NoError(t, err)
+
+	var mut descriptorVersionBurstMutator
+	rng := newRand()
+	mutations := mut.Generate(rng, plan)
+	require.NotEmpty(t, mutations)
+	plan.applyMutations(rng, mutations)
+
+	// Collect the lengths of every sequence of consecutive schema
+	// changes, verifying that they only run in a mixed-binary state.
+	var bursts []int
+	var current int
+	for _, ss := range plan.singleSteps() {
+		if _, ok := ss.impl.(toggleExcludeFromBackupStep); !ok {
+			if current > 0 {
+				bursts = append(bursts, current)
+			}
+			current = 0
+			continue
+		}
+
+		numUpgraded := len(ss.context.System.NodesInNextVersion())
+		require.Greater(t, numUpgraded, 0, "schema change before upgrade started:\n%s", plan.PrettyPrint())
+		require.Less(
+			t, numUpgraded, len(ss.context.System.Descriptor.Nodes),
+			"schema change after all nodes upgraded:\n%s", plan.PrettyPrint(),
+		)
+		current++
+	}
+	require.Zero(t, current, "plan ends with a schema change")
+
+	require.NotEmpty(t, bursts)
+	var total int
+	for _, n := range bursts {
+		require.GreaterOrEqual(t, n, minDescriptorVersionBurst)
+		require.LessOrEqual(t, n, maxDescriptorVersionBurst)
+		total += n
+	}
+	require.Equal(t, len(mutations), total)
+}
+
+func TestImportMutator(t *testing.T) {
+	mvt := newBasicUpgradeTest(NumUpgrades(3))
+	plan, err := mvt.plan()
+	require.NoError(t, err)
+
+	var mut importMutator
+	rng := newRand()
+	mutations := mut.Generate(rng, plan)
+	require.NotEmpty(t, mutations)
+	require.True(t, len(mutations)%2 == 0, "should produce even number of mutations") // one import and one verification per upgrade
+
+	plan.applyMutations(rng, mutations)
+
+	// Every import must run in a mixed-binary state, and be verified
+	// only after the cluster version is finalized.
+	imported := make(map[string]struct{})
+	var numVerified int
+	steps := plan.singleSteps()
+	for j, ss := range steps {
+		switch s := ss.impl.(type) {
+		case importStep:
+			numUpgraded := len(ss.context.System.NodesInNextVersion())
+			require.Greater(t, numUpgraded, 0, "import before upgrade started:\n%s", plan.PrettyPrint())
+			require.Less(
+				t, numUpgraded, len(ss.context.System.Descriptor.Nodes),
+				"import after all nodes upgraded:\n%s", plan.PrettyPrint(),
+			)
+			require.Contains(t, ss.context.System.Descriptor.Nodes, s.node)
+			imported[s.table] = struct{}{}
+
+		case verifyImportStep:
+			require.Contains(t, imported, s.table, "verification before import:\n%s", plan.PrettyPrint())
+			require.Greater(t, j, 0)
+			require.IsType(t, waitForStableClusterVersionStep{}, steps[j-1].impl)
+			require.Equal(t, RunningUpgradeMigrationsStage, ss.context.System.Stage)
+			numVerified++
+		}
+	}
+	require.Len(t, imported, len(mutations)/2)
+	require.Equal(t, len(mutations)/2, numVerified)
+}
+
+func TestCertRotationMutator(t *testing.T) {
+	mvt := newBasicUpgradeTest(NumUpgrades(3))
+	plan, err := mvt.plan()
+	require.NoError(t, err)
+
+	var mut certRotationMutator
+	rng := newRand()
+	mutations := mut.Generate(rng, plan)
+	require.NotEmpty(t, mutations)
+	require.True(t, len(mutations)%2 == 0, "should produce even number of mutations") // one rotation and one connectivity check per upgrade
+
+	plan.applyMutations(rng, mutations)
+
+	// Every rotation must happen in a mixed-binary state, on a subset
+	// of the cluster's nodes, and be immediately followed by a
+	// connectivity check.
+	steps := plan.singleSteps()
+	var numRotations int
+	for j, ss := range steps {
+		rotation, ok := ss.impl.(rotateNodeCertsStep)
+		if !ok {
+			continue
+		}
+		numRotations++
+
+		numUpgraded := len(ss.context.System.NodesInNextVersion())
+		require.Greater(t, numUpgraded, 0, "rotation before upgrade started:\n%s", plan.PrettyPrint())
+		require.Less(
+			t, numUpgraded, len(ss.context.System.Descriptor.Nodes),
+			"rotation after all nodes upgraded:\n%s", plan.PrettyPrint(),
+		)
+
+		require.NotEmpty(t, rotation.nodes)
+		seen := make(map[int]struct{})
+		for _, node := range rotation.nodes {
+			require.Contains(t, ss.context.System.Descriptor.Nodes, node)
+			require.NotContains(t, seen, node, "node %d rotated twice", node)
+			seen[node] = struct{}{}
+		}
+
+		require.Less(t, j+1, len(steps), "rotation is the last step:\n%s", plan.PrettyPrint())
+		require.IsType(t, checkNodeConnectivityStep{}, steps[j+1].impl)
+	}
+	require.Equal(t, len(mutations)/2, numRotations)
+}
+
+func TestQuorumLossMutator(t *testing.T) {
+	// The mutator is only enabled in tests that opt into it.
+	quorumLossEnabled := func(opts ...CustomOption) bool {
+		plan, err := newBasicUpgradeTest(append(opts, NumUpgrades(3))...).plan()
+		require.NoError(t, err)
+		return slices.ContainsFunc(plan.enabledMutators, func(mut mutator) bool {
+			return mut.Name() == QuorumLoss
+		})
+	}
+	require.False(t, quorumLossEnabled())
+	require.True(t, quorumLossEnabled(WithMutatorProbability(QuorumLoss, 1)))
+
+	mvt := newBasicUpgradeTest(NumUpgrades(3))
+	plan, err := mvt.plan()
+	require.NoError(t, err)
+
+	var mut quorumLossMutator
+	rng := newRand()
+	mutations := mut.Generate(rng, plan)
+	require.NotEmpty(t, mutations)
+	plan.applyMutations(rng, mutations)
+
+	// Every outage must stop a majority of the nodes in a mixed-binary
+	// state, last a bounded amount of time, and be followed by the
+	// restart of every stopped node and a wait for ranges to recover.
+	steps := plan.singleSteps()
+	var numOutages int
+	for j, ss := range steps {
+		stop, ok := ss.impl.(stopNodesStep)
+		if !ok {
+			continue
+		}
+		numOutages++
+
+		nodes := ss.context.System.Descriptor.Nodes
+		numUpgraded := len(ss.context.System.NodesInNextVersion())
+		require.Greater(t, numUpgraded, 0, "outage before upgrade started:\n%s", plan.PrettyPrint())
+		require.Less(t, numUpgraded, len(nodes), "outage after all nodes upgraded:\n%s", plan.PrettyPrint())
+		require.Greater(t, len(stop.nodes), len(nodes)/2, "outage does not stop a majority of nodes")
+		require.Less(t, len(stop.nodes), len(nodes), "outage stops every node")
+
+		require.Less(t, j+len(stop.nodes)+2, len(steps), "outage is not followed by recovery:\n%s", plan.PrettyPrint())
+		wait, ok := steps[j+1].impl.(waitStep)
+		require.True(t, ok, "expected wait after stopping nodes, found %T:\n%s", steps[j+1].impl, plan.PrettyPrint())
+		require.GreaterOrEqual(t, wait.dur, minQuorumLossOutage)
+		require.LessOrEqual(t, wait.dur, maxQuorumLossOutage)
+
+		for k, node := range stop.nodes {
+			restartStep := steps[j+2+k]
+			restart, ok := restartStep.impl.(restartWithNewBinaryStep)
+			require.True(
+				t, ok, "expected restart of node %d, found %T:\n%s", node, restartStep.impl, plan.PrettyPrint(),
+			)
+			require.Equal(t, node, restart.node)
+
+			nodeVersion, err := restartStep.context.System.NodeVersion(node)
+			require.NoError(t, err)
+			require.True(t, nodeVersion.Equal(restart.version))
+		}
+		require.IsType(t, waitForRangeAvailabilityStep{}, steps[j+2+len(stop.nodes)].impl)
+	}
+	require.Greater(t, numOutages, 0)
+}
+
+func TestReplicationCutoverMutator(t *testing.T) {
+	mvt := newBasicUpgradeTest(NumUpgrades(3))
+	plan, err := mvt.plan()
+	require.NoError(t, err)
+
+	var mut replicationCutoverMutator
+	rng := newRand()
+	mutations := mut.Generate(rng, plan)
+	require.NotEmpty(t, mutations)
+	plan.applyMutations(rng, mutations)
+
+	// Every cutover must target a standby into which a replication
+	// stream was previously started, and both must happen in a
+	// mixed-binary state where every node supports replication.
+	started := make(map[string]struct{})
+	var numCutovers int
+	for _, ss := range plan.singleSteps() {
+		var standby string
+		switch s := ss.impl.(type) {
+		case startReplicationStep:
+			require.NotEqual(t, s.source, s.standby)
+			require.Contains(t, ss.context.System.Descriptor.Nodes, s.node)
+			require.NotContains(t, started, s.standby, "standby %s started twice", s.standby)
+			started[s.standby] = struct{}{}
+			standby = s.standby
+		case replicationCutoverStep:
+			require.Contains(t, started, s.standby, "cutover before replication started:\n%s", plan.PrettyPrint())
+			require.GreaterOrEqual(t, s.lag, time.Duration(0))
+			require.LessOrEqual(t, s.lag, maxReplicationCutoverLag)
+			numCutovers++
+			standby = s.standby
+		default:
+			continue
+		}
+
+		numUpgraded := len(ss.context.System.NodesInNextVersion())
+		require.Greater(t, numUpgraded, 0, "%s before upgrade started:\n%s", standby, plan.PrettyPrint())
+		require.Less(t, numUpgraded, len(ss.context.System.Descriptor.Nodes))
+		require.True(t, ss.context.System.FromVersion.AtLeast(minReplicationCutoverVersion))
+	}
+	require.Equal(t, len(mutations)/2, numCutovers)
+	require.Len(t, started, numCutovers)
+
+	// The cutover timestamp lags behind the replicated time, but is
+	// never earlier than the retained time.
+	retained := time.Unix(1000, 0)
+	replicated := retained.Add(time.Minute)
+	require.Equal(t, replicated, cutoverTime(retained, replicated, 0))
+	require.Equal(t, replicated.Add(-10*time.Second), cutoverTime(retained, replicated, 10*time.Second))
+	require.Equal(t, retained, cutoverTime(retained, replicated, 2*time.Minute))
+}
+
+func TestProtectedTimestampGCMutator(t *testing.T) {
+	mvt := newBasicUpgradeTest(NumUpgrades(3))
+	plan, err := mvt.plan()
+	require.NoError(t, err)
+
+	var mut protectedTimestampGCMutator
+	rng := newRand()
+	mutations := mut.Generate(rng, plan)
+	require.NotEmpty(t, mutations)
+	require.Zero(t, len(mutations)%3)
+	plan.applyMutations(rng, mutations)
+
+	// Every table must be protected, then dropped, and then verified,
+	// in that order. The first two happen in a mixed-binary state, and
+	// the verification only after the upgrade is finalized.
+	const (
+		protected = iota + 1
+		dropped
+		verified
+	)
+	tables := make(map[string]int)
+	for _, ss := range plan.singleSteps() {
+		mixed := func() {
+			numUpgraded := len(ss.context.System.NodesInNextVersion())
+			require.Greater(t, numUpgraded, 0, "step before upgrade started:\n%s", plan.PrettyPrint())
+			require.Less(t, numUpgraded, len(ss.context.System.Descriptor.Nodes))
+		}
+
+		switch s := ss.impl.(type) {
+		case protectTableStep:
+			require.NotContains(t, tables, s.table, "%s protected twice", s.table)
+			mixed()
+			tables[s.table] = protected
+		case dropProtectedTableStep:
+			require.Equal(t, protected, tables[s.table], "%s dropped out of order:\n%s", s.table, plan.PrettyPrint())
+			mixed()
+			tables[s.table] = dropped
+		case verifyProtectedTableStep:
+			require.Equal(t, dropped, tables[s.table], "%s verified out of order:\n%s", s.table, plan.PrettyPrint())
+			require.Equal(t, RunningUpgradeMigrationsStage, ss.context.System.Stage)
+			tables[s.table] = verified
+		}
+	}
+
+	require.Len(t, tables, len(mutations)/3)
+	for table, state := range tables {
+		require.Equal(t, verified, state, "%s was never verified", table)
+	}
+}
+
+func TestPointInTimeRestoreMutator(t *testing.T) {
+	mvt := newBasicUpgradeTest(NumUpgrades(3))
+	plan, err := mvt.plan()
+	require.NoError(t, err)
+
+	var mut pointInTimeRestoreMutator
+	rng := newRand()
+	mutations := mut.Generate(rng, plan)
+	require.NotEmpty(t, mutations)
+	require.Zero(t, len(mutations)%2)
+	plan.applyMutations(rng, mutations)
+
+	// Every table must be backed up before it is restored, and both
+	// must happen in a mixed-binary state of the same upgrade.
+	backups := make(map[string]*clusterupgrade.Version)
+	restored := make(map[string]struct{})
+	for _, ss := range plan.singleSteps() {
+		var table string
+		switch s := ss.impl.(type) {
+		case pitrBackupStep:
+			table = s.backup.table
+			require.NotContains(t, backups, table, "%s backed up twice", table)
+			require.Contains(t, ss.context.System.Descriptor.Nodes, s.backup.node)
+			backups[table] = ss.context.System.FromVersion
+		case pitrRestoreStep:
+			table = s.backup.table
+			require.Contains(t, backups, table, "%s restored before backup:\n%s", table, plan.PrettyPrint())
+			require.True(t, backups[table].Equal(ss.context.System.FromVersion))
+			require.NotContains(t, restored, table, "%s restored twice", table)
+			require.GreaterOrEqual(t, s.offset, 0.0)
+			require.Less(t, s.offset, 1.0)
+			restored[table] = struct{}{}
+		default:
+			continue
+		}
+
+		numUpgraded := len(ss.context.System.NodesInNextVersion())
+		require.Greater(t, numUpgraded, 0, "%s before upgrade started:\n%s", table, plan.PrettyPrint())
+		require.Less(t, numUpgraded, len(ss.context.System.Descriptor.Nodes))
+	}
+	require.Len(t, backups, len(mutations)/2)
+	require.Len(t, restored, len(backups))
+
+	// The restore timestamp always falls within the revision history
+	// of the backup.
+	start := hlc.Timestamp{WallTime: 1000, Logical: 3}
+	end := hlc.Timestamp{WallTime: 2000}
+	for _, offset := range []float64{-1, 0, 1e-9, 0.25, 0.5, 0.999, 1, 2} {
+		ts := restoreTime(start, end, offset)
+		require.True(t, start.LessEq(ts), "offset %f: %s before %s", offset, ts, start)
+		require.True(t, ts.LessEq(end), "offset %f: %s after %s", offset, ts, end)
+	}
+	require.Equal(t, start, restoreTime(start, end, 0))
+	require.Equal(t, hlc.Timestamp{WallTime: 1500}, restoreTime(start, end, 0.5))
+	require.Equal(t, end, restoreTime(start, end, 1))
+	require.Equal(t, start, restoreTime(start, start, 0.5))
+}
+
+func TestNodePauseMutator(t *testing.T) {
+	mvt := newBasicUpgradeTest(NumUpgrades(3))
+	plan, err := mvt.plan()
+	require.NoError(t, err)
+
+	var mut nodePauseMutator
+	rng := newRand()
+	mutations := mut.Generate(rng, plan)
+	require.NotEmpty(t, mutations)
+	plan.applyMutations(rng, mutations)
+
+	// Every pause must be bounded, run concurrently with the step that
+	// waits for migrations to finish, and be followed by a connectivity
+	// check once both are done.
+	index := newStepIndex(plan)
+	steps := plan.singleSteps()
+	var numPauses int
+	for j, ss := range steps {
+		pause, ok := ss.impl.(pauseNodeStep)
+		if !ok {
+			continue
+		}
+		numPauses++
+
+		require.Contains(t, ss.context.System.Descriptor.Nodes, pause.node)
+		require.GreaterOrEqual(t, pause.dur, minNodePause)
+		require.LessOrEqual(t, pause.dur, maxNodePause)
+		require.Equal(t, RunningUpgradeMigrationsStage, ss.context.System.Stage)
+		require.True(t, index.IsConcurrent(ss), "pause is not concurrent:\n%s", plan.PrettyPrint())
+
+		require.Greater(t, j, 0)
+		require.Less(t, j+1, len(steps))
+		waitIdx := j - 1
+		if _, ok := steps[waitIdx].impl.(waitForStableClusterVersionStep); !ok {
+			waitIdx = j + 1
+		}
+		require.IsType(
+			t, waitForStableClusterVersionStep{}, steps[waitIdx].impl,
+			"pause does not overlap migrations:\n%s", plan.PrettyPrint(),
+		)
+		require.True(t, index.IsConcurrent(steps[waitIdx]))
+
+		last := max(j, waitIdx)
+		require.Less(t, last+1, len(steps), "pause is the last step:\n%s", plan.PrettyPrint())
+		require.IsType(t, checkNodeConnectivityStep{}, steps[last+1].impl)
+	}
+	require.Equal(t, len(mutations)/2, numPauses)
+}
+
+func TestSurvivalGoalMutator(t *testing.T) {
+	mvt := newBasicUpgradeTest(NumUpgrades(3))
+	plan, err := mvt.plan()
+	require.NoError(t, err)
+
+	var mut survivalGoalMutator
+	rng := newRand()
+	mutations := mut.Generate(rng, plan)
+	require.NotEmpty(t, mutations)
+	plan.applyMutations(rng, mutations)
+
+	// Every survival goal change must happen in a mixed-binary state,
+	// and be verified with the same goal after the upgrade is
+	// finalized.
+	changes := make(map[string]string)
+	var numVerified int
+	for _, ss := range plan.singleSteps() {
+		switch s := ss.impl.(type) {
+		case changeSurvivalGoalStep:
+			numUpgraded := len(ss.context.System.NodesInNextVersion())
+			require.Greater(t, numUpgraded, 0, "change before upgrade started:\n%s", plan.PrettyPrint())
+			require.Less(t, numUpgraded, len(ss.context.System.Descriptor.Nodes), "change after all nodes upgraded:\n%s", plan.PrettyPrint())
+			require.Contains(t, survivalGoals, s.goal)
+			require.NotContains(t, changes, s.database)
+			changes[s.database] = s.goal
+		case verifySurvivalGoalStep:
+			require.Contains(t, changes, s.database, "verification before change:\n%s", plan.PrettyPrint())
+			require.Equal(t, changes[s.database], s.goal)
+			require.Equal(t, RunningUpgradeMigrationsStage, ss.context.System.Stage)
+			numVerified++
+		}
+	}
+	require.Len(t, changes, len(mutations)/2)
+	require.Equal(t, len(changes), numVerified)
+}
+
+func TestInflightJobMutator(t *testing.T) {
+	mvt := newBasicUpgradeTest(NumUpgrades(3))
+	plan, err := mvt.plan()
+	require.NoError(t, err)
+
+	var mut inflightJobMutator
+	rng := newRand()
+	mutations := mut.Generate(rng, plan)
+	require.NotEmpty(t, mutations)
+	plan.applyMutations(rng, mutations)
+
+	// Every job must be created in a mixed-binary state, before the
+	// upgrade migrations run, and resumed after the upgrade is
+	// finalized.
+	paused := make(map[inflightJob]struct{})
+	var numResumed int
+	for _, ss := range plan.singleSteps() {
+		switch s := ss.impl.(type) {
+		case pauseSchemaChangeJobStep:
+			numUpgraded := len(ss.context.System.NodesInNextVersion())
+			require.Greater(t, numUpgraded, 0, "job created before upgrade started:\n%s", plan.PrettyPrint())
+			require.Less(t, numUpgraded, len(ss.context.System.Descriptor.Nodes), "job created after all nodes upgraded:\n%s", plan.PrettyPrint())
+			require.NotEqual(t, RunningUpgradeMigrationsStage, ss.context.System.Stage)
+			require.NotContains(t, paused, s.job)
+			paused[s.job] = struct{}{}
+		case resumeSchemaChangeJobStep:
+			require.Contains(t, paused, s.job, "job resumed before it was created:\n%s", plan.PrettyPrint())
+			require.Equal(t, RunningUpgradeMigrationsStage, ss.context.System.Stage)
+			numResumed++
+		}
+	}
+	require.Len(t, paused, len(mutations)/2)
+	require.Equal(t, len(paused), numResumed)
+}
+
+func TestSchemaChangePauseResumeMutator(t *testing.T) {
+	mvt := newBasicUpgradeTest(NumUpgrades(3))
+	plan, err := mvt.plan()
+	require.NoError(t, err)
+
+	var mut schemaChangePauseResumeMutator
+	rng := newRand()
+	mutations := mut.Generate(rng, plan)
+	require.NotEmpty(t, mutations)
+	plan.applyMutations(rng, mutations)
+
+	// Every job must be paused in a mixed-binary state, before the
+	// cluster version is finalized, and resumed after it is. The
+	// value in `paused` indicates whether the upgrade was finalized
+	// since the job was paused.
+	paused := make(map[inflightJob]bool)
+	var numResumed int
+	for _, ss := range plan.singleSteps() {
+		switch s := ss.impl.(type) {
+		case pauseRunningSchemaChangeJobStep:
+			numUpgraded := len(ss.context.System.NodesInNextVersion())
+			require.Greater(t, numUpgraded, 0, "job paused before upgrade started:\n%s", plan.PrettyPrint())
+			require.Less(t, numUpgraded, len(ss.context.System.Descriptor.Nodes), "job paused after all nodes upgraded:\n%s", plan.PrettyPrint())
+			require.NotEqual(t, RunningUpgradeMigrationsStage, ss.context.System.Stage)
+			require.NotContains(t, paused, s.job)
+			require.Equal(t, pauseResumeJobRows, s.job.rows)
+			paused[s.job] = false
+		case waitForStableClusterVersionStep:
+			if ss.context.System.Stage == RunningUpgradeMigrationsStage {
+				for job := range paused {
+					paused[job] = true
+				}
+			}
+		case resumeSchemaChangeJobStep:
+			finalized, ok := paused[s.job]
+			require.True(t, ok, "job resumed before it was paused:\n%s", plan.PrettyPrint())
+			require.True(t, finalized, "job resumed before upgrade was finalized:\n%s", plan.PrettyPrint())
+			numResumed++
+		}
+	}
+	require.Len(t, paused, len(mutations)/2)
+	require.Equal(t, len(paused), numResumed)
+}
+
+func TestCPULimitMutator(t *testing.T) {
+	mvt := newBasicUpgradeTest(NumUpgrades(3))
+	plan, err := mvt.plan()
+	require.NoError(t, err)
+
+	var mut cpuLimitMutator
+	rng := newRand()
+	mutations := mut.Generate(rng, plan)
+	require.NotEmpty(t, mutations)
+	plan.applyMutations(rng, mutations)
+
+	// Every CPU limit must be applied to a strict subset of the nodes
+	// in a mixed-binary state, and released before the upgrade is
+	// finalized.
+	var limited option.NodeListOption
+	var numReleased int
+	for _, ss := range plan.singleSteps() {
+		switch s := ss.impl.(type) {
+		case limitCPUStep:
+			require.Nil(t, limited, "CPU limited twice:\n%s", plan.PrettyPrint())
+			numUpgraded := len(ss.context.System.NodesInNextVersion())
+			require.Greater(t, numUpgraded, 0, "limit before upgrade started:\n%s", plan.PrettyPrint())
+			require.Less(t, numUpgraded, len(ss.context.System.Descriptor.Nodes), "limit after all nodes upgraded:\n%s", plan.PrettyPrint())
+			require.NotEmpty(t, s.nodes)
+			require.Less(t, len(s.nodes), len(ss.context.System.Descriptor.Nodes))
+			require.GreaterOrEqual(t, s.percent, minCPULimitPercent)
+			require.LessOrEqual(t, s.percent, maxCPULimitPercent)
+			limited = s.nodes
+		case releaseCPUStep:
+			require.Equal(t, limited, s.nodes, "release without limit:\n%s", plan.PrettyPrint())
+			limited = nil
+			numReleased++
+		case waitForStableClusterVersionStep:
+			if ss.context.System.Stage == RunningUpgradeMigrationsStage {
+				require.Nil(t, limited, "CPU limited during finalization:\n%s", plan.PrettyPrint())
+			}
+		}
+	}
+	require.Nil(t, limited)
+	require.Equal(t, len(mutations)/2, numReleased)
+}
+
+func TestDecommissionRejoinMutator(t *testing.T) {
+	mvt := newBasicUpgradeTest(NumUpgrades(3))
+	plan, err := mvt.plan()
+	require.NoError(t, err)
+
+	var mut decommissionRejoinMutator
+	rng := newRand()
+	mutations := mut.Generate(rng, plan)
+	require.NotEmpty(t, mutations)
+	plan.applyMutations(rng, mutations)
+
+	// Every decommissioned node must leave enough nodes behind to keep
+	// quorum, and be stopped, wiped, and started again with the binary
+	// it was running, followed by a wait for ranges to recover.
+	steps := plan.singleSteps()
+	var numDecommissions int
+	for j, ss := range steps {
+		decommission, ok := ss.impl.(decommissionNodeStep)
+		if !ok {
+			continue
+		}
+		numDecommissions++
+
+		nodes := ss.context.System.Descriptor.Nodes
+		numUpgraded := len(ss.context.System.NodesInNextVersion())
+		require.Greater(t, numUpgraded, 0, "decommission before upgrade started:\n%s", plan.PrettyPrint())
+		require.Less(t, numUpgraded, len(nodes), "decommission after all nodes upgraded:\n%s", plan.PrettyPrint())
+		require.GreaterOrEqual(t, len(nodes), minDecommissionRejoinNodes)
+		require.NotEqual(t, nodes[0], decommission.node, "first node decommissioned")
+
+		nodeVersion, err := ss.context.System.NodeVersion(decommission.node)
+		require.NoError(t, err)
+		require.True(t, nodeVersion.Equal(decommission.version))
+
+		require.Less(t, j+4, len(steps), "decommission is not followed by rejoin:\n%s", plan.PrettyPrint())
+		require.Equal(t, stopNodesStep{nodes: option.NodeListOption{decommission.node}}, steps[j+1].impl)
+		require.Equal(t, wipeNodesStep{nodes: option.NodeListOption{decommission.node}}, steps[j+2].impl)
+		restart, ok := steps[j+3].impl.(restartWithNewBinaryStep)
+		require.True(t, ok, "expected restart of node %d, found %T:\n%s", decommission.node, steps[j+3].impl, plan.PrettyPrint())
+		require.Equal(t, decommission.node, restart.node)
+		require.True(t, nodeVersion.Equal(restart.version))
+		require.IsType(t, waitForRangeAvailabilityStep{}, steps[j+4].impl)
+	}
+	require.Greater(t, numDecommissions, 0)
+}
+
+func TestTenantCapabilitiesMutator(t *testing.T) {
+	mvt := newBasicUpgradeTest(NumUpgrades(3))
+	plan, err := mvt.plan()
+	require.NoError(t, err)
+
+	var mut tenantCapabilitiesMutator
+	rng := newRand()
+	mutations := mut.Generate(rng, plan)
+	require.NotEmpty(t, mutations)
+	plan.applyMutations(rng, mutations)
+
+	// Capabilities must only be changed in a mixed-binary state, on a
+	// virtual cluster owned by the mutator, and be verified with the
+	// same values after the upgrade is finalized.
+	changes := make(map[string][]tenantCapability)
+	var numVerified int
+	for _, ss := range plan.singleSteps() {
+		switch s := ss.impl.(type) {
+		case setTenantCapabilitiesStep:
+			numUpgraded := len(ss.context.System.NodesInNextVersion())
+			require.Greater(t, numUpgraded, 0, "change before upgrade started:\n%s", plan.PrettyPrint())
+			require.Less(t, numUpgraded, len(ss.context.System.Descriptor.Nodes), "change after all nodes upgraded:\n%s", plan.PrettyPrint())
+			require.True(t, ss.context.System.FromVersion.AtLeast(minTenantCapabilitiesVersion))
+			require.True(t, strings.HasPrefix(s.tenant, tenantCapabilitiesPrefix))
+			require.NotContains(t, changes, s.tenant)
+			require.NotEmpty(t, s.capabilities)
+
+			names := make(map[string]struct{})
+			for _, c := range s.capabilities {
+				require.Contains(t, tenantCapabilityNames, c.name)
+				require.NotContains(t, names, c.name, "capability %s changed twice", c.name)
+				names[c.name] = struct{}{}
+			}
+			changes[s.tenant] = s.capabilities
+		case verifyTenantCapabilitiesStep:
+			require.Contains(t, changes, s.tenant, "verification before change:\n%s", plan.PrettyPrint())
+			require.Equal(t, changes[s.tenant], s.capabilities)
+			require.Equal(t, RunningUpgradeMigrationsStage, ss.context.System.Stage)
+			numVerified++
+		}
+	}
+	require.Len(t, changes, len(mutations)/2)
+	require.Equal(t, len(changes), numVerified)
+}
+
+func TestRollingRestartMutator(t *testing.T) {
+	mvt := newBasicUpgradeTest(NumUpgrades(3))
+	plan, err := mvt.plan()
+	require.NoError(t, err)
+
+	var mut rollingRestartMutator
+	rng := newRand()
+	mutations := mut.Generate(rng, plan)
+	require.NotEmpty(t, mutations)
+
+	// Every node must be restarted exactly once before each chosen
+	// step, with the binary it was running at that point.
+	restarts := make(map[*singleStep]map[int]struct{})
+	for _, m := range mutations {
+		restart, ok := m.impl.(restartWithNewBinaryStep)
+		require.True(t, ok, "unexpected step %T", m.impl)
+		require.Equal(t, mutationInsertBefore, m.op)
+
+		stepContext := m.reference.context
+		numUpgraded := len(stepContext.System.NodesInNextVersion())
+		require.Greater(t, numUpgraded, 0, "restart before upgrade started")
+		require.Less(t, numUpgraded, len(stepContext.System.Descriptor.Nodes), "restart after all nodes upgraded")
+
+		nodeVersion, err := stepContext.System.NodeVersion(restart.node)
+		require.NoError(t, err)
+		require.True(t, nodeVersion.Equal(restart.version), "node %d restarted with a different version", restart.node)
+
+		if restarts[m.reference] == nil {
+			restarts[m.reference] = make(map[int]struct{})
+		}
+		require.NotContains(t, restarts[m.reference], restart.node, "node %d restarted twice", restart.node)
+		restarts[m.reference][restart.node] = struct{}{}
+	}
+	for ref, nodes := range restarts {
+		require.Len(t, nodes, len(ref.context.System.Descriptor.Nodes))
+	}
+
+	plan.applyMutations(rng, mutations)
+}
+
+func TestRPCCompressionMutator(t *testing.T) {
+	mvt := newBasicUpgradeTest(NumUpgrades(3))
+	plan, err := mvt.plan()
+	require.NoError(t, err)
+
+	var mut rpcCompressionMutator
+	rng := newRand()
+	mutations := mut.Generate(rng, plan)
+	require.NotEmpty(t, mutations)
+
+	// compressionEnv returns the value of the RPC compression
+	// environment variable passed to a restart, if any.
+	compressionEnv := func(restart restartWithNewBinaryStep) string {
+		var value string
+		for _, opt := range restart.settings {
+			env, ok := opt.(install.EnvOption)
+			if !ok {
+				continue
+			}
+			for _, v := range env {
+				if strings.HasPrefix(v, rpcCompressionEnvVar+"=") {
+					value = strings.TrimPrefix(v, rpcCompressionEnvVar+"=")
+				}
+			}
+		}
+		return value
+	}
+
+	// Every node must be restarted exactly once before each chosen
+	// step, with the binary it was running at that point, alternately
+	// enabling and disabling compression. The restarts are followed by
+	// a connectivity check.
+	restarts := make(map[*singleStep]map[int]struct{})
+	lastValue := make(map[*singleStep]string)
+	checked := make(map[*singleStep]bool)
+	for _, m := range mutations {
+		require.Equal(t, mutationInsertBefore, m.op)
+		if _, ok := m.impl.(checkNodeConnectivityStep); ok {
+			require.Len(t, restarts[m.reference], len(m.reference.context.System.Descriptor.Nodes))
+			checked[m.reference] = true
+			continue
+		}
+
+		restart, ok := m.impl.(restartWithNewBinaryStep)
+		require.True(t, ok, "unexpected step %T", m.impl)
+		require.False(t, checked[m.reference], "restart after connectivity check")
+
+		stepContext := m.reference.context
+		numUpgraded := len(stepContext.System.NodesInNextVersion())
+		require.Greater(t, numUpgraded, 0, "restart before upgrade started")
+		require.Less(t, numUpgraded, len(stepContext.System.Descriptor.Nodes), "restart after all nodes upgraded")
+
+		nodeVersion, err := stepContext.System.NodeVersion(restart.node)
+		require.NoError(t, err)
+		require.True(t, nodeVersion.Equal(restart.version), "node %d restarted with a different version", restart.node)
+
+		value := compressionEnv(restart)
+		require.Contains(t, []string{"true", "false"}, value)
+		require.NotEqual(t, lastValue[m.reference], value, "compression not toggled for node %d", restart.node)
+		lastValue[m.reference] = value
+
+		if restarts[m.reference] == nil {
+			restarts[m.reference] = make(map[int]struct{})
+		}
+		require.NotContains(t, restarts[m.reference], restart.node, "node %d restarted twice", restart.node)
+		restarts[m.reference][restart.node] = struct{}{}
+	}
+	for ref := range restarts {
+		require.True(t, checked[ref], "restarts not followed by connectivity check")
+	}
+
+	// Restarts planned by the upgrade itself must not be affected.
+	for _, s := range plan.newStepSelector() {
+		if restart, ok := s.impl.(restartWithNewBinaryStep); ok {
+			require.Empty(t, compressionEnv(restart))
+		}
+	}
+
+	plan.applyMutations(rng, mutations)
+}
+
+func TestSQLConnectionChurnMutator(t *testing.T) {
+	mvt := newBasicUpgradeTest(NumUpgrades(3))
+	plan, err := mvt.plan()
+	require.NoError(t, err)
+
+	var mut sqlConnectionChurnMutator
+	rng := newRand()
+	mutations := mut.Generate(rng, plan)
+	require.NotEmpty(t, mutations)
+	plan.applyMutations(rng, mutations)
+
+	// Every churn must open a bounded number of connections to nodes
+	// other than the one being restarted, run concurrently with that
+	// restart, and be followed by a connectivity check once both are
+	// done.
+	index := newStepIndex(plan)
+	steps := plan.singleSteps()
+	var numChurns int
+	for j, ss := range steps {
+		churn, ok := ss.impl.(sqlConnectionChurnStep)
+		if !ok {
+			continue
+		}
+		numChurns++
+
+		require.GreaterOrEqual(t, churn.connections, minSQLConnectionChurn)
+		require.LessOrEqual(t, churn.connections, maxSQLConnectionChurn)
+		require.True(t, index.IsConcurrent(ss), "churn is not concurrent:\n%s", plan.PrettyPrint())
+
+		require.Greater(t, j, 0)
+		require.Less(t, j+1, len(steps))
+		restartIdx := j - 1
+		if _, ok := steps[restartIdx].impl.(restartWithNewBinaryStep); !ok {
+			restartIdx = j + 1
+		}
+		restart, ok := steps[restartIdx].impl.(restartWithNewBinaryStep)
+		require.True(t, ok, "churn does not overlap a restart:\n%s", plan.PrettyPrint())
+		require.True(t, index.IsConcurrent(steps[restartIdx]))
+		require.NotContains(t, churn.nodes, restart.node)
+		require.Len(t, churn.nodes, len(ss.context.System.Descriptor.Nodes)-1)
+
+		last := max(j, restartIdx)
+		require.Less(t, last+1, len(steps), "churn is the last step:\n%s", plan.PrettyPrint())
+		require.IsType(t, checkNodeConnectivityStep{}, steps[last+1].impl)
+	}
+	require.Equal(t, len(mutations)/2, numChurns)
+}
+
 // TestClusterSettingMutator does not validate the specific mutations
 // generated by the clusterSettingMutartor; instead, it validates the
 // invariants that the mutator should provide. For example: expected
@@ -247,3 +1167,444 @@ func TestClusterSettingMutator(t *testing.T) {
 		Values:   generator,
 	}))
 }
+
+// clusterSettingMutatorsWithPrefix returns the cluster setting
+// mutators registered in `planMutators` whose setting name starts
+// with the given prefix.
+func clusterSettingMutatorsWithPrefix(prefix string) []clusterSettingMutator {
+	var result []clusterSettingMutator
+	for _, mut := range planMutators {
+		if csm, ok := mut.(clusterSettingMutator); ok && strings.HasPrefix(csm.name, prefix) {
+			result = append(result, csm)
+		}
+	}
+
+	return result
+}
+
+// verifySettingMutatorsVersionValid checks that every mutator passed
+// is able to generate steps for a plan upgrading to `currentVersion`,
+// and that every step generated is only ever executed in a context
+// where at least one node knows about the cluster setting. For
+// settings that were removed, the nodes selected to service the change
+// are also checked to still know about it.
+func verifySettingMutatorsVersionValid(
+	t *testing.T, currentVersion string, mutators []clusterSettingMutator,
+) {
+	require.NotEmpty(t, mutators)
+	current := clusterupgrade.MustParseVersion(currentVersion)
+
+	for _, mut := range mutators {
+		if mut.minVersion != nil {
+			require.True(
+				t, current.AtLeast(mut.minVersion),
+				"%s: minimum version %s is newer than %s", mut.name, mut.minVersion, current,
+			)
+		}
+
+		for _, v := range mut.possibleValues {
+			switch v.(type) {
+			case string, bool, int:
+			default:
+				t.Fatalf("%s: unsupported value type %T", mut.name, v)
+			}
+		}
+
+		mvt := newBasicUpgradeTest(NumUpgrades(3))
+		plan, err := mvt.plan()
+		require.NoError(t, err)
+
+		mutations := mut.Generate(newRand(), plan)
+		require.NotEmpty(t, mutations, "%s: no mutations generated", mut.name)
+		for _, m := range mutations {
+			if mut.minVersion == nil && mut.maxVersion == nil {
+				continue
+			}
+
+			knowsSetting := func(v *clusterupgrade.Version) bool {
+				return (mut.minVersion == nil || v.AtLeast(mut.minVersion)) &&
+					(mut.maxVersion == nil || !v.AtLeast(mut.maxVersion))
+			}
+
+			var canService bool
+			stepContext := m.reference.context
+			for _, node := range stepContext.System.Descriptor.Nodes {
+				nodeV, err := stepContext.NodeVersion(node)
+				require.NoError(t, err)
+				canService = canService || knowsSetting(nodeV)
+			}
+			require.True(t, canService, "%s: no node can service the change", mut.name)
+
+			if mut.maxVersion != nil && stepContext.System.ToVersion.AtLeast(mut.maxVersion) {
+				for _, node := range stepContext.System.NodesInPreviousVersion() {
+					nodeV, err := stepContext.NodeVersion(node)
+					require.NoError(t, err)
+					require.True(t, knowsSetting(nodeV), "%s: node %d cannot service the change", mut.name, node)
+				}
+			}
+		}
+	}
+}
+
+func TestProtectedTimestampSettingMutators(t *testing.T) {
+	const currentVersion = "v24.2.12"
+	defer withTestBuildVersion(currentVersion)()
+
+	verifySettingMutatorsVersionValid(
+		t, currentVersion, clusterSettingMutatorsWithPrefix("kv.protectedts."),
+	)
+}
+
+func TestJobsSettingMutators(t *testing.T) {
+	const currentVersion = "v24.2.12"
+	defer withTestBuildVersion(currentVersion)()
+
+	verifySettingMutatorsVersionValid(
+		t, currentVersion, clusterSettingMutatorsWithPrefix("jobs."),
+	)
+}
+
+func TestSQLMemorySettingMutators(t *testing.T) {
+	const currentVersion = "v24.2.12"
+	defer withTestBuildVersion(currentVersion)()
+
+	mutators := clusterSettingMutatorsWithPrefix("sql.distsql.temp_storage.")
+	verifySettingMutatorsVersionValid(t, currentVersion, mutators)
+
+	// Work memory values must be small enough to make queries spill to
+	// disk, but large enough for them to still make progress.
+	const minWorkmem, maxWorkmem = 1 << 20 /* 1MiB */, 1 << 30 /* 1GiB */
+	var foundWorkmem bool
+	for _, mut := range mutators {
+		if mut.name != "sql.distsql.temp_storage.workmem" {
+			continue
+		}
+		foundWorkmem = true
+
+		for _, v := range mut.possibleValues {
+			s, ok := v.(string)
+			require.True(t, ok, "unexpected value type %T", v)
+			bytes, err := humanizeutil.ParseBytes(s)
+			require.NoError(t, err)
+			require.GreaterOrEqual(t, bytes, int64(minWorkmem))
+			require.LessOrEqual(t, bytes, int64(maxWorkmem))
+		}
+	}
+	require.True(t, foundWorkmem)
+}
+
+func TestSpanConfigSettingMutators(t *testing.T) {
+	const currentVersion = "v24.2.12"
+	defer withTestBuildVersion(currentVersion)()
+
+	verifySettingMutatorsVersionValid(
+		t, currentVersion, clusterSettingMutatorsWithPrefix("spanconfig."),
+	)
+}
+
+func TestDeclarativeSchemaChangerSettingMutators(t *testing.T) {
+	const currentVersion = "v24.2.12"
+	defer withTestBuildVersion(currentVersion)()
+
+	mutators := append(
+		clusterSettingMutatorsWithPrefix("sql.defaults.use_declarative_schema_changer"),
+		clusterSettingMutatorsWithPrefix("sql.schema.force_declarative_statements")...,
+	)
+	require.Len(t, mutators, 2)
+	for _, mut := range mutators {
+		require.NotNil(t, mut.minVersion, "%s: declarative schema changer settings must be version gated", mut.name)
+	}
+
+	verifySettingMutatorsVersionValid(t, currentVersion, mutators)
+}
+
+func TestLeaseTransferSettingMutators(t *testing.T) {
+	const currentVersion = "v24.2.12"
+	defer withTestBuildVersion(currentVersion)()
+
+	verifySettingMutatorsVersionValid(
+		t, currentVersion, clusterSettingMutatorsWithPrefix("kv.allocator."),
+	)
+}
+
+func TestSessionDefaultSettingMutators(t *testing.T) {
+	const currentVersion = "v24.2.12"
+	defer withTestBuildVersion(currentVersion)()
+
+	var mutators []clusterSettingMutator
+	for _, name := range []string{
+		"sql.defaults.distsql",
+		"sql.defaults.vectorize",
+		"sql.defaults.insert_fast_path.enabled",
+		"sql.defaults.optimizer_use_histograms.enabled",
+		"sql.defaults.zigzag_join.enabled",
+	} {
+		muts := clusterSettingMutatorsWithPrefix(name)
+		require.Len(t, muts, 1, "%s: expected exactly one mutator", name)
+		require.NotNil(t, muts[0].minVersion, "%s: session default settings must be version gated", name)
+		mutators = append(mutators, muts...)
+	}
+
+	verifySettingMutatorsVersionValid(t, currentVersion, mutators)
+}
+
+func TestSerialNormalizationSettingMutator(t *testing.T) {
+	const currentVersion = "v24.2.12"
+	defer withTestBuildVersion(currentVersion)()
+
+	mutators := clusterSettingMutatorsWithPrefix("sql.defaults.serial_normalization")
+	require.Len(t, mutators, 1)
+	for _, v := range mutators[0].possibleValues {
+		_, ok := sessiondatapb.SerialNormalizationModeFromString(v.(string))
+		require.True(t, ok, "invalid serial_normalization mode %q", v)
+	}
+
+	verifySettingMutatorsVersionValid(t, currentVersion, mutators)
+}
+
+func TestRaftLogSettingMutators(t *testing.T) {
+	const currentVersion = "v24.2.12"
+	defer withTestBuildVersion(currentVersion)()
+
+	mutators := append(
+		clusterSettingMutatorsWithPrefix("kv.raft."),
+		clusterSettingMutatorsWithPrefix("kv.raft_log.")...,
+	)
+	require.Len(t, mutators, 3)
+	verifySettingMutatorsVersionValid(t, currentVersion, mutators)
+
+	// Batches of Raft commands must not exceed the default maximum
+	// command size of 64MiB.
+	const minBatchSize, maxBatchSize = 256 << 10 /* 256KiB */, 64 << 20 /* 64MiB */
+	for _, mut := range mutators {
+		if mut.name != "kv.raft.command.target_batch_size" {
+			continue
+		}
+
+		for _, v := range mut.possibleValues {
+			s, ok := v.(string)
+			require.True(t, ok, "unexpected value type %T", v)
+			bytes, err := humanizeutil.ParseBytes(s)
+			require.NoError(t, err)
+			require.GreaterOrEqual(t, bytes, int64(minBatchSize))
+			require.LessOrEqual(t, bytes, int64(maxBatchSize))
+		}
+	}
+}
+
+func TestElasticAdmissionControlSettingMutators(t *testing.T) {
+	const currentVersion = "v24.2.12"
+	defer withTestBuildVersion(currentVersion)()
+
+	mutators := append(
+		clusterSettingMutatorsWithPrefix("admission."),
+		clusterSettingMutatorsWithPrefix("kvadmission.")...,
+	)
+	require.Len(t, mutators, 5)
+	for _, mut := range mutators {
+		require.NotNil(t, mut.minVersion, "%s: admission control settings must be version gated", mut.name)
+	}
+	verifySettingMutatorsVersionValid(t, currentVersion, mutators)
+
+	// The provisioned bandwidth bounds the disk bandwidth available to
+	// elastic work, so it must leave room for backfills to make progress.
+	const minBandwidth = 1 << 30 /* 1GiB */
+	var foundBandwidth bool
+	for _, mut := range mutators {
+		if mut.name != "kvadmission.store.provisioned_bandwidth" {
+			continue
+		}
+		foundBandwidth = true
+
+		for _, v := range mut.possibleValues {
+			s, ok := v.(string)
+			require.True(t, ok, "unexpected value type %T", v)
+			bytes, err := humanizeutil.ParseBytes(s)
+			require.NoError(t, err)
+			require.GreaterOrEqual(t, bytes, int64(minBandwidth))
+		}
+	}
+	require.True(t, foundBandwidth)
+}
+
+func TestGlobalReadsClosedTimestampSettingMutators(t *testing.T) {
+	const currentVersion = "v24.2.12"
+	defer withTestBuildVersion(currentVersion)()
+
+	mutators := clusterSettingMutatorsWithPrefix("kv.closed_timestamp.")
+	require.Len(t, mutators, 2)
+	for _, mut := range mutators {
+		require.NotNil(t, mut.minVersion, "%s: closed timestamp settings must be version gated", mut.name)
+	}
+	verifySettingMutatorsVersionValid(t, currentVersion, mutators)
+
+	// Every value must be a bounded duration, and the side-transport
+	// must never be disabled, as GLOBAL tables rely on it to publish
+	// closed timestamps for idle ranges.
+	const maxDuration = 2 * time.Second
+	for _, mut := range mutators {
+		for _, v := range mut.possibleValues {
+			s, ok := v.(string)
+			require.True(t, ok, "unexpected value type %T", v)
+			d, err := time.ParseDuration(s)
+			require.NoError(t, err)
+			require.LessOrEqual(t, d, maxDuration, "%s: value %s is too large", mut.name, s)
+			if mut.name == "kv.closed_timestamp.side_transport_interval" {
+				require.Positive(t, d, "%s: side-transport must not be disabled", mut.name)
+			}
+		}
+	}
+}
+
+func TestRangefeedSettingMutators(t *testing.T) {
+	const currentVersion = "v24.2.12"
+	defer withTestBuildVersion(currentVersion)()
+
+	mutators := append(
+		clusterSettingMutatorsWithPrefix("kv.rangefeed."),
+		clusterSettingMutatorsWithPrefix("changefeed.mux_rangefeed.")...,
+	)
+	require.Len(t, mutators, 3)
+	verifySettingMutatorsVersionValid(t, currentVersion, mutators)
+
+	for _, mut := range mutators {
+		// Disabling rangefeeds would break changefeeds created by the
+		// test.
+		require.NotEqual(t, "kv.rangefeed.enabled", mut.name)
+
+		// The multiplexed rangefeed setting was removed in 24.1, so it
+		// must never be changed once a plan is finalized on that release.
+		if mut.name != "changefeed.mux_rangefeed.enabled" {
+			continue
+		}
+		require.NotNil(t, mut.maxVersion)
+
+		mvt := newBasicUpgradeTest(NumUpgrades(3))
+		plan, err := mvt.plan()
+		require.NoError(t, err)
+		for _, m := range mut.Generate(newRand(), plan) {
+			system := m.reference.context.System
+			require.False(
+				t, system.FromVersion.AtLeast(mut.maxVersion),
+				"changing removed setting when upgrading from %s", system.FromVersion,
+			)
+		}
+	}
+}
+
+func TestLocalityOrderedUpgradeMutator(t *testing.T) {
+	var mut localityOrderedUpgradeMutator
+
+	// Without declared localities, the mutator is a no-op.
+	mvt := newBasicUpgradeTest(NumUpgrades(3))
+	plan, err := mvt.plan()
+	require.NoError(t, err)
+	require.Empty(t, mut.Generate(newRand(), plan))
+	require.Empty(t, plan.nodeLocalities)
+
+	localities := map[int]string{
+		1: "us-east1",
+		2: "us-east1",
+		3: "us-west1",
+		4: "europe-west2",
+	}
+	mvt = newBasicUpgradeTest(NumUpgrades(3), NodeLocalities(localities))
+	plan, err = mvt.plan()
+	require.NoError(t, err)
+	require.Empty(t, mut.Generate(newRand(), plan))
+
+	// The last upgrade is always reordered; find the steps of its final
+	// stage, where every node is restarted into the new binary.
+	steps := plan.singleSteps()
+	var lastVersion *clusterupgrade.Version
+	for _, s := range steps {
+		if restart, ok := s.impl.(restartWithNewBinaryStep); ok {
+			lastVersion = restart.version
+		}
+	}
+	require.NotNil(t, lastVersion)
+
+	var restarted option.NodeListOption
+	var visitedLocalities []string
+	for _, s := range steps {
+		sc := s.context.System
+		if sc.Stage != LastUpgradeStage || !sc.ToVersion.Equal(lastVersion) {
+			continue
+		}
+
+		// The context of every step must reflect exactly the nodes
+		// restarted so far.
+		require.ElementsMatch(
+			t, restarted, sc.NodesInNextVersion(), "invalid context for step %q:\n%s",
+			s.impl.Description(), plan.PrettyPrint(),
+		)
+
+		restart, ok := s.impl.(restartWithNewBinaryStep)
+		if !ok {
+			continue
+		}
+		require.NotContains(t, restarted, restart.node, "node %d restarted twice", restart.node)
+		restarted = append(restarted, restart.node)
+
+		locality := localities[restart.node]
+		if n := len(visitedLocalities); n == 0 || visitedLocalities[n-1] != locality {
+			require.NotContains(
+				t, visitedLocalities, locality, "locality %s upgraded non-contiguously:\n%s",
+				locality, plan.PrettyPrint(),
+			)
+			visitedLocalities = append(visitedLocalities, locality)
+		}
+	}
+	require.ElementsMatch(t, option.NodeListOption{1, 2, 3, 4}, restarted)
+	require.Len(t, visitedLocalities, 3)
+}
+
+func TestStorageEngineSettingMutators(t *testing.T) {
+	const currentVersion = "v24.2.12"
+	defer withTestBuildVersion(currentVersion)()
+
+	pebbleSettings := map[string]struct{}{
+		"rocksdb.min_wal_sync_interval":               {},
+		"storage.ingest_as_flushable.enabled":         {},
+		"storage.max_download_compaction_concurrency": {},
+		"storage.wal_failover.unhealthy_op_threshold": {},
+	}
+	var mutators []clusterSettingMutator
+	for _, mut := range append(
+		clusterSettingMutatorsWithPrefix("rocksdb."),
+		clusterSettingMutatorsWithPrefix("storage.")...,
+	) {
+		if _, ok := pebbleSettings[mut.name]; ok {
+			mutators = append(mutators, mut)
+		}
+	}
+	require.Len(t, mutators, len(pebbleSettings))
+	verifySettingMutatorsVersionValid(t, currentVersion, mutators)
+
+	// Values must stay within bounds that keep the storage engine
+	// healthy while upgrade migrations write to it.
+	parseDuration := func(v interface{}) time.Duration {
+		s, ok := v.(string)
+		require.True(t, ok, "unexpected value type %T", v)
+		d, err := time.ParseDuration(s)
+		require.NoError(t, err)
+		return d
+	}
+	for _, mut := range mutators {
+		for _, v := range mut.possibleValues {
+			switch mut.name {
+			case "rocksdb.min_wal_sync_interval":
+				d := parseDuration(v)
+				require.GreaterOrEqual(t, d, time.Duration(0))
+				require.LessOrEqual(t, d, 10*time.Millisecond, "WAL syncs delayed by %s", d)
+			case "storage.max_download_compaction_concurrency":
+				n, ok := v.(int)
+				require.True(t, ok, "unexpected value type %T", v)
+				require.GreaterOrEqual(t, n, 1)
+				require.LessOrEqual(t, n, 8)
+			case "storage.wal_failover.unhealthy_op_threshold":
+				require.GreaterOrEqual(t, parseDuration(v), 100*time.Millisecond)
+			}
+		}
+	}
+}
@@ -50,6 +50,9 @@ type (
 		// isLocal indicates if this test plan is generated for a `local`
 		// run.
 		isLocal bool
+		// nodeLocalities maps every cockroach node to its locality, if
+		// the test declared them.
+		nodeLocalities map[int]string
 	}
 
 	// testSetup includes the sequence of steps that run to setup the
@@ -179,7 +182,29 @@ const (
 // implementations. A subset of these mutations might be enabled in
 // any mixedversion test plan.
 var planMutators = []mutator{
+	// Relabels the nodes restarted in each upgrade, so it must run
+	// before any other mutator refers to specific nodes.
+	localityOrderedUpgradeMutator{},
 	preserveDowngradeOptionRandomizerMutator{},
+	diskPressureMutator{},
+	staleDescriptorLeaseMutator{},
+	descriptorVersionBurstMutator{},
+	importMutator{},
+	certRotationMutator{},
+	quorumLossMutator{},
+	replicationCutoverMutator{},
+	protectedTimestampGCMutator{},
+	nodePauseMutator{},
+	survivalGoalMutator{},
+	inflightJobMutator{},
+	schemaChangePauseResumeMutator{},
+	cpuLimitMutator{},
+	decommissionRejoinMutator{},
+	tenantCapabilitiesMutator{},
+	rollingRestartMutator{},
+	rpcCompressionMutator{},
+	sqlConnectionChurnMutator{},
+	pointInTimeRestoreMutator{},
 	newClusterSettingMutator(
 		"kv.expiration_leases_only.enabled",
 		[]bool{true, false},
@@ -200,6 +225,257 @@ var planMutators = []mutator{
 		[]string{"snappy", "zstd"},
 		clusterSettingMinimumVersion("v24.1.0-alpha.0"),
 	),
+	// Protected timestamp settings. Values are never lowered below
+	// their defaults in a way that could cause backups or changefeeds
+	// run by the test to fail to protect their spans.
+	newClusterSettingMutator(
+		"kv.protectedts.poll_interval",
+		[]string{"10s", "30s", "5m"},
+	),
+	newClusterSettingMutator(
+		"kv.protectedts.reconciliation.interval",
+		[]string{"30s", "1m", "10m"},
+	),
+	newClusterSettingMutator(
+		"kv.protectedts.max_spans",
+		[]int{65536, 131072},
+	),
+	// Jobs settings. Schema changes and upgrade migrations run as
+	// jobs, so changing how often jobs are adopted and cancelled
+	// exercises the registry while nodes disagree on the version.
+	newClusterSettingMutator(
+		"jobs.registry.interval.adopt",
+		[]string{"5s", "10s", "1m"},
+	),
+	newClusterSettingMutator(
+		"jobs.registry.interval.cancel",
+		[]string{"1s", "30s"},
+	),
+	newClusterSettingMutator(
+		"jobs.retention_time",
+		[]string{"24h", "72h"},
+	),
+	// SQL memory settings. The work memory limit is kept within bounds
+	// that force queries to spill to disk without starving them.
+	newClusterSettingMutator(
+		"sql.distsql.temp_storage.workmem",
+		[]string{"4MiB", "16MiB", "256MiB"},
+	),
+	newClusterSettingMutator(
+		"sql.distsql.temp_storage.hash_agg.enabled",
+		[]bool{true, false},
+	),
+	// Span config settings. The reconciliation job is never disabled,
+	// as zone configurations would stop being applied.
+	newClusterSettingMutator(
+		"spanconfig.reconciliation_job.check_interval",
+		[]string{"30s", "10m"},
+	),
+	newClusterSettingMutator(
+		"spanconfig.reconciliation_job.checkpoint_interval",
+		[]string{"1s", "30s"},
+	),
+	newClusterSettingMutator(
+		"spanconfig.storage_coalesce_adjacent.enabled",
+		[]bool{true, false},
+		clusterSettingMinimumVersion("v23.1.0"),
+	),
+	newClusterSettingMutator(
+		"spanconfig.tenant_coalesce_adjacent.enabled",
+		[]bool{true, false},
+		clusterSettingMinimumVersion("v23.1.0"),
+	),
+	// Declarative schema changer settings. Switching between schema
+	// changers while a test issues DDL means schema change jobs planned
+	// by one node may be resumed by a node running a different binary.
+	newClusterSettingMutator(
+		"sql.defaults.use_declarative_schema_changer",
+		[]string{"off", "on"},
+		clusterSettingMinimumVersion("v22.2.0"),
+	),
+	newClusterSettingMutator(
+		"sql.schema.force_declarative_statements",
+		[]string{"!ALTER TABLE", "!CREATE INDEX,!DROP INDEX"},
+		clusterSettingMinimumVersion("v23.1.0"),
+	),
+	// Lease transfer and load-based rebalancing settings. Leases already
+	// move frequently while nodes are restarted, and these settings
+	// change how aggressively they are moved on top of that.
+	newClusterSettingMutator(
+		"kv.allocator.load_based_rebalancing",
+		[]string{"off", "leases", "leases and replicas"},
+	),
+	newClusterSettingMutator(
+		"kv.allocator.load_based_lease_rebalancing.enabled",
+		[]bool{true, false},
+	),
+	newClusterSettingMutator(
+		"kv.allocator.load_based_rebalancing.objective",
+		[]string{"qps", "cpu"},
+		clusterSettingMinimumVersion("v23.1.0"),
+	),
+	newClusterSettingMutator(
+		"kv.allocator.load_based_rebalancing_interval",
+		[]string{"10s", "1m"},
+		clusterSettingMinimumVersion("v23.1.0"),
+	),
+	newClusterSettingMutator(
+		"kv.allocator.min_lease_transfer_interval",
+		[]string{"0s", "5s"},
+		clusterSettingMinimumVersion("v24.1.0"),
+	),
+	// Session variable defaults. These `sql.defaults` settings were
+	// grandfathered in 22.2, when defaults started to be stored in
+	// `system.database_role_settings` instead, so nodes on either side
+	// of the upgrade need to resolve them to the same session values.
+	newClusterSettingMutator(
+		"sql.defaults.distsql",
+		[]string{"off", "auto", "on"},
+		clusterSettingMinimumVersion("v22.2.0"),
+	),
+	newClusterSettingMutator(
+		"sql.defaults.vectorize",
+		[]string{"on", "off"},
+		clusterSettingMinimumVersion("v22.2.0"),
+	),
+	newClusterSettingMutator(
+		"sql.defaults.insert_fast_path.enabled",
+		[]bool{true, false},
+		clusterSettingMinimumVersion("v22.2.0"),
+	),
+	newClusterSettingMutator(
+		"sql.defaults.optimizer_use_histograms.enabled",
+		[]bool{true, false},
+		clusterSettingMinimumVersion("v22.2.0"),
+	),
+	newClusterSettingMutator(
+		"sql.defaults.zigzag_join.enabled",
+		[]bool{true, false},
+		clusterSettingMinimumVersion("v22.2.0"),
+	),
+	// SERIAL normalization. Tables created with SERIAL columns at
+	// different points of the upgrade end up with different column
+	// types and defaults, some of which are backed by sequences.
+	newClusterSettingMutator(
+		"sql.defaults.serial_normalization",
+		[]string{"rowid", "unordered_rowid", "virtual_sequence", "sql_sequence", "sql_sequence_cached"},
+	),
+	// Raft log settings. Replicas of the same range may run different
+	// binaries, so these change how entries are batched, synced and
+	// truncated while the members of a Raft group disagree on the
+	// version. Batch sizes are kept below the maximum command size.
+	newClusterSettingMutator(
+		"kv.raft.command.target_batch_size",
+		[]string{"1MiB", "16MiB", "64MiB"},
+	),
+	newClusterSettingMutator(
+		"kv.raft_log.loosely_coupled_truncation.enabled",
+		[]bool{true, false},
+	),
+	newClusterSettingMutator(
+		"kv.raft_log.non_blocking_synchronization.enabled",
+		[]bool{true, false},
+		clusterSettingMinimumVersion("v23.1.0"),
+	),
+	// Elastic admission control settings. Backfills, backups and
+	// rangefeed catch-up scans are throttled as elastic work, and
+	// upgrade migrations add to that work while nodes disagree on the
+	// version. Elastic CPU and IO control were extended to more work
+	// over several releases, so each setting is gated on the release
+	// that introduced it. The provisioned bandwidth is never set low
+	// enough to starve elastic work.
+	newClusterSettingMutator(
+		"admission.elastic_cpu.enabled",
+		[]bool{true, false},
+		clusterSettingMinimumVersion("v22.2.0"),
+	),
+	newClusterSettingMutator(
+		"kvadmission.export_request_elastic_control.enabled",
+		[]bool{true, false},
+		clusterSettingMinimumVersion("v22.2.0"),
+	),
+	newClusterSettingMutator(
+		"kvadmission.rangefeed_catchup_scan_elastic_control.enabled",
+		[]bool{true, false},
+		clusterSettingMinimumVersion("v23.1.0"),
+	),
+	newClusterSettingMutator(
+		"kvadmission.store.provisioned_bandwidth",
+		[]string{"1GiB", "4GiB"},
+		clusterSettingMinimumVersion("v22.2.0"),
+	),
+	newClusterSettingMutator(
+		"admission.disk_bandwidth_tokens.elastic.enabled",
+		[]bool{true, false},
+		clusterSettingMinimumVersion("v24.1.0"),
+	),
+	// Closed timestamp settings for GLOBAL tables. Ranges with the
+	// LEAD_FOR_GLOBAL_READS policy close timestamps in the future, and
+	// the lead time they publish is derived from these settings, so
+	// changing them while nodes run different binaries exercises
+	// follower reads on GLOBAL tables under version skew. They have no
+	// visible effect on clusters without GLOBAL tables. A zero override
+	// falls back to the computed lead time; the side-transport is never
+	// disabled, and leads are kept short so that writes to GLOBAL
+	// tables do not commit-wait for long.
+	newClusterSettingMutator(
+		"kv.closed_timestamp.lead_for_global_reads_override",
+		[]string{"0s", "250ms", "2s"},
+		clusterSettingMinimumVersion("v22.2.0"),
+	),
+	newClusterSettingMutator(
+		"kv.closed_timestamp.side_transport_interval",
+		[]string{"50ms", "200ms", "1s"},
+		clusterSettingMinimumVersion("v22.2.0"),
+	),
+	// Rangefeed settings. Rangefeeds back changefeeds, span config and
+	// settings watchers, and they are served by nodes running either
+	// binary while the upgrade is in progress. The multiplexed rangefeed
+	// protocol was rolled out progressively before it became the only
+	// protocol in 24.1, when its setting was removed. Rangefeeds
+	// themselves are never disabled, as changefeeds created by the test
+	// would fail.
+	newClusterSettingMutator(
+		"changefeed.mux_rangefeed.enabled",
+		[]bool{true, false},
+		clusterSettingMinimumVersion("v22.2.0"),
+		clusterSettingMaximumVersion("v24.1.0-alpha.0"),
+	),
+	newClusterSettingMutator(
+		"kv.rangefeed.scheduler.enabled",
+		[]bool{true, false},
+		clusterSettingMinimumVersion("v23.2.0"),
+	),
+	newClusterSettingMutator(
+		"kv.rangefeed.closed_timestamp_refresh_interval",
+		[]string{"0s", "200ms", "3s"},
+	),
+	// Pebble settings. Upgrade migrations rewrite system tables and
+	// backfill descriptors, so these tune the storage engine while it
+	// takes a burst of writes and nodes run different binaries. WAL
+	// syncs are delayed by at most a few milliseconds, compaction
+	// concurrency is never reduced to zero, and a WAL write is only
+	// considered unhealthy (which matters only if WAL failover is
+	// configured) after a substantial delay.
+	newClusterSettingMutator(
+		"rocksdb.min_wal_sync_interval",
+		[]string{"0s", "1ms", "5ms"},
+	),
+	newClusterSettingMutator(
+		"storage.ingest_as_flushable.enabled",
+		[]bool{true, false},
+		clusterSettingMinimumVersion("v23.1.0"),
+	),
+	newClusterSettingMutator(
+		"storage.max_download_compaction_concurrency",
+		[]int{1, 4, 8},
+		clusterSettingMinimumVersion("v24.2.0"),
+	),
+	newClusterSettingMutator(
+		"storage.wal_failover.unhealthy_op_threshold",
+		[]string{"100ms", "500ms"},
+		clusterSettingMinimumVersion("v24.1.0"),
+	),
 }
 
 // Plan returns the TestPlan used to upgrade the cluster from the
@@ -271,6 +547,7 @@ func (p *testPlanner) Plan() *TestPlan {
 		upgrades:       testUpgrades,
 		deploymentMode: p.deploymentMode,
 		isLocal:        p.isLocal,
+		nodeLocalities: p.options.nodeLocalities,
 	}
 
 	// Probabilistically enable some of of the mutators on the base test
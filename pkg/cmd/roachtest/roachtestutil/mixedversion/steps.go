@@ -12,15 +12,23 @@ package mixedversion
 
 import (
 	"context"
+	gosql "database/sql"
 	"fmt"
 	"math/rand"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/cmd/roachtest/option"
+	"github.com/cockroachdb/cockroach/pkg/cmd/roachtest/roachtestutil"
 	"github.com/cockroachdb/cockroach/pkg/cmd/roachtest/roachtestutil/clusterupgrade"
 	"github.com/cockroachdb/cockroach/pkg/cmd/roachtest/test"
+	"github.com/cockroachdb/cockroach/pkg/roachprod"
 	"github.com/cockroachdb/cockroach/pkg/roachprod/install"
 	"github.com/cockroachdb/cockroach/pkg/roachprod/logger"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/errors"
 )
 
 // installFixturesStep is the step that copies the fixtures from
@@ -225,6 +233,7 @@ func (s runHookStep) Run(ctx context.Context, l *logger.Logger, rng *rand.Rand,
 // setClusterSettingStep sets the cluster setting `name` to `value`.
 type setClusterSettingStep struct {
 	minVersion         *clusterupgrade.Version
+	maxVersion         *clusterupgrade.Version
 	name               string
 	value              interface{}
 	virtualClusterName string
@@ -262,13 +271,14 @@ func (s setClusterSettingStep) Run(
 	}
 
 	return serviceByName(h, s.virtualClusterName).ExecWithGateway(
-		rng, nodesRunningAtLeast(s.virtualClusterName, s.minVersion, h), stmt, args...,
+		rng, clusterSettingNodes(s.virtualClusterName, s.minVersion, s.maxVersion, h), stmt, args...,
 	)
 }
 
 // resetClusterSetting resets cluster setting `name`.
 type resetClusterSettingStep struct {
 	minVersion         *clusterupgrade.Version
+	maxVersion         *clusterupgrade.Version
 	name               string
 	virtualClusterName string
 }
@@ -283,7 +293,1581 @@ func (s resetClusterSettingStep) Run(
 	ctx context.Context, l *logger.Logger, rng *rand.Rand, h *Helper,
 ) error {
 	stmt := fmt.Sprintf("RESET CLUSTER SETTING %s", s.name)
-	return h.ExecWithGateway(rng, nodesRunningAtLeast(s.virtualClusterName, s.minVersion, h), stmt)
+	return h.ExecWithGateway(
+		rng, clusterSettingNodes(s.virtualClusterName, s.minVersion, s.maxVersion, h), stmt,
+	)
+}
+
+// ballastFile is the path of the file allocated by `fillStoreStep`
+// to simulate disk pressure on a node.
+const ballastFile = "{store-dir}/mixedversion_ballast"
+
+// fillStoreStep allocates a ballast file in the store directory of
+// `node`, leaving only `freePercent` of the disk available.
+type fillStoreStep struct {
+	node        int
+	freePercent int
+}
+
+func (s fillStoreStep) Background() shouldStop { return nil }
+
+func (s fillStoreStep) Description() string {
+	return fmt.Sprintf(
+		"fill store on node %d, leaving %d%% of disk space available", s.node, s.freePercent,
+	)
+}
+
+func (s fillStoreStep) Run(ctx context.Context, l *logger.Logger, _ *rand.Rand, h *Helper) error {
+	// If the disk already has less than `freePercent` of space
+	// available, we don't allocate anything.
+	cmd := fmt.Sprintf(
+		`size=$(df --output=size,avail -B1 {store-dir} | tail -n1 | awk '{printf "%%d", $2 - $1 * %d / 100}') && `+
+			`if [ "$size" -gt 0 ]; then fallocate -l "$size" %s; fi`,
+		s.freePercent, ballastFile,
+	)
+
+	l.Printf("allocating ballast file on node %d", s.node)
+	return h.runner.cluster.RunE(ctx, option.WithNodes(h.runner.cluster.Node(s.node)), cmd)
+}
+
+// restoreStoreStep removes the ballast file allocated by
+// `fillStoreStep` on `node`, and verifies that the node is able to
+// serve queries once the disk pressure is gone.
+type restoreStoreStep struct {
+	node int
+}
+
+func (s restoreStoreStep) Background() shouldStop { return nil }
+
+func (s restoreStoreStep) Description() string {
+	return fmt.Sprintf("restore store on node %d", s.node)
+}
+
+func (s restoreStoreStep) Run(
+	ctx context.Context, l *logger.Logger, _ *rand.Rand, h *Helper,
+) error {
+	l.Printf("removing ballast file on node %d", s.node)
+	if err := h.runner.cluster.RunE(
+		ctx, option.WithNodes(h.runner.cluster.Node(s.node)), "rm -f "+ballastFile,
+	); err != nil {
+		return err
+	}
+
+	_, err := h.System.Connect(s.node).ExecContext(ctx, "SELECT 1")
+	return errors.Wrapf(err, "node %d unavailable after disk pressure", s.node)
+}
+
+// leaseTable is the table altered by `leaseSchemaChangeStep`.
+const leaseTable = "defaultdb.mixedversion_lease"
+
+// createLeaseTableStmts creates and populates `leaseTable`, if it
+// does not exist yet.
+var createLeaseTableStmts = []string{
+	fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (k INT PRIMARY KEY)", leaseTable),
+	fmt.Sprintf("UPSERT INTO %s (k) SELECT generate_series(1, 100)", leaseTable),
+}
+
+// leaseSchemaChangeStep creates a new version of the descriptor of
+// `leaseTable` by adding or dropping one of its columns. The table is
+// created, and populated, on first use.
+type leaseSchemaChangeStep struct{}
+
+func (s leaseSchemaChangeStep) Background() shouldStop { return nil }
+
+func (s leaseSchemaChangeStep) Description() string {
+	return fmt.Sprintf("run schema change on %s", leaseTable)
+}
+
+func (s leaseSchemaChangeStep) Run(
+	ctx context.Context, l *logger.Logger, rng *rand.Rand, h *Helper,
+) error {
+	stmts := append([]string{}, createLeaseTableStmts...)
+	if rng.Float64() < 0.5 {
+		stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS v INT DEFAULT 0", leaseTable))
+	} else {
+		stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s DROP COLUMN IF EXISTS v", leaseTable))
+	}
+
+	for _, stmt := range stmts {
+		if err := h.Exec(rng, stmt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// toggleExcludeFromBackupStep sets the `exclude_data_from_backup`
+// storage parameter of `leaseTable`. It is a cheap way of creating a
+// new descriptor version, as it does not require a backfill.
+type toggleExcludeFromBackupStep struct {
+	exclude bool
+}
+
+func (s toggleExcludeFromBackupStep) Background() shouldStop { return nil }
+
+func (s toggleExcludeFromBackupStep) Description() string {
+	return fmt.Sprintf("set exclude_data_from_backup = %t on %s", s.exclude, leaseTable)
+}
+
+func (s toggleExcludeFromBackupStep) Run(
+	ctx context.Context, l *logger.Logger, rng *rand.Rand, h *Helper,
+) error {
+	stmts := append([]string{}, createLeaseTableStmts...)
+	stmts = append(stmts, fmt.Sprintf(
+		"ALTER TABLE %s SET (exclude_data_from_backup = %t)", leaseTable, s.exclude,
+	))
+
+	for _, stmt := range stmts {
+		if err := h.Exec(rng, stmt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+const (
+	// importTablePrefix is the prefix of the tables created by
+	// `importStep`.
+	importTablePrefix = "defaultdb.mixedversion_import"
+	// importRows is the number of rows imported by `importStep`.
+	importRows = 10000
+)
+
+// importStep generates a CSV file in the external IO directory of
+// `node` and imports it into a newly created `table`.
+type importStep struct {
+	node  int
+	table string
+}
+
+func (s importStep) Background() shouldStop { return nil }
+
+func (s importStep) Description() string {
+	return fmt.Sprintf("import data from node %d into %s", s.node, s.table)
+}
+
+func (s importStep) Run(ctx context.Context, l *logger.Logger, rng *rand.Rand, h *Helper) error {
+	const fileName = "mixedversion_import.csv"
+	cmd := fmt.Sprintf(
+		`mkdir -p {store-dir}/extern && seq 1 %d | awk '{print $1","$1*2}' > {store-dir}/extern/%s`,
+		importRows, fileName,
+	)
+
+	l.Printf("generating import data on node %d", s.node)
+	if err := h.runner.cluster.RunE(ctx, option.WithNodes(h.runner.cluster.Node(s.node)), cmd); err != nil {
+		return err
+	}
+
+	if err := h.Exec(rng, fmt.Sprintf("CREATE TABLE %s (k INT PRIMARY KEY, v INT)", s.table)); err != nil {
+		return err
+	}
+
+	return h.Exec(rng, fmt.Sprintf(
+		"IMPORT INTO %s (k, v) CSV DATA ('nodelocal://%d/%s')", s.table, s.node, fileName,
+	))
+}
+
+// verifyImportStep checks that every row imported into `table` by an
+// `importStep` is present.
+type verifyImportStep struct {
+	table string
+}
+
+func (s verifyImportStep) Background() shouldStop { return nil }
+
+func (s verifyImportStep) Description() string {
+	return fmt.Sprintf("verify data imported into %s", s.table)
+}
+
+func (s verifyImportStep) Run(
+	ctx context.Context, l *logger.Logger, rng *rand.Rand, h *Helper,
+) error {
+	var count, sum int
+	if err := h.QueryRow(
+		rng, fmt.Sprintf("SELECT count(*), COALESCE(sum(v - 2*k), 0) FROM %s", s.table),
+	).Scan(&count, &sum); err != nil {
+		return err
+	}
+
+	if count != importRows || sum != 0 {
+		return errors.Newf(
+			"expected %d valid rows in %s, found %d rows (checksum %d)", importRows, s.table, count, sum,
+		)
+	}
+
+	return nil
+}
+
+// rotateNodeCertsCmd replaces the node certificate and key in the
+// certs directory with a freshly generated pair signed by the same
+// CA, carrying over the subject alternative names of the previous
+// certificate.
+const rotateNodeCertsCmd = `set -e
+cd certs
+SAN=$(openssl x509 -in node.crt -noout -ext subjectAltName | tail -n +2 | sed -e 's/IP Address/IP/g' -e 's/ //g')
+printf 'subjectAltName=%s\nkeyUsage=digitalSignature,keyEncipherment\nextendedKeyUsage=serverAuth,clientAuth\n' "$SAN" > node.ext
+openssl req -new -newkey rsa:2048 -nodes -subj '/O=Cockroach/CN=node' -keyout node.key.new -out node.csr
+openssl x509 -req -in node.csr -CA ca.crt -CAkey ca.key -CAcreateserial -days 365 -extfile node.ext -out node.crt.new
+chmod 600 node.key.new
+mv node.key.new node.key
+mv node.crt.new node.crt
+rm node.csr node.ext`
+
+// rotateNodeCertsStep generates new node certificates on each of the
+// `nodes` and sends a SIGHUP to their cockroach process, causing the
+// new certificates to be loaded without a restart. It is a no-op on
+// insecure clusters.
+type rotateNodeCertsStep struct {
+	nodes option.NodeListOption
+}
+
+func (s rotateNodeCertsStep) Background() shouldStop { return nil }
+
+func (s rotateNodeCertsStep) Description() string {
+	return fmt.Sprintf("rotate certificates of node(s) %s", s.nodes)
+}
+
+func (s rotateNodeCertsStep) Run(
+	ctx context.Context, l *logger.Logger, rng *rand.Rand, h *Helper,
+) error {
+	if !h.runner.cluster.IsSecure() {
+		l.Printf("cluster is insecure, skipping certificate rotation")
+		return nil
+	}
+
+	for _, node := range s.nodes {
+		l.Printf("rotating certificates on node %d", node)
+		if err := h.runner.cluster.RunE(
+			ctx, option.WithNodes(h.runner.cluster.Node(node)), rotateNodeCertsCmd,
+		); err != nil {
+			return errors.Wrapf(err, "rotating certificates on node %d", node)
+		}
+	}
+
+	return h.runner.cluster.SignalE(ctx, l, 1 /* SIGHUP */, option.WithNodes(s.nodes))
+}
+
+// checkNodeConnectivityStep verifies that every node is able to reach
+// every other node in the cluster over RPC.
+type checkNodeConnectivityStep struct{}
+
+func (s checkNodeConnectivityStep) Background() shouldStop { return nil }
+
+func (s checkNodeConnectivityStep) Description() string {
+	return "check connectivity between nodes"
+}
+
+func (s checkNodeConnectivityStep) Run(
+	ctx context.Context, l *logger.Logger, rng *rand.Rand, h *Helper,
+) error {
+	nodes := h.System.Descriptor.Nodes
+	for _, node := range nodes {
+		// Reading the status of every node requires a fan-out RPC from
+		// the gateway to every other node in the cluster. Nodes that
+		// were decommissioned and replaced by a fresh node on the same
+		// machine are not counted.
+		var numNodes int
+		if err := h.System.Connect(node).QueryRowContext(
+			ctx, `SELECT count(*) FROM crdb_internal.kv_node_status
+WHERE node_id IN (SELECT node_id FROM crdb_internal.gossip_liveness WHERE membership = 'active')`,
+		).Scan(&numNodes); err != nil {
+			return errors.Wrapf(err, "reading node status from node %d", node)
+		}
+
+		if numNodes != len(nodes) {
+			return errors.Newf("node %d: expected status of %d nodes, found %d", node, len(nodes), numNodes)
+		}
+	}
+
+	return nil
+}
+
+// churnSessionSettings are the session settings changed on the
+// connections opened by the `sqlConnectionChurnStep`, along with the
+// values they may be set to. Values are displayed by SHOW exactly as
+// they are set.
+var churnSessionSettings = []struct {
+	name   string
+	values []string
+}{
+	{name: "application_name", values: []string{"mixedversion_churn_a", "mixedversion_churn_b"}},
+	{name: "default_int_size", values: []string{"4", "8"}},
+	{name: "default_transaction_priority", values: []string{"low", "normal", "high"}},
+	{name: "extra_float_digits", values: []string{"0", "1", "3"}},
+	{name: "timezone", values: []string{"UTC", "America/New_York", "Asia/Kolkata"}},
+}
+
+// sqlConnectionChurnStep opens `connections` SQL connections to
+// random nodes in `nodes`, one after the other. Each connection
+// changes a random subset of session settings and reads them back
+// before being closed.
+type sqlConnectionChurnStep struct {
+	nodes       option.NodeListOption
+	connections int
+}
+
+func (s sqlConnectionChurnStep) Background() shouldStop { return nil }
+
+func (s sqlConnectionChurnStep) Description() string {
+	return fmt.Sprintf("open and close %d SQL connections to node(s) %s", s.connections, s.nodes)
+}
+
+func (s sqlConnectionChurnStep) Run(
+	ctx context.Context, l *logger.Logger, rng *rand.Rand, h *Helper,
+) error {
+	for j := 0; j < s.connections; j++ {
+		node := s.nodes[rng.Intn(len(s.nodes))]
+		if err := s.churnConnection(ctx, rng, h, l, node); err != nil {
+			return errors.Wrapf(err, "connection %d to node %d", j, node)
+		}
+	}
+
+	l.Printf("opened and closed %d connections", s.connections)
+	return nil
+}
+
+// churnConnection opens a new connection to `node`, changes session
+// settings on it, and closes it.
+func (s sqlConnectionChurnStep) churnConnection(
+	ctx context.Context, rng *rand.Rand, h *Helper, l *logger.Logger, node int,
+) error {
+	// A new connection pool is used for every connection, so that
+	// closing it also closes the underlying connection.
+	db, err := h.runner.cluster.ConnE(ctx, l, node)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	for _, setting := range churnSessionSettings {
+		if rng.Float64() < 0.5 {
+			continue
+		}
+
+		value := setting.values[rng.Intn(len(setting.values))]
+		if _, err := conn.ExecContext(
+			ctx, fmt.Sprintf("SET %s = '%s'", setting.name, value),
+		); err != nil {
+			return errors.Wrapf(err, "setting %s", setting.name)
+		}
+
+		var actual string
+		if err := conn.QueryRowContext(
+			ctx, fmt.Sprintf("SHOW %s", setting.name),
+		).Scan(&actual); err != nil {
+			return errors.Wrapf(err, "reading %s", setting.name)
+		}
+		if actual != value {
+			return errors.Newf("expected %s to be %q, found %q", setting.name, value, actual)
+		}
+	}
+
+	return nil
+}
+
+// stopNodesStep stops the cockroach process on each of the `nodes`.
+type stopNodesStep struct {
+	nodes option.NodeListOption
+}
+
+func (s stopNodesStep) Background() shouldStop { return nil }
+
+func (s stopNodesStep) Description() string {
+	return fmt.Sprintf("stop node(s) %s", s.nodes)
+}
+
+func (s stopNodesStep) Run(ctx context.Context, l *logger.Logger, rng *rand.Rand, h *Helper) error {
+	h.ExpectDeaths(len(s.nodes))
+	return h.runner.cluster.StopE(ctx, l, option.DefaultStopOpts(), option.WithNodes(s.nodes))
+}
+
+// decommissionNodeStep fully decommissions `node`, using the binary
+// for the `version` it is running, and waits for all of its replicas
+// to be moved to other nodes.
+type decommissionNodeStep struct {
+	rt      test.Test
+	node    int
+	version *clusterupgrade.Version
+}
+
+func (s decommissionNodeStep) Background() shouldStop { return nil }
+
+func (s decommissionNodeStep) Description() string {
+	return fmt.Sprintf("decommission node %d", s.node)
+}
+
+func (s decommissionNodeStep) Run(
+	ctx context.Context, l *logger.Logger, rng *rand.Rand, h *Helper,
+) error {
+	certsFlag := "--insecure"
+	if h.runner.cluster.IsSecure() {
+		certsFlag = fmt.Sprintf("--certs-dir=%s", install.CockroachNodeCertsDir)
+	}
+
+	return errors.Wrapf(h.runner.cluster.RunE(
+		ctx, option.WithNodes(h.runner.cluster.Node(s.node)),
+		fmt.Sprintf(
+			"%s node decommission --self --wait=all %s --port={pgport:%d}",
+			clusterupgrade.CockroachPathForVersion(s.rt, s.version), certsFlag, s.node,
+		),
+	), "decommissioning node %d", s.node)
+}
+
+// wipeNodesStep removes the data of the stopped cockroach processes
+// on `nodes`, so that they join the cluster as new nodes the next
+// time they are started.
+type wipeNodesStep struct {
+	nodes option.NodeListOption
+}
+
+func (s wipeNodesStep) Background() shouldStop { return nil }
+
+func (s wipeNodesStep) Description() string {
+	return fmt.Sprintf("wipe node(s) %s", s.nodes)
+}
+
+func (s wipeNodesStep) Run(ctx context.Context, l *logger.Logger, rng *rand.Rand, h *Helper) error {
+	return h.runner.cluster.WipeE(ctx, l, option.WithNodes(s.nodes))
+}
+
+// pauseNodeStep pauses the cockroach process on `node` with SIGSTOP,
+// and resumes it with SIGCONT after `dur`.
+type pauseNodeStep struct {
+	node int
+	dur  time.Duration
+}
+
+func (s pauseNodeStep) Background() shouldStop { return nil }
+
+func (s pauseNodeStep) Description() string {
+	return fmt.Sprintf("pause node %d for %s", s.node, s.dur)
+}
+
+func (s pauseNodeStep) Run(ctx context.Context, l *logger.Logger, rng *rand.Rand, h *Helper) error {
+	nodes := option.WithNodes(h.runner.cluster.Node(s.node))
+	if err := h.runner.cluster.SignalE(ctx, l, 19 /* SIGSTOP */, nodes); err != nil {
+		return errors.Wrapf(err, "pausing node %d", s.node)
+	}
+
+	l.Printf("node %d paused, resuming in %s", s.node, s.dur)
+	select {
+	case <-time.After(s.dur):
+	case <-ctx.Done():
+	}
+
+	// The node is resumed even if the test was canceled in the
+	// meantime, so that it can be stopped cleanly.
+	return errors.Wrapf(
+		h.runner.cluster.SignalE(context.Background(), l, 18 /* SIGCONT */, nodes),
+		"resuming node %d", s.node,
+	)
+}
+
+// cpuMaxPath returns the path of the cgroup v2 file that controls
+// the CPU bandwidth of the cockroach process.
+func cpuMaxPath() string {
+	return filepath.Join(
+		"/sys/fs/cgroup/system.slice", roachtestutil.SystemInterfaceSystemdUnitName()+".service", "cpu.max",
+	)
+}
+
+// limitCPUStep limits the cockroach process on `nodes` to `percent`
+// of each CPU on the machine. The limit is lifted if the process is
+// restarted, as it then runs in a new systemd unit.
+type limitCPUStep struct {
+	nodes   option.NodeListOption
+	percent int
+}
+
+func (s limitCPUStep) Background() shouldStop { return nil }
+
+func (s limitCPUStep) Description() string {
+	return fmt.Sprintf("limit CPU on %s to %d%%", s.nodes.String(), s.percent)
+}
+
+func (s limitCPUStep) Run(ctx context.Context, l *logger.Logger, rng *rand.Rand, h *Helper) error {
+	if h.runner.cluster.IsLocal() {
+		l.Printf("local cluster, skipping CPU limit")
+		return nil
+	}
+
+	// The quota is expressed in microseconds per 100ms period, for
+	// all CPUs combined.
+	return errors.Wrapf(h.runner.cluster.RunE(
+		ctx, option.WithNodes(s.nodes), "sudo", "/bin/bash", "-c",
+		fmt.Sprintf(`'echo "$(( $(nproc) * %d000 )) 100000" > %s'`, s.percent, cpuMaxPath()),
+	), "limiting CPU on %s", s.nodes)
+}
+
+// releaseCPUStep lifts the limit set by a `limitCPUStep` on `nodes`.
+type releaseCPUStep struct {
+	nodes option.NodeListOption
+}
+
+func (s releaseCPUStep) Background() shouldStop { return nil }
+
+func (s releaseCPUStep) Description() string {
+	return fmt.Sprintf("release CPU limit on %s", s.nodes.String())
+}
+
+func (s releaseCPUStep) Run(
+	ctx context.Context, l *logger.Logger, rng *rand.Rand, h *Helper,
+) error {
+	if h.runner.cluster.IsLocal() {
+		return nil
+	}
+
+	return errors.Wrapf(h.runner.cluster.RunE(
+		ctx, option.WithNodes(s.nodes), "sudo", "/bin/bash", "-c",
+		fmt.Sprintf(`'echo "max 100000" > %s'`, cpuMaxPath()),
+	), "releasing CPU limit on %s", s.nodes)
+}
+
+// rangeAvailabilityTimeout is the maximum amount of time we wait for
+// every range to become available after nodes are restarted.
+const rangeAvailabilityTimeout = 5 * time.Minute
+
+// waitForRangeAvailabilityStep waits until no store in the cluster
+// reports unavailable ranges.
+type waitForRangeAvailabilityStep struct{}
+
+func (s waitForRangeAvailabilityStep) Background() shouldStop { return nil }
+
+func (s waitForRangeAvailabilityStep) Description() string {
+	return "wait for all ranges to be available"
+}
+
+func (s waitForRangeAvailabilityStep) Run(
+	ctx context.Context, l *logger.Logger, rng *rand.Rand, h *Helper,
+) error {
+	return retry.ForDuration(rangeAvailabilityTimeout, func() error {
+		var unavailable int
+		if err := h.QueryRow(
+			rng,
+			"SELECT COALESCE(sum((metrics->>'ranges.unavailable')::INT8), 0) FROM crdb_internal.kv_store_status",
+		).Scan(&unavailable); err != nil {
+			return err
+		}
+
+		if unavailable > 0 {
+			l.Printf("%d ranges still unavailable", unavailable)
+			return errors.Newf("%d unavailable ranges", unavailable)
+		}
+
+		return nil
+	})
+}
+
+// minReplicationCutoverVersion is the minimum version in which we
+// start replication streams between virtual clusters and cut over to
+// the standby.
+var minReplicationCutoverVersion = clusterupgrade.MustParseVersion("v23.2.0")
+
+const (
+	replicationSourcePrefix  = "mixedversion-source"
+	replicationStandbyPrefix = "mixedversion-standby"
+
+	// replicationCutoverTimeout is the maximum amount of time we wait
+	// for the standby to catch up, and for the cutover to complete.
+	replicationCutoverTimeout = 10 * time.Minute
+)
+
+// startReplicationStep creates the `source` virtual cluster and
+// starts replicating it into the `standby` virtual cluster. The
+// stream is established through the system interface of `node`.
+type startReplicationStep struct {
+	node    int
+	source  string
+	standby string
+}
+
+func (s startReplicationStep) Background() shouldStop { return nil }
+
+func (s startReplicationStep) Description() string {
+	return fmt.Sprintf(
+		"start replication of virtual cluster %s into %s via node %d", s.source, s.standby, s.node,
+	)
+}
+
+func (s startReplicationStep) Run(
+	ctx context.Context, l *logger.Logger, rng *rand.Rand, h *Helper,
+) error {
+	pgURLs, err := h.runner.cluster.InternalPGUrl(
+		ctx, l, option.NodeListOption{s.node}, roachprod.PGURLOptions{},
+	)
+	if err != nil {
+		return errors.Wrapf(err, "fetching pgurl for node %d", s.node)
+	}
+
+	stmts := []string{
+		"SET CLUSTER SETTING kv.rangefeed.enabled = true",
+		fmt.Sprintf("CREATE VIRTUAL CLUSTER %q", s.source),
+		fmt.Sprintf(
+			"CREATE VIRTUAL CLUSTER %q FROM REPLICATION OF %q ON '%s'", s.standby, s.source, pgURLs[0],
+		),
+	}
+	for _, stmt := range stmts {
+		if err := h.System.ExecWithGateway(rng, option.NodeListOption{s.node}, stmt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// replicationCutoverStep waits for the `standby` virtual cluster to
+// catch up with its source, and then cuts over to a timestamp that is
+// `lag` behind its replicated time. The cutover timestamp is never
+// earlier than the retained time of the standby.
+type replicationCutoverStep struct {
+	standby string
+	lag     time.Duration
+}
+
+func (s replicationCutoverStep) Background() shouldStop { return nil }
+
+func (s replicationCutoverStep) Description() string {
+	return fmt.Sprintf("cut over to virtual cluster %s (lag: %s)", s.standby, s.lag)
+}
+
+func (s replicationCutoverStep) Run(
+	ctx context.Context, l *logger.Logger, rng *rand.Rand, h *Helper,
+) error {
+	var cutover time.Time
+	if err := retry.ForDuration(replicationCutoverTimeout, func() error {
+		var retained, replicated gosql.NullTime
+		if err := h.QueryRow(
+			rng,
+			"SELECT retained_time, replicated_time FROM [SHOW VIRTUAL CLUSTER $1 WITH REPLICATION STATUS]",
+			s.standby,
+		).Scan(&retained, &replicated); err != nil {
+			return err
+		}
+
+		// The replicated time is only populated once the initial scan
+		// of the source virtual cluster is complete.
+		if !retained.Valid || !replicated.Valid {
+			return errors.Newf("virtual cluster %s has not caught up with its source", s.standby)
+		}
+
+		cutover = cutoverTime(retained.Time, replicated.Time, s.lag)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	l.Printf("cutting over to %s", cutover)
+	if err := h.Exec(
+		rng,
+		"ALTER VIRTUAL CLUSTER $1 COMPLETE REPLICATION TO SYSTEM TIME $2::STRING",
+		s.standby, fmt.Sprintf("%d", cutover.UnixNano()),
+	); err != nil {
+		return err
+	}
+
+	return retry.ForDuration(replicationCutoverTimeout, func() error {
+		var dataState string
+		if err := h.QueryRow(
+			rng, "SELECT data_state FROM [SHOW VIRTUAL CLUSTER $1]", s.standby,
+		).Scan(&dataState); err != nil {
+			return err
+		}
+
+		if dataState != "ready" {
+			return errors.Newf("virtual cluster %s in data state %q", s.standby, dataState)
+		}
+
+		return nil
+	})
+}
+
+// cutoverTime returns the timestamp to cut over to when the standby
+// has replicated data up to `replicated`, and retains history up to
+// `retained`.
+func cutoverTime(retained, replicated time.Time, lag time.Duration) time.Time {
+	if ts := replicated.Add(-lag); ts.After(retained) {
+		return ts
+	}
+
+	return retained
+}
+
+const (
+	// protectedTablePrefix is the prefix of the tables created by
+	// `protectTableStep`.
+	protectedTablePrefix = "defaultdb.mixedversion_protected"
+	// protectedTableMetadata is the table where the changefeed
+	// protecting each table, and the time at which it was dropped,
+	// are recorded so that they can be used by later steps.
+	protectedTableMetadata = "defaultdb.mixedversion_protected_metadata"
+	// protectedRows is the number of rows written by
+	// `protectTableStep`.
+	protectedRows = 1000
+	// changefeedPauseTimeout is the maximum amount of time we wait for
+	// a changefeed to be paused.
+	changefeedPauseTimeout = 2 * time.Minute
+)
+
+// protectTableStep creates `table`, with a GC TTL of one second, and
+// protects it from garbage collection by creating a changefeed on it
+// and pausing it with `protect_data_from_gc_on_pause`.
+type protectTableStep struct {
+	table string
+}
+
+func (s protectTableStep) Background() shouldStop { return nil }
+
+func (s protectTableStep) Description() string {
+	return fmt.Sprintf("protect %s with a paused changefeed", s.table)
+}
+
+func (s protectTableStep) Run(
+	ctx context.Context, l *logger.Logger, rng *rand.Rand, h *Helper,
+) error {
+	stmts := []string{
+		"SET CLUSTER SETTING kv.rangefeed.enabled = true",
+		fmt.Sprintf(
+			"CREATE TABLE IF NOT EXISTS %s (table_name STRING PRIMARY KEY, job_id INT8, drop_ts DECIMAL)",
+			protectedTableMetadata,
+		),
+		fmt.Sprintf("CREATE TABLE %s (k INT8 PRIMARY KEY, v INT8)", s.table),
+		fmt.Sprintf(
+			"INSERT INTO %s SELECT i, 2*i FROM generate_series(1, %d) AS g(i)", s.table, protectedRows,
+		),
+		fmt.Sprintf("ALTER TABLE %s CONFIGURE ZONE USING gc.ttlseconds = 1", s.table),
+	}
+	for _, stmt := range stmts {
+		if err := h.Exec(rng, stmt); err != nil {
+			return err
+		}
+	}
+
+	var jobID int
+	if err := h.QueryRow(rng, fmt.Sprintf(
+		"CREATE CHANGEFEED FOR TABLE %s INTO 'null://' WITH protect_data_from_gc_on_pause, initial_scan = 'no'",
+		s.table,
+	)).Scan(&jobID); err != nil {
+		return errors.Wrapf(err, "creating changefeed on %s", s.table)
+	}
+
+	if err := h.Exec(rng, "PAUSE JOB $1", jobID); err != nil {
+		return err
+	}
+	if err := retry.ForDuration(changefeedPauseTimeout, func() error {
+		var status string
+		if err := h.QueryRow(rng, "SELECT status FROM [SHOW JOB $1]", jobID).Scan(&status); err != nil {
+			return err
+		}
+
+		if status != "paused" {
+			return errors.Newf("changefeed %d has status %q", jobID, status)
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return h.Exec(
+		rng,
+		fmt.Sprintf("INSERT INTO %s (table_name, job_id) VALUES ($1, $2)", protectedTableMetadata),
+		s.table, jobID,
+	)
+}
+
+// dropProtectedTableStep drops a `table` protected by a
+// `protectTableStep`, recording the timestamp immediately preceding
+// the drop.
+type dropProtectedTableStep struct {
+	table string
+}
+
+func (s dropProtectedTableStep) Background() shouldStop { return nil }
+
+func (s dropProtectedTableStep) Description() string {
+	return fmt.Sprintf("drop protected table %s", s.table)
+}
+
+func (s dropProtectedTableStep) Run(
+	ctx context.Context, l *logger.Logger, rng *rand.Rand, h *Helper,
+) error {
+	if err := h.Exec(
+		rng,
+		fmt.Sprintf(
+			"UPDATE %s SET drop_ts = cluster_logical_timestamp() WHERE table_name = $1",
+			protectedTableMetadata,
+		),
+		s.table,
+	); err != nil {
+		return err
+	}
+
+	return h.Exec(rng, fmt.Sprintf("DROP TABLE %s", s.table))
+}
+
+// verifyProtectedTableStep checks that every row of a `table` dropped
+// by a `dropProtectedTableStep` can still be read as of the time it
+// was dropped, and then releases the protected timestamp by canceling
+// the changefeed.
+type verifyProtectedTableStep struct {
+	table string
+}
+
+func (s verifyProtectedTableStep) Background() shouldStop { return nil }
+
+func (s verifyProtectedTableStep) Description() string {
+	return fmt.Sprintf("verify data in dropped table %s was not garbage collected", s.table)
+}
+
+func (s verifyProtectedTableStep) Run(
+	ctx context.Context, l *logger.Logger, rng *rand.Rand, h *Helper,
+) error {
+	var jobID int
+	var dropTS string
+	if err := h.QueryRow(
+		rng,
+		fmt.Sprintf("SELECT job_id, drop_ts FROM %s WHERE table_name = $1", protectedTableMetadata),
+		s.table,
+	).Scan(&jobID, &dropTS); err != nil {
+		return errors.Wrapf(err, "reading metadata for %s", s.table)
+	}
+
+	var count, sum int
+	if err := h.QueryRow(rng, fmt.Sprintf(
+		"SELECT count(*), COALESCE(sum(v - 2*k), 0) FROM %s AS OF SYSTEM TIME '%s'", s.table, dropTS,
+	)).Scan(&count, &sum); err != nil {
+		return errors.Wrapf(err, "reading %s as of %s", s.table, dropTS)
+	}
+
+	if count != protectedRows || sum != 0 {
+		return errors.Newf(
+			"expected %d valid rows in %s as of %s, found %d rows (checksum %d)",
+			protectedRows, s.table, dropTS, count, sum,
+		)
+	}
+
+	return h.Exec(rng, "CANCEL JOB $1", jobID)
+}
+
+const (
+	// pitrTablePrefix is the prefix of the tables created and backed
+	// up by `pitrBackupStep`.
+	pitrTablePrefix = "defaultdb.mixedversion_pitr"
+	// pitrRestoreDatabasePrefix is the prefix of the databases into
+	// which `pitrRestoreStep` restores a backed up table.
+	pitrRestoreDatabasePrefix = "mixedversion_pitr_restore"
+	// pitrMetadata is the table where the revision history covered by
+	// each backup is recorded so that it can be used by later steps.
+	pitrMetadata = "defaultdb.mixedversion_pitr_metadata"
+	// pitrRows is the number of rows written by `pitrBackupStep`.
+	pitrRows = 1000
+)
+
+// pitrBackup identifies a backup taken by a `pitrBackupStep`: the
+// backup of `table` into a collection in the external IO directory of
+// `node`.
+type pitrBackup struct {
+	node  int
+	table string
+}
+
+// collection returns the URI of the collection the backup is taken
+// into.
+func (b pitrBackup) collection() string {
+	return fmt.Sprintf("nodelocal://%d/%s", b.node, b.tableName())
+}
+
+// tableName returns the name of the backed up table, without the
+// database it belongs to.
+func (b pitrBackup) tableName() string {
+	return b.table[strings.LastIndex(b.table, ".")+1:]
+}
+
+// pitrBackupStep creates a `table`, writes to it, updates every row
+// and then backs it up with revision history. The span of time in
+// which the contents of the table can be restored, starting when the
+// initial rows were written and ending when the backup was taken, is
+// recorded in the `pitrMetadata` table.
+type pitrBackupStep struct {
+	backup pitrBackup
+}
+
+func (s pitrBackupStep) Background() shouldStop { return nil }
+
+func (s pitrBackupStep) Description() string {
+	return fmt.Sprintf(
+		"back up %s with revision history into %s", s.backup.table, s.backup.collection(),
+	)
+}
+
+func (s pitrBackupStep) Run(ctx context.Context, l *logger.Logger, rng *rand.Rand, h *Helper) error {
+	stmts := []string{
+		fmt.Sprintf(
+			"CREATE TABLE IF NOT EXISTS %s (table_name STRING PRIMARY KEY, start_ts STRING, end_ts STRING)",
+			pitrMetadata,
+		),
+		fmt.Sprintf("CREATE TABLE %s (k INT8 PRIMARY KEY, v INT8)", s.backup.table),
+		fmt.Sprintf(
+			"INSERT INTO %s SELECT i, i FROM generate_series(1, %d) AS g(i)", s.backup.table, pitrRows,
+		),
+	}
+	for _, stmt := range stmts {
+		if err := h.Exec(rng, stmt); err != nil {
+			return err
+		}
+	}
+
+	// The revision history of the table starts at the commit timestamp
+	// of the initial rows; the table is empty at any earlier time.
+	var start string
+	if err := h.QueryRow(rng, fmt.Sprintf(
+		"SELECT max(crdb_internal_mvcc_timestamp)::STRING FROM %s", s.backup.table,
+	)).Scan(&start); err != nil {
+		return errors.Wrapf(err, "reading commit timestamp of %s", s.backup.table)
+	}
+
+	if err := h.Exec(rng, fmt.Sprintf("UPDATE %s SET v = 2*k", s.backup.table)); err != nil {
+		return err
+	}
+	if err := h.Exec(rng, fmt.Sprintf(
+		"BACKUP TABLE %s INTO '%s' WITH revision_history", s.backup.table, s.backup.collection(),
+	)); err != nil {
+		return err
+	}
+
+	var end time.Time
+	if err := h.QueryRow(rng, fmt.Sprintf(
+		"SELECT max(end_time) FROM [SHOW BACKUP LATEST IN '%s']", s.backup.collection(),
+	)).Scan(&end); err != nil {
+		return errors.Wrapf(err, "reading end time of backup in %s", s.backup.collection())
+	}
+
+	endTS := hlc.Timestamp{WallTime: end.UnixNano()}
+	l.Printf("backup of %s covers revisions from %s to %s", s.backup.table, start, endTS.AsOfSystemTime())
+	return h.Exec(
+		rng,
+		fmt.Sprintf("INSERT INTO %s (table_name, start_ts, end_ts) VALUES ($1, $2, $3)", pitrMetadata),
+		s.backup.table, start, endTS.AsOfSystemTime(),
+	)
+}
+
+// pitrRestoreStep restores the table backed up by a `pitrBackupStep`
+// into a new `database`, as of the timestamp `offset` of the way
+// through the revision history of the backup. It then checks that
+// the restored table matches the contents of the original table as
+// of that timestamp.
+type pitrRestoreStep struct {
+	backup   pitrBackup
+	database string
+	offset   float64
+}
+
+func (s pitrRestoreStep) Background() shouldStop { return nil }
+
+func (s pitrRestoreStep) Description() string {
+	return fmt.Sprintf(
+		"restore %s into database %s as of a point in its revision history (offset: %.2f)",
+		s.backup.table, s.database, s.offset,
+	)
+}
+
+func (s pitrRestoreStep) Run(
+	ctx context.Context, l *logger.Logger, rng *rand.Rand, h *Helper,
+) error {
+	var startStr, endStr string
+	if err := h.QueryRow(
+		rng,
+		fmt.Sprintf("SELECT start_ts, end_ts FROM %s WHERE table_name = $1", pitrMetadata),
+		s.backup.table,
+	).Scan(&startStr, &endStr); err != nil {
+		return errors.Wrapf(err, "reading metadata for %s", s.backup.table)
+	}
+
+	start, err := hlc.ParseHLC(startStr)
+	if err != nil {
+		return errors.Wrapf(err, "parsing start of revision history %q", startStr)
+	}
+	end, err := hlc.ParseHLC(endStr)
+	if err != nil {
+		return errors.Wrapf(err, "parsing end of revision history %q", endStr)
+	}
+	aost := restoreTime(start, end, s.offset).AsOfSystemTime()
+
+	l.Printf("restoring %s as of %s", s.backup.table, aost)
+	stmts := []string{
+		fmt.Sprintf("CREATE DATABASE %s", s.database),
+		fmt.Sprintf(
+			"RESTORE TABLE %s FROM LATEST IN '%s' AS OF SYSTEM TIME '%s' WITH into_db = '%s'",
+			s.backup.table, s.backup.collection(), aost, s.database,
+		),
+	}
+	for _, stmt := range stmts {
+		if err := h.Exec(rng, stmt); err != nil {
+			return err
+		}
+	}
+
+	const checksumQuery = "SELECT count(*), COALESCE(sum(v), 0) FROM %s"
+	var expectedCount, expectedSum int
+	if err := h.QueryRow(rng, fmt.Sprintf(
+		checksumQuery+" AS OF SYSTEM TIME '%s'", s.backup.table, aost,
+	)).Scan(&expectedCount, &expectedSum); err != nil {
+		return errors.Wrapf(err, "reading %s as of %s", s.backup.table, aost)
+	}
+
+	restored := fmt.Sprintf("%s.%s", s.database, s.backup.tableName())
+	var count, sum int
+	if err := h.QueryRow(rng, fmt.Sprintf(checksumQuery, restored)).Scan(&count, &sum); err != nil {
+		return errors.Wrapf(err, "reading %s", restored)
+	}
+
+	if count != pitrRows || count != expectedCount || sum != expectedSum {
+		return errors.Newf(
+			"expected %d rows (checksum %d) in %s, found %d rows (checksum %d)",
+			expectedCount, expectedSum, restored, count, sum,
+		)
+	}
+
+	return nil
+}
+
+// restoreTime returns the timestamp `offset` of the way from `start`
+// to `end`, where `offset` is expected to be in [0, 1]. The returned
+// timestamp is never outside of the [start, end] interval.
+func restoreTime(start, end hlc.Timestamp, offset float64) hlc.Timestamp {
+	if offset <= 0 || end.LessEq(start) {
+		return start
+	}
+	if offset >= 1 {
+		return end
+	}
+
+	ts := hlc.Timestamp{
+		WallTime: start.WallTime + int64(offset*float64(end.WallTime-start.WallTime)),
+	}
+	if ts.Less(start) {
+		return start
+	}
+
+	return ts
+}
+
+const (
+	// survivalGoalDatabasePrefix is the prefix of the databases
+	// created by `changeSurvivalGoalStep`.
+	survivalGoalDatabasePrefix = "mixedversion_survival"
+	// minSurvivalGoalRegions is the number of regions required to
+	// survive region failures.
+	minSurvivalGoalRegions = 3
+)
+
+// survivalGoals are the goals a database may be configured to
+// survive, as reported by `SHOW DATABASES`.
+var survivalGoals = []string{"zone", "region"}
+
+// otherSurvivalGoal returns the survival goal that is not `goal`.
+func otherSurvivalGoal(goal string) string {
+	if goal == survivalGoals[0] {
+		return survivalGoals[1]
+	}
+
+	return survivalGoals[0]
+}
+
+// clusterRegions returns the regions of the nodes in the cluster.
+func clusterRegions(rng *rand.Rand, h *Helper) ([]string, error) {
+	rows, err := h.Query(rng, "SELECT region FROM [SHOW REGIONS FROM CLUSTER]")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var regions []string
+	for rows.Next() {
+		var region string
+		if err := rows.Scan(&region); err != nil {
+			return nil, err
+		}
+		regions = append(regions, region)
+	}
+
+	return regions, rows.Err()
+}
+
+// changeSurvivalGoalStep creates a multi-region `database` spanning
+// every region in the cluster that survives the failures opposite to
+// `goal`, and then changes its survival goal to `goal`.
+type changeSurvivalGoalStep struct {
+	database string
+	goal     string
+}
+
+func (s changeSurvivalGoalStep) Background() shouldStop { return nil }
+
+func (s changeSurvivalGoalStep) Description() string {
+	return fmt.Sprintf("change survival goal of database %s to %s failure", s.database, s.goal)
+}
+
+func (s changeSurvivalGoalStep) Run(
+	ctx context.Context, l *logger.Logger, rng *rand.Rand, h *Helper,
+) error {
+	regions, err := clusterRegions(rng, h)
+	if err != nil {
+		return err
+	}
+	if len(regions) < minSurvivalGoalRegions {
+		l.Printf("cluster has %d region(s), skipping survival goal change", len(regions))
+		return nil
+	}
+
+	quotedRegions := make([]string, len(regions))
+	for j, region := range regions {
+		quotedRegions[j] = fmt.Sprintf("%q", region)
+	}
+
+	stmts := []string{
+		fmt.Sprintf(
+			"CREATE DATABASE %s PRIMARY REGION %s REGIONS %s SURVIVE %s FAILURE",
+			s.database, quotedRegions[0], strings.Join(quotedRegions, ", "), otherSurvivalGoal(s.goal),
+		),
+		fmt.Sprintf("CREATE TABLE %s.t (k INT8 PRIMARY KEY, v STRING)", s.database),
+		fmt.Sprintf("INSERT INTO %s.t SELECT i, i::STRING FROM generate_series(1, 100) AS g(i)", s.database),
+		fmt.Sprintf("ALTER DATABASE %s SURVIVE %s FAILURE", s.database, s.goal),
+	}
+	for _, stmt := range stmts {
+		if err := h.Exec(rng, stmt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// verifySurvivalGoalStep checks that the survival goal of a
+// `database` changed by a `changeSurvivalGoalStep` is `goal`.
+type verifySurvivalGoalStep struct {
+	database string
+	goal     string
+}
+
+func (s verifySurvivalGoalStep) Background() shouldStop { return nil }
+
+func (s verifySurvivalGoalStep) Description() string {
+	return fmt.Sprintf("verify database %s survives %s failure", s.database, s.goal)
+}
+
+func (s verifySurvivalGoalStep) Run(
+	ctx context.Context, l *logger.Logger, rng *rand.Rand, h *Helper,
+) error {
+	regions, err := clusterRegions(rng, h)
+	if err != nil {
+		return err
+	}
+	if len(regions) < minSurvivalGoalRegions {
+		l.Printf("cluster has %d region(s), skipping survival goal verification", len(regions))
+		return nil
+	}
+
+	var goal string
+	if err := h.QueryRow(
+		rng, "SELECT survival_goal FROM [SHOW DATABASES] WHERE database_name = $1", s.database,
+	).Scan(&goal); err != nil {
+		return errors.Wrapf(err, "reading survival goal of %s", s.database)
+	}
+
+	if goal != s.goal {
+		return errors.Newf("expected database %s to survive %s failure, found %s", s.database, s.goal, goal)
+	}
+
+	var count int
+	if err := h.QueryRow(
+		rng, fmt.Sprintf("SELECT count(*) FROM %s.t", s.database),
+	).Scan(&count); err != nil {
+		return err
+	}
+
+	if count != 100 {
+		return errors.Newf("expected 100 rows in %s.t, found %d", s.database, count)
+	}
+
+	return nil
+}
+
+const (
+	// inflightJobTablePrefix is the prefix of the tables indexed by the
+	// jobs created by `pauseSchemaChangeJobStep`.
+	inflightJobTablePrefix = "defaultdb.mixedversion_inflight"
+	// inflightJobIndexPrefix is the prefix of the indexes created by
+	// `pauseSchemaChangeJobStep`.
+	inflightJobIndexPrefix = "mixedversion_inflight"
+	// inflightJobRows is the number of rows backfilled by the jobs
+	// created by `pauseSchemaChangeJobStep`.
+	inflightJobRows = 1000
+	// pauseResumeJobTablePrefix is the prefix of the tables indexed by
+	// the jobs paused by `pauseRunningSchemaChangeJobStep`.
+	pauseResumeJobTablePrefix = "defaultdb.mixedversion_pause_resume"
+	// pauseResumeJobIndexPrefix is the prefix of the indexes created by
+	// the jobs paused by `pauseRunningSchemaChangeJobStep`.
+	pauseResumeJobIndexPrefix = "mixedversion_pause_resume"
+	// pauseResumeJobRows is the number of rows backfilled by the jobs
+	// paused by `pauseRunningSchemaChangeJobStep`.
+	pauseResumeJobRows = 100_000
+	// pauseResumeJobBatchSize is the index backfill batch size used
+	// while the job is paused by `pauseRunningSchemaChangeJobStep`.
+	// It is small enough that the job is still running by the time it
+	// is paused.
+	pauseResumeJobBatchSize = 100
+	// schemaChangeJobTimeout is the maximum amount of time we wait for
+	// a schema change job to reach the expected status.
+	schemaChangeJobTimeout = 5 * time.Minute
+)
+
+// schemaChangePausepoints pause schema change jobs, in either schema
+// changer, before they start executing.
+var schemaChangePausepoints = []string{
+	"schemachanger.before.exec",
+	"newschemachanger.before.exec",
+}
+
+// inflightJob identifies the schema change job that creates `index`
+// on `table`, which has `rows` rows.
+type inflightJob struct {
+	table string
+	index string
+	rows  int
+}
+
+// jobID returns the ID of the job that creates the index, and its
+// current status.
+func (j inflightJob) jobID(rng *rand.Rand, h *Helper) (int, string, error) {
+	var jobID int
+	var status string
+	if err := h.QueryRow(
+		rng,
+		`SELECT job_id, status FROM [SHOW JOBS]
+WHERE job_type IN ('SCHEMA CHANGE', 'NEW SCHEMA CHANGE') AND description LIKE $1
+ORDER BY created DESC LIMIT 1`,
+		fmt.Sprintf("%%CREATE INDEX %s%%", j.index),
+	).Scan(&jobID, &status); err != nil {
+		return 0, "", errors.Wrapf(err, "finding job creating index %s", j.index)
+	}
+
+	return jobID, status, nil
+}
+
+// waitForStatus waits for the job that creates the index to reach
+// `expected` status.
+func (j inflightJob) waitForStatus(rng *rand.Rand, h *Helper, expected string) error {
+	return retry.ForDuration(schemaChangeJobTimeout, func() error {
+		jobID, status, err := j.jobID(rng, h)
+		if err != nil {
+			return err
+		}
+
+		if status != expected {
+			return errors.Newf("job %d creating index %s has status %q", jobID, j.index, status)
+		}
+
+		return nil
+	})
+}
+
+// createPaused creates the index on the table while a pausepoint is
+// set, so that the schema change job is paused before it runs.
+func (j inflightJob) createPaused(l *logger.Logger, rng *rand.Rand, h *Helper) (retErr error) {
+	if err := h.Exec(rng, fmt.Sprintf(
+		"SET CLUSTER SETTING jobs.debug.pausepoints = '%s'", strings.Join(schemaChangePausepoints, ","),
+	)); err != nil {
+		return err
+	}
+	// Other schema changes running concurrently would also be paused,
+	// so the pausepoints are cleared as soon as the job is created.
+	defer func() {
+		retErr = errors.CombineErrors(retErr, h.Exec(rng, "SET CLUSTER SETTING jobs.debug.pausepoints = ''"))
+	}()
+
+	// The statement returns an error once the job is paused.
+	err := h.Exec(rng, fmt.Sprintf("CREATE INDEX %s ON %s (v)", j.index, j.table))
+	if err == nil {
+		return errors.Newf("expected job creating index %s to be paused", j.index)
+	}
+	l.Printf("creating index %s returned: %v", j.index, err)
+
+	return j.waitForStatus(rng, h, "paused")
+}
+
+// pauseSchemaChangeJobStep populates a table and creates a schema
+// change job that backfills an index on it. A pausepoint is set while
+// the index is created so that the job is paused before it runs,
+// leaving it in flight for the rest of the upgrade.
+type pauseSchemaChangeJobStep struct {
+	job inflightJob
+}
+
+func (s pauseSchemaChangeJobStep) Background() shouldStop { return nil }
+
+func (s pauseSchemaChangeJobStep) Description() string {
+	return fmt.Sprintf("create paused job creating index %s on %s", s.job.index, s.job.table)
+}
+
+func (s pauseSchemaChangeJobStep) Run(
+	ctx context.Context, l *logger.Logger, rng *rand.Rand, h *Helper,
+) error {
+	stmts := []string{
+		fmt.Sprintf("CREATE TABLE %s (k INT8 PRIMARY KEY, v INT8)", s.job.table),
+		fmt.Sprintf(
+			"INSERT INTO %s SELECT i, 2*i FROM generate_series(1, %d) AS g(i)", s.job.table, s.job.rows,
+		),
+	}
+	for _, stmt := range stmts {
+		if err := h.Exec(rng, stmt); err != nil {
+			return err
+		}
+	}
+
+	return s.job.createPaused(l, rng, h)
+}
+
+// resumeSchemaChangeJobStep resumes a job paused by a
+// `pauseSchemaChangeJobStep` or `pauseRunningSchemaChangeJobStep`,
+// and checks that it succeeds and that the index it creates is
+// usable.
+type resumeSchemaChangeJobStep struct {
+	job inflightJob
+}
+
+func (s resumeSchemaChangeJobStep) Background() shouldStop { return nil }
+
+func (s resumeSchemaChangeJobStep) Description() string {
+	return fmt.Sprintf("resume job creating index %s on %s", s.job.index, s.job.table)
+}
+
+func (s resumeSchemaChangeJobStep) Run(
+	ctx context.Context, l *logger.Logger, rng *rand.Rand, h *Helper,
+) error {
+	jobID, _, err := s.job.jobID(rng, h)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Exec(rng, "RESUME JOB $1", jobID); err != nil {
+		return err
+	}
+	if err := s.job.waitForStatus(rng, h, "succeeded"); err != nil {
+		return err
+	}
+
+	var count, sum int
+	if err := h.QueryRow(rng, fmt.Sprintf(
+		"SELECT count(*), COALESCE(sum(v - 2*k), 0) FROM %s@%s", s.job.table, s.job.index,
+	)).Scan(&count, &sum); err != nil {
+		return errors.Wrapf(err, "reading index %s", s.job.index)
+	}
+
+	if count != s.job.rows || sum != 0 {
+		return errors.Newf(
+			"expected %d valid rows in index %s, found %d rows (checksum %d)",
+			s.job.rows, s.job.index, count, sum,
+		)
+	}
+
+	return nil
+}
+
+// pauseRunningSchemaChangeJobStep populates a table and starts a
+// schema change job that backfills an index on it. The backfill is
+// throttled, and the job is paused with PAUSE JOB once it is running,
+// leaving it paused halfway through for the rest of the upgrade.
+type pauseRunningSchemaChangeJobStep struct {
+	job inflightJob
+}
+
+func (s pauseRunningSchemaChangeJobStep) Background() shouldStop { return nil }
+
+func (s pauseRunningSchemaChangeJobStep) Description() string {
+	return fmt.Sprintf("start and pause job creating index %s on %s", s.job.index, s.job.table)
+}
+
+func (s pauseRunningSchemaChangeJobStep) Run(
+	ctx context.Context, l *logger.Logger, rng *rand.Rand, h *Helper,
+) (retErr error) {
+	stmts := []string{
+		fmt.Sprintf("CREATE TABLE %s (k INT8 PRIMARY KEY, v INT8)", s.job.table),
+		fmt.Sprintf(
+			"INSERT INTO %s SELECT i, 2*i FROM generate_series(1, %d) AS g(i)", s.job.table, s.job.rows,
+		),
+		fmt.Sprintf(
+			"SET CLUSTER SETTING bulkio.index_backfill.batch_size = %d", pauseResumeJobBatchSize,
+		),
+	}
+	for _, stmt := range stmts {
+		if err := h.Exec(rng, stmt); err != nil {
+			return err
+		}
+	}
+	// The job is resumed with the default batch size once the upgrade
+	// is finalized.
+	defer func() {
+		retErr = errors.CombineErrors(retErr, h.Exec(rng, "RESET CLUSTER SETTING bulkio.index_backfill.batch_size"))
+	}()
+
+	// The job is created paused, so that we know its ID before it
+	// starts running.
+	if err := s.job.createPaused(l, rng, h); err != nil {
+		return err
+	}
+	jobID, _, err := s.job.jobID(rng, h)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Exec(rng, "RESUME JOB $1", jobID); err != nil {
+		return err
+	}
+	if err := s.job.waitForStatus(rng, h, "running"); err != nil {
+		return err
+	}
+
+	if err := h.Exec(rng, "PAUSE JOB $1", jobID); err != nil {
+		return errors.Wrapf(err, "pausing running job %d", jobID)
+	}
+	return s.job.waitForStatus(rng, h, "paused")
+}
+
+const (
+	// tenantCapabilitiesPrefix is the prefix of the virtual clusters
+	// created by `setTenantCapabilitiesStep`.
+	tenantCapabilitiesPrefix = "mixedversion-capabilities"
+
+	// tenantCapabilitiesTimeout is the maximum amount of time we wait
+	// for every node to observe a change to tenant capabilities.
+	tenantCapabilitiesTimeout = 2 * time.Minute
+)
+
+// minTenantCapabilitiesVersion is the minimum version in which
+// capabilities can be granted to virtual clusters.
+var minTenantCapabilitiesVersion = clusterupgrade.MustParseVersion("v23.1.0")
+
+// tenantCapability is the state of a boolean capability of a virtual
+// cluster.
+type tenantCapability struct {
+	name    string
+	granted bool
+}
+
+func (c tenantCapability) String() string {
+	return fmt.Sprintf("%s=%t", c.name, c.granted)
+}
+
+// waitForTenantCapabilities waits until the in-memory capabilities
+// cache of every node reports the given `capabilities` for `tenant`.
+// Capability changes are propagated to every node asynchronously, so
+// nodes may temporarily disagree.
+func waitForTenantCapabilities(
+	ctx context.Context, h *Helper, tenant string, capabilities []tenantCapability,
+) error {
+	return retry.ForDuration(tenantCapabilitiesTimeout, func() error {
+		for _, node := range h.System.Descriptor.Nodes {
+			for _, c := range capabilities {
+				var value string
+				if err := h.System.Connect(node).QueryRowContext(ctx, `
+SELECT capability_value FROM crdb_internal.node_tenant_capabilities_cache
+WHERE tenant_id = (SELECT id FROM system.tenants WHERE name = $1) AND capability_name = $2`,
+					tenant, c.name,
+				).Scan(&value); err != nil {
+					return errors.Wrapf(err, "reading capability %s of %s from node %d", c.name, tenant, node)
+				}
+
+				if value != fmt.Sprintf("%t", c.granted) {
+					return errors.Newf(
+						"node %d: expected %s of %s to be %t, found %s", node, c.name, tenant, c.granted, value,
+					)
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// setTenantCapabilitiesStep creates the `tenant` virtual cluster,
+// grants or revokes each of the `capabilities`, and waits for every
+// node to observe the change.
+type setTenantCapabilitiesStep struct {
+	tenant       string
+	capabilities []tenantCapability
+}
+
+func (s setTenantCapabilitiesStep) Background() shouldStop { return nil }
+
+func (s setTenantCapabilitiesStep) Description() string {
+	return fmt.Sprintf("set capabilities %v of virtual cluster %s", s.capabilities, s.tenant)
+}
+
+func (s setTenantCapabilitiesStep) Run(
+	ctx context.Context, l *logger.Logger, rng *rand.Rand, h *Helper,
+) error {
+	// We use the TENANT syntax, as VIRTUAL CLUSTER is not available in
+	// every release that supports capabilities.
+	stmts := []string{fmt.Sprintf("CREATE TENANT IF NOT EXISTS %q", s.tenant)}
+	for _, c := range s.capabilities {
+		action := "REVOKE"
+		if c.granted {
+			action = "GRANT"
+		}
+		stmts = append(stmts, fmt.Sprintf("ALTER TENANT %q %s CAPABILITY %s", s.tenant, action, c.name))
+	}
+	for _, stmt := range stmts {
+		if err := h.System.Exec(rng, stmt); err != nil {
+			return err
+		}
+	}
+
+	return waitForTenantCapabilities(ctx, h, s.tenant, s.capabilities)
+}
+
+// verifyTenantCapabilitiesStep checks that the capabilities of a
+// `tenant` changed by a `setTenantCapabilitiesStep` are still in
+// place, both in the system tenant's records and in the capabilities
+// cache of every node.
+type verifyTenantCapabilitiesStep struct {
+	tenant       string
+	capabilities []tenantCapability
+}
+
+func (s verifyTenantCapabilitiesStep) Background() shouldStop { return nil }
+
+func (s verifyTenantCapabilitiesStep) Description() string {
+	return fmt.Sprintf("verify capabilities %v of virtual cluster %s", s.capabilities, s.tenant)
+}
+
+func (s verifyTenantCapabilitiesStep) Run(
+	ctx context.Context, l *logger.Logger, rng *rand.Rand, h *Helper,
+) error {
+	for _, c := range s.capabilities {
+		var value string
+		if err := h.System.QueryRow(
+			rng,
+			fmt.Sprintf("SELECT capability_value FROM [SHOW TENANT %q WITH CAPABILITIES] WHERE capability_name = $1", s.tenant),
+			c.name,
+		).Scan(&value); err != nil {
+			return errors.Wrapf(err, "reading capability %s of %s", c.name, s.tenant)
+		}
+
+		if value != fmt.Sprintf("%t", c.granted) {
+			return errors.Newf("expected %s of %s to be %t, found %s", c.name, s.tenant, c.granted, value)
+		}
+	}
+
+	return waitForTenantCapabilities(ctx, h, s.tenant, s.capabilities)
 }
 
 // nodesRunningAtLeast returns a list of nodes running a system or
@@ -308,6 +1892,25 @@ func nodesRunningAtLeast(
 	return service.NodesInNextVersion()
 }
 
+// clusterSettingNodes returns a list of nodes able to service a change
+// to a cluster setting introduced in `minVersion` and removed in
+// `maxVersion`, either of which may be nil. It assumes that the caller
+// made sure that there *is* one such node.
+func clusterSettingNodes(
+	virtualClusterName string, minVersion, maxVersion *clusterupgrade.Version, h *Helper,
+) option.NodeListOption {
+	service := serviceByName(h, virtualClusterName)
+
+	// If we are upgrading to a version where the cluster setting was
+	// removed, only nodes running the previous version know about it.
+	// Callers guarantee that those nodes also satisfy `minVersion`.
+	if maxVersion != nil && service.ToVersion.AtLeast(maxVersion) {
+		return service.NodesInPreviousVersion()
+	}
+
+	return nodesRunningAtLeast(virtualClusterName, minVersion, h)
+}
+
 func serviceByName(h *Helper, virtualClusterName string) *Service {
 	if virtualClusterName == install.SystemInterfaceName {
 		return h.System
@@ -14,8 +14,10 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -58,6 +60,134 @@ func (og *operationGenerator) sequenceExists(
    )`, seqName.Schema(), seqName.Object())
 }
 
+// sequenceIsDependedOn returns whether any other object, such as a column
+// whose default is nextval() of the sequence, depends on the sequence. The
+// sequence is expected to exist.
+func (og *operationGenerator) sequenceIsDependedOn(
+	ctx context.Context, tx pgx.Tx, seqName *tree.TableName,
+) (bool, error) {
+	return og.scanBool(ctx, tx, `SELECT EXISTS (
+	SELECT descriptor_id
+    FROM crdb_internal.backward_dependencies
+   WHERE dependson_id = $1::REGCLASS::INT8
+     AND descriptor_id != dependson_id
+   )`, seqName.String())
+}
+
+// workloadTypeExists returns whether an enum, or a composite type if isEnum
+// isn't set, created by the workload exists, i.e. whether randTypeName can
+// pick an existing one.
+func (og *operationGenerator) workloadTypeExists(
+	ctx context.Context, tx pgx.Tx, isEnum bool,
+) (bool, error) {
+	return og.scanBool(ctx, tx, `SELECT EXISTS (SELECT * FROM [SHOW TYPES] WHERE name LIKE $1)`,
+		typeNamePrefix(isEnum)+"%")
+}
+
+// sequenceDefaultRegex matches the default expression of a column that
+// defaults to nextval() of a sequence, as shown by SHOW COLUMNS, capturing the
+// name of the sequence.
+var sequenceDefaultRegex = regexp.MustCompile(`^nextval\('(.+)'::REGCLASS\)$`)
+
+// nextSequenceValues returns the values that the next n calls to nextval() of
+// the sequence will return, provided no other transaction draws values from it
+// in the meantime. ok is false if the values cannot be predicted, because the
+// sequence caches values, or if the sequence runs out of values first.
+func (og *operationGenerator) nextSequenceValues(
+	ctx context.Context, tx pgx.Tx, seqName string, n int,
+) (values []int64, ok bool, err error) {
+	type sequenceState struct {
+		LastValue int64
+		IsCalled  bool
+		Increment int64
+		Min       int64
+		Max       int64
+		Cache     int64
+	}
+	states, err := Collect(ctx, og, tx, pgx.RowToStructByPos[sequenceState], fmt.Sprintf(`
+	SELECT s.last_value, s.is_called, p.seqincrement, p.seqmin, p.seqmax, p.seqcache
+	  FROM %s AS s, pg_catalog.pg_sequence AS p
+	 WHERE p.seqrelid = $1::REGCLASS
+	`, seqName), seqName)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(states) != 1 || states[0].Cache > 1 {
+		return nil, false, nil
+	}
+
+	s := states[0]
+	cur := s.LastValue
+	for i := 0; i < n; i++ {
+		if i > 0 || s.IsCalled {
+			// Sequences never cycle, so running past either bound fails.
+			if (s.Increment > 0 && cur > s.Max-s.Increment) ||
+				(s.Increment < 0 && cur < s.Min-s.Increment) {
+				return nil, false, nil
+			}
+			cur += s.Increment
+		}
+		if cur < s.Min || cur > s.Max {
+			return nil, false, nil
+		}
+		values = append(values, cur)
+	}
+	return values, true, nil
+}
+
+// intValuesFitType returns whether every value fits in the integer type typ.
+func intValuesFitType(values []int64, typ *types.T) bool {
+	width := typ.Width()
+	if width == 0 || width >= 64 {
+		return true
+	}
+	limit := int64(1) << (width - 1)
+	for _, v := range values {
+		if v < -limit || v >= limit {
+			return false
+		}
+	}
+	return true
+}
+
+// ownedSequencesAreDependedOn returns whether a sequence owned by the table,
+// or by the given column of it if columnName is not empty, is used by another
+// object. Owned sequences are dropped along with their owner, so such a drop
+// fails unless it cascades. A sequence used only by the table being dropped,
+// or only by the column being dropped, does not block the drop.
+func (og *operationGenerator) ownedSequencesAreDependedOn(
+	ctx context.Context, tx pgx.Tx, tableName *tree.TableName, columnName string,
+) (bool, error) {
+	return og.scanBool(ctx, tx, With([]CTE{
+		{"descriptors", descJSONQuery},
+		{"owned", `SELECT
+				(col->>'id')::INT8 AS column_id,
+				seq_id::INT8 AS seq_id
+			FROM descriptors,
+				jsonb_array_elements(descriptor->'table'->'columns') AS col,
+				jsonb_array_elements_text(COALESCE(col->'ownsSequenceIds', '[]'::JSONB)) AS seq_id
+			WHERE id = $1::REGCLASS::INT8
+			AND ($2 = '' OR col->>'name' = $2)`},
+	}, `SELECT EXISTS(
+			SELECT *
+			FROM owned
+			JOIN descriptors AS seq ON seq.id = owned.seq_id,
+				jsonb_array_elements(COALESCE(seq.descriptor->'table'->'dependedOnBy', '[]'::JSONB)) AS dep
+			WHERE (dep->>'id')::INT8 != $1::REGCLASS::INT8
+			OR ($2 != '' AND dep->'columnIds' != jsonb_build_array(owned.column_id))
+		)`), tableName.String(), columnName)
+}
+
+// serialNormalizationUsesSequence returns whether SERIAL columns are backed by
+// a sequence under the serial_normalization setting of the session, as opposed
+// to defaulting to a row ID.
+func (og *operationGenerator) serialNormalizationUsesSequence(
+	ctx context.Context, tx pgx.Tx,
+) (bool, error) {
+	return og.scanBool(ctx, tx,
+		`SELECT current_setting('serial_normalization') NOT IN ('rowid', 'unordered_rowid')`)
+}
+
 func (og *operationGenerator) columnExistsOnTable(
 	ctx context.Context, tx pgx.Tx, tableName *tree.TableName, columnName string,
 ) (bool, error) {
@@ -98,6 +228,42 @@ func (og *operationGenerator) schemaExists(
 	)`, schemaName)
 }
 
+func (og *operationGenerator) databaseExists(
+	ctx context.Context, tx pgx.Tx, databaseName string,
+) (bool, error) {
+	return og.scanBool(ctx, tx, `SELECT EXISTS (
+	SELECT database_name
+		FROM [SHOW DATABASES]
+   WHERE database_name = $1
+	)`, databaseName)
+}
+
+// databaseHasObjects returns true if the database contains any tables, views,
+// sequences or types.
+func (og *operationGenerator) databaseHasObjects(
+	ctx context.Context, tx pgx.Tx, databaseName string,
+) (bool, error) {
+	return og.scanBool(ctx, tx, `SELECT EXISTS (
+	SELECT ns.id
+		FROM system.namespace AS ns
+		JOIN system.namespace AS db ON ns."parentID" = db.id
+   WHERE db."parentID" = 0 AND db.name = $1 AND ns."parentSchemaID" != 0
+	)`, databaseName)
+}
+
+// databaseHasUserSchemas returns true if the database contains any schemas
+// other than public.
+func (og *operationGenerator) databaseHasUserSchemas(
+	ctx context.Context, tx pgx.Tx, databaseName string,
+) (bool, error) {
+	return og.scanBool(ctx, tx, `SELECT EXISTS (
+	SELECT ns.id
+		FROM system.namespace AS ns
+		JOIN system.namespace AS db ON ns."parentID" = db.id
+   WHERE db."parentID" = 0 AND db.name = $1 AND ns."parentSchemaID" = 0 AND ns.name != 'public'
+	)`, databaseName)
+}
+
 func (og *operationGenerator) fnExists(
 	ctx context.Context, tx pgx.Tx, fnName string, argTypes string,
 ) (bool, error) {
@@ -212,6 +378,41 @@ SELECT array_agg(column_name)
 	return false, nil
 }
 
+// defaultRowIDPrimaryKeyColumn returns the name of the hidden rowid column
+// backing a table's primary key, if the table was created without an explicit
+// primary key and has not been given one since. An empty string is returned
+// for tables that have an explicit primary key.
+func (og *operationGenerator) defaultRowIDPrimaryKeyColumn(
+	ctx context.Context, tx pgx.Tx, tableName *tree.TableName,
+) (string, error) {
+	return Scan[string](ctx, og, tx, fmt.Sprintf(`
+SELECT COALESCE(
+        (
+          SELECT quote_ident(ic.column_name)
+            FROM crdb_internal.index_columns AS ic
+            JOIN crdb_internal.table_indexes AS ti ON ti.descriptor_id = ic.descriptor_id
+                                                   AND ti.index_id = ic.index_id
+            JOIN [SHOW COLUMNS FROM %s] AS c ON c.column_name = ic.column_name
+           WHERE ic.descriptor_id = $1::REGCLASS
+             AND ti.index_type = 'primary'
+             AND ic.column_type = 'key'
+             AND NOT ic.implicit
+             AND c.is_hidden
+             AND c.column_default = 'unique_rowid()'
+             AND (
+                  SELECT count(*)
+                    FROM crdb_internal.index_columns
+                   WHERE descriptor_id = ic.descriptor_id
+                     AND index_id = ic.index_id
+                     AND column_type = 'key'
+                     AND NOT implicit
+                 ) = 1
+        ),
+        ''
+       )
+`, tableName.String()), tableName.String())
+}
+
 // exprColumnCollector collects all the columns observed inside
 // an expression.
 type exprColumnCollector struct {
@@ -477,9 +678,25 @@ func (og *operationGenerator) valuesViolateUniqueConstraints(
 // disallowed on a table because PK swap is already in progress.
 var ErrSchemaChangesDisallowedDueToPkSwap = errors.New("not schema changes allowed on selected table due to PK swap")
 
+// ErrSchemaChangesDisallowedDueToTTLChange is generated when schema changes
+// are disallowed on a table because row-level TTL is being added or removed.
+var ErrSchemaChangesDisallowedDueToTTLChange = errors.New("no schema changes allowed on selected table due to row-level TTL change")
+
+// tableHasPrimaryKeySwapActive returns ErrSchemaChangesDisallowedDueToPkSwap
+// if the primary key of the table is being swapped, and
+// ErrSchemaChangesDisallowedDueToTTLChange if row-level TTL is being added to
+// or removed from it, since any schema change queued after either of them
+// fails.
 func (og *operationGenerator) tableHasPrimaryKeySwapActive(
 	ctx context.Context, tx pgx.Tx, tableName *tree.TableName,
 ) error {
+	hasTTLChange, err := og.tableHasOngoingRowLevelTTLChange(ctx, tx, tableName)
+	if err != nil {
+		return err
+	}
+	if hasTTLChange {
+		return ErrSchemaChangesDisallowedDueToTTLChange
+	}
 
 	indexName, err := og.scanStringArray(
 		ctx,
@@ -913,35 +1130,49 @@ func (og *operationGenerator) constraintIsPrimary(
 	`, tableName.String(), constraintName))
 }
 
-// Checks if a column has a single unique constraint.
-func (og *operationGenerator) columnHasSingleUniqueConstraint(
-	ctx context.Context, tx pgx.Tx, tableName *tree.TableName, columnName string,
+// columnsHaveUniqueConstraint checks if the table has a primary key or UNIQUE
+// constraint on exactly the given set of columns, in any order, which a
+// foreign key can reference.
+func (og *operationGenerator) columnsHaveUniqueConstraint(
+	ctx context.Context, tx pgx.Tx, tableName *tree.TableName, columnNames []string,
 ) (bool, error) {
 	// Rowid will always be unique, though the index is hidden.
-	if columnName == "rowid" {
+	if len(columnNames) == 1 && columnNames[0] == "rowid" {
 		return true, nil
 	}
-	return og.scanBool(ctx, tx, `
-	SELECT EXISTS(
-	        SELECT column_name
-	          FROM (
-	                SELECT table_schema, table_name, column_name, ordinal_position,
-	                       concat(table_schema,'.',table_name)::REGCLASS::INT8 AS tableid
-	                  FROM information_schema.columns
-	               ) AS cols
-	          JOIN (
-	                SELECT contype, conkey, conrelid
-	                  FROM pg_catalog.pg_constraint
-	               ) AS cons ON cons.conrelid = cols.tableid
+	constraintColumns, err := og.scanStringArrayRows(ctx, tx, `
+	SELECT array_agg(cols.column_name)
+	  FROM (
+	        SELECT conname, unnest(conkey) AS key, conrelid
+	          FROM pg_catalog.pg_constraint
+	         WHERE contype IN ('u', 'p')
+	       ) AS cons
+	  JOIN (
+	        SELECT column_name, ordinal_position,
+	               concat(table_schema,'.',table_name)::REGCLASS::INT8 AS tableid
+	          FROM information_schema.columns
 	         WHERE table_schema = $1
 	           AND table_name = $2
-	           AND column_name = $3
-	           AND (contype = 'u' OR contype = 'p')
-	           AND array_length(conkey, 1) = 1
-					   AND conkey[1] = ordinal_position
-	       )
-	`, tableName.Schema(), tableName.Object(), columnName)
+	       ) AS cols ON cons.conrelid = cols.tableid AND cons.key = cols.ordinal_position
+	GROUP BY cons.conname
+	`, tableName.Schema(), tableName.Object())
+	if err != nil {
+		return false, err
+	}
+	for _, cols := range constraintColumns {
+		if len(cols) != len(columnNames) {
+			continue
+		}
+		slices.Sort(cols)
+		sorted := append([]string(nil), columnNames...)
+		slices.Sort(sorted)
+		if slices.Equal(cols, sorted) {
+			return true, nil
+		}
+	}
+	return false, nil
 }
+
 func (og *operationGenerator) constraintIsUnique(
 	ctx context.Context, tx pgx.Tx, tableName *tree.TableName, constraintName string,
 ) (bool, error) {
@@ -998,57 +1229,85 @@ func (og *operationGenerator) constraintExists(
 	 )`, constraintName)
 }
 
-func (og *operationGenerator) rowsSatisfyFkConstraint(
+// foreignKeyRowsAreViolated returns true if some rows of the child table
+// reference a row of the parent table that doesn't exist. The column names must
+// already be quoted, and are matched by position. Following MATCH SIMPLE
+// semantics, rows with a NULL in any of the child columns satisfy the
+// constraint.
+func (og *operationGenerator) foreignKeyRowsAreViolated(
 	ctx context.Context,
 	tx pgx.Tx,
-	parentTable *tree.TableName,
-	parentColumn *column,
-	childTable *tree.TableName,
-	childColumn *column,
+	childTable string,
+	childColumns []string,
+	parentTable string,
+	parentColumns []string,
 ) (bool, error) {
-	// Self referential foreign key constraints are acceptable.
-	selfReferential, err := og.scanBool(ctx, tx,
-		`SELECT $1:::REGCLASS=$2:::REGCLASS`,
-		parentTable.String(), childTable.String())
-	if err != nil {
-		return false, err
+	var notNull, matches []string
+	for i := range childColumns {
+		notNull = append(notNull, fmt.Sprintf("child.%s IS NOT NULL", childColumns[i]))
+		matches = append(matches, fmt.Sprintf("parent.%s = child.%s", parentColumns[i], childColumns[i]))
 	}
-	if selfReferential && parentColumn.name == childColumn.name {
-		return true, nil
+	violated, err := og.scanBool(ctx, tx, fmt.Sprintf(`
+SELECT EXISTS (
+        SELECT *
+          FROM %[1]s AS child
+         WHERE %[2]s
+           AND NOT EXISTS (SELECT * FROM %[3]s AS parent WHERE %[4]s)
+       )`, childTable, strings.Join(notNull, " AND "), parentTable, strings.Join(matches, " AND ")))
+	if err != nil {
+		return false, og.checkAndAdjustForUnknownSchemaErrors(err)
 	}
+	return violated, nil
+}
 
-	// Validate the parent table has rows.
-	childRows, err := og.scanInt(ctx, tx,
-		fmt.Sprintf(`
-SELECT count(*) FROM %s
-		`, childTable.String()),
-	)
+// checkConstraintIsViolated returns true if some rows of the table do not
+// satisfy the check constraint expression, as found in pg_constraint.consrc.
+// Rows for which the expression is NULL satisfy the constraint.
+func (og *operationGenerator) checkConstraintIsViolated(
+	ctx context.Context, tx pgx.Tx, tableName *tree.TableName, expr string,
+) (bool, error) {
+	violated, err := og.scanBool(ctx, tx, fmt.Sprintf(
+		`SELECT EXISTS (SELECT * FROM %s WHERE NOT %s)`, tableName, expr))
 	if err != nil {
-		return false, err
+		return false, og.checkAndAdjustForUnknownSchemaErrors(err)
 	}
+	return violated, nil
+}
 
-	// If child table is empty then no violation can exist.
-	if childRows == 0 {
-		return true, nil
+// foreignKeyConstraintIsViolated returns true if some rows of the table
+// reference a row of the parent table that doesn't exist.
+func (og *operationGenerator) foreignKeyConstraintIsViolated(
+	ctx context.Context, tx pgx.Tx, tableName *tree.TableName, constraintName string,
+) (bool, error) {
+	fkColumns, err := Collect(ctx, og, tx, pgx.RowToMap, `
+		SELECT quote_ident(child.attname) AS child_column,
+		       fk.confrelid::REGCLASS::STRING AS parent_table,
+		       quote_ident(parent.attname) AS parent_column
+		  FROM pg_catalog.pg_constraint AS fk
+		  CROSS JOIN LATERAL generate_series(1, array_length(fk.conkey, 1)) AS g (i)
+		  JOIN pg_catalog.pg_attribute AS child ON child.attrelid = fk.conrelid
+		                                       AND child.attnum = fk.conkey[g.i]
+		  JOIN pg_catalog.pg_attribute AS parent ON parent.attrelid = fk.confrelid
+		                                        AND parent.attnum = fk.confkey[g.i]
+		 WHERE fk.conrelid = $1::REGCLASS
+		   AND fk.conname = $2
+		ORDER BY g.i
+	`, tableName.String(), constraintName)
+	if err != nil {
+		return false, og.checkAndAdjustForUnknownSchemaErrors(err)
+	}
+	if len(fkColumns) == 0 {
+		return false, nil
 	}
 
-	q := fmt.Sprintf(`
-	  SELECT count(*)
-	    FROM %s as t1
-		  LEFT JOIN %s as t2
-				     ON t1.%s = t2.%s
-			WHERE t2.%s IS NOT NULL
-`, childTable.String(), parentTable.String(), tree.NameString(childColumn.name), tree.NameString(parentColumn.name), tree.NameString(parentColumn.name))
-
-	numJoinRows, err := og.scanInt(ctx, tx, q)
-	if err != nil {
-		// UndefinedFunction errors mean that the column type is not comparable.
-		if pgErr := new(pgconn.PgError); errors.As(err, &pgErr) && pgcode.MakeCode(pgErr.Code) == pgcode.UndefinedFunction {
-			return false, nil
-		}
-		return false, err
+	var childColumns, parentColumns []string
+	for _, fkColumn := range fkColumns {
+		childColumns = append(childColumns, fkColumn["child_column"].(string))
+		parentColumns = append(parentColumns, fkColumn["parent_column"].(string))
 	}
-	return numJoinRows == childRows, err
+	return og.foreignKeyRowsAreViolated(
+		ctx, tx, tableName.String(), childColumns, fkColumns[0]["parent_table"].(string), parentColumns,
+	)
 }
 
 var (
@@ -1074,6 +1333,36 @@ func (og *operationGenerator) checkAndAdjustForUnknownSchemaErrors(err error) er
 	return err
 }
 
+// tableHasCompositeForeignKeys checks if the table has a foreign key on more
+// than one column, which violatesFkConstraints doesn't screen for.
+func (og *operationGenerator) tableHasCompositeForeignKeys(
+	ctx context.Context, tx pgx.Tx, tableName *tree.TableName,
+) (bool, error) {
+	return og.scanBool(ctx, tx, `
+	SELECT EXISTS(
+	        SELECT *
+	          FROM pg_catalog.pg_constraint
+	         WHERE contype = 'f'
+	           AND conrelid = $1::REGCLASS
+	           AND array_length(conkey, 1) > 1
+	       )`, tableName.String())
+}
+
+// tableIsReferencedByForeignKeys checks if any foreign key references the
+// table, in which case deleting its rows may be rejected or cascade to the
+// referencing rows.
+func (og *operationGenerator) tableIsReferencedByForeignKeys(
+	ctx context.Context, tx pgx.Tx, tableName *tree.TableName,
+) (bool, error) {
+	return og.scanBool(ctx, tx, `
+	SELECT EXISTS(
+	        SELECT *
+	          FROM pg_catalog.pg_constraint
+	         WHERE contype = 'f'
+	           AND confrelid = $1::REGCLASS
+	       )`, tableName.String())
+}
+
 // violatesFkConstraints checks if the rows to be inserted will result in a foreign key violation.
 func (og *operationGenerator) violatesFkConstraints(
 	ctx context.Context,
@@ -1094,6 +1383,7 @@ func (og *operationGenerator) violatesFkConstraints(
 		          FROM pg_constraint
 		         WHERE contype = 'f'
 		           AND conrelid = '%s'::REGCLASS::INT8
+		           AND array_length(conkey, 1) = 1
 		       ) AS con
 		  JOIN (
 		        SELECT column_name, ordinal_position, column_default
@@ -1239,6 +1529,100 @@ func (og *operationGenerator) violatesFkConstraintsHelper(
 	return og.scanBool(ctx, tx, q)
 }
 
+// violatesCheckConstraints returns whether any of the rows to be inserted
+// fails a CHECK constraint of the table. Constraints added with NOT VALID are
+// included, since they are enforced on writes even though the existing rows
+// were never checked. The constraint expressions are evaluated against the
+// values of the rows, so that the outcome is exactly the one of the insert,
+// including for NULLs, which only fail constraints such as
+// CHECK (<column> IS NOT NULL). Unlike a NOT NULL column, a column guarded by
+// such a constraint is still reported as nullable, so the values generated
+// for it may contain NULLs. Constraints on columns that are not inserted
+// explicitly are skipped.
+func (og *operationGenerator) violatesCheckConstraints(
+	ctx context.Context, tx pgx.Tx, tableName *tree.TableName, cols []column, rows [][]string,
+) (bool, error) {
+	constraints, err := og.scanStringArrayRows(ctx, tx, `
+		SELECT ARRAY[con.consrc] || array_agg(quote_ident(attr.attname))
+		  FROM pg_catalog.pg_constraint AS con
+		  JOIN pg_catalog.pg_attribute AS attr ON attr.attrelid = con.conrelid
+		                                      AND attr.attnum = ANY (con.conkey)
+		 WHERE con.contype = 'c'
+		   AND con.conrelid = $1::REGCLASS
+		 GROUP BY con.oid, con.consrc
+`, tableName.String())
+	if err != nil {
+		return false, og.checkAndAdjustForUnknownSchemaErrors(err)
+	}
+
+	colIdx := make(map[string]int, len(cols))
+	for i, col := range cols {
+		colIdx[col.name] = i
+	}
+	for _, constraint := range constraints {
+		expr, conCols := constraint[0], constraint[1:]
+		idxs := make([]int, 0, len(conCols))
+		for _, name := range conCols {
+			if i, ok := colIdx[name]; ok {
+				idxs = append(idxs, i)
+			}
+		}
+		if len(idxs) != len(conCols) {
+			continue
+		}
+		values := make([]string, len(rows))
+		for r, row := range rows {
+			rowValues := make([]string, len(idxs))
+			for j, i := range idxs {
+				rowValues[j] = row[i]
+				// NULLs are typed, so that the expression type checks like it
+				// does against the column.
+				if row[i] == "NULL" {
+					rowValues[j] = fmt.Sprintf("NULL::%s", cols[i].typ.SQLString())
+				}
+			}
+			values[r] = fmt.Sprintf("(%s)", strings.Join(rowValues, ", "))
+		}
+		violation, err := og.scanBool(ctx, tx, fmt.Sprintf(
+			`SELECT EXISTS (SELECT * FROM (VALUES %s) AS v (%s) WHERE NOT (%s))`,
+			strings.Join(values, ", "), strings.Join(conCols, ", "), expr))
+		if err != nil {
+			return false, err
+		}
+		if violation {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// rolesWithDependencies returns which of the given roles own objects or hold
+// privileges in any database, or hold global privileges, in which case they
+// cannot be dropped.
+func (og *operationGenerator) rolesWithDependencies(
+	ctx context.Context, tx pgx.Tx, roles []string,
+) ([]string, error) {
+	return og.scanStringArray(ctx, tx, `
+WITH objects AS (
+	SELECT (json_each(crdb_internal.pb_to_json('desc', descriptor))).@2 AS object
+	  FROM system.descriptor
+)
+SELECT COALESCE(array_agg(role), ARRAY[]::STRING[])
+  FROM unnest($1::STRING[]) AS role
+ WHERE EXISTS (
+        SELECT *
+          FROM objects
+         WHERE object->'privileges'->>'ownerProto' = role
+            OR EXISTS (
+                SELECT *
+                  FROM jsonb_array_elements(COALESCE(object->'privileges'->'users', '[]'::JSONB)) AS u
+                 WHERE u->>'userProto' = role
+               )
+       )
+    OR EXISTS (SELECT * FROM system.privileges WHERE username = role)
+`, roles)
+}
+
 func (og *operationGenerator) columnIsInDroppingIndex(
 	ctx context.Context, tx pgx.Tx, tableName *tree.TableName, columnName string,
 ) (bool, error) {
@@ -1259,6 +1643,313 @@ SELECT EXISTS(
 `, tableName.String(), columnName)
 }
 
+// enumValueUsed returns whether any row stores the given enum value in a
+// column of the enum type, or of its array type. Both the type name and value
+// are expected to be escaped.
+func (og *operationGenerator) enumValueUsed(
+	ctx context.Context, tx pgx.Tx, typeName string, value string,
+) (bool, error) {
+	columns, err := og.scanStringArrayRows(ctx, tx, `
+SELECT ARRAY[a.attrelid::REGCLASS::STRING, quote_ident(a.attname), (a.atttypid != $1::REGTYPE)::STRING]
+  FROM pg_catalog.pg_attribute AS a
+  JOIN pg_catalog.pg_class AS c ON c.oid = a.attrelid
+ WHERE c.relkind IN ('r', 'm')
+   AND NOT a.attisdropped
+   AND a.atttypid IN ($1::REGTYPE, (SELECT typarray FROM pg_catalog.pg_type WHERE oid = $1::REGTYPE))
+`, typeName)
+	if err != nil {
+		return false, err
+	}
+
+	for _, column := range columns {
+		predicate := fmt.Sprintf(`%s = %s`, column[1], value)
+		if column[2] == "true" {
+			predicate = fmt.Sprintf(`%s = ANY(%s)`, value, column[1])
+		}
+		used, err := og.scanBool(ctx, tx, fmt.Sprintf(
+			`SELECT EXISTS(SELECT 1 FROM %s WHERE %s)`, column[0], predicate,
+		))
+		if err != nil || used {
+			return used, err
+		}
+	}
+	return false, nil
+}
+
+// enumTypeUsedInExpressions returns whether an expression of another
+// descriptor, such as a default expression, a check constraint or the query
+// of a view, refers to the enum type. Expressions refer to types by their
+// OID, e.g. 'a':::@100105. The type name is expected to be escaped.
+func (og *operationGenerator) enumTypeUsedInExpressions(
+	ctx context.Context, tx pgx.Tx, typeName string,
+) (bool, error) {
+	return og.scanBool(ctx, tx, `
+SELECT EXISTS(
+        SELECT *
+          FROM system.descriptor
+         WHERE crdb_internal.pb_to_json('desc', descriptor)::STRING
+               LIKE '%@' || ($1::REGTYPE::OID::INT8)::STRING || '%'
+       )
+`, typeName)
+}
+
+// secondaryIndexesBackingForeignKeys returns the secondary indexes of the
+// table which are the only unique index able to serve an inbound foreign
+// key, and so cannot be dropped without dropping that foreign key as well.
+// Partial indexes can't serve foreign keys, and the implicit key columns of
+// an index, such as those of hash sharded or implicitly partitioned indexes,
+// don't take part in the columns it serves.
+// Each result is made up of the index name, the foreign key name, and the
+// schema and name of the table the foreign key is defined on.
+func (og *operationGenerator) secondaryIndexesBackingForeignKeys(
+	ctx context.Context, tx pgx.Tx, tableName *tree.TableName,
+) ([][]string, error) {
+	return og.scanStringArrayRows(ctx, tx, `
+WITH unique_indexes AS (
+        SELECT ti.index_name,
+               ti.index_type,
+               ARRAY(
+                SELECT ic.column_id
+                  FROM crdb_internal.index_columns AS ic
+                 WHERE ic.descriptor_id = ti.descriptor_id
+                   AND ic.index_id = ti.index_id
+                   AND ic.column_type = 'key'
+                   AND NOT ic.implicit
+              ORDER BY ic.column_id
+               ) AS key_columns
+          FROM crdb_internal.table_indexes AS ti
+         WHERE ti.descriptor_id = $1::REGCLASS
+           AND ti.is_unique
+           AND NOT EXISTS(
+                SELECT 1
+                  FROM pg_catalog.pg_index AS pi
+                  JOIN pg_catalog.pg_class AS pc ON pc.oid = pi.indexrelid
+                 WHERE pi.indrelid = $1::REGCLASS
+                   AND pc.relname = ti.index_name
+                   AND pi.indpred IS NOT NULL
+               )
+     ),
+     inbound_fks AS (
+        SELECT con.conname,
+               ns.nspname,
+               cls.relname,
+               ARRAY(SELECT unnest(con.confkey)::INT8 AS k ORDER BY k) AS referenced_columns
+          FROM pg_catalog.pg_constraint AS con
+          JOIN pg_catalog.pg_class AS cls ON cls.oid = con.conrelid
+          JOIN pg_catalog.pg_namespace AS ns ON ns.oid = cls.relnamespace
+         WHERE con.contype = 'f'
+           AND con.confrelid = $1::REGCLASS
+     )
+SELECT ARRAY[idx.index_name, fk.conname, fk.nspname, fk.relname]
+  FROM unique_indexes AS idx
+  JOIN inbound_fks AS fk ON fk.referenced_columns = idx.key_columns
+ WHERE idx.index_type = 'secondary'
+   AND NOT EXISTS(
+        SELECT 1
+          FROM unique_indexes AS other
+         WHERE other.index_name != idx.index_name
+           AND other.key_columns = fk.referenced_columns
+       );
+`, tableName.String())
+}
+
+// columnIsInPartialIndexPredicate returns whether the column is referenced
+// by the predicate of any partial index on the table, which prevents the
+// column from being dropped.
+func (og *operationGenerator) columnIsInPartialIndexPredicate(
+	ctx context.Context, tx pgx.Tx, tableName *tree.TableName, columnName string,
+) (bool, error) {
+	return og.scanBool(ctx, tx, `
+SELECT EXISTS(
+        SELECT indexrelid
+          FROM pg_catalog.pg_index
+         WHERE indrelid = $1::REGCLASS
+           AND indpred IS NOT NULL
+           AND indpred ~ $2
+       );
+`, tableName.String(), `\b`+regexp.QuoteMeta(columnName)+`\b`)
+}
+
+// columnIsDependedOnByView returns true if a view, or a function, refers to
+// the column.
+func (og *operationGenerator) columnIsDependedOnByView(
+	ctx context.Context, tx pgx.Tx, tableName *tree.TableName, columnName string,
+) (bool, error) {
+	return og.scanBool(ctx, tx, `
+SELECT EXISTS(
+        SELECT *
+          FROM (
+                SELECT unnest(
+                        string_to_array(
+                         rtrim(ltrim(fd.dependedonby_details, 'Columns: ['), ']'),
+                         ' '
+                        )::INT8[]
+                       ) AS column_id
+                  FROM crdb_internal.forward_dependencies AS fd
+                 WHERE fd.descriptor_id = $1::REGCLASS
+                   AND fd.dependedonby_type = 'view'
+               ) AS deps
+          JOIN information_schema.columns AS cols ON cols.ordinal_position = deps.column_id
+         WHERE cols.table_schema = $2
+           AND cols.table_name = $3
+           AND cols.column_name = $4
+       );
+`, tableName.String(), tableName.Schema(), tableName.Object(), columnName)
+}
+
+// constrainedColumns returns the columns of the given columns of a table whose
+// values may violate something when changed: the columns of the constraints
+// of the table and of the foreign keys referencing it, the columns of its
+// unique indexes, and the columns referenced by the expressions and
+// predicates of its indexes or by its computed columns.
+func (og *operationGenerator) constrainedColumns(
+	ctx context.Context, tx pgx.Tx, tableName *tree.TableName, cols []column,
+) ([]string, error) {
+	constrained, err := og.scanStringArray(ctx, tx, `
+SELECT COALESCE(array_agg(DISTINCT quote_ident(a.attname)), ARRAY[]::STRING[])
+  FROM pg_catalog.pg_attribute AS a
+ WHERE a.attrelid = $1::REGCLASS
+   AND (
+        EXISTS(
+               SELECT *
+                 FROM pg_catalog.pg_constraint AS c
+                WHERE (c.conrelid = a.attrelid AND a.attnum = ANY (c.conkey))
+                   OR (c.confrelid = a.attrelid AND a.attnum = ANY (c.confkey))
+              )
+        OR EXISTS(
+                  SELECT *
+                    FROM pg_catalog.pg_index AS i
+                   WHERE i.indrelid = a.attrelid
+                     AND i.indisunique
+                     AND a.attnum = ANY (i.indkey)
+                 )
+       )
+`, tableName.String())
+	if err != nil {
+		return nil, err
+	}
+	exprs, err := og.scanStringArray(ctx, tx, `
+SELECT COALESCE(array_agg(expr), ARRAY[]::STRING[])
+  FROM (
+        SELECT indexprs AS expr FROM pg_catalog.pg_index WHERE indrelid = $1::REGCLASS
+        UNION ALL SELECT indpred FROM pg_catalog.pg_index WHERE indrelid = $1::REGCLASS
+       )
+ WHERE expr IS NOT NULL
+`, tableName.String())
+	if err != nil {
+		return nil, err
+	}
+	for _, col := range cols {
+		if col.generated {
+			exprs = append(exprs, col.generatedExpression)
+		}
+	}
+	for _, col := range cols {
+		if slices.Contains(constrained, col.name) {
+			continue
+		}
+		re := regexp.MustCompile(`\b` + regexp.QuoteMeta(col.name) + `\b`)
+		if slices.ContainsFunc(exprs, re.MatchString) {
+			constrained = append(constrained, col.name)
+		}
+	}
+	return constrained, nil
+}
+
+// columnIsInComputedExpression returns true if the expression of another,
+// computed, column of the table refers to the column.
+func (og *operationGenerator) columnIsInComputedExpression(
+	ctx context.Context, tx pgx.Tx, tableName *tree.TableName, columnName string,
+) (bool, error) {
+	return og.scanBool(ctx, tx, `
+SELECT EXISTS(
+        SELECT *
+          FROM information_schema.columns
+         WHERE table_schema = $1
+           AND table_name = $2
+           AND column_name != $3
+           AND generation_expression ~ $4
+       );
+`, tableName.Schema(), tableName.Object(), columnName, `\b`+regexp.QuoteMeta(columnName)+`\b`)
+}
+
+// columnIsIdentity returns true if the column is a GENERATED ... AS IDENTITY
+// column.
+func (og *operationGenerator) columnIsIdentity(
+	ctx context.Context, tx pgx.Tx, tableName *tree.TableName, columnName string,
+) (bool, error) {
+	return og.scanBool(ctx, tx, `
+SELECT EXISTS(
+        SELECT *
+          FROM information_schema.columns
+         WHERE table_schema = $1
+           AND table_name = $2
+           AND column_name = $3
+           AND is_identity = 'YES'
+       );
+`, tableName.Schema(), tableName.Object(), columnName)
+}
+
+// columnHasForeignKeyUpdateAction returns true if the column is part of a
+// foreign key whose ON UPDATE action changes it, in which case the column
+// can't have an ON UPDATE expression.
+func (og *operationGenerator) columnHasForeignKeyUpdateAction(
+	ctx context.Context, tx pgx.Tx, tableName *tree.TableName, columnName string,
+) (bool, error) {
+	return og.scanBool(ctx, tx, `
+SELECT EXISTS(
+        SELECT *
+          FROM pg_catalog.pg_constraint AS con
+          JOIN pg_catalog.pg_attribute AS att ON att.attrelid = con.conrelid
+                                             AND att.attnum = ANY (con.conkey)
+         WHERE con.conrelid = $1::REGCLASS
+           AND con.contype = 'f'
+           AND con.confupdtype NOT IN ('a', 'r')
+           AND att.attname = $2
+       );
+`, tableName.String(), columnName)
+}
+
+// columnIsInTTLExpirationExpression returns true if the row-level TTL
+// ttl_expiration_expression of the table refers to the column.
+func (og *operationGenerator) columnIsInTTLExpirationExpression(
+	ctx context.Context, tx pgx.Tx, tableName *tree.TableName, columnName string,
+) (bool, error) {
+	return og.scanBool(ctx, tx, `
+SELECT COALESCE(
+        crdb_internal.pb_to_json(
+            'cockroach.sql.sqlbase.Descriptor',
+            descriptor
+        )->'table'->'rowLevelTtl'->>'expirationExpr',
+        ''
+       ) ~ $2
+  FROM system.descriptor
+ WHERE id = $1::REGCLASS;
+`, tableName.String(), `\b`+regexp.QuoteMeta(columnName)+`\b`)
+}
+
+// columnIsTTLExpireAfterColumn returns true if the column is the
+// crdb_internal_expiration column maintained by the ttl_expire_after of the
+// table, which can't be altered, renamed or dropped while it is set.
+func (og *operationGenerator) columnIsTTLExpireAfterColumn(
+	ctx context.Context, tx pgx.Tx, tableName *tree.TableName, columnName string,
+) (bool, error) {
+	if columnName != catpb.TTLDefaultExpirationColumnName {
+		return false, nil
+	}
+	return og.scanBool(ctx, tx, `
+SELECT COALESCE(
+        crdb_internal.pb_to_json(
+            'cockroach.sql.sqlbase.Descriptor',
+            descriptor
+        )->'table'->'rowLevelTtl'->>'durationExpr',
+        ''
+       ) != ''
+  FROM system.descriptor
+ WHERE id = $1::REGCLASS;
+`, tableName.String())
+}
+
 // A pair of CTE definitions that expect the first argument to be a table name.
 const descriptorsAndConstraintMutationsCTE = `descriptors AS (
                     SELECT crdb_internal.pb_to_json(
@@ -1296,6 +1987,25 @@ SELECT true
 `, tableName.String(), constraintName)
 }
 
+// constraintInMutation returns whether the named constraint is being added or
+// dropped on the table, which is the case when it was added or dropped
+// earlier in the transaction.
+func (og *operationGenerator) constraintInMutation(
+	ctx context.Context, tx pgx.Tx, tableName *tree.TableName, constraintName string,
+) (bool, error) {
+	return og.scanBool(ctx, tx, `
+  WITH `+descriptorsAndConstraintMutationsCTE+`
+SELECT true
+       IN (
+            SELECT (t.f).value @> json_set('{}', ARRAY['name'], to_json($2:::STRING))
+              FROM (
+                    SELECT json_each(mut->'constraint') AS f
+                      FROM constraint_mutations
+                   ) AS t
+        );
+`, tableName.String(), constraintName)
+}
+
 func (og *operationGenerator) columnNotNullConstraintInMutation(
 	ctx context.Context, tx pgx.Tx, tableName *tree.TableName, columnName string,
 ) (bool, error) {
@@ -1424,6 +2134,29 @@ WHERE
 	)
 }
 
+// tableHasOngoingRowLevelTTLChange returns whether row-level TTL is being
+// added to or removed from the table.
+func (og *operationGenerator) tableHasOngoingRowLevelTTLChange(
+	ctx context.Context, tx pgx.Tx, tableName *tree.TableName,
+) (bool, error) {
+	return og.scanBool(ctx, tx, `
+SELECT EXISTS(
+        SELECT *
+          FROM system.descriptor,
+               jsonb_array_elements(
+                COALESCE(
+                    crdb_internal.pb_to_json(
+                        'cockroach.sql.sqlbase.Descriptor',
+                        descriptor
+                    )->'table'->'mutations',
+                    '[]'
+                )
+               ) AS m
+         WHERE id = $1::REGCLASS AND m ? 'modifyRowLevelTtl'
+       );
+`, tableName.String())
+}
+
 // tableHasOngoingAlterPKSchemaChanges checks whether a given table has an ALTER
 // PRIMARY KEY related change in progress.
 func (og *operationGenerator) tableHasOngoingAlterPKSchemaChanges(
@@ -1599,6 +2332,79 @@ SELECT EXISTS (
 	)
 }
 
+// regionInTransition determines whether the region is still being added to or
+// dropped from the database.
+func (og *operationGenerator) regionInTransition(
+	ctx context.Context, tx pgx.Tx, region tree.Name,
+) (bool, error) {
+	return og.scanBool(ctx, tx, With([]CTE{
+		{"descriptors", descJSONQuery},
+		{"enums", enumDescsQuery},
+		{"enum_members", enumMemberDescsQuery},
+	}, `SELECT EXISTS(
+		SELECT 1 FROM enum_members
+		WHERE id = ('public.crdb_internal_region'::REGTYPE::INT8 - 100000)
+		AND member->>'logicalRepresentation' = $1
+		AND COALESCE(member->>'direction', 'NONE') <> 'NONE'
+	)`), string(region))
+}
+
+// enumValueIsReadOnly determines whether value, an enum value formatted by
+// randColumnValue, is a member of the enum type that is still being added or
+// dropped, and can't be written.
+func (og *operationGenerator) enumValueIsReadOnly(
+	ctx context.Context, tx pgx.Tx, typ *types.T, value string,
+) (bool, error) {
+	expr, err := parser.ParseExpr(value)
+	if err != nil {
+		return false, err
+	}
+	// NULL isn't annotated with the enum type.
+	annotated, ok := expr.(*tree.AnnotateTypeExpr)
+	if !ok {
+		return false, nil
+	}
+	label, ok := annotated.Expr.(*tree.StrVal)
+	if !ok {
+		return false, nil
+	}
+	return og.scanBool(ctx, tx, With([]CTE{
+		{"descriptors", descJSONQuery},
+		{"enums", enumDescsQuery},
+		{"enum_members", enumMemberDescsQuery},
+	}, `SELECT EXISTS(
+		SELECT 1 FROM enum_members
+		WHERE id = ($1::REGTYPE::INT8 - 100000)
+		AND member->>'logicalRepresentation' = $2
+		AND COALESCE(member->>'capability', 'ALL') = 'READ_ONLY'
+	)`), typ.SQLString(), label.RawString())
+}
+
+// databaseSurvivesRegionFailure determines whether the database is configured
+// to survive a region failure.
+func (og *operationGenerator) databaseSurvivesRegionFailure(
+	ctx context.Context, tx pgx.Tx, database string,
+) (bool, error) {
+	return og.scanBool(ctx, tx, fmt.Sprintf(
+		`SELECT COALESCE(survival_goal = 'region', false) FROM [SHOW SURVIVAL GOAL FROM DATABASE %q]`,
+		database,
+	))
+}
+
+// regionHomesTable determines whether any REGIONAL BY TABLE table in the
+// current database is explicitly homed in the given region.
+func (og *operationGenerator) regionHomesTable(
+	ctx context.Context, tx pgx.Tx, region tree.Name,
+) (bool, error) {
+	return og.scanBool(ctx, tx, With([]CTE{
+		{"descriptors", descJSONQuery},
+		{"tables", tableDescQuery},
+	}, `SELECT EXISTS(
+		SELECT 1 FROM tables
+		WHERE descriptor->'table'->'localityConfig'->'regionalByTable'->>'region' = $1
+	)`), string(region))
+}
+
 // databaseHasRegionalByRowChange checks whether a given database has any tables
 // which are currently undergoing a change to or from REGIONAL BY ROW, or
 // REGIONAL BY ROW tables with schema changes on it.
@@ -1677,6 +2483,20 @@ func (og *operationGenerator) databaseHasTablesWithPartitioning(
 	)
 }
 
+// isCCLBuildQuery returns whether the node runs a CCL binary, which is
+// required to partition indexes.
+const isCCLBuildQuery = `SELECT value LIKE 'CockroachDB CCL %'
+  FROM crdb_internal.node_build_info
+ WHERE field = 'Build'`
+
+// clusterSupportsPartitioning returns true if indexes can be partitioned,
+// which requires a CCL binary.
+func (og *operationGenerator) clusterSupportsPartitioning(
+	ctx context.Context, tx pgx.Tx,
+) (bool, error) {
+	return og.scanBool(ctx, tx, isCCLBuildQuery)
+}
+
 // getUniqueConstraintsForTable returns the set of expressions associated with unique indexes
 // in the specified tableName.
 func getUniqueConstraintsForTable(
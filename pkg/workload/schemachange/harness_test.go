@@ -0,0 +1,214 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package schemachange
+
+import (
+	"context"
+	gosql "database/sql"
+	"net/url"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/multiregionccl/multiregionccltestutils"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+	"github.com/cockroachdb/errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// generatorTestHarness runs the statements of an operationGenerator against
+// a test server, each in its own transaction like the statements generated
+// by randOp.
+type generatorTestHarness struct {
+	t   *testing.T
+	ctx context.Context
+	srv serverutils.TestServerInterface
+	// tdb runs statements outside of the generator, to set up and inspect
+	// the state the generator works on.
+	tdb *sqlutils.SQLRunner
+	// conn is the connection the generated statements run on.
+	conn *pgx.Conn
+	og   *operationGenerator
+	// commitOutcome is the code the last transaction committed by runInTxn
+	// ended with.
+	commitOutcome pgcode.Code
+}
+
+// newGeneratorTestHarness starts a test server, runs the setup statements on
+// it and connects to it. The setup statements run before the connection is
+// opened, so that the cluster settings they set apply to its session. params
+// is given a random number generator and an empty role pool if it has none.
+// The returned function
+// closes the connection and stops the server, and must be deferred by the
+// test.
+func newGeneratorTestHarness(
+	t *testing.T, params *operationGeneratorParams, setup ...string,
+) (*generatorTestHarness, func()) {
+	srv, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	return connectGeneratorTestHarness(t, srv, sqlDB, params, setup, func() {
+		srv.Stopper().Stop(context.Background())
+	})
+}
+
+// newMultiRegionGeneratorTestHarness is newGeneratorTestHarness for a cluster
+// of numRegions nodes, each in its own region named "us-east1", "us-east2",
+// and so on. The generated statements run on the first node.
+func newMultiRegionGeneratorTestHarness(
+	t *testing.T, numRegions int, params *operationGeneratorParams, setup ...string,
+) (*generatorTestHarness, func()) {
+	tc, sqlDB, cleanup := multiregionccltestutils.TestingCreateMultiRegionCluster(
+		t, numRegions, base.TestingKnobs{},
+	)
+	return connectGeneratorTestHarness(t, tc.Server(0), sqlDB, params, setup, cleanup)
+}
+
+// connectGeneratorTestHarness runs the setup statements on srv and connects
+// to it. stop is called by the returned function once the connection is
+// closed.
+func connectGeneratorTestHarness(
+	t *testing.T,
+	srv serverutils.TestServerInterface,
+	sqlDB *gosql.DB,
+	params *operationGeneratorParams,
+	setup []string,
+	stop func(),
+) (*generatorTestHarness, func()) {
+	ctx := context.Background()
+	tdb := sqlutils.MakeSQLRunner(sqlDB)
+	for _, stmt := range setup {
+		tdb.Exec(t, stmt)
+	}
+
+	pgURL, cleanupURL := sqlutils.PGUrl(
+		t, srv.ApplicationLayer().AdvSQLAddr(), t.Name(), url.User(username.RootUser),
+	)
+	conn, err := pgx.Connect(ctx, pgURL.String())
+	require.NoError(t, err)
+
+	if params.rng == nil {
+		params.rng, _ = randutil.NewTestRand()
+	}
+	if params.roles == nil {
+		params.roles = newRolePool()
+	}
+	h := &generatorTestHarness{
+		t:    t,
+		ctx:  ctx,
+		srv:  srv,
+		tdb:  tdb,
+		conn: conn,
+		og:   makeOperationGenerator(params),
+	}
+	return h, func() {
+		require.NoError(t, conn.Close(ctx))
+		cleanupURL()
+		stop()
+	}
+}
+
+// begin resets the state of the generator for a new transaction using the
+// legacy schema changer, and begins it.
+func (h *generatorTestHarness) begin() pgx.Tx {
+	h.og.resetTxnState()
+	h.og.resetOpState(false /* useDeclarativeSchemaChanger */)
+	tx, err := h.conn.Begin(h.ctx)
+	require.NoError(h.t, err)
+	return tx
+}
+
+// runInTxn generates a statement with gen in its own transaction and
+// executes it, which fails the test if the errors predicted for it are
+// wrong. The transaction is committed if commit is set and the statement
+// succeeded, which fails the test if the commit errors predicted for it are
+// wrong, and is rolled back otherwise. Nil is returned if gen had no
+// statement to generate.
+func (h *generatorTestHarness) runInTxn(
+	gen func(context.Context, pgx.Tx) (*opStmt, error), commit bool,
+) *opStmt {
+	tx := h.begin()
+	stmt, err := gen(h.ctx, tx)
+	if errors.Is(err, pgx.ErrNoRows) {
+		require.NoError(h.t, tx.Rollback(h.ctx))
+		return nil
+	}
+	require.NoError(h.t, err)
+	h.og.stmtsInTxt = append(h.og.stmtsInTxt, stmt)
+	h.og.expectedCommitErrors.merge(h.og.candidateExpectedCommitErrors)
+	if err := stmt.executeStmt(h.ctx, tx, h.og); err != nil {
+		require.Truef(h.t, errors.Is(err, errRunInTxnRbkSentinel), "%+v", err)
+		require.NoError(h.t, tx.Rollback(h.ctx))
+		return stmt
+	}
+	if commit {
+		err := tx.Commit(h.ctx)
+		h.commitOutcome = opLogOutcome(err)
+		if err == nil {
+			require.Truef(h.t, h.og.expectedCommitErrors.empty(),
+				"expected commit errors %v", h.og.expectedCommitErrors.StringSlice())
+			h.og.txnCommitted()
+		} else {
+			require.Truef(h.t,
+				h.og.expectedCommitErrors.contains(h.commitOutcome) ||
+					h.og.potentialCommitErrors.contains(h.commitOutcome),
+				"unexpected commit error: %+v", err)
+		}
+	} else {
+		require.NoError(h.t, tx.Rollback(h.ctx))
+	}
+	return stmt
+}
+
+// run is runInTxn, committing the statements that succeed.
+func (h *generatorTestHarness) run(gen func(context.Context, pgx.Tx) (*opStmt, error)) *opStmt {
+	return h.runInTxn(gen, true /* commit */)
+}
+
+// outcomes generates and executes n statements with gen like runInTxn,
+// rolling each of them back so that every statement sees the same state, and
+// returns how many ended with each code. Each statement fails the test if the
+// errors predicted for it are wrong.
+func (h *generatorTestHarness) outcomes(
+	gen func(context.Context, pgx.Tx) (*opStmt, error), n int,
+) map[pgcode.Code]int {
+	counts := map[pgcode.Code]int{}
+	for i := 0; i < n; i++ {
+		if stmt := h.runInTxn(gen, false /* commit */); stmt != nil {
+			counts[stmt.outcome]++
+		}
+	}
+	return counts
+}
+
+// validate validates the descriptors in its own transaction, like the
+// validate operation.
+func (h *generatorTestHarness) validate() error {
+	h.og.resetTxnState()
+	tx, err := h.conn.Begin(h.ctx)
+	require.NoError(h.t, err)
+	defer func() { require.NoError(h.t, tx.Rollback(h.ctx)) }()
+	_, err = h.og.validate(h.ctx, tx)
+	return err
+}
+
+// publicTableName returns the name of a table of the public schema, qualified
+// like the names the generator picks.
+func publicTableName(name string) *tree.TableName {
+	tn := tree.MakeTableNameFromPrefix(tree.ObjectNamePrefix{
+		SchemaName:     "public",
+		ExplicitSchema: true,
+	}, tree.Name(name))
+	return &tn
+}
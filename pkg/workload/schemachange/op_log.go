@@ -0,0 +1,318 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package schemachange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// The operations ending a transaction in the operation log.
+const (
+	opLogCommit   = "COMMIT"
+	opLogRollback = "ROLLBACK"
+)
+
+// opLogEntry is a line of the operation log written with --op-log. Every
+// statement executed by a worker gets an entry, and so does the end of its
+// transaction, whose op is either opLogCommit or opLogRollback. The entries of
+// a transaction are written together once it ends, so transactions appear in
+// the order in which they finished, and can be replayed with --op-replay.
+type opLogEntry struct {
+	WorkerID int `json:"workerId"`
+	// Txn numbers the transactions run by the worker.
+	Txn         int    `json:"txn"`
+	Declarative bool   `json:"declarative"`
+	TimeZone    string `json:"timeZone"`
+	// Implicit is set if the statement of the transaction ran on its own,
+	// outside of an explicit transaction, like those of implicitTxnOps.
+	Implicit bool `json:"implicit,omitempty"`
+	// Op is the name of the opType that generated the statement.
+	Op  string `json:"op"`
+	SQL string `json:"sql,omitempty"`
+	// ExpectedErrors and PotentialErrors are the errors predicted for the
+	// statement, or for the commit of the transaction.
+	ExpectedErrors  []string `json:"expectedErrors,omitempty"`
+	PotentialErrors []string `json:"potentialErrors,omitempty"`
+	// Outcome is the error code returned by the statement or the commit, which
+	// is pgcode.SuccessfulCompletion if there was no error. It is empty if the
+	// statement was not executed, or failed without an error code.
+	Outcome string `json:"outcome"`
+}
+
+// opLogger collects the entries of the transaction being run by a worker, and
+// writes them to the operation log once it ends, if log is set. A nil
+// *opLogger logs nothing.
+type opLogger struct {
+	log      *atomicLog
+	workerID int
+	txn      int
+
+	declarative bool
+	timeZone    string
+	implicit    bool
+	ops         []opType
+	stmts       []*opStmt
+}
+
+// startTxn starts collecting the entries of a new transaction.
+func (l *opLogger) startTxn(declarative bool, timeZone string) {
+	if l == nil {
+		return
+	}
+	l.txn++
+	l.declarative = declarative
+	l.timeZone = timeZone
+	l.implicit = false
+	l.ops = nil
+	l.stmts = nil
+}
+
+// startImplicitTxn starts collecting the entries of a statement that runs on
+// its own, outside of an explicit transaction, with the legacy schema changer.
+func (l *opLogger) startImplicitTxn(timeZone string) {
+	if l == nil {
+		return
+	}
+	l.startTxn(false /* declarative */, timeZone)
+	l.implicit = true
+}
+
+// logStmt adds a statement to the current transaction. Its outcome is read
+// once the transaction ends, so it may be logged before being executed.
+func (l *opLogger) logStmt(op opType, stmt *opStmt) {
+	if l == nil {
+		return
+	}
+	l.ops = append(l.ops, op)
+	l.stmts = append(l.stmts, stmt)
+}
+
+// endTxn writes the entries of the current transaction, which ended with the
+// given commit or rollback, predicted errors and error, and returns them.
+func (l *opLogger) endTxn(
+	end string, expected, potential errorCodeSet, err error,
+) (entries []opLogEntry) {
+	if l == nil {
+		return nil
+	}
+	entry := func(op string) opLogEntry {
+		return opLogEntry{
+			WorkerID:    l.workerID,
+			Txn:         l.txn,
+			Declarative: l.declarative,
+			TimeZone:    l.timeZone,
+			Implicit:    l.implicit,
+			Op:          op,
+		}
+	}
+	for i, stmt := range l.stmts {
+		e := entry(l.ops[i].String())
+		e.SQL = stmt.sql
+		e.ExpectedErrors = stmt.expectedExecErrors.StringSlice()
+		e.PotentialErrors = stmt.potentialExecErrors.StringSlice()
+		e.Outcome = stmt.outcome.String()
+		entries = append(entries, e)
+	}
+	e := entry(end)
+	e.ExpectedErrors = expected.StringSlice()
+	e.PotentialErrors = potential.StringSlice()
+	e.Outcome = opLogOutcome(err).String()
+	entries = append(entries, e)
+	l.ops = nil
+	l.stmts = nil
+	if l.log == nil {
+		return entries
+	}
+	lines := make([]string, 0, len(entries))
+	for _, e := range entries {
+		b, err := json.Marshal(e)
+		if err != nil {
+			panic(errors.NewAssertionErrorWithWrappedErrf(err, "encoding operation log entry"))
+		}
+		lines = append(lines, string(b))
+	}
+	// The transaction is written at once, so that the entries of transactions
+	// run concurrently by other workers are not interleaved with it.
+	l.log.printLn(strings.Join(lines, "\n"))
+	return entries
+}
+
+// opLogOutcome returns the error code of err, pgcode.SuccessfulCompletion if
+// it is nil, or an empty code if it has none.
+func opLogOutcome(err error) pgcode.Code {
+	if err == nil {
+		return pgcode.SuccessfulCompletion
+	}
+	if pgErr := new(pgconn.PgError); errors.As(err, &pgErr) {
+		return commitErrorCode(pgErr)
+	}
+	return pgcode.Code{}
+}
+
+var underlyingErrorCodeRegex = regexp.MustCompile(`\([A-Z0-9]{5}\)`)
+
+// commitErrorCode returns the error code of an error returned when committing
+// a transaction. Schema changes that fail after the transaction committed
+// return pgcode.TransactionCommittedWithSchemaChangeFailure, in which case the
+// code of the underlying error is parsed from the message.
+func commitErrorCode(pgErr *pgconn.PgError) pgcode.Code {
+	if pgErr.Code == pgcode.TransactionCommittedWithSchemaChangeFailure.String() {
+		if code := underlyingErrorCodeRegex.FindString(pgErr.Error()); code != "" {
+			return pgcode.MakeCode(code[1 : len(code)-1])
+		}
+	}
+	return pgcode.MakeCode(pgErr.Code)
+}
+
+// opLogConn is the part of a connection used to replay an operation log.
+type opLogConn interface {
+	opStmtExecutor
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// replayOpLog re-issues the statements of an operation log, one transaction
+// at a time and in the order in which they were logged. Each statement is
+// checked against the errors predicted when it was generated, like it was
+// in the original run, and its outcome must also match the logged one. Any
+// divergence is returned as an error. Transactions that hit a serialization
+// failure are skipped, since they depend on the concurrent transactions.
+func replayOpLog(ctx context.Context, conn opLogConn, r io.Reader) (numTxns int, _ error) {
+	dec := json.NewDecoder(r)
+	var txn []opLogEntry
+	for line := 1; ; line++ {
+		var e opLogEntry
+		if err := dec.Decode(&e); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return numTxns, errors.Wrapf(err, "reading operation log entry %d", line)
+		}
+		txn = append(txn, e)
+		if e.Op != opLogCommit && e.Op != opLogRollback {
+			continue
+		}
+		if err := replayOpLogTxn(ctx, conn, txn); err != nil {
+			return numTxns, errors.Wrapf(err, "replaying transaction %d of worker %d, ending at entry %d",
+				e.Txn, e.WorkerID, line)
+		}
+		txn = nil
+		numTxns++
+	}
+	if len(txn) > 0 {
+		return numTxns, errors.Newf("operation log ends in the middle of transaction %d of worker %d",
+			txn[0].Txn, txn[0].WorkerID)
+	}
+	return numTxns, nil
+}
+
+// replayOpLogTxn replays the entries of a single transaction, the last of
+// which ends it.
+func replayOpLogTxn(ctx context.Context, conn opLogConn, txn []opLogEntry) error {
+	for _, e := range txn {
+		if e.Outcome == pgcode.SerializationFailure.String() {
+			return nil
+		}
+	}
+	end := txn[len(txn)-1]
+	schemaChanger := "off"
+	if end.Declarative {
+		schemaChanger = "unsafe_always"
+	}
+	if _, err := conn.Exec(ctx, fmt.Sprintf("SET use_declarative_schema_changer = '%s'", schemaChanger)); err != nil {
+		return err
+	}
+	if _, err := conn.Exec(ctx, fmt.Sprintf("SET TIME ZONE '%s'", end.TimeZone)); err != nil {
+		return err
+	}
+
+	og := makeOperationGenerator(&operationGeneratorParams{})
+	og.resetOpState(end.Declarative)
+	if end.Implicit {
+		return replayOpLogStmts(ctx, conn, og, txn[:len(txn)-1])
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := replayOpLogStmts(ctx, tx, og, txn[:len(txn)-1]); err != nil {
+		return err
+	}
+	if end.Op == opLogRollback {
+		return tx.Rollback(ctx)
+	}
+
+	og.expectedCommitErrors = makeErrorCodeSet(end.ExpectedErrors)
+	og.potentialCommitErrors = makeErrorCodeSet(end.PotentialErrors)
+	err = tx.Commit(ctx)
+	outcome := opLogOutcome(err)
+	if end.Outcome != "" && outcome.String() != end.Outcome {
+		return errors.WithSecondaryError(
+			errors.Newf("commit returned %q instead of %q", outcome, end.Outcome), err)
+	}
+	switch {
+	case err == nil && !og.expectedCommitErrors.empty():
+		return og.WrapWithErrorState(
+			errors.New("***FAIL; Failed to receive a commit error when at least one commit error was expected"),
+			&opStmt{},
+		)
+	case err != nil && !og.expectedCommitErrors.contains(outcome) && !og.potentialCommitErrors.contains(outcome):
+		return og.WrapWithErrorState(
+			errors.Wrap(err, "***UNEXPECTED COMMIT ERROR; Received an unexpected commit error"),
+			&opStmt{},
+		)
+	}
+	return nil
+}
+
+// replayOpLogStmts replays the statements of a transaction, without the entry
+// ending it, on db.
+func replayOpLogStmts(
+	ctx context.Context, db opStmtExecutor, og *operationGenerator, stmts []opLogEntry,
+) error {
+	for _, e := range stmts {
+		stmt := &opStmt{
+			sql:                 e.SQL,
+			expectedExecErrors:  makeErrorCodeSet(e.ExpectedErrors),
+			potentialExecErrors: makeErrorCodeSet(e.PotentialErrors),
+		}
+		err := stmt.executeStmt(ctx, db, og)
+		if e.Outcome != "" && stmt.outcome.String() != e.Outcome {
+			return errors.WithSecondaryError(
+				errors.Newf("statement %q returned %q instead of %q", e.SQL, stmt.outcome, e.Outcome), err)
+		}
+		if err != nil && !errors.Is(err, errRunInTxnRbkSentinel) {
+			return err
+		}
+		og.stmtsInTxt = append(og.stmtsInTxt, stmt)
+	}
+	return nil
+}
+
+// makeErrorCodeSet returns a set of the given error codes.
+func makeErrorCodeSet(codes []string) errorCodeSet {
+	set := makeExpectedErrorSet()
+	for _, code := range codes {
+		set.add(pgcode.MakeCode(code))
+	}
+	return set
+}
@@ -0,0 +1,192 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package schemachange
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOpLogReplay runs a few transactions while writing them to an operation
+// log, and checks that replaying the log against an empty database reproduces
+// them, while replaying it against a database in a different state fails.
+func TestOpLogReplay(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	srv, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer srv.Stopper().Stop(ctx)
+
+	tdb := sqlutils.MakeSQLRunner(sqlDB)
+	tdb.Exec(t, `CREATE DATABASE original`)
+	tdb.Exec(t, `CREATE DATABASE replay`)
+	tdb.Exec(t, `CREATE DATABASE skipped`)
+
+	pgURL, cleanup := sqlutils.PGUrl(
+		t, srv.ApplicationLayer().AdvSQLAddr(), t.Name(), url.User(username.RootUser),
+	)
+	defer cleanup()
+	var conns []*pgx.Conn
+	defer func() {
+		for _, conn := range conns {
+			require.NoError(t, conn.Close(ctx))
+		}
+	}()
+	connect := func(db string) *pgx.Conn {
+		dbURL := pgURL
+		dbURL.Path = db
+		conn, err := pgx.Connect(ctx, dbURL.String())
+		require.NoError(t, err)
+		conns = append(conns, conn)
+		return conn
+	}
+
+	var buf bytes.Buffer
+	opLog := &opLogger{log: makeAtomicLog(&buf), workerID: 3}
+
+	// runTxn runs the statements in a transaction, like a worker would, and
+	// writes them to the operation log.
+	type loggedStmt struct {
+		op       opType
+		sql      string
+		expected []pgcode.Code
+	}
+	conn := connect("original")
+	runTxn := func(declarative bool, stmts ...loggedStmt) {
+		og := makeOperationGenerator(&operationGeneratorParams{})
+		og.resetTxnState()
+		og.resetOpState(declarative)
+		schemaChanger := "off"
+		if declarative {
+			schemaChanger = "unsafe_always"
+		}
+		_, err := conn.Exec(ctx, "SET use_declarative_schema_changer = '"+schemaChanger+"'")
+		require.NoError(t, err)
+		_, err = conn.Exec(ctx, "SET TIME ZONE 'Asia/Kolkata'")
+		require.NoError(t, err)
+		opLog.startTxn(declarative, "Asia/Kolkata")
+
+		tx, err := conn.Begin(ctx)
+		require.NoError(t, err)
+		for _, s := range stmts {
+			stmt := makeOpStmtForSingleError(OpStmtDDL, s.sql, s.expected...)
+			opLog.logStmt(s.op, stmt)
+			if err := stmt.executeStmt(ctx, tx, og); err != nil {
+				require.Truef(t, errors.Is(err, errRunInTxnRbkSentinel), "%+v", err)
+				require.NoError(t, tx.Rollback(ctx))
+				opLog.endTxn(opLogRollback, nil, nil, err)
+				return
+			}
+		}
+		err = tx.Commit(ctx)
+		require.NoError(t, err)
+		opLog.endTxn(opLogCommit, og.expectedCommitErrors, og.potentialCommitErrors, err)
+	}
+
+	runTxn(false, /* declarative */
+		loggedStmt{op: createTable, sql: `CREATE TABLE t (a INT8 PRIMARY KEY, b TIMESTAMPTZ)`},
+		loggedStmt{op: insertRow, sql: `INSERT INTO t VALUES (1, '2024-01-01 00:00:00')`},
+	)
+	runTxn(true, /* declarative */
+		loggedStmt{op: createIndex, sql: `CREATE INDEX ON t (b)`},
+	)
+	runTxn(false, /* declarative */
+		loggedStmt{op: insertRow, sql: `INSERT INTO t VALUES (2, NULL)`},
+		loggedStmt{
+			op:       insertRow,
+			sql:      `INSERT INTO t VALUES (1, NULL)`,
+			expected: []pgcode.Code{pgcode.UniqueViolation},
+		},
+	)
+
+	// Every statement and the end of every transaction is logged as a line.
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 8)
+	var entries []opLogEntry
+	for _, line := range lines {
+		var e opLogEntry
+		require.NoError(t, json.Unmarshal([]byte(line), &e))
+		require.Equal(t, 3, e.WorkerID)
+		require.Equal(t, "Asia/Kolkata", e.TimeZone)
+		entries = append(entries, e)
+	}
+	require.Equal(t, opLogEntry{
+		WorkerID:    3,
+		Txn:         1,
+		TimeZone:    "Asia/Kolkata",
+		Op:          "insertRow",
+		SQL:         `INSERT INTO t VALUES (1, '2024-01-01 00:00:00')`,
+		Outcome:     pgcode.SuccessfulCompletion.String(),
+		Declarative: false,
+	}, entries[1])
+	require.Equal(t, opLogCommit, entries[2].Op)
+	require.True(t, entries[3].Declarative)
+	require.Equal(t, opLogEntry{
+		WorkerID:       3,
+		Txn:            3,
+		TimeZone:       "Asia/Kolkata",
+		Op:             "insertRow",
+		SQL:            `INSERT INTO t VALUES (1, NULL)`,
+		ExpectedErrors: []string{pgcode.UniqueViolation.String()},
+		Outcome:        pgcode.UniqueViolation.String(),
+	}, entries[6])
+	require.Equal(t, opLogRollback, entries[7].Op)
+
+	// Replaying the log against an empty database reproduces the
+	// transactions, including the one that was rolled back.
+	numTxns, err := replayOpLog(ctx, connect("replay"), strings.NewReader(buf.String()))
+	require.NoError(t, err)
+	require.Equal(t, 3, numTxns)
+	tdb.CheckQueryResults(t, `SELECT a FROM replay.t`, [][]string{{"1"}})
+	tdb.CheckQueryResults(t,
+		`SELECT count(DISTINCT index_name) FROM [SHOW INDEXES FROM replay.t] WHERE index_name != 't_pkey'`,
+		[][]string{{"1"}},
+	)
+
+	// Replaying it again diverges, as the table already exists.
+	_, err = replayOpLog(ctx, connect("replay"), strings.NewReader(buf.String()))
+	require.ErrorContains(t, err, `returned "42P07" instead of "00000"`)
+	require.ErrorContains(t, err, "transaction 1 of worker 3")
+
+	// Transactions that hit a serialization failure are skipped.
+	entries[1].Outcome = pgcode.SerializationFailure.String()
+	var skipped bytes.Buffer
+	enc := json.NewEncoder(&skipped)
+	for _, e := range entries[:3] {
+		require.NoError(t, enc.Encode(e))
+	}
+	_, err = replayOpLog(ctx, connect("skipped"), &skipped)
+	require.NoError(t, err)
+	tdb.CheckQueryResults(t,
+		`SELECT count(*) FROM [SHOW TABLES FROM skipped]`,
+		[][]string{{"0"}},
+	)
+
+	// A log cut in the middle of a transaction is rejected.
+	_, err = replayOpLog(ctx, connect("skipped"), strings.NewReader(lines[0]))
+	require.ErrorContains(t, err, "ends in the middle of transaction 1 of worker 3")
+}
@@ -15,6 +15,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"math/rand"
 	"regexp"
 	"slices"
@@ -32,15 +33,19 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/randgen"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachange"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/cast"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/lib/pq/oid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/robfig/cron/v3"
 )
 
 // All of the fields of operationGeneratorParams should only be accessed by
@@ -53,10 +58,19 @@ type operationGeneratorParams struct {
 	rng                *rand.Rand
 	ops                *deck
 	declarativeOps     *deck
+	soakBuildOps       *deck
+	dmlOps             *deck
 	maxSourceTables    int
 	sequenceOwnedByPct int
 	fkParentInvalidPct int
 	fkChildInvalidPct  int
+	wideTablePct       int
+	roles              *rolePool
+	// concurrency is the number of workers running statements, including the
+	// one this generator belongs to.
+	concurrency int
+	// opResults, if set, counts the results of the statements executed.
+	opResults *prometheus.CounterVec
 }
 
 // The OperationBuilder has the sole responsibility of generating ops
@@ -84,6 +98,46 @@ type operationGenerator struct {
 
 	// useDeclarativeSchemaChanger indices if the declarative schema changer is used.
 	useDeclarativeSchemaChanger bool
+
+	// rolesCreatedInTxn and rolesDroppedInTxn are the roles created and dropped
+	// by the current transaction, which are applied to params.roles once it
+	// commits.
+	rolesCreatedInTxn []string
+	rolesDroppedInTxn []string
+
+	// enumPlacementsInTxn are the enum values added next to an existing value
+	// by the current transaction, which are added to enumPlacements once it
+	// commits. validate checks that the committed values are still ordered as
+	// requested.
+	enumPlacementsInTxn []enumPlacement
+	enumPlacements      []enumPlacement
+
+	// ownerReassignmentsInTxn are the objects reassigned to another role by
+	// the current transaction, which are added to ownerReassignments once it
+	// commits. validate checks that the committed objects are owned by their
+	// new owner until they change again.
+	ownerReassignmentsInTxn []ownerReassignment
+	ownerReassignments      []ownerReassignment
+}
+
+// ownerReassignment is an object of the current database reassigned to a new
+// owner by REASSIGN OWNED BY. version is the version of the descriptor of the
+// object before it was reassigned.
+type ownerReassignment struct {
+	stmt    *opStmt
+	id      int64
+	version int64
+	owner   string
+}
+
+// enumPlacement is an enum value added BEFORE or AFTER an existing value of
+// the same type. The type is a quoted, qualified name and the values are
+// quoted literals, as in the generated statement.
+type enumPlacement struct {
+	typeName string
+	value    string
+	neighbor string
+	before   bool
 }
 
 // OpGenLogQuery a query with a single value result.
@@ -152,6 +206,66 @@ func (og *operationGenerator) resetTxnState() {
 	og.potentialCommitErrors.reset()
 	og.opsInTxn = nil
 	og.stmtsInTxt = nil
+	og.rolesCreatedInTxn = nil
+	og.rolesDroppedInTxn = nil
+	og.enumPlacementsInTxn = nil
+	og.ownerReassignmentsInTxn = nil
+}
+
+// txnCommitted applies the state tracked by the current transaction once it
+// committed.
+func (og *operationGenerator) txnCommitted() {
+	og.params.roles.apply(og.rolesCreatedInTxn, og.rolesDroppedInTxn)
+	og.enumPlacements = append(og.enumPlacements, og.enumPlacementsInTxn...)
+	// Reassignments are only checked if no other schema change of the
+	// transaction may have changed the same descriptors, so that each one is
+	// known to be at the version following the reassignment.
+	for _, r := range og.ownerReassignmentsInTxn {
+		if !slices.ContainsFunc(og.stmtsInTxt, func(stmt *opStmt) bool {
+			return stmt != r.stmt && stmt.queryType == OpStmtDDL
+		}) {
+			og.ownerReassignments = append(og.ownerReassignments, r)
+		}
+	}
+}
+
+// roles returns the roles known to exist from the point of view of the
+// current transaction.
+func (og *operationGenerator) roles() []string {
+	roles := util.Filter(og.params.roles.list(), func(role string) bool {
+		return !slices.Contains(og.rolesDroppedInTxn, role)
+	})
+	for _, role := range og.rolesCreatedInTxn {
+		if !slices.Contains(roles, role) && !slices.Contains(og.rolesDroppedInTxn, role) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// tableCreatedInTxn returns whether a statement of the current transaction
+// created the table.
+func (og *operationGenerator) tableCreatedInTxn(tableName *tree.TableName) bool {
+	return slices.ContainsFunc(og.stmtsInTxt, func(stmt *opStmt) bool {
+		parsed, err := parser.ParseOne(stmt.sql)
+		if err != nil {
+			return false
+		}
+		create, ok := parsed.AST.(*tree.CreateTable)
+		return ok && create.Table.String() == tableName.String()
+	})
+}
+
+// existingRoles returns the roles of roles() that exist as of the current
+// transaction. The pool only learns of the roles created and dropped by
+// other transactions once they commit, which may be after the current
+// transaction started.
+func (og *operationGenerator) existingRoles(ctx context.Context, tx pgx.Tx) ([]string, error) {
+	return og.scanStringArray(ctx, tx, `
+SELECT COALESCE(array_agg(role ORDER BY role), ARRAY[]::STRING[])
+  FROM unnest($1::STRING[]) AS role
+ WHERE EXISTS (SELECT * FROM system.users WHERE username = role)
+`, og.roles())
 }
 
 // getSupportedDeclarativeOp generates declarative operations until,
@@ -179,20 +293,28 @@ func (og *operationGenerator) getSupportedDeclarativeOp(
 // triple `(randOp, log, error)`. On success `randOp` is the random schema
 // change constructed. Constructing a random schema change may require a few
 // stochastic attempts and if verbosity is >= 2 the unsuccessful attempts are
-// recorded in `log` to help with debugging of the workload.
+// recorded in `log` to help with debugging of the workload. In soak mode, the
+// operations are drawn from the deck of the phase of the workload.
 func (og *operationGenerator) randOp(
-	ctx context.Context, tx pgx.Tx, useDeclarativeSchemaChanger bool,
+	ctx context.Context, tx pgx.Tx, useDeclarativeSchemaChanger bool, phase soakPhase,
 ) (stmt *opStmt, err error) {
 	for {
 		var op opType
 		// The declarative schema changer has a more limited deck of operations.
-		if useDeclarativeSchemaChanger {
+		if phase == soakBuilding {
+			op = opType(og.params.soakBuildOps.Int())
+		} else if phase == soakRunning {
+			op = opType(og.params.dmlOps.Int())
+		} else if useDeclarativeSchemaChanger {
 			op, err = og.getSupportedDeclarativeOp(ctx, tx)
 			if err != nil {
 				return nil, err
 			}
 		} else {
 			op = opType(og.params.ops.Int())
+			if slices.Contains(implicitTxnOps, op) {
+				continue
+			}
 		}
 		og.resetOpState(useDeclarativeSchemaChanger)
 		stmt, err = opFuncs[op](og, ctx, tx)
@@ -203,9 +325,9 @@ func (og *operationGenerator) randOp(
 				!errors.HasType(err, &pgconn.PgError{}) {
 				continue
 			}
-			// Table select had a primary key swap, so no statement
-			// generated.
-			if errors.Is(err, ErrSchemaChangesDisallowedDueToPkSwap) {
+			// Table select had a primary key swap or a row-level TTL
+			// change, so no statement generated.
+			if errors.IsAny(err, ErrSchemaChangesDisallowedDueToPkSwap, ErrSchemaChangesDisallowedDueToTTLChange) {
 				continue
 			}
 			return nil, err
@@ -252,6 +374,15 @@ func (og *operationGenerator) addColumn(ctx context.Context, tx pgx.Tx) (*opStmt
 	if err != nil {
 		return nil, err
 	}
+	// CIDR is a PostgreSQL network address type which is not implemented, so
+	// it should be rejected up front rather than resolved as a user-defined
+	// type.
+	unimplementedType := false
+	if og.produceError() && og.randIntn(10) == 0 {
+		cidrTypeName := tree.MakeUnqualifiedTypeName("cidr")
+		typName, typ = &cidrTypeName, nil
+		unimplementedType = true
+	}
 
 	def := &tree.ColumnTableDef{
 		Name: tree.Name(columnName),
@@ -272,6 +403,59 @@ func (og *operationGenerator) addColumn(ctx context.Context, tx pgx.Tx) (*opStmt
 		def.Unique.IsUnique = true
 	}
 
+	// Floats may default to NaN or +/-Infinity, which have their own encoding
+	// and sort order (NaN sorts before every other value). A constant default
+	// would be duplicated across all existing rows, so it is never combined
+	// with UNIQUE.
+	if typ != nil && typ.Family() == types.FloatFamily && !def.Unique.IsUnique && og.randIntn(3) == 0 {
+		specialValues := []string{"NaN", "Infinity", "-Infinity"}
+		def.DefaultExpr.Expr = &tree.CastExpr{
+			Expr:       tree.NewStrVal(specialValues[og.randIntn(len(specialValues))]),
+			Type:       typ,
+			SyntaxMode: tree.CastShort,
+		}
+	}
+
+	// INET values are ordered by family, then netmask, then address, and an
+	// IPv4-mapped IPv6 address is distinct from the IPv4 address it maps, so
+	// defaults cover either family, with and without a netmask. A constant
+	// default is never combined with UNIQUE.
+	if typ != nil && typ.Family() == types.INetFamily && !def.Unique.IsUnique && og.randIntn(3) == 0 {
+		def.DefaultExpr.Expr = &tree.CastExpr{
+			Expr:       tree.NewStrVal(inetDefaults[og.randIntn(len(inetDefaults))]),
+			Type:       typ,
+			SyntaxMode: tree.CastShort,
+		}
+	}
+
+	// Occasionally add a SERIAL column instead. Depending on serial_normalization
+	// it becomes an INT8 column defaulting to a row ID, or a column of the
+	// requested width defaulting to nextval() of a new sequence owned by it.
+	// SERIAL columns are implicitly NOT NULL, so when an error is requested the
+	// column is explicitly made nullable.
+	invalidSerialNullability := false
+	serialUsesSequence := false
+	if !unimplementedType && og.randIntn(10) == 0 {
+		def = og.randSerialColumnDef(def.Name)
+		typ = tree.MustBeStaticallyKnownType(def.Type)
+		if og.produceError() {
+			def.Nullable.Nullability = tree.Null
+			invalidSerialNullability = true
+		}
+		serialUsesSequence, err = og.serialNormalizationUsesSequence(ctx, tx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// TIMESTAMPTZ columns may default to the current time, which is the same
+	// for every row backfilled by the statement, so this is never combined with
+	// UNIQUE either.
+	if typ != nil && typ.Identical(types.TimestampTZ) && !def.Unique.IsUnique && og.randIntn(3) == 0 {
+		nowFuncs := []string{"now", "current_timestamp"}
+		def.DefaultExpr.Expr = &tree.FuncExpr{Func: tree.WrapFunction(nowFuncs[og.randIntn(len(nowFuncs))])}
+	}
+
 	columnExistsOnTable, err := og.columnExistsOnTable(ctx, tx, tableName, columnName)
 	if err != nil {
 		return nil, err
@@ -291,25 +475,266 @@ func (og *operationGenerator) addColumn(ctx context.Context, tx pgx.Tx) (*opStmt
 	op := makeOpStmt(OpStmtDDL)
 	op.expectedExecErrors.addAll(codesWithConditions{
 		{code: pgcode.DuplicateColumn, condition: columnExistsOnTable},
-		{code: pgcode.UndefinedObject, condition: typ == nil},
-		{code: pgcode.NotNullViolation, condition: hasRows && def.Nullable.Nullability == tree.NotNull},
+		{code: pgcode.UndefinedObject, condition: typ == nil && !unimplementedType},
+		{code: pgcode.FeatureNotSupported, condition: unimplementedType},
+		{code: pgcode.NotNullViolation, condition: hasRows && def.Nullable.Nullability == tree.NotNull && def.DefaultExpr.Expr == nil && !def.IsSerial},
 		{code: pgcode.FeatureNotSupported, condition: hasAlterPKSchemaChange},
+		{code: pgcode.Syntax, condition: invalidSerialNullability},
 		// UNIQUE is only supported for indexable types.
 		{
 			code:      pgcode.FeatureNotSupported,
 			condition: def.Unique.IsUnique && typ != nil && !colinfo.ColumnTypeIsIndexable(typ),
 		},
 	})
+	// Backfilling a column whose default is nextval() of a sequence created in
+	// the same statement is not supported by every schema changer.
+	if serialUsesSequence && hasRows {
+		op.potentialExecErrors.add(pgcode.FeatureNotSupported)
+	}
 	op.sql = fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s`, tableName, tree.Serialize(def))
 	return op, nil
 }
 
+// inetDefaults are the default values of INET columns added by addColumn.
+var inetDefaults = []string{
+	"192.168.0.1",
+	"10.0.0.0/8",
+	"::1",
+	"2001:db8::/32",
+	"::ffff:192.168.0.1",
+}
+
+// serialTypes are the widths a SERIAL column can be declared with, i.e.
+// SMALLSERIAL, SERIAL4 and BIGSERIAL.
+var serialTypes = []*types.T{types.Int2, types.Int4, types.Int}
+
+// randSerialColumnDef returns the definition of a SERIAL column of a random
+// width. Its representation is only decided when the statement is executed,
+// based on the serial_normalization session variable.
+func (og *operationGenerator) randSerialColumnDef(name tree.Name) *tree.ColumnTableDef {
+	def := &tree.ColumnTableDef{
+		Name:     name,
+		Type:     serialTypes[og.randIntn(len(serialTypes))],
+		IsSerial: true,
+	}
+	if og.randIntn(2) == 0 {
+		def.Nullable.Nullability = tree.NotNull
+	} else {
+		def.Nullable.Nullability = tree.SilentNull
+	}
+	return def
+}
+
 func (og *operationGenerator) addConstraint(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
 	// TODO(peter): unimplemented
 	// - Export sqlbase.randColumnTableDef.
 	return nil, nil
 }
 
+// addCheckNotNullConstraint adds a CHECK (<column> IS NOT NULL) constraint,
+// the check constraint equivalent of a NOT NULL column. Both forms are
+// generated against the same columns, since CockroachDB converts between them
+// internally (SET NOT NULL is itself validated through a temporary check
+// constraint) and the two representations must reject the same rows.
+// On string columns, the constraint may also enforce a format through a
+// regular expression.
+func (og *operationGenerator) addCheckNotNullConstraint(
+	ctx context.Context, tx pgx.Tx,
+) (*opStmt, error) {
+	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
+	if err != nil {
+		return nil, err
+	}
+	tableExists, err := og.tableExists(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if !tableExists {
+		return makeOpStmtForSingleError(OpStmtDDL,
+			fmt.Sprintf(`ALTER TABLE %s ADD CONSTRAINT IrrelevantConstraintName CHECK (IrrelevantColumnName IS NOT NULL)`, tableName),
+			pgcode.UndefinedTable), nil
+	}
+	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	col, err := og.randColumnWithMeta(ctx, tx, *tableName, og.pctExisting(true))
+	if err != nil {
+		return nil, err
+	}
+	columnName := col.name
+	columnExists, err := og.columnExistsOnTable(ctx, tx, tableName, columnName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Occasionally skip validating the existing rows, leaving that to a later
+	// ALTER TABLE ... VALIDATE CONSTRAINT.
+	notValid := og.randIntn(4) == 0
+
+	stmt := makeOpStmt(OpStmtDDL)
+	predicate := fmt.Sprintf(`"%s" IS NOT NULL`, columnName)
+	if !columnExists {
+		stmt.expectedExecErrors.add(pgcode.UndefinedColumn)
+	} else {
+		// Occasionally extend the predicate with a term that is always true, but
+		// calls a function. When an error is requested, the term uses a function
+		// that is never allowed in a CHECK constraint.
+		if og.produceError() && og.randIntn(2) == 0 {
+			term := invalidCheckPredicateTerms[og.randIntn(len(invalidCheckPredicateTerms))]
+			predicate = fmt.Sprintf(`%s AND %s`, predicate, term.term)
+			stmt.expectedExecErrors.add(term.code)
+		} else if og.randIntn(3) == 0 {
+			terms, err := og.immutableCheckPredicateTerms(ctx, tx)
+			if err != nil {
+				return nil, err
+			}
+			if len(terms) > 0 {
+				predicate = fmt.Sprintf(`%s AND %s`, predicate, terms[og.randIntn(len(terms))])
+			}
+		}
+		// String columns may additionally have their format enforced through a
+		// regular expression. This is limited to validated constraints, since
+		// an invalid pattern would otherwise make every later write fail.
+		if !notValid && col.typ != nil && col.typ.Family() == types.StringFamily && og.randIntn(3) == 0 {
+			regexPredicate, err := og.checkRegexPredicate(ctx, tx, tableName, columnName)
+			if err != nil {
+				return nil, err
+			}
+			predicate = fmt.Sprintf(`%s AND %s`, predicate, regexPredicate)
+		}
+		// Existing NULLs are only detected when the constraint is validated,
+		// which happens once the transaction commits.
+		colContainsNull, err := og.columnContainsNull(ctx, tx, tableName, columnName)
+		if err != nil {
+			return nil, err
+		}
+		if colContainsNull && !notValid {
+			og.candidateExpectedCommitErrors.add(pgcode.CheckViolation)
+		}
+	}
+
+	constraintName := tree.Name(fmt.Sprintf("check_not_null_%s", og.newUniqueSeqNumSuffix()))
+	stmt.sql = fmt.Sprintf(`ALTER TABLE %s ADD CONSTRAINT %s CHECK (%s)`,
+		tableName, constraintName.String(), predicate)
+	if notValid {
+		stmt.sql += " NOT VALID"
+	}
+	return stmt, nil
+}
+
+// checkRegexPredicate returns a `<column> ~ '<pattern>'` term for a CHECK
+// constraint on a string column. Rows that already fail to match, or a
+// pattern that fails to compile, are only detected once the constraint is
+// validated at commit time.
+func (og *operationGenerator) checkRegexPredicate(
+	ctx context.Context, tx pgx.Tx, tableName *tree.TableName, columnName string,
+) (string, error) {
+	tableHasRows, err := og.tableHasRows(ctx, tx, tableName)
+	if err != nil {
+		return "", err
+	}
+	// An invalid pattern is only ever evaluated against existing rows, so it
+	// is limited to tables that have some. Otherwise, the constraint would be
+	// added successfully and make every later write to the table fail.
+	if tableHasRows && og.produceError() {
+		pattern := invalidCheckRegexPatterns[og.randIntn(len(invalidCheckRegexPatterns))]
+		og.candidateExpectedCommitErrors.add(pgcode.InvalidRegularExpression)
+		return fmt.Sprintf(`"%s" ~ %s`, columnName, tree.NewDString(pattern)), nil
+	}
+	pattern := checkRegexPatterns[og.randIntn(len(checkRegexPatterns))]
+	predicate := fmt.Sprintf(`"%s" ~ %s`, columnName, tree.NewDString(pattern))
+	if tableHasRows {
+		violation, err := og.scanBool(ctx, tx, fmt.Sprintf(
+			`SELECT EXISTS (SELECT * FROM %s WHERE NOT (%s))`, tableName, predicate))
+		if err != nil {
+			return "", og.checkAndAdjustForUnknownSchemaErrors(err)
+		}
+		if violation {
+			og.candidateExpectedCommitErrors.add(pgcode.CheckViolation)
+		}
+	}
+	return predicate, nil
+}
+
+// checkRegexPatterns enforce a format on string columns. Randomly generated
+// strings rarely match the stricter ones.
+var checkRegexPatterns = []string{
+	`^[a-z]+$`,
+	`^[[:alnum:]]*$`,
+	`^.{0,16}$`,
+	`^[^;]*$`,
+	`.*`,
+}
+
+// invalidCheckRegexPatterns fail to compile as regular expressions.
+var invalidCheckRegexPatterns = []string{
+	`[`,
+	`(a`,
+	`a{2,1}`,
+	`*a`,
+}
+
+// checkPredicateTerms are always true, and each call the given function.
+// They cover every function volatility, but only the immutable ones are used
+// by immutableCheckPredicateTerms.
+var checkPredicateTerms = []struct {
+	term     string
+	function string
+}{
+	{term: `length('check') = 5`, function: "length"},
+	{term: `abs(-1) = 1`, function: "abs"},
+	{term: `now() > '2000-01-01'::TIMESTAMPTZ`, function: "now"},
+	{term: `current_user() IS NOT NULL`, function: "current_user"},
+	{term: `random() >= 0`, function: "random"},
+}
+
+// immutableCheckPredicateTerms returns the checkPredicateTerms of which every
+// overload of the function is immutable according to pg_proc. CHECK
+// constraints accept stable and volatile functions, but their result depends
+// on when a row is written, so the error screening for writes, which
+// evaluates the constraints of a table against the inserted rows, can't rely
+// on them.
+func (og *operationGenerator) immutableCheckPredicateTerms(
+	ctx context.Context, tx pgx.Tx,
+) ([]string, error) {
+	functions := make([]string, 0, len(checkPredicateTerms))
+	for _, t := range checkPredicateTerms {
+		functions = append(functions, t.function)
+	}
+	immutable, err := og.scanStringArray(ctx, tx, `
+SELECT COALESCE(array_agg(proname), ARRAY[]::STRING[])
+  FROM (
+        SELECT proname
+          FROM pg_catalog.pg_proc
+         WHERE proname = ANY ($1::STRING[])
+      GROUP BY proname
+        HAVING bool_and(provolatile = 'i')
+       )`, functions)
+	if err != nil {
+		return nil, err
+	}
+	var terms []string
+	for _, t := range checkPredicateTerms {
+		if slices.Contains(immutable, t.function) {
+			terms = append(terms, t.term)
+		}
+	}
+	return terms, nil
+}
+
+// invalidCheckPredicateTerms use functions that are rejected in CHECK
+// constraints regardless of their volatility.
+var invalidCheckPredicateTerms = []struct {
+	term string
+	code pgcode.Code
+}{
+	{term: `count(*) >= 0`, code: pgcode.Grouping},
+	{term: `rank() OVER () >= 1`, code: pgcode.Windowing},
+	{term: `generate_series(1, 1) = 1`, code: pgcode.FeatureNotSupported},
+}
+
 func (og *operationGenerator) addUniqueConstraint(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
 	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
 	if err != nil {
@@ -345,9 +770,30 @@ func (og *operationGenerator) addUniqueConstraint(ctx context.Context, tx pgx.Tx
 		return nil, err
 	}
 
+	tableIsRegionalByRow, err := og.tableIsRegionalByRow(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unique constraints on REGIONAL BY ROW tables are implicitly partitioned
+	// by the region column, which is prepended to the key of the backing index
+	// while uniqueness is still enforced on the constraint columns alone.
+	// Occasionally include the region column explicitly instead, in which case
+	// it must be the first column and no implicit partitioning is needed.
+	constraintColumns := []string{columnForConstraint.name}
+	if tableIsRegionalByRow && columnExistsOnTable && og.randIntn(3) == 0 {
+		regionColumn, err := og.getRegionColumn(ctx, tx, tableName)
+		if err != nil {
+			return nil, err
+		}
+		if regionColumn != columnForConstraint.name {
+			constraintColumns = []string{regionColumn, columnForConstraint.name}
+		}
+	}
+
 	canApplyConstraint := true
 	if columnExistsOnTable {
-		canApplyConstraint, err = og.canApplyUniqueConstraint(ctx, tx, tableName, []string{columnForConstraint.name})
+		canApplyConstraint, err = og.canApplyUniqueConstraint(ctx, tx, tableName, constraintColumns)
 		if err != nil {
 			return nil, err
 		}
@@ -362,10 +808,6 @@ func (og *operationGenerator) addUniqueConstraint(ctx context.Context, tx pgx.Tx
 	if err != nil {
 		return nil, err
 	}
-	tableIsRegionalByRow, err := og.tableIsRegionalByRow(ctx, tx, tableName)
-	if err != nil {
-		return nil, err
-	}
 	stmt := makeOpStmt(OpStmtDDL)
 	stmt.expectedExecErrors.addAll(codesWithConditions{
 		{code: pgcode.UndefinedColumn, condition: !columnExistsOnTable},
@@ -379,7 +821,119 @@ func (og *operationGenerator) addUniqueConstraint(ctx context.Context, tx pgx.Tx
 		og.candidateExpectedCommitErrors.add(pgcode.UniqueViolation)
 	}
 
-	stmt.sql = fmt.Sprintf(`ALTER TABLE %s ADD CONSTRAINT %s UNIQUE (%s)`, tableName, constaintName, columnForConstraint.name)
+	stmt.sql = fmt.Sprintf(`ALTER TABLE %s ADD CONSTRAINT %s UNIQUE (%s)`, tableName, constaintName, strings.Join(constraintColumns, ", "))
+	return stmt, nil
+}
+
+// addPrimaryKeyConstraint gives a table an explicit primary key. This is only
+// allowed while the table is still keyed on the implicit rowid column, so it
+// exercises the transition from an implicit to an explicit primary key. Once
+// the new key is in place the declarative schema changer drops the rowid
+// column, unless it is part of the new key (which we occasionally do on
+// purpose), while the legacy schema changer keeps it.
+func (og *operationGenerator) addPrimaryKeyConstraint(
+	ctx context.Context, tx pgx.Tx,
+) (*opStmt, error) {
+	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
+	if err != nil {
+		return nil, err
+	}
+	tableExists, err := og.tableExists(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if !tableExists {
+		return makeOpStmtForSingleError(OpStmtDDL,
+			fmt.Sprintf(`ALTER TABLE %s ADD CONSTRAINT IrrelevantConstraintName PRIMARY KEY (IrrelevantColumnName)`, tableName),
+			pgcode.UndefinedTable), nil
+	}
+	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	rowIDColumn, err := og.defaultRowIDPrimaryKeyColumn(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := og.getTableColumns(ctx, tx, tableName, true /* shuffle */)
+	if err != nil {
+		return nil, err
+	}
+	// The rowid column isn't returned, so a table may have no column to key
+	// on.
+	if len(columns) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+
+	numColumns := 1 + og.randIntn(min(len(columns), 3))
+	columnNames := make([]string, 0, numColumns+1)
+	hasNullableColumn := false
+	hasUnindexableColumn := false
+	for _, col := range columns[:numColumns] {
+		columnNames = append(columnNames, col.name)
+		hasNullableColumn = hasNullableColumn || col.nullable
+		hasUnindexableColumn = hasUnindexableColumn || !colinfo.ColumnTypeIsIndexable(col.typ)
+	}
+	// Keeping the rowid column as the last key column guarantees uniqueness and
+	// means the column has to be retained once the new primary key is in place.
+	if rowIDColumn != "" && og.randIntn(4) == 0 {
+		columnNames = append(columnNames, rowIDColumn)
+	}
+	columnsExist := true
+	if og.produceError() {
+		columnNames = append(columnNames, "NonExistentColumn")
+		columnsExist = false
+	}
+
+	// Rows with NULLs are treated as unique by canApplyUniqueConstraint, which
+	// is fine since nullable columns are rejected before any backfill.
+	canApplyConstraint, err := og.canApplyUniqueConstraint(ctx, tx, tableName, columnNames[:numColumns])
+	if err != nil {
+		return nil, err
+	}
+	retainsRowID := len(columnNames) > numColumns && columnNames[numColumns] == rowIDColumn
+
+	hasOngoingSchemaChanges, err := og.tableHasOngoingSchemaChanges(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	tableHasDependencies, err := og.tableHasDependencies(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	databaseHasRegionChange, err := og.databaseHasRegionChange(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	tableIsRegionalByRow, err := og.tableIsRegionalByRow(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := makeOpStmt(OpStmtDDL)
+	stmt.expectedExecErrors.addAll(codesWithConditions{
+		{code: pgcode.InvalidTableDefinition, condition: rowIDColumn == ""},
+		{code: pgcode.UndefinedColumn, condition: rowIDColumn != "" && !columnsExist},
+		{code: pgcode.InvalidSchemaDefinition, condition: rowIDColumn != "" && columnsExist && hasNullableColumn},
+		{code: pgcode.ObjectNotInPrerequisiteState, condition: databaseHasRegionChange && tableIsRegionalByRow},
+		{code: pgcode.FeatureNotSupported, condition: hasUnindexableColumn},
+	})
+	// The remaining checks depend on the order in which the schema changer
+	// validates the new key, so they may or may not be hit.
+	stmt.potentialExecErrors.addAll(codesWithConditions{
+		{code: pgcode.FeatureNotSupported, condition: hasOngoingSchemaChanges},
+		{code: pgcode.DependentObjectsStillExist, condition: tableHasDependencies},
+	})
+	if rowIDColumn != "" && columnsExist && !hasNullableColumn && !canApplyConstraint && !retainsRowID {
+		og.candidateExpectedCommitErrors.add(pgcode.UniqueViolation)
+	}
+
+	stmt.sql = fmt.Sprintf(`ALTER TABLE %s ADD CONSTRAINT %s PRIMARY KEY (%s)`,
+		tableName,
+		tree.Name(fmt.Sprintf("%s_pkey_%s", tableName.Object(), og.newUniqueSeqNumSuffix())).String(),
+		strings.Join(columnNames, ", "),
+	)
 	return stmt, nil
 }
 
@@ -627,32 +1181,35 @@ func (og *operationGenerator) alterDatabaseAddSuperRegion(
 		return nil, err
 	}
 
-	hasPrimaryRegion := false
-	var superRegions []*tree.Name
-	var superRegionRegions []tree.NodeFormatter
-	var regionsNotInDatabase []tree.NodeFormatter
-	var nonSuperRegionRegions []tree.NodeFormatter
+	survivesRegionFailure, err := og.databaseSurvivesRegionFailure(ctx, tx, database)
+	if err != nil {
+		return nil, err
+	}
+
+	superRegions := superRegionNames(regionInfos)
+	var superRegionRegions []*tree.Name
+	var regionsNotInDatabase []*tree.Name
+	var nonSuperRegionRegions []*tree.Name
 
 	for _, region := range regionInfos {
 		region := region
-		hasPrimaryRegion = hasPrimaryRegion || region.IsPrimary
-
 		if !region.InUse {
 			regionsNotInDatabase = append(regionsNotInDatabase, &region.Name)
-			continue
-		}
-
-		if region.SuperRegion == nil {
+		} else if region.SuperRegion == nil {
 			nonSuperRegionRegions = append(nonSuperRegionRegions, &region.Name)
 		} else {
 			superRegionRegions = append(superRegionRegions, &region.Name)
-
-			if !slices.Contains(superRegions, region.SuperRegion) {
-				superRegions = append(superRegions, region.SuperRegion)
-			}
 		}
 	}
 
+	// pickedRegions tracks the regions that make up the new super region.
+	var pickedRegions []*tree.Name
+	pickRegions := func(regions []*tree.Name) (Values, error) {
+		var err error
+		pickedRegions, err = PickAtLeast(og.params.rng, 1, regions)
+		return AsValues(pickedRegions, err)
+	}
+
 	stmt, expectedCode, err := Generate[*tree.AlterDatabaseAddSuperRegion](og.params.rng, og.produceError(), []GenerationCase{
 		// Alter a database that doesn't exist.
 		{pgcode.InvalidCatalogName, `ALTER DATABASE "NonExistentDatabase" ADD SUPER REGION "Irrelevant" VALUES Irrelevant`},
@@ -673,30 +1230,34 @@ func (og *operationGenerator) alterDatabaseAddSuperRegion(
 			return PickOne(og.params.rng, superRegions)
 		},
 		"SuperRegionRegions": func() (Values, error) {
-			return PickAtLeast(og.params.rng, 1, superRegionRegions)
+			return pickRegions(superRegionRegions)
 		},
 		"NonSuperRegionRegions": func() (Values, error) {
-			return PickAtLeast(og.params.rng, 1, nonSuperRegionRegions)
+			return pickRegions(nonSuperRegionRegions)
 		},
 		"UniqueName": func() *tree.Name {
 			name := tree.Name(fmt.Sprintf("super_region_%s", og.newUniqueSeqNumSuffix()))
 			return &name
 		},
 		"RegionsNotPartOfDatabase": func() (Values, error) {
-			return PickAtLeast(og.params.rng, 1, regionsNotInDatabase)
+			return pickRegions(regionsNotInDatabase)
 		},
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return newOpStmt(stmt, codesWithConditions{
+	opStmt := newOpStmt(stmt, codesWithConditions{
 		{expectedCode, true},
 		{pgcode.InvalidName, !isMultiRegion},
-	}), nil
+	})
+	if expectedCode != pgcode.InvalidCatalogName {
+		opStmt.expectedExecErrors.addAll(superRegionErrors(regionInfos, pickedRegions, survivesRegionFailure))
+	}
+	return opStmt, nil
 }
 
-func (og *operationGenerator) alterDatabaseDropSuperRegion(
+func (og *operationGenerator) alterDatabaseAlterSuperRegion(
 	ctx context.Context, tx pgx.Tx,
 ) (*opStmt, error) {
 	database, err := og.getDatabase(ctx, tx)
@@ -709,1609 +1270,2550 @@ func (og *operationGenerator) alterDatabaseDropSuperRegion(
 		return nil, err
 	}
 
-	superRegions, err := Collect(ctx, og, tx, pgx.RowTo[tree.Name], fmt.Sprintf(`
-		SELECT super_region_name FROM [SHOW SUPER REGIONS FROM DATABASE %q]
-	`, database))
+	regionInfos, err := og.getRegionInfo(ctx, tx, database)
 	if err != nil {
 		return nil, err
 	}
 
-	superRegion := tree.Name("IrrelevantSuperRegion")
-	if !og.produceError() && len(superRegions) > 0 {
-		superRegion = superRegions[og.randIntn(len(superRegions))]
-	}
-
-	stmt := makeOpStmt(OpStmtDDL)
-	stmt.sql = tree.Serialize(&tree.AlterDatabaseDropSuperRegion{
-		DatabaseName:    tree.Name(database),
-		SuperRegionName: superRegion,
-	})
-	stmt.expectedExecErrors.addAll(codesWithConditions{
-		{pgcode.InvalidName, !isMultiRegion},
-		{pgcode.Uncategorized, superRegion == "IrrelevantSuperRegion"},
-	})
-	return stmt, nil
-}
-
-func (og *operationGenerator) primaryRegion(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
-	// Allow changing the primary region even if it's part of a super region.
-	if _, err := tx.Exec(ctx, `SET alter_primary_region_super_region_override = 'on'`); err != nil {
-		return nil, err
-	}
-
-	database, err := og.getDatabase(ctx, tx)
+	survivesRegionFailure, err := og.databaseSurvivesRegionFailure(ctx, tx, database)
 	if err != nil {
 		return nil, err
 	}
 
-	regionInfos, err := og.getRegionInfo(ctx, tx, database)
+	superRegions := superRegionNames(regionInfos)
+	// superRegion tracks the super region being altered, so that the regions
+	// it's altered to can be picked relative to it.
+	var superRegion *tree.Name
+	var pickedRegions []*tree.Name
+	pickRegions := func(include func(r regionInfo) bool) (Values, error) {
+		var candidates []*tree.Name
+		for _, region := range regionInfos {
+			region := region
+			if include(region) {
+				candidates = append(candidates, &region.Name)
+			}
+		}
+		var err error
+		pickedRegions, err = PickAtLeast(og.params.rng, 1, candidates)
+		return AsValues(pickedRegions, err)
+	}
+	inSuperRegion := func(r regionInfo) bool {
+		return superRegion != nil && r.SuperRegion != nil && *r.SuperRegion == *superRegion
+	}
+
+	stmt, expectedCode, err := Generate[*tree.AlterDatabaseAlterSuperRegion](og.params.rng, og.produceError(), []GenerationCase{
+		// Alter a database that doesn't exist.
+		{pgcode.InvalidCatalogName, `ALTER DATABASE "NonExistentDatabase" ALTER SUPER REGION "Irrelevant" VALUES Irrelevant`},
+		// Alter a super region that doesn't exist.
+		{pgcode.UndefinedObject, `ALTER DATABASE {Database} ALTER SUPER REGION "SuperRegionThatDoesntExist" VALUES {AvailableRegions}`},
+		// Use regions that are part of another super region.
+		{pgcode.Uncategorized, `ALTER DATABASE {Database} ALTER SUPER REGION {ExistingSuperRegion} VALUES {OtherSuperRegionRegions}`},
+		// Use regions that haven't been added to that database.
+		{pgcode.Uncategorized, `ALTER DATABASE {Database} ALTER SUPER REGION {ExistingSuperRegion} VALUES {RegionsNotPartOfDatabase}`},
+		// Successful case.
+		{pgcode.SuccessfulCompletion, `ALTER DATABASE {Database} ALTER SUPER REGION {ExistingSuperRegion} VALUES {AvailableRegions}`},
+	}, map[string]any{
+		"Database": func() *tree.Name {
+			db := tree.Name(database)
+			return &db
+		},
+		"ExistingSuperRegion": func() (*tree.Name, error) {
+			var err error
+			superRegion, err = PickOne(og.params.rng, superRegions)
+			return superRegion, err
+		},
+		"AvailableRegions": func() (Values, error) {
+			return pickRegions(func(r regionInfo) bool {
+				return r.InUse && (r.SuperRegion == nil || inSuperRegion(r))
+			})
+		},
+		"OtherSuperRegionRegions": func() (Values, error) {
+			return pickRegions(func(r regionInfo) bool {
+				return r.InUse && r.SuperRegion != nil && !inSuperRegion(r)
+			})
+		},
+		"RegionsNotPartOfDatabase": func() (Values, error) {
+			return pickRegions(func(r regionInfo) bool {
+				return !r.InUse
+			})
+		},
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	regionsInDB := util.Filter(regionInfos, func(r regionInfo) bool {
-		return r.InUse
+	opStmt := newOpStmt(stmt, codesWithConditions{
+		{expectedCode, true},
+		{pgcode.InvalidName, !isMultiRegion},
 	})
+	if expectedCode != pgcode.InvalidCatalogName {
+		opStmt.expectedExecErrors.addAll(superRegionErrors(regionInfos, pickedRegions, survivesRegionFailure))
+	}
+	return opStmt, nil
+}
 
-	// No regions in cluster, try PRIMARY REGION an invalid region and expect an error.
-	if len(regionInfos) == 0 {
-		return makeOpStmtForSingleError(OpStmtDDL,
-			fmt.Sprintf(`ALTER DATABASE %s PRIMARY REGION "invalid-region"`, database),
-			pgcode.InvalidName, pgcode.InvalidDatabaseDefinition), nil
+// superRegionNames returns the distinct names of the super regions defined on
+// the database of the given regions.
+func superRegionNames(regions []regionInfo) []*tree.Name {
+	var names []*tree.Name
+	for _, region := range regions {
+		if region.SuperRegion == nil {
+			continue
+		}
+		if !slices.ContainsFunc(names, func(name *tree.Name) bool {
+			return *name == *region.SuperRegion
+		}) {
+			names = append(names, region.SuperRegion)
+		}
 	}
+	return names
+}
 
-	// Conversion to multi-region is only allowed if the data is not already
-	// partitioned.
-	stmt := makeOpStmt(OpStmtDDL)
-	hasPartitioning, err := og.databaseHasTablesWithPartitioning(ctx, tx, database)
+// superRegionErrors returns the errors that adding or altering a super region
+// to be made up of superRegionRegions may result in, beyond those specific to
+// the super region itself. Every region of a super region must be able to
+// satisfy the survival goal of the database, and the primary and secondary
+// regions must either both be part of the super region or neither be.
+func superRegionErrors(
+	regions []regionInfo, superRegionRegions []*tree.Name, survivesRegionFailure bool,
+) codesWithConditions {
+	contains := func(name tree.Name) bool {
+		return slices.ContainsFunc(superRegionRegions, func(n *tree.Name) bool {
+			return *n == name
+		})
+	}
+	allInDatabase := true
+	for _, name := range superRegionRegions {
+		allInDatabase = allInDatabase && slices.ContainsFunc(regions, func(r regionInfo) bool {
+			return r.InUse && r.Name == *name
+		})
+	}
+	var primary, secondary *regionInfo
+	for i := range regions {
+		if regions[i].IsPrimary {
+			primary = &regions[i]
+		}
+		if regions[i].IsSecondary {
+			secondary = &regions[i]
+		}
+	}
+
+	return codesWithConditions{
+		{
+			pgcode.InvalidDatabaseDefinition,
+			primary != nil && secondary != nil && contains(primary.Name) != contains(secondary.Name),
+		},
+		{
+			pgcode.InvalidParameterValue,
+			allInDatabase && survivesRegionFailure && len(superRegionRegions) < 3,
+		},
+	}
+}
+
+func (og *operationGenerator) alterDatabaseDropSuperRegion(
+	ctx context.Context, tx pgx.Tx,
+) (*opStmt, error) {
+	database, err := og.getDatabase(ctx, tx)
 	if err != nil {
 		return nil, err
 	}
-	if hasPartitioning {
-		stmt.expectedExecErrors.add(pgcode.ObjectNotInPrerequisiteState)
+
+	isMultiRegion, err := og.databaseIsMultiRegion(ctx, tx)
+	if err != nil {
+		return nil, err
 	}
 
-	// No regions in database, set a random region to be the PRIMARY REGION.
-	if len(regionsInDB) == 0 {
-		idx := og.params.rng.Intn(len(regionInfos))
-		stmt.sql = fmt.Sprintf(
-			`ALTER DATABASE %s PRIMARY REGION "%s"`,
-			database,
-			regionInfos[idx].Name,
-		)
-		return stmt, nil
+	superRegions, err := Collect(ctx, og, tx, pgx.RowTo[tree.Name], fmt.Sprintf(`
+		SELECT super_region_name FROM [SHOW SUPER REGIONS FROM DATABASE %q]
+	`, database))
+	if err != nil {
+		return nil, err
 	}
 
-	// Regions exist in database, so set a random region to be the primary region.
-	idx := og.params.rng.Intn(len(regionsInDB))
-	stmt.sql = fmt.Sprintf(
-		`ALTER DATABASE %s PRIMARY REGION "%s"`,
-		database,
-		regionsInDB[idx].Name,
-	)
+	superRegion := tree.Name("IrrelevantSuperRegion")
+	if !og.produceError() && len(superRegions) > 0 {
+		superRegion = superRegions[og.randIntn(len(superRegions))]
+	}
+
+	stmt := makeOpStmt(OpStmtDDL)
+	stmt.sql = tree.Serialize(&tree.AlterDatabaseDropSuperRegion{
+		DatabaseName:    tree.Name(database),
+		SuperRegionName: superRegion,
+	})
+	stmt.expectedExecErrors.addAll(codesWithConditions{
+		{pgcode.InvalidName, !isMultiRegion},
+		{pgcode.Uncategorized, superRegion == "IrrelevantSuperRegion"},
+	})
 	return stmt, nil
 }
 
-func (og *operationGenerator) addForeignKeyConstraint(
+func (og *operationGenerator) alterDatabaseSecondaryRegion(
 	ctx context.Context, tx pgx.Tx,
 ) (*opStmt, error) {
-	parentTable, parentColumn, err := og.randParentColumnForFkRelation(ctx, tx, og.randIntn(100) >= og.params.fkParentInvalidPct)
+	database, err := og.getDatabase(ctx, tx)
 	if err != nil {
 		return nil, err
 	}
 
-	fetchInvalidChild := og.randIntn(100) < og.params.fkChildInvalidPct
-	// Potentially create an error by choosing the wrong type for the child column.
-	childType := parentColumn.typ
-	if fetchInvalidChild {
-		_, typ, err := og.randType(ctx, tx, og.pctExisting(true))
-		if err != nil {
-			return nil, err
+	regions, err := og.getRegionInfo(ctx, tx, database)
+	if err != nil {
+		return nil, err
+	}
+
+	// No regions in cluster, try to set an invalid region as the secondary
+	// region and expect an error.
+	if len(regions) == 0 {
+		return makeOpStmtForSingleError(OpStmtDDL,
+			fmt.Sprintf(`ALTER DATABASE %s SET SECONDARY REGION "invalid-region"`, database),
+			pgcode.InvalidDatabaseDefinition), nil
+	}
+
+	var primary *regionInfo
+	for i := range regions {
+		if regions[i].IsPrimary {
+			primary = &regions[i]
 		}
-		if typ != nil {
-			childType = typ
+	}
+	// The secondary region must be a region of the database other than the
+	// primary region. If the primary region is part of a super region, the
+	// secondary region must be part of the same one, otherwise it can't be
+	// part of any.
+	isValidSecondaryRegion := func(r regionInfo) bool {
+		if primary == nil || !r.InUse || r.IsPrimary {
+			return false
+		}
+		if primary.SuperRegion == nil || r.SuperRegion == nil {
+			return primary.SuperRegion == r.SuperRegion
 		}
+		return *primary.SuperRegion == *r.SuperRegion
+	}
+
+	region := regions[og.randIntn(len(regions))]
+	if validRegions := util.Filter(regions, isValidSecondaryRegion); !og.produceError() && len(validRegions) > 0 {
+		region = validRegions[og.randIntn(len(validRegions))]
 	}
 
-	childTable, childColumn, err := og.randChildColumnForFkRelation(ctx, tx, !fetchInvalidChild, childType.SQLString())
+	stmt := makeOpStmt(OpStmtDDL)
+	stmt.sql = tree.Serialize(&tree.AlterDatabaseSecondaryRegion{
+		DatabaseName:    tree.Name(database),
+		SecondaryRegion: region.Name,
+	})
+	// Setting the current secondary region again is a no-op.
+	if region.IsSecondary || isValidSecondaryRegion(region) {
+		return stmt, nil
+	}
+
+	// Regions that are still being added to the database may be used as the
+	// secondary region, despite not being shown as part of the database.
+	databaseHasRegionChange, err := og.databaseHasRegionChange(ctx, tx)
 	if err != nil {
 		return nil, err
 	}
-	childColumnIsVirtualComputed, err := og.columnIsVirtualComputed(ctx, tx, childTable, childColumn.name)
+	if primary != nil && !region.InUse && databaseHasRegionChange {
+		stmt.potentialExecErrors.add(pgcode.InvalidDatabaseDefinition)
+	} else {
+		stmt.expectedExecErrors.add(pgcode.InvalidDatabaseDefinition)
+	}
+	return stmt, nil
+}
+
+func (og *operationGenerator) alterDatabaseDropRegion(
+	ctx context.Context, tx pgx.Tx,
+) (*opStmt, error) {
+	database, err := og.getDatabase(ctx, tx)
 	if err != nil {
 		return nil, err
 	}
-	childColumnIsStoredComputed, err := og.columnIsStoredComputed(ctx, tx, childTable, childColumn.name)
+
+	regions, err := og.getRegionInfo(ctx, tx, database)
 	if err != nil {
 		return nil, err
 	}
-	referenceActions := og.randReferenceActions(childColumnIsStoredComputed || childColumnIsVirtualComputed)
 
-	constraintName := tree.Name(fmt.Sprintf("%s_%s_%s_%s_fk", parentTable.Object(), parentColumn.name, childTable.Object(), childColumn.name))
+	regionsInDatabase := util.Filter(regions, func(r regionInfo) bool {
+		return r.InUse
+	})
+	regionsNotInDatabase := util.Filter(regions, func(r regionInfo) bool {
+		return !r.InUse
+	})
 
-	def := &tree.AlterTable{
-		Table: childTable.ToUnresolvedObjectName(),
-		Cmds: tree.AlterTableCmds{
-			&tree.AlterTableAddConstraint{
-				ConstraintDef: &tree.ForeignKeyConstraintTableDef{
-					Name:     constraintName,
-					Table:    *parentTable,
-					FromCols: tree.NameList{tree.Name(childColumn.name)},
-					ToCols:   tree.NameList{tree.Name(parentColumn.name)},
-					Actions:  referenceActions,
-				},
-				ValidationBehavior: tree.ValidationDefault,
-			},
-		},
+	stmt := makeOpStmt(OpStmtDDL)
+	dropRegion := &tree.AlterDatabaseDropRegion{
+		Name:   tree.Name(database),
+		Region: "invalid-region",
 	}
 
-	parentColumnHasUniqueConstraint, err := og.columnHasSingleUniqueConstraint(ctx, tx, parentTable, parentColumn.name)
-	if err != nil {
-		return nil, err
+	// The database isn't multi-region, so there is nothing to drop.
+	if len(regionsInDatabase) == 0 {
+		if len(regionsNotInDatabase) > 0 {
+			dropRegion.Region = regionsNotInDatabase[og.randIntn(len(regionsNotInDatabase))].Name
+		}
+		stmt.sql = tree.Serialize(dropRegion)
+		stmt.expectedExecErrors.add(pgcode.InvalidDatabaseDefinition)
+		return stmt, nil
 	}
-	parentColumnIsVirtualComputed, err := og.columnIsVirtualComputed(ctx, tx, parentTable, parentColumn.name)
+
+	databaseHasRegionalByRowChange, err := og.databaseHasRegionalByRowChange(ctx, tx)
 	if err != nil {
 		return nil, err
 	}
-	constraintExists, err := og.constraintExists(ctx, tx, string(constraintName))
+	if databaseHasRegionalByRowChange {
+		// Similar to addRegion, the REGIONAL BY ROW change may have completed
+		// by the time the statement runs, in which case the region is not found.
+		stmt.sql = tree.Serialize(dropRegion)
+		stmt.expectedExecErrors.addAll(codesWithConditions{
+			{pgcode.UndefinedObject, true},
+			{pgcode.ObjectNotInPrerequisiteState, true},
+		})
+		return stmt, nil
+	}
+
+	survivesRegionFailure, err := og.databaseSurvivesRegionFailure(ctx, tx, database)
 	if err != nil {
 		return nil, err
 	}
-	// If we are intentionally using an invalid child type, then it doesn't make
-	// sense to validate if the rows validate the constraint.
-	rowsSatisfyConstraint := true
-	if !fetchInvalidChild {
-		rowsSatisfyConstraint, err = og.rowsSatisfyFkConstraint(ctx, tx, parentTable, parentColumn, childTable, childColumn)
-		if err != nil {
-			return nil, err
+	// Dropping any region, even one that isn't part of the database, first
+	// validates that the remaining regions still satisfy the survival goal.
+	tooFewRegions := survivesRegionFailure && len(regionsInDatabase)-1 < 3
+
+	// Never drop the last remaining region of the database, which would turn
+	// it back into a non multi-region database. Instead, drop a region that
+	// hasn't been added to it.
+	if len(regionsInDatabase) == 1 || og.produceError() {
+		if len(regionsNotInDatabase) > 0 {
+			dropRegion.Region = regionsNotInDatabase[og.randIntn(len(regionsNotInDatabase))].Name
 		}
+		dropRegion.IfExists = og.randIntn(2) == 0
+		stmt.sql = tree.Serialize(dropRegion)
+		stmt.expectedExecErrors.addAll(codesWithConditions{
+			{pgcode.InvalidParameterValue, tooFewRegions},
+			{pgcode.UndefinedObject, !tooFewRegions && !dropRegion.IfExists},
+		})
+		return stmt, nil
 	}
 
-	stmt := makeOpStmt(OpStmtDDL)
+	region := regionsInDatabase[og.randIntn(len(regionsInDatabase))]
+	dropRegion.Region = region.Name
+	stmt.sql = tree.Serialize(dropRegion)
+
+	// The primary region can only be dropped once it is the last region, and
+	// the secondary region can't be dropped at all.
+	if region.IsPrimary || region.IsSecondary {
+		stmt.expectedExecErrors.add(pgcode.InvalidDatabaseDefinition)
+		return stmt, nil
+	}
 	stmt.expectedExecErrors.addAll(codesWithConditions{
-		{code: pgcode.ForeignKeyViolation, condition: !parentColumnHasUniqueConstraint},
-		{code: pgcode.FeatureNotSupported, condition: childColumnIsVirtualComputed},
-		{code: pgcode.FeatureNotSupported, condition: parentColumnIsVirtualComputed},
-		{code: pgcode.DuplicateObject, condition: constraintExists},
-		{code: pgcode.DatatypeMismatch, condition: !childColumn.typ.Equivalent(parentColumn.typ)},
+		{pgcode.DependentObjectsStillExist, region.SuperRegion != nil},
+		{pgcode.InvalidParameterValue, region.SuperRegion == nil && tooFewRegions},
 	})
-	og.expectedCommitErrors.addAll(codesWithConditions{})
-
-	// TODO(fqazi): We need to do after the fact validation for foreign key violations
-	// errors. Due to how adding foreign key constraints are implemented with a
-	// separate job validating the constraint, we can't at transaction time predict,
-	// perfectly if an error is expected. We can confirm post transaction with a time
-	// travel query.
-	_ = rowsSatisfyConstraint
-	stmt.potentialExecErrors.add(pgcode.ForeignKeyViolation)
-	og.potentialCommitErrors.add(pgcode.ForeignKeyViolation)
-
-	// It's possible for the table to be dropped concurrently, while we are running
-	// validation. In which case a potential commit error is an undefined table
-	// error.
-	og.potentialCommitErrors.add(pgcode.UndefinedTable)
-	stmt.sql = tree.Serialize(def)
-	return stmt, nil
-}
 
-func (og *operationGenerator) createIndex(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
-	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
+	// Regions that are still being added or dropped can't be dropped again.
+	regionInTransition, err := og.regionInTransition(ctx, tx, region.Name)
 	if err != nil {
 		return nil, err
 	}
+	if stmt.expectedExecErrors.empty() {
+		stmt.expectedExecErrors.addAll(codesWithConditions{
+			{pgcode.ObjectNotInPrerequisiteState, regionInTransition},
+		})
+	}
 
-	tableExists, err := og.tableExists(ctx, tx, tableName)
+	// Whether the region is still in use is only validated once the
+	// transaction commits, where REGIONAL BY TABLE tables homed in the region
+	// fail with an uncategorized error and rows of REGIONAL BY ROW tables
+	// stored in the region fail with a dependent objects error.
+	regionHomesTable, err := og.regionHomesTable(ctx, tx, region.Name)
 	if err != nil {
 		return nil, err
 	}
-	if !tableExists {
-		def := &tree.CreateIndex{
-			Name:  tree.Name("IrrelevantName"),
-			Table: *tableName,
-			Columns: tree.IndexElemList{
-				{Column: "IrrelevantColumn", Direction: tree.Ascending},
-			},
-		}
-		return makeOpStmtForSingleError(OpStmtDDL,
-			tree.Serialize(def),
-			pgcode.UndefinedTable), nil
-	}
-	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
+	regionHomesRows, err := og.enumValueUsed(
+		ctx, tx, "public.crdb_internal_region", tree.NewDString(string(region.Name)).String(),
+	)
 	if err != nil {
 		return nil, err
 	}
+	if stmt.expectedExecErrors.empty() {
+		og.candidateExpectedCommitErrors.addAll(codesWithConditions{
+			{pgcode.Uncategorized, regionHomesTable},
+			{pgcode.DependentObjectsStillExist, regionHomesRows},
+		})
+	}
+	return stmt, nil
+}
 
-	columnNames, err := og.getTableColumns(ctx, tx, tableName, true)
+func (og *operationGenerator) alterDatabaseDropSecondaryRegion(
+	ctx context.Context, tx pgx.Tx,
+) (*opStmt, error) {
+	database, err := og.getDatabase(ctx, tx)
 	if err != nil {
 		return nil, err
 	}
 
-	indexName, err := og.randIndex(ctx, tx, *tableName, og.pctExisting(false))
+	regions, err := og.getRegionInfo(ctx, tx, database)
 	if err != nil {
 		return nil, err
 	}
 
-	indexExists, err := og.indexExists(ctx, tx, tableName, indexName)
-	if err != nil {
-		return nil, err
+	isMultiRegion := false
+	hasSecondaryRegion := false
+	for _, region := range regions {
+		isMultiRegion = isMultiRegion || region.InUse
+		hasSecondaryRegion = hasSecondaryRegion || region.IsSecondary
 	}
 
-	invisibility := tree.IndexInvisibility{Value: 0.0}
-	if notvisible := og.randIntn(20) == 0; notvisible {
-		invisibility.Value = 1.0
-		if og.randIntn(2) == 0 {
-			invisibility.Value = 1 - og.params.rng.Float64()
-			invisibility.FloatProvided = true
-		}
+	ifExists := og.randIntn(2) == 0
+	stmt := makeOpStmt(OpStmtDDL)
+	stmt.sql = tree.Serialize(&tree.AlterDatabaseDropSecondaryRegion{
+		DatabaseName: tree.Name(database),
+		IfExists:     ifExists,
+	})
+	stmt.expectedExecErrors.addAll(codesWithConditions{
+		{pgcode.InvalidDatabaseDefinition, !isMultiRegion},
+		{pgcode.UndefinedParameter, isMultiRegion && !hasSecondaryRegion && !ifExists},
+	})
+	return stmt, nil
+}
+
+func (og *operationGenerator) primaryRegion(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	// Allow changing the primary region even if it's part of a super region.
+	if _, err := tx.Exec(ctx, `SET alter_primary_region_super_region_override = 'on'`); err != nil {
+		return nil, err
 	}
 
-	def := &tree.CreateIndex{
-		Name:         tree.Name(indexName),
-		Table:        *tableName,
-		Unique:       og.randIntn(4) == 0,  // 25% UNIQUE
-		Inverted:     og.randIntn(10) == 0, // 10% INVERTED
-		IfNotExists:  og.randIntn(2) == 0,  // 50% IF NOT EXISTS
-		Invisibility: invisibility,         // 5% NOT VISIBLE
+	database, err := og.getDatabase(ctx, tx)
+	if err != nil {
+		return nil, err
 	}
 
-	regionColumn := ""
-	tableIsRegionalByRow, err := og.tableIsRegionalByRow(ctx, tx, tableName)
+	regionInfos, err := og.getRegionInfo(ctx, tx, database)
 	if err != nil {
 		return nil, err
 	}
-	if tableIsRegionalByRow {
-		regionColumn, err = og.getRegionColumn(ctx, tx, tableName)
-		if err != nil {
-			return nil, err
-		}
+
+	regionsInDB := util.Filter(regionInfos, func(r regionInfo) bool {
+		return r.InUse
+	})
+
+	// No regions in cluster, try PRIMARY REGION an invalid region and expect an error.
+	if len(regionInfos) == 0 {
+		return makeOpStmtForSingleError(OpStmtDDL,
+			fmt.Sprintf(`ALTER DATABASE %s PRIMARY REGION "invalid-region"`, database),
+			pgcode.InvalidName, pgcode.InvalidDatabaseDefinition), nil
 	}
 
-	// Define columns on which to create an index. Check for types which cannot be indexed.
-	duplicateRegionColumn := false
-	nonIndexableType := false
-	def.Columns = make(tree.IndexElemList, 1+og.randIntn(len(columnNames)))
+	// Conversion to multi-region is only allowed if the data is not already
+	// partitioned.
+	stmt := makeOpStmt(OpStmtDDL)
+	hasPartitioning, err := og.databaseHasTablesWithPartitioning(ctx, tx, database)
 	if err != nil {
 		return nil, err
 	}
-	for i := range def.Columns {
-		def.Columns[i].Column = tree.Name(columnNames[i].name)
-		def.Columns[i].Direction = tree.Direction(og.randIntn(1 + int(tree.Descending)))
+	if hasPartitioning {
+		stmt.expectedExecErrors.add(pgcode.ObjectNotInPrerequisiteState)
+	}
 
-		// When creating an index, the column being used as the region column
-		// for a REGIONAL BY ROW table can only be included in indexes as the
-		// first column. If it's not the first column, we need to add an error
-		// below.
-		if columnNames[i].name == regionColumn && i != 0 {
-			duplicateRegionColumn = true
+	// No regions in database, set a random region to be the PRIMARY REGION.
+	if len(regionsInDB) == 0 {
+		idx := og.params.rng.Intn(len(regionInfos))
+		stmt.sql = fmt.Sprintf(
+			`ALTER DATABASE %s PRIMARY REGION "%s"`,
+			database,
+			regionInfos[idx].Name,
+		)
+		return stmt, nil
+	}
+
+	// Regions exist in database, so set a random region to be the primary region.
+	idx := og.params.rng.Intn(len(regionsInDB))
+	stmt.sql = fmt.Sprintf(
+		`ALTER DATABASE %s PRIMARY REGION "%s"`,
+		database,
+		regionsInDB[idx].Name,
+	)
+	return stmt, nil
+}
+
+func (og *operationGenerator) addForeignKeyConstraint(
+	ctx context.Context, tx pgx.Tx,
+) (*opStmt, error) {
+	var parentTable *tree.TableName
+	var parentColumns []*column
+	var err error
+	// Occasionally reference every column of a multi-column primary key or
+	// UNIQUE constraint with a composite foreign key.
+	if og.randIntn(4) == 0 {
+		parentTable, parentColumns, err = og.randParentColumnsForCompositeFkRelation(ctx, tx)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
 		}
-		if def.Inverted {
-			// We can have an inverted index on a set of columns if the last column
-			// is an inverted indexable type and the preceding columns are not.
-			invertedIndexableType := colinfo.ColumnTypeIsInvertedIndexable(columnNames[i].typ)
-			if (invertedIndexableType && i < len(def.Columns)-1) ||
-				(!invertedIndexableType && i == len(def.Columns)-1) {
-				nonIndexableType = true
-			}
-		} else {
-			if !colinfo.ColumnTypeIsIndexable(columnNames[i].typ) {
-				nonIndexableType = true
+	}
+	if parentColumns == nil {
+		parentKind := fkParentAnyColumn
+		if og.randIntn(100) >= og.params.fkParentInvalidPct {
+			parentKind = fkParentUniqueColumn
+			// Occasionally reference the column of a UNIQUE constraint rather
+			// than one that may be covered by the primary key.
+			if og.randIntn(4) == 0 {
+				parentKind = fkParentUniqueConstraintColumn
 			}
 		}
+		var parentColumn *column
+		parentTable, parentColumn, err = og.randParentColumnForFkRelation(ctx, tx, parentKind)
+		if parentKind == fkParentUniqueConstraintColumn && errors.Is(err, pgx.ErrNoRows) {
+			// No table has a UNIQUE constraint that can be referenced yet, so add
+			// one, allowing a later operation to reference it.
+			return og.addUniqueConstraint(ctx, tx)
+		}
+		if err != nil {
+			return nil, err
+		}
+		parentColumns = []*column{parentColumn}
 	}
 
-	// If there are extra columns not used in the index, randomly use them
-	// as stored columns.
-	stmt := makeOpStmt(OpStmtDDL)
-	duplicateStore := false
-	virtualComputedStored := false
-	regionColStored := false
-	columnNames = columnNames[len(def.Columns):]
-	if n := len(columnNames); n > 0 {
-		def.Storing = make(tree.NameList, og.randIntn(1+n))
-		for i := range def.Storing {
-			def.Storing[i] = tree.Name(columnNames[i].name)
+	fetchInvalidChild := og.randIntn(100) < og.params.fkChildInvalidPct
+	// Potentially create an error by choosing the wrong type for one of the
+	// child columns.
+	childTypes := make([]string, len(parentColumns))
+	for i, parentColumn := range parentColumns {
+		childTypes[i] = parentColumn.typ.SQLString()
+	}
+	if fetchInvalidChild {
+		_, typ, err := og.randType(ctx, tx, og.pctExisting(true))
+		if err != nil {
+			return nil, err
+		}
+		if typ != nil {
+			childTypes[og.randIntn(len(childTypes))] = typ.SQLString()
+		}
+	}
 
-			// The region column can not be stored.
-			if tableIsRegionalByRow && columnNames[i].name == regionColumn {
-				regionColStored = true
-			}
+	// Occasionally pick the child columns from the parent table, making the
+	// foreign key self-referencing.
+	var childTableFilter *tree.TableName
+	if og.randIntn(4) == 0 {
+		childTableFilter = parentTable
+	}
+	childTable, childColumns, err := og.randChildColumnsForFkRelation(ctx, tx, !fetchInvalidChild, childTypes, childTableFilter)
+	if err != nil {
+		return nil, err
+	}
+	actions := og.randReferenceActions(childColumns, fetchInvalidChild /* childIsComputed */, og.produceError())
+	// The foreign key is added to the child table, which can't be changed
+	// while row-level TTL is being added to or removed from it.
+	hasTTLChange, err := og.tableHasOngoingRowLevelTTLChange(ctx, tx, childTable)
+	if err != nil {
+		return nil, err
+	}
+	if hasTTLChange {
+		return nil, ErrSchemaChangesDisallowedDueToTTLChange
+	}
 
-			// Virtual computed columns are not allowed to be indexed
-			if columnNames[i].generated && !virtualComputedStored {
-				isStored, err := og.columnIsStoredComputed(ctx, tx, tableName, columnNames[i].name)
-				if err != nil {
-					return nil, err
-				}
-				if !isStored {
-					virtualComputedStored = true
-				}
-			}
+	// Occasionally skip validating the existing rows, leaving that to a later
+	// ALTER TABLE ... VALIDATE CONSTRAINT.
+	validationBehavior := tree.ValidationDefault
+	if og.randIntn(4) == 0 {
+		validationBehavior = tree.ValidationSkip
+	}
 
-			// If the column is already used in the primary key, then attempting to store
-			// it using an index will produce a pgcode.DuplicateColumn error.
-			if !duplicateStore {
-				colUsedInPrimaryIdx, err := og.colIsPrimaryKey(ctx, tx, tableName, columnNames[i].name)
-				if err != nil {
-					return nil, err
-				}
-				if colUsedInPrimaryIdx {
-					duplicateStore = true
-				}
-			}
-		}
+	return og.addForeignKeyConstraintStmt(
+		ctx, tx, parentTable, parentColumns, childTable, childColumns, actions, validationBehavior,
+	)
+}
+
+// addForeignKeyConstraintStmt returns a statement adding a foreign key from
+// the child columns to the parent columns, which are matched by position, and
+// sets up the errors it is expected to produce.
+func (og *operationGenerator) addForeignKeyConstraintStmt(
+	ctx context.Context,
+	tx pgx.Tx,
+	parentTable *tree.TableName,
+	parentColumns []*column,
+	childTable *tree.TableName,
+	childColumns []*column,
+	actions tree.ReferenceActions,
+	validationBehavior tree.ValidationBehavior,
+) (*opStmt, error) {
+	var parentColumnNames, childColumnNames []string
+	var quotedParentColumnNames, quotedChildColumnNames []string
+	var fromCols, toCols tree.NameList
+	typesMatch := true
+	for i := range parentColumns {
+		fromCols = append(fromCols, tree.Name(childColumns[i].name))
+		toCols = append(toCols, tree.Name(parentColumns[i].name))
+		parentColumnNames = append(parentColumnNames, parentColumns[i].name)
+		childColumnNames = append(childColumnNames, childColumns[i].name)
+		quotedParentColumnNames = append(quotedParentColumnNames, tree.NameString(parentColumns[i].name))
+		quotedChildColumnNames = append(quotedChildColumnNames, tree.NameString(childColumns[i].name))
+		typesMatch = typesMatch && childColumns[i].typ.Equivalent(parentColumns[i].typ)
+	}
+
+	constraintName := tree.Name(fmt.Sprintf("%s_%s_%s_%s_fk",
+		parentTable.Object(), strings.Join(parentColumnNames, "_"),
+		childTable.Object(), strings.Join(childColumnNames, "_")))
+
+	def := &tree.AlterTable{
+		Table: childTable.ToUnresolvedObjectName(),
+		Cmds: tree.AlterTableCmds{
+			&tree.AlterTableAddConstraint{
+				ConstraintDef: &tree.ForeignKeyConstraintTableDef{
+					Name:     constraintName,
+					Table:    *parentTable,
+					FromCols: fromCols,
+					ToCols:   toCols,
+					Actions:  actions,
+				},
+				ValidationBehavior: validationBehavior,
+			},
+		},
 	}
 
-	// Verify that a unique constraint can be added given the existing rows which may exist in the table.
-	uniqueViolationWillNotOccur := true
-	if def.Unique {
-		columns := []string{}
-		for _, col := range def.Columns {
-			columns = append(columns, string(col.Column))
+	childIsComputed := false
+	childIsVirtualComputed := false
+	for _, childColumn := range childColumns {
+		isVirtualComputed, err := og.columnIsVirtualComputed(ctx, tx, childTable, childColumn.name)
+		if err != nil {
+			return nil, err
 		}
-		uniqueViolationWillNotOccur, err = og.canApplyUniqueConstraint(ctx, tx, tableName, columns)
+		isStoredComputed, err := og.columnIsStoredComputed(ctx, tx, childTable, childColumn.name)
 		if err != nil {
 			return nil, err
 		}
+		childIsComputed = childIsComputed || isVirtualComputed || isStoredComputed
+		childIsVirtualComputed = childIsVirtualComputed || isVirtualComputed
 	}
-
-	hasAlterPKSchemaChange, err := og.tableHasOngoingAlterPKSchemaChanges(ctx, tx, tableName)
+	parentIsVirtualComputed := false
+	for _, parentColumn := range parentColumns {
+		isVirtualComputed, err := og.columnIsVirtualComputed(ctx, tx, parentTable, parentColumn.name)
+		if err != nil {
+			return nil, err
+		}
+		parentIsVirtualComputed = parentIsVirtualComputed || isVirtualComputed
+	}
+	parentColumnsHaveUniqueConstraint, err := og.columnsHaveUniqueConstraint(ctx, tx, parentTable, parentColumnNames)
 	if err != nil {
 		return nil, err
 	}
-
-	databaseHasRegionChange, err := og.databaseHasRegionChange(ctx, tx)
+	constraintExists, err := og.constraintExists(ctx, tx, string(constraintName))
 	if err != nil {
 		return nil, err
 	}
-	if databaseHasRegionChange && tableIsRegionalByRow {
-		stmt.expectedExecErrors.add(pgcode.ObjectNotInPrerequisiteState)
+	// Existing rows are only checked if the constraint is validated, and it
+	// doesn't make sense to compare values of mismatched types.
+	rowsViolateConstraint := false
+	if typesMatch && validationBehavior != tree.ValidationSkip {
+		rowsViolateConstraint, err = og.foreignKeyRowsAreViolated(
+			ctx, tx, childTable.String(), quotedChildColumnNames, parentTable.String(), quotedParentColumnNames,
+		)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// When an index exists, but `IF NOT EXISTS` is used, then
-	// the index will not be created and the op will complete without errors.
-	if !(indexExists && def.IfNotExists) {
-		stmt.expectedExecErrors.addAll(codesWithConditions{
-			{code: pgcode.DuplicateRelation, condition: indexExists},
-			// Inverted indexes do not support stored columns.
-			{code: pgcode.InvalidSQLStatementName, condition: len(def.Storing) > 0 && def.Inverted},
-			// Inverted indexes cannot be unique.
-			{code: pgcode.InvalidSQLStatementName, condition: def.Unique && def.Inverted},
-			// If there is data in the table such that a unique index cannot be created,
-			// a pgcode.UniqueViolation will occur and will be wrapped in a
-			// pgcode.TransactionCommittedWithSchemaChangeFailure. The schemachange worker
-			// is expected to parse for the underlying error.
-			{code: pgcode.UniqueViolation, condition: !uniqueViolationWillNotOccur},
-			{code: pgcode.DuplicateColumn, condition: duplicateStore},
-			{code: pgcode.FeatureNotSupported, condition: nonIndexableType},
-			{code: pgcode.FeatureNotSupported, condition: regionColStored},
-			{code: pgcode.FeatureNotSupported, condition: duplicateRegionColumn},
-			{code: pgcode.Uncategorized, condition: virtualComputedStored},
-			{code: pgcode.FeatureNotSupported, condition: hasAlterPKSchemaChange},
+	stmt := makeOpStmt(OpStmtDDL)
+	stmt.expectedExecErrors.addAll(codesWithConditions{
+		{code: pgcode.ForeignKeyViolation, condition: !parentColumnsHaveUniqueConstraint},
+		{code: pgcode.FeatureNotSupported, condition: childIsVirtualComputed},
+		{code: pgcode.FeatureNotSupported, condition: parentIsVirtualComputed},
+		{code: pgcode.DuplicateObject, condition: constraintExists},
+		{code: pgcode.DatatypeMismatch, condition: !typesMatch},
+		{code: pgcode.InvalidForeignKey, condition: invalidReferenceActions(actions, childColumns, childIsComputed)},
+	})
+	// The existing rows are validated by a job once the transaction commits,
+	// so a violation is reported as a commit error. The rows of a table
+	// created in the same transaction may instead already be validated while
+	// executing the statement.
+	if rowsViolateConstraint && og.tableCreatedInTxn(childTable) {
+		stmt.potentialExecErrors.add(pgcode.ForeignKeyViolation)
+		og.potentialCommitErrors.add(pgcode.ForeignKeyViolation)
+	} else {
+		og.candidateExpectedCommitErrors.addAll(codesWithConditions{
+			{code: pgcode.ForeignKeyViolation, condition: rowsViolateConstraint},
 		})
 	}
-
 	stmt.sql = tree.Serialize(def)
 	return stmt, nil
 }
 
-func (og *operationGenerator) createSequence(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
-	seqName, err := og.randSequence(ctx, tx, og.pctExisting(false), "")
+// validateConstraint validates a constraint of a table, preferring those
+// added with NOT VALID, which are the only ones whose rows are checked.
+func (og *operationGenerator) validateConstraint(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
 	if err != nil {
 		return nil, err
 	}
-
-	schemaExists, err := og.schemaExists(ctx, tx, seqName.Schema())
+	tableExists, err := og.tableExists(ctx, tx, tableName)
 	if err != nil {
 		return nil, err
 	}
-	sequenceExists, err := og.sequenceExists(ctx, tx, seqName)
+	if !tableExists {
+		return makeOpStmtForSingleError(OpStmtDDL,
+			fmt.Sprintf(`ALTER TABLE %s VALIDATE CONSTRAINT "IrrelevantConstraintName"`, tableName),
+			pgcode.UndefinedTable), nil
+	}
+	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
 	if err != nil {
 		return nil, err
 	}
 
-	// If the sequence exists and an error should be produced, then
-	// exclude the IF NOT EXISTS clause from the statement. Otherwise, default
-	// to including the clause prevent all pgcode.DuplicateRelation errors.
-	ifNotExists := true
-	if sequenceExists && og.produceError() {
-		ifNotExists = false
+	type tableConstraint struct {
+		Name        string
+		Type        string
+		Validated   bool
+		IndexBacked bool
+		Expr        string
 	}
-	stmt := makeOpStmt(OpStmtDDL)
-	stmt.expectedExecErrors.addAll(codesWithConditions{
-		{code: pgcode.UndefinedSchema, condition: !schemaExists},
-		{code: pgcode.DuplicateRelation, condition: sequenceExists && !ifNotExists},
+	constraints, err := Collect(ctx, og, tx, pgx.RowToStructByPos[tableConstraint], `
+		SELECT conname, contype::STRING, convalidated, conindid != 0, COALESCE(consrc, '')
+			FROM pg_catalog.pg_constraint
+		 WHERE conrelid = $1::REGCLASS
+	`, tableName.String())
+	if err != nil {
+		return nil, og.checkAndAdjustForUnknownSchemaErrors(err)
+	}
+	unvalidated := util.Filter(constraints, func(c tableConstraint) bool {
+		return !c.Validated
 	})
 
-	var seqOptions tree.SequenceOptions
-	// Decide if the sequence should be owned by a column. If so, it can
-	// set using the tree.SeqOptOwnedBy sequence option.
-	if og.randIntn(100) < og.params.sequenceOwnedByPct {
-		table, err := og.randTable(ctx, tx, og.pctExisting(true), "")
-		if err != nil {
-			return nil, err
+	stmt := makeOpStmt(OpStmtDDL)
+	constraint := tableConstraint{Name: "ConstraintThatDoesntExist"}
+	switch {
+	case og.produceError() && og.randIntn(2) == 0:
+		stmt.expectedExecErrors.add(pgcode.UndefinedObject)
+	case len(unvalidated) > 0 && og.randIntn(4) != 0:
+		constraint, _ = PickOne(og.params.rng, unvalidated)
+	case len(constraints) > 0:
+		constraint, _ = PickOne(og.params.rng, constraints)
+	default:
+		stmt.expectedExecErrors.add(pgcode.UndefinedObject)
+	}
+
+	switch {
+	case constraint.IndexBacked:
+		// Only the declarative schema changer rejects validating constraints
+		// backed by an index, which are always valid.
+		if og.useDeclarativeSchemaChanger {
+			stmt.expectedExecErrors.add(pgcode.WrongObjectType)
+		}
+	case !constraint.Validated && constraint.Type != "":
+		// Unique constraints without an index are the only other kind of
+		// constraint that can be unvalidated, and are never created by the
+		// workload.
+		var violated bool
+		var code pgcode.Code
+		switch constraint.Type {
+		case "c":
+			code = pgcode.CheckViolation
+			violated, err = og.checkConstraintIsViolated(ctx, tx, tableName, constraint.Expr)
+		case "f":
+			code = pgcode.ForeignKeyViolation
+			violated, err = og.foreignKeyConstraintIsViolated(ctx, tx, tableName, constraint.Name)
 		}
-		tableExists, err := og.tableExists(ctx, tx, table)
 		if err != nil {
 			return nil, err
 		}
+		// The legacy schema changer validates the rows while executing the
+		// statement, whereas the declarative schema changer does so once the
+		// transaction commits.
+		if violated && og.useDeclarativeSchemaChanger {
+			og.candidateExpectedCommitErrors.add(code)
+		} else if violated {
+			stmt.expectedExecErrors.add(code)
+		}
+	}
 
-		if !tableExists {
-			seqOptions = append(
-				seqOptions,
-				tree.SequenceOption{
-					Name:          tree.SeqOptOwnedBy,
-					ColumnItemVal: &tree.ColumnItem{TableName: table.ToUnresolvedObjectName(), ColumnName: "IrrelevantColumnName"}},
-			)
-			if !(sequenceExists && ifNotExists) { // IF NOT EXISTS prevents the error
-				stmt.expectedExecErrors.add(pgcode.UndefinedTable)
-			}
-		} else {
-			column, err := og.randColumn(ctx, tx, *table, og.pctExisting(true))
-			if err != nil {
-				return nil, err
-			}
-			columnExists, err := og.columnExistsOnTable(ctx, tx, table, column)
-			if err != nil {
-				return nil, err
-			}
-			// If a duplicate sequence exists, then a new sequence will not be created. In this case,
-			// a pgcode.UndefinedColumn will not occur.
-			if !columnExists && !sequenceExists {
-				stmt.expectedExecErrors.add(pgcode.UndefinedColumn)
-			}
-
-			seqOptions = append(
-				seqOptions,
-				tree.SequenceOption{
-					Name:          tree.SeqOptOwnedBy,
-					ColumnItemVal: &tree.ColumnItem{TableName: table.ToUnresolvedObjectName(), ColumnName: tree.Name(column)}},
-			)
-		}
-	}
-
-	createSeq := &tree.CreateSequence{
-		IfNotExists: ifNotExists,
-		Name:        *seqName,
-		Options:     seqOptions,
+	// A constraint added or dropped earlier in this transaction cannot be
+	// validated until that is committed.
+	inMutation, err := og.constraintInMutation(ctx, tx, tableName, constraint.Name)
+	if err != nil {
+		return nil, err
 	}
+	stmt.potentialExecErrors.addAll(codesWithConditions{
+		{pgcode.ObjectNotInPrerequisiteState, inMutation},
+		{pgcode.FeatureNotSupported, inMutation && og.useDeclarativeSchemaChanger},
+	})
 
-	stmt.sql = tree.Serialize(createSeq)
+	stmt.sql = fmt.Sprintf(`ALTER TABLE %s VALIDATE CONSTRAINT %s`,
+		tableName, tree.NameString(constraint.Name))
 	return stmt, nil
 }
 
-var trailingDigits = regexp.MustCompile(`\d+$`)
-
-func (og *operationGenerator) createTable(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
-	tableName, err := og.randTable(ctx, tx, og.pctExisting(false), "")
+func (og *operationGenerator) createIndex(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
 	if err != nil {
 		return nil, err
 	}
 
-	tableIdxStr := trailingDigits.FindString(tableName.Table())
-	tableIdx, err := strconv.Atoi(tableIdxStr)
+	tableExists, err := og.tableExists(ctx, tx, tableName)
 	if err != nil {
 		return nil, err
 	}
-	databaseHasMultiRegion, err := og.databaseIsMultiRegion(ctx, tx)
+	if !tableExists {
+		def := &tree.CreateIndex{
+			Name:  tree.Name("IrrelevantName"),
+			Table: *tableName,
+			Columns: tree.IndexElemList{
+				{Column: "IrrelevantColumn", Direction: tree.Ascending},
+			},
+		}
+		return makeOpStmtForSingleError(OpStmtDDL,
+			tree.Serialize(def),
+			pgcode.UndefinedTable), nil
+	}
+	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
 	if err != nil {
 		return nil, err
 	}
 
-	stmt := randgen.RandCreateTableWithColumnIndexNumberGenerator(
-		ctx, og.params.rng, "table", tableIdx, databaseHasMultiRegion,
-		true /* allowPartiallyVisibleIndex */, og.newUniqueSeqNumSuffix,
-	)
-	stmt.Table = *tableName
-	stmt.IfNotExists = og.randIntn(2) == 0
-	hasVectorType := func() bool {
-		// Check if any of the indexes have PGVector types involved.
-		for _, def := range stmt.Defs {
-			if col, ok := def.(*tree.ColumnTableDef); ok && strings.HasPrefix(col.Type.SQLString(), "VECTOR") {
-				return true
-			}
-		}
-		return false
-	}()
-
-	tableExists, err := og.tableExists(ctx, tx, tableName)
+	columnNames, err := og.getTableColumns(ctx, tx, tableName, true)
 	if err != nil {
 		return nil, err
 	}
-	schemaExists, err := og.schemaExists(ctx, tx, tableName.Schema())
-	if err != nil {
-		return nil, err
+	// Occasionally lead the index with the computed columns of the table. The
+	// values of STORED columns are read from the primary index when building
+	// and maintaining the index, while those of VIRTUAL columns have to be
+	// recomputed from the columns they reference.
+	if og.randIntn(3) == 0 {
+		slices.SortStableFunc(columnNames, func(a, b column) int {
+			switch {
+			case a.generated && !b.generated:
+				return -1
+			case !a.generated && b.generated:
+				return 1
+			}
+			return 0
+		})
 	}
-	opStmt := makeOpStmt(OpStmtDDL)
-	opStmt.expectedExecErrors.addAll(codesWithConditions{
-		{code: pgcode.DuplicateRelation, condition: tableExists && !stmt.IfNotExists},
-		{code: pgcode.UndefinedSchema, condition: !schemaExists},
-	})
-	// Compatibility errors aren't guaranteed since the cluster version update is not
-	// fully transaction aware.
-	opStmt.potentialExecErrors.addAll(codesWithConditions{
-		{code: pgcode.Syntax, condition: hasVectorType},
-		{code: pgcode.FeatureNotSupported, condition: hasVectorType},
-	})
-	opStmt.sql = tree.Serialize(stmt)
-	return opStmt, nil
-}
-
-func (og *operationGenerator) createEnum(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
-	return og.createType(ctx, tx, true)
-}
-
-func (og *operationGenerator) createCompositeType(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
-	return og.createType(ctx, tx, false)
-}
-
-func (og *operationGenerator) createType(
-	ctx context.Context, tx pgx.Tx, isEnum bool,
-) (*opStmt, error) {
 
-	typName, typeExists, err := og.randTypeName(ctx, tx, og.pctExisting(false), isEnum)
+	// Occasionally lead the index with the INET columns of the table instead,
+	// whose keys are ordered by family, netmask and address.
+	isINetColumn := func(c column) bool {
+		return c.typ != nil && c.typ.Family() == types.INetFamily
+	}
+	if og.randIntn(3) == 0 {
+		slices.SortStableFunc(columnNames, func(a, b column) int {
+			switch {
+			case isINetColumn(a) && !isINetColumn(b):
+				return -1
+			case !isINetColumn(a) && isINetColumn(b):
+				return 1
+			}
+			return 0
+		})
+	}
 
+	indexName, err := og.randIndex(ctx, tx, *tableName, og.pctExisting(false))
 	if err != nil {
 		return nil, err
 	}
-	schemaExists, err := og.schemaExists(ctx, tx, typName.Schema())
+
+	indexExists, err := og.indexExists(ctx, tx, tableName, indexName)
 	if err != nil {
 		return nil, err
 	}
-	opStmt := makeOpStmt(OpStmtDDL)
-	opStmt.expectedExecErrors.addAll(codesWithConditions{
-		{code: pgcode.DuplicateObject, condition: typeExists},
-		{code: pgcode.InvalidSchemaName, condition: !schemaExists},
-	})
-
-	const letters = "abcdefghijklmnopqrstuvwxyz"
-	var statement tree.Statement
 
-	if isEnum {
-		statement = randgen.RandCreateEnumType(og.params.rng, typName.Object(), letters)
-	} else {
-		statement = randgen.RandCreateCompositeType(og.params.rng, typName.Object(), letters)
+	invisibility := tree.IndexInvisibility{Value: 0.0}
+	if notvisible := og.randIntn(20) == 0; notvisible {
+		invisibility.Value = 1.0
+		if og.randIntn(2) == 0 {
+			invisibility.Value = 1 - og.params.rng.Float64()
+			invisibility.FloatProvided = true
+		}
 	}
 
-	statement.(*tree.CreateType).TypeName = typName.ToUnresolvedObjectName()
-	opStmt.sql = tree.Serialize(statement)
-	return opStmt, nil
-}
-
-func (og *operationGenerator) createTableAs(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
-	const MaxRowsToConsume = 300000
-	numSourceTables := og.randIntn(og.params.maxSourceTables) + 1
+	def := &tree.CreateIndex{
+		Name:         tree.Name(indexName),
+		Table:        *tableName,
+		Unique:       og.randIntn(4) == 0,  // 25% UNIQUE
+		Inverted:     og.randIntn(10) == 0, // 10% INVERTED
+		IfNotExists:  og.randIntn(2) == 0,  // 50% IF NOT EXISTS
+		Invisibility: invisibility,         // 5% NOT VISIBLE
+	}
 
-	sourceTableNames := make([]tree.TableExpr, numSourceTables)
-	sourceTableExistence := make([]bool, numSourceTables)
+	// Occasionally create an inverted index over the JSONB columns of the
+	// table, prefixed by other JSONB columns and possibly indexing a specific
+	// path of the last one.
+	isJSONColumn := func(c column) bool {
+		return c.typ != nil && c.typ.Family() == types.JsonFamily
+	}
+	numJSONColumns := len(util.Filter(columnNames, isJSONColumn))
+	jsonInvertedIndex := def.Inverted && numJSONColumns > 0 && og.randIntn(2) == 0
+	if jsonInvertedIndex {
+		slices.SortStableFunc(columnNames, func(a, b column) int {
+			switch {
+			case isJSONColumn(a) && !isJSONColumn(b):
+				return -1
+			case !isJSONColumn(a) && isJSONColumn(b):
+				return 1
+			}
+			return 0
+		})
+	}
 
-	// uniqueTableNames and duplicateSourceTables are used to track unique
-	// tables. If there are any duplicates, then a pgcode.DuplicateAlias error
-	// is expected on execution.
-	uniqueTableNames := map[string]bool{}
-	duplicateSourceTables := false
+	regionColumn := ""
+	tableIsRegionalByRow, err := og.tableIsRegionalByRow(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if tableIsRegionalByRow {
+		regionColumn, err = og.getRegionColumn(ctx, tx, tableName)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	// Collect a random set of size numSourceTables that contains tables and views
-	// from which to use columns.
-	for i := 0; i < numSourceTables; i++ {
-		var tableName *tree.TableName
-		var err error
-		var sourceTableExists bool
+	// Define columns on which to create an index. Check for types which cannot be indexed.
+	duplicateRegionColumn := false
+	nonIndexableType := false
+	def.Columns = make(tree.IndexElemList, 1+og.randIntn(len(columnNames)))
+	if jsonInvertedIndex {
+		def.Columns = make(tree.IndexElemList, 1+og.randIntn(numJSONColumns))
+	}
+	for i := range def.Columns {
+		def.Columns[i].Column = tree.Name(columnNames[i].name)
+		if !jsonInvertedIndex {
+			def.Columns[i].Direction = tree.Direction(og.randIntn(1 + int(tree.Descending)))
+		}
 
-		switch randInt := og.randIntn(1); randInt {
-		case 0:
-			tableName, err = og.randTable(ctx, tx, og.pctExisting(true), "")
-			if err != nil {
-				return nil, err
+		// When creating an index, the column being used as the region column
+		// for a REGIONAL BY ROW table can only be included in indexes as the
+		// first column. If it's not the first column, we need to add an error
+		// below.
+		if columnNames[i].name == regionColumn && i != 0 {
+			duplicateRegionColumn = true
+		}
+		if def.Inverted {
+			// We can have an inverted index on a set of columns if the last column
+			// is an inverted indexable type and the preceding columns can be
+			// indexed in a forward index.
+			if (i < len(def.Columns)-1 && !colinfo.ColumnTypeIsIndexable(columnNames[i].typ)) ||
+				(i == len(def.Columns)-1 && !colinfo.ColumnTypeIsInvertedIndexable(columnNames[i].typ)) {
+				nonIndexableType = true
 			}
-			sourceTableExists, err = og.tableExists(ctx, tx, tableName)
-			if err != nil {
-				return nil, err
+		} else {
+			if !colinfo.ColumnTypeIsIndexable(columnNames[i].typ) {
+				nonIndexableType = true
 			}
+		}
+	}
 
-		case 1:
-			tableName, err = og.randView(ctx, tx, og.pctExisting(true), "")
-			if err != nil {
-				return nil, err
-			}
-			sourceTableExists, err = og.viewExists(ctx, tx, tableName)
+	// Inverted indexes over a JSONB column may instead index a path within
+	// it, and may specify an operator class.
+	lastColumn := &def.Columns[len(def.Columns)-1]
+	unsupportedOpClass := false
+	if jsonInvertedIndex {
+		if og.randIntn(2) == 0 {
+			path, err := parser.ParseExpr(fmt.Sprintf(
+				"%s->%s", tree.NameString(string(lastColumn.Column)), tree.NewDString(fmt.Sprintf("key_%d", og.randIntn(3))),
+			))
 			if err != nil {
 				return nil, err
 			}
+			lastColumn.Column, lastColumn.Expr = "", path
+		}
+		switch og.randIntn(4) {
+		case 0:
+			lastColumn.OpClass = "jsonb_ops"
+		case 1:
+			// The jsonb_path_ops operator class isn't supported yet, see #81115.
+			lastColumn.OpClass = "jsonb_path_ops"
+			unsupportedOpClass = true
 		}
+	}
+	// Trigram indexes are the only inverted indexes on STRING columns, so an
+	// operator class must be specified for them.
+	missingOpClass := def.Inverted && lastColumn.OpClass == "" &&
+		columnNames[len(def.Columns)-1].typ != nil &&
+		columnNames[len(def.Columns)-1].typ.Family() == types.StringFamily
 
-		sourceTableNames[i] = tableName
-		sourceTableExistence[i] = sourceTableExists
-		if _, exists := uniqueTableNames[tableName.String()]; exists {
-			duplicateSourceTables = true
-		} else {
-			uniqueTableNames[tableName.String()] = true
+	// Occasionally make the index partial, with a predicate that spans
+	// multiple columns.
+	if og.randIntn(4) == 0 {
+		def.Predicate, err = og.randMultiColumnPredicate(columnNames)
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	selectStatement := tree.SelectClause{
-		From: tree.From{Tables: sourceTableNames},
+	// Occasionally customize the S2 configuration of spatial indexes, i.e.
+	// inverted indexes on a GEOMETRY or GEOGRAPHY column.
+	invalidS2Config := false
+	if lastColumnType := columnNames[len(def.Columns)-1].typ; def.Inverted && lastColumnType != nil &&
+		(lastColumnType.Family() == types.GeometryFamily || lastColumnType.Family() == types.GeographyFamily) &&
+		og.randIntn(2) == 0 {
+		invalidS2Config = og.produceError()
+		def.StorageParams = randS2IndexStorageParams(og.params.rng, lastColumnType, invalidS2Config)
 	}
 
-	// uniqueColumnNames and duplicateColumns are used to track unique
-	// columns. If there are any duplicates, then a pgcode.DuplicateColumn error
-	// is expected on execution.
-	opStmt := makeOpStmt(OpStmtDDL)
-	uniqueColumnNames := map[string]bool{}
-	duplicateColumns := false
-	for i := 0; i < numSourceTables; i++ {
-		tableName := sourceTableNames[i]
-		tableExists := sourceTableExistence[i]
+	// If there are extra columns not used in the index, randomly use them
+	// as stored columns.
+	stmt := makeOpStmt(OpStmtDDL)
+	duplicateStore := false
+	virtualComputedStored := false
+	regionColStored := false
+	columnNames = columnNames[len(def.Columns):]
+	if n := len(columnNames); n > 0 {
+		def.Storing = make(tree.NameList, og.randIntn(1+n))
+		for i := range def.Storing {
+			def.Storing[i] = tree.Name(columnNames[i].name)
 
-		// If the table does not exist, columns cannot be fetched from it. For this reason, the placeholder
-		// "IrrelevantColumnName" is used, and a pgcode.UndefinedTable error is expected on execution.
-		if tableExists {
-			columnNamesForTable, err := og.tableColumnsShuffled(ctx, tx, tableName.(*tree.TableName).String())
-			if err != nil {
-				return nil, err
+			// The region column can not be stored.
+			if tableIsRegionalByRow && columnNames[i].name == regionColumn {
+				regionColStored = true
 			}
-			columnNamesForTable = columnNamesForTable[:1+og.randIntn(len(columnNamesForTable))]
 
-			for j := range columnNamesForTable {
-				colItem := tree.ColumnItem{
-					TableName:  tableName.(*tree.TableName).ToUnresolvedObjectName(),
-					ColumnName: tree.Name(columnNamesForTable[j]),
+			// Virtual computed columns are not allowed to be indexed
+			if columnNames[i].generated && !virtualComputedStored {
+				isStored, err := og.columnIsStoredComputed(ctx, tx, tableName, columnNames[i].name)
+				if err != nil {
+					return nil, err
 				}
-				selectStatement.Exprs = append(selectStatement.Exprs, tree.SelectExpr{Expr: &colItem})
+				if !isStored {
+					virtualComputedStored = true
+				}
+			}
 
-				if _, exists := uniqueColumnNames[columnNamesForTable[j]]; exists {
-					duplicateColumns = true
-				} else {
-					uniqueColumnNames[columnNamesForTable[j]] = true
+			// If the column is already used in the primary key, then attempting to store
+			// it using an index will produce a pgcode.DuplicateColumn error.
+			if !duplicateStore {
+				colUsedInPrimaryIdx, err := og.colIsPrimaryKey(ctx, tx, tableName, columnNames[i].name)
+				if err != nil {
+					return nil, err
+				}
+				if colUsedInPrimaryIdx {
+					duplicateStore = true
 				}
 			}
-		} else {
-			opStmt.expectedExecErrors.add(pgcode.UndefinedTable)
-			colItem := tree.ColumnItem{
-				ColumnName: tree.Name("IrrelevantColumnName"),
-			}
-			selectStatement.Exprs = append(selectStatement.Exprs, tree.SelectExpr{Expr: &colItem})
 		}
 	}
 
-	destTableName, err := og.randTable(ctx, tx, og.pctExisting(false), "")
-	if err != nil {
-		return nil, err
+	// Verify that a unique constraint can be added given the existing rows which may exist in the table.
+	// The check considers every row in the table, so for a partial index a
+	// violation is only possible rather than guaranteed.
+	uniqueViolationWillNotOccur := true
+	if def.Unique && !def.Inverted {
+		columns := []string{}
+		for _, col := range def.Columns {
+			columns = append(columns, string(col.Column))
+		}
+		uniqueViolationWillNotOccur, err = og.canApplyUniqueConstraint(ctx, tx, tableName, columns)
+		if err != nil {
+			return nil, err
+		}
 	}
-	schemaExists, err := og.schemaExists(ctx, tx, destTableName.Schema())
+
+	hasAlterPKSchemaChange, err := og.tableHasOngoingAlterPKSchemaChanges(ctx, tx, tableName)
 	if err != nil {
 		return nil, err
 	}
-	tableExists, err := og.tableExists(ctx, tx, destTableName)
+
+	databaseHasRegionChange, err := og.databaseHasRegionChange(ctx, tx)
 	if err != nil {
 		return nil, err
 	}
-
-	opStmt.expectedExecErrors.addAll(codesWithConditions{
-		{code: pgcode.InvalidSchemaName, condition: !schemaExists},
-		{code: pgcode.DuplicateRelation, condition: tableExists},
-		{code: pgcode.Syntax, condition: len(selectStatement.Exprs) == 0},
-		{code: pgcode.DuplicateAlias, condition: duplicateSourceTables},
-		{code: pgcode.DuplicateColumn, condition: duplicateColumns},
-	})
-	// Confirm the select itself doesn't run into any column generation errors,
-	// by executing it independently first until we add validation when adding
-	// generated columns. See issue: #81698?, which will allow us to remove this
-	// logic in the future.
-	if opStmt.expectedExecErrors.empty() {
-		opStmt.potentialExecErrors.merge(getValidGenerationErrors())
+	if databaseHasRegionChange && tableIsRegionalByRow {
+		stmt.expectedExecErrors.add(pgcode.ObjectNotInPrerequisiteState)
 	}
 
-	opStmt.sql = fmt.Sprintf(`CREATE TABLE %s AS %s FETCH FIRST %d ROWS ONLY`,
-		destTableName, selectStatement.String(), MaxRowsToConsume)
-	return opStmt, nil
-}
-
-func (og *operationGenerator) createView(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
-
-	numSourceTables := og.randIntn(og.params.maxSourceTables) + 1
-
-	sourceTableNames := make([]tree.TableExpr, numSourceTables)
-	sourceTableExistence := make([]bool, numSourceTables)
+	// When an index exists, but `IF NOT EXISTS` is used, then
+	// the index will not be created and the op will complete without errors.
+	if !(indexExists && def.IfNotExists) {
+		stmt.expectedExecErrors.addAll(codesWithConditions{
+			{code: pgcode.DuplicateRelation, condition: indexExists},
+			// Inverted indexes do not support stored columns.
+			{code: pgcode.InvalidSQLStatementName, condition: len(def.Storing) > 0 && def.Inverted},
+			// Inverted indexes cannot be unique.
+			{code: pgcode.InvalidSQLStatementName, condition: def.Unique && def.Inverted},
+			// If there is data in the table such that a unique index cannot be created,
+			// a pgcode.UniqueViolation will occur and will be wrapped in a
+			// pgcode.TransactionCommittedWithSchemaChangeFailure. The schemachange worker
+			// is expected to parse for the underlying error.
+			{code: pgcode.UniqueViolation, condition: !uniqueViolationWillNotOccur && def.Predicate == nil},
+			{code: pgcode.DuplicateColumn, condition: duplicateStore},
+			{code: pgcode.FeatureNotSupported, condition: nonIndexableType},
+			{code: pgcode.FeatureNotSupported, condition: regionColStored},
+			{code: pgcode.FeatureNotSupported, condition: duplicateRegionColumn},
+			{code: pgcode.Uncategorized, condition: virtualComputedStored},
+			{code: pgcode.FeatureNotSupported, condition: hasAlterPKSchemaChange},
+			{code: pgcode.InvalidParameterValue, condition: invalidS2Config},
+			{code: pgcode.FeatureNotSupported, condition: unsupportedOpClass},
+			{code: pgcode.UndefinedObject, condition: missingOpClass},
+			// The last column of an inverted index can't be descending.
+			{code: pgcode.FeatureNotSupported, condition: def.Inverted && lastColumn.Direction == tree.Descending},
+		})
+	}
 
-	// uniqueTableNames and duplicateSourceTables are used to track unique
-	// tables. If there are any duplicates, then a pgcode.DuplicateColumn error
-	// is expected on execution.
-	uniqueTableNames := map[string]bool{}
-	duplicateSourceTables := false
+	// The declarative schema changer rejects descending prefix columns of an
+	// inverted index as well.
+	descendingInvertedPrefix := false
+	if def.Inverted {
+		for _, col := range def.Columns[:len(def.Columns)-1] {
+			descendingInvertedPrefix = descendingInvertedPrefix || col.Direction == tree.Descending
+		}
+	}
+	stmt.potentialExecErrors.addAll(codesWithConditions{
+		{code: pgcode.UniqueViolation, condition: !uniqueViolationWillNotOccur && def.Predicate != nil},
+		{code: pgcode.FeatureNotSupported, condition: descendingInvertedPrefix},
+	})
 
-	// Collect a random set of size numSourceTables that contains tables and views
-	// from which to use columns.
-	for i := 0; i < numSourceTables; i++ {
-		var tableName *tree.TableName
-		var err error
-		var sourceTableExists bool
+	stmt.sql = tree.Serialize(def)
+	return stmt, nil
+}
 
-		switch randInt := og.randIntn(1); randInt {
+// randS2IndexStorageParams returns storage parameters customizing the S2
+// configuration of a spatial index on a column of the given type, which must
+// be GEOMETRY or GEOGRAPHY. If invalid is set, the parameters are rejected
+// with an InvalidParameterValue error, and they are accepted otherwise.
+func randS2IndexStorageParams(rng *rand.Rand, typ *types.T, invalid bool) tree.StorageParams {
+	levelMod := 1 + rng.Intn(3)
+	maxLevel := levelMod * (1 + rng.Intn(30/levelMod))
+	maxCells := 1 + rng.Intn(32)
+	// The bounds of GEOMETRY indexes default to those of the SRID of the
+	// column, and may be overridden by custom ones.
+	withBounds := typ.Family() == types.GeometryFamily && rng.Intn(2) == 0
+	minX, maxX := -float64(1+rng.Intn(10000)), float64(1+rng.Intn(10000))
+	minY, maxY := -float64(1+rng.Intn(10000)), float64(1+rng.Intn(10000))
+
+	if invalid {
+		switch rng.Intn(3) {
 		case 0:
-			tableName, err = og.randTable(ctx, tx, og.pctExisting(true), "")
-			if err != nil {
-				return nil, err
-			}
-			sourceTableExists, err = og.tableExists(ctx, tx, tableName)
-			if err != nil {
-				return nil, err
-			}
-
+			// The maximum level must be a multiple of the level mod.
+			levelMod = 2 + rng.Intn(2)
+			maxLevel = levelMod*rng.Intn(30/levelMod) + 1
 		case 1:
-			tableName, err = og.randView(ctx, tx, og.pctExisting(true), "")
-			if err != nil {
-				return nil, err
-			}
-			sourceTableExists, err = og.viewExists(ctx, tx, tableName)
-			if err != nil {
-				return nil, err
+			// At most 32 cells may cover a shape.
+			maxCells = 33 + rng.Intn(32)
+		case 2:
+			// Bounds may only be set on GEOMETRY indexes, and the maximum of each
+			// axis must be greater than its minimum.
+			withBounds = true
+			if typ.Family() == types.GeometryFamily {
+				minX, maxX = maxX, minX
 			}
 		}
-
-		sourceTableNames[i] = tableName
-		sourceTableExistence[i] = sourceTableExists
-		if _, exists := uniqueTableNames[tableName.String()]; exists {
-			duplicateSourceTables = true
-		} else {
-			uniqueTableNames[tableName.String()] = true
-		}
 	}
 
-	selectStatement := tree.SelectClause{
-		From: tree.From{Tables: sourceTableNames},
+	params := tree.StorageParams{
+		{Key: "s2_level_mod", Value: tree.NewDInt(tree.DInt(levelMod))},
+		{Key: "s2_max_level", Value: tree.NewDInt(tree.DInt(maxLevel))},
+		{Key: "s2_max_cells", Value: tree.NewDInt(tree.DInt(maxCells))},
 	}
-
-	// uniqueColumnNames and duplicateColumns are used to track unique
-	// columns. If there are any duplicates, then a pgcode.DuplicateColumn error
-	// is expected on execution.
-	opStmt := makeOpStmt(OpStmtDDL)
-	uniqueColumnNames := map[string]bool{}
-	duplicateColumns := false
-	for i := 0; i < numSourceTables; i++ {
-		tableName := sourceTableNames[i]
-		tableExists := sourceTableExistence[i]
-
-		// If the table does not exist, columns cannot be fetched from it. For this reason, the placeholder
-		// "IrrelevantColumnName" is used, and a pgcode.UndefinedTable error is expected on execution.
-		if tableExists {
-			columnNamesForTable, err := og.tableColumnsShuffled(ctx, tx, tableName.(*tree.TableName).String())
-			if err != nil {
-				return nil, err
-			}
-			columnNamesForTable = columnNamesForTable[:1+og.randIntn(len(columnNamesForTable))]
-
-			for j := range columnNamesForTable {
-				colItem := tree.ColumnItem{
-					TableName:  tableName.(*tree.TableName).ToUnresolvedObjectName(),
-					ColumnName: tree.Name(columnNamesForTable[j]),
-				}
-				selectStatement.Exprs = append(selectStatement.Exprs, tree.SelectExpr{Expr: &colItem})
-
-				if _, exists := uniqueColumnNames[columnNamesForTable[j]]; exists {
-					duplicateColumns = true
-				} else {
-					uniqueColumnNames[columnNamesForTable[j]] = true
-				}
-			}
-		} else {
-			opStmt.expectedExecErrors.add(pgcode.UndefinedTable)
-			colItem := tree.ColumnItem{
-				ColumnName: tree.Name("IrrelevantColumnName"),
-			}
-			selectStatement.Exprs = append(selectStatement.Exprs, tree.SelectExpr{Expr: &colItem})
-		}
+	if withBounds {
+		params = append(params,
+			tree.StorageParam{Key: "geometry_min_x", Value: tree.NewDFloat(tree.DFloat(minX))},
+			tree.StorageParam{Key: "geometry_max_x", Value: tree.NewDFloat(tree.DFloat(maxX))},
+			tree.StorageParam{Key: "geometry_min_y", Value: tree.NewDFloat(tree.DFloat(minY))},
+			tree.StorageParam{Key: "geometry_max_y", Value: tree.NewDFloat(tree.DFloat(maxY))},
+		)
 	}
+	return params
+}
 
-	destViewName, err := og.randView(ctx, tx, og.pctExisting(false), "")
-	if err != nil {
-		return nil, err
-	}
-	schemaExists, err := og.schemaExists(ctx, tx, destViewName.Schema())
-	if err != nil {
-		return nil, err
+// randMultiColumnPredicate returns a partial index predicate that
+// combines IS NULL and IS NOT NULL tests on two or more of the given
+// columns with AND and OR. Generated columns are never referenced. If
+// there are fewer than two candidate columns, nil is returned.
+func (og *operationGenerator) randMultiColumnPredicate(columns []column) (tree.Expr, error) {
+	var candidates []column
+	for _, col := range columns {
+		if !col.generated {
+			candidates = append(candidates, col)
+		}
 	}
-	viewExists, err := og.viewExists(ctx, tx, destViewName)
-	if err != nil {
-		return nil, err
+	if len(candidates) < 2 {
+		return nil, nil
 	}
 
-	opStmt.expectedExecErrors.addAll(codesWithConditions{
-		{code: pgcode.InvalidSchemaName, condition: !schemaExists},
-		{code: pgcode.DuplicateRelation, condition: viewExists},
-		{code: pgcode.Syntax, condition: len(selectStatement.Exprs) == 0},
-		{code: pgcode.DuplicateAlias, condition: duplicateSourceTables},
-		{code: pgcode.DuplicateColumn, condition: duplicateColumns},
+	og.params.rng.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
 	})
-	// Descriptor ID generator may be temporarily unavailable, so
-	// allow uncategorized errors temporarily.
-	opStmt.sql = fmt.Sprintf(`CREATE VIEW %s AS %s`,
-		destViewName, selectStatement.String())
-	return opStmt, nil
+	candidates = candidates[:2+og.randIntn(len(candidates)-1)]
+
+	var pred tree.Expr
+	for _, col := range candidates {
+		var term tree.Expr
+		// The names of the columns are quoted identifiers, which are parsed
+		// so that they aren't quoted again.
+		colName, err := parser.ParseExpr(col.name)
+		if err != nil {
+			return nil, err
+		}
+		if og.randIntn(2) == 0 {
+			term = &tree.IsNullExpr{Expr: colName}
+		} else {
+			term = &tree.IsNotNullExpr{Expr: colName}
+		}
+		if pred == nil {
+			pred = term
+		} else if og.randIntn(2) == 0 {
+			pred = &tree.AndExpr{Left: pred, Right: term}
+		} else {
+			pred = &tree.OrExpr{Left: pred, Right: term}
+		}
+	}
+	return pred, nil
 }
 
-func (og *operationGenerator) dropColumn(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
-	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
+func (og *operationGenerator) createSequence(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	seqName, err := og.randSequence(ctx, tx, og.pctExisting(false), "")
 	if err != nil {
 		return nil, err
 	}
 
-	tableExists, err := og.tableExists(ctx, tx, tableName)
+	schemaExists, err := og.schemaExists(ctx, tx, seqName.Schema())
 	if err != nil {
 		return nil, err
 	}
-	if !tableExists {
-		return makeOpStmtForSingleError(OpStmtDDL,
-			fmt.Sprintf(`ALTER TABLE %s DROP COLUMN "IrrelevantColumnName"`, tableName),
-			pgcode.UndefinedTable), nil
-	}
-	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
+	sequenceExists, err := og.sequenceExists(ctx, tx, seqName)
 	if err != nil {
 		return nil, err
 	}
 
-	columnName, err := og.randColumn(ctx, tx, *tableName, og.pctExisting(true))
-	if err != nil {
-		return nil, err
-	}
-	columnExists, err := og.columnExistsOnTable(ctx, tx, tableName, columnName)
-	if err != nil {
-		return nil, err
-	}
-	colIsPrimaryKey, err := og.colIsPrimaryKey(ctx, tx, tableName, columnName)
-	if err != nil {
-		return nil, err
-	}
-	columnIsDependedOn, err := og.columnIsDependedOn(ctx, tx, tableName, columnName)
-	if err != nil {
-		return nil, err
-	}
-	columnIsInDroppingIndex, err := og.columnIsInDroppingIndex(ctx, tx, tableName, columnName)
-	if err != nil {
-		return nil, err
-	}
-	hasAlterPKSchemaChange, err := og.tableHasOngoingAlterPKSchemaChanges(ctx, tx, tableName)
-	if err != nil {
-		return nil, err
+	// If the sequence exists and an error should be produced, then
+	// exclude the IF NOT EXISTS clause from the statement. Otherwise, default
+	// to including the clause prevent all pgcode.DuplicateRelation errors.
+	ifNotExists := true
+	if sequenceExists && og.produceError() {
+		ifNotExists = false
 	}
-
 	stmt := makeOpStmt(OpStmtDDL)
 	stmt.expectedExecErrors.addAll(codesWithConditions{
-		{code: pgcode.ObjectNotInPrerequisiteState, condition: columnIsInDroppingIndex},
-		{code: pgcode.UndefinedColumn, condition: !columnExists},
-		{code: pgcode.InvalidColumnReference, condition: colIsPrimaryKey},
-		{code: pgcode.DependentObjectsStillExist, condition: columnIsDependedOn},
-		{code: pgcode.FeatureNotSupported, condition: hasAlterPKSchemaChange},
+		{code: pgcode.UndefinedSchema, condition: !schemaExists},
+		{code: pgcode.DuplicateRelation, condition: sequenceExists && !ifNotExists},
 	})
-	stmt.sql = fmt.Sprintf(`ALTER TABLE %s DROP COLUMN "%s"`, tableName, columnName)
-	return stmt, nil
-}
 
-func (og *operationGenerator) dropColumnDefault(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
-	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
-	if err != nil {
-		return nil, err
-	}
-	tableExists, err := og.tableExists(ctx, tx, tableName)
-	if err != nil {
-		return nil, err
-	}
-	if !tableExists {
-		return makeOpStmtForSingleError(OpStmtDDL,
-			fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN "IrrelevantColumnName" DROP DEFAULT`, tableName),
-			pgcode.UndefinedTable), nil
-	}
-	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
-	if err != nil {
-		return nil, err
-	}
-	columnName, err := og.randColumn(ctx, tx, *tableName, og.pctExisting(true))
-	if err != nil {
-		return nil, err
-	}
-	columnExists, err := og.columnExistsOnTable(ctx, tx, tableName, columnName)
-	if err != nil {
-		return nil, err
+	var seqOptions tree.SequenceOptions
+	// Decide if the sequence should be owned by a column. If so, it can
+	// set using the tree.SeqOptOwnedBy sequence option.
+	if og.randIntn(100) < og.params.sequenceOwnedByPct {
+		table, err := og.randTable(ctx, tx, og.pctExisting(true), "")
+		if err != nil {
+			return nil, err
+		}
+		tableExists, err := og.tableExists(ctx, tx, table)
+		if err != nil {
+			return nil, err
+		}
+
+		if !tableExists {
+			seqOptions = append(
+				seqOptions,
+				tree.SequenceOption{
+					Name:          tree.SeqOptOwnedBy,
+					ColumnItemVal: &tree.ColumnItem{TableName: table.ToUnresolvedObjectName(), ColumnName: "IrrelevantColumnName"}},
+			)
+			if !(sequenceExists && ifNotExists) { // IF NOT EXISTS prevents the error
+				stmt.expectedExecErrors.add(pgcode.UndefinedTable)
+			}
+		} else {
+			column, err := og.randColumn(ctx, tx, *table, og.pctExisting(true))
+			if err != nil {
+				return nil, err
+			}
+			columnExists, err := og.columnExistsOnTable(ctx, tx, table, column)
+			if err != nil {
+				return nil, err
+			}
+			// If a duplicate sequence exists, then a new sequence will not be created. In this case,
+			// a pgcode.UndefinedColumn will not occur.
+			if !columnExists && !sequenceExists {
+				stmt.expectedExecErrors.add(pgcode.UndefinedColumn)
+			}
+
+			seqOptions = append(
+				seqOptions,
+				tree.SequenceOption{
+					Name:          tree.SeqOptOwnedBy,
+					ColumnItemVal: &tree.ColumnItem{TableName: table.ToUnresolvedObjectName(), ColumnName: tree.Name(column)}},
+			)
+		}
 	}
-	stmt := makeOpStmt(OpStmtDDL)
-	if !columnExists {
-		stmt.expectedExecErrors.add(pgcode.UndefinedColumn)
+
+	createSeq := &tree.CreateSequence{
+		IfNotExists: ifNotExists,
+		Name:        *seqName,
+		Options:     seqOptions,
 	}
-	stmt.sql = fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN "%s" DROP DEFAULT`, tableName, columnName)
+
+	stmt.sql = tree.Serialize(createSeq)
 	return stmt, nil
 }
 
-func (og *operationGenerator) dropColumnNotNull(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
-	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
-	if err != nil {
-		return nil, err
-	}
-	tableExists, err := og.tableExists(ctx, tx, tableName)
-	if err != nil {
-		return nil, err
-	}
-	if !tableExists {
-		return makeOpStmtForSingleError(OpStmtDDL,
-			fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN "IrrelevantColumnName" DROP NOT NULL`, tableName),
-			pgcode.UndefinedTable), nil
+var trailingDigits = regexp.MustCompile(`\d+$`)
+
+// sequenceOptions are the options of a sequence that alterSequence keeps
+// consistent with each other.
+type sequenceOptions struct {
+	Increment int64
+	MinValue  int64
+	MaxValue  int64
+	Start     int64
+	// AsIntegerType is the integer type of the sequence, e.g. INT2, which
+	// bounds its values. It is empty for sequences of the default type.
+	AsIntegerType string
+}
+
+// typeBounds returns the range of values of the integer type of the sequence.
+func (opts sequenceOptions) typeBounds() (lo, hi int64) {
+	switch opts.AsIntegerType {
+	case types.Int2.SQLString():
+		return math.MinInt16, math.MaxInt16
+	case types.Int4.SQLString():
+		return math.MinInt32, math.MaxInt32
 	}
-	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
-	if err != nil {
-		return nil, err
+	return math.MinInt64, math.MaxInt64
+}
+
+// randAlterSequenceOptions returns a random, non-empty set of ALTER SEQUENCE
+// clauses changing the given options, along with the options that result from
+// them. The resulting start value always lies within the resulting bounds, and
+// the increment is never zero. The bounds are kept within the range of the
+// integer type of the sequence.
+func randAlterSequenceOptions(
+	rng *rand.Rand, cur sequenceOptions,
+) (clauses []string, opts sequenceOptions) {
+	opts = cur
+	_, hi := opts.typeBounds()
+	for len(clauses) == 0 {
+		if rng.Intn(2) == 0 {
+			opts.Increment = rng.Int63n(5) + 1
+			if rng.Intn(2) == 0 {
+				opts.Increment = -opts.Increment
+			}
+			clauses = append(clauses, fmt.Sprintf("INCREMENT BY %d", opts.Increment))
+		}
+		if rng.Intn(2) == 0 {
+			opts.MinValue = -rng.Int63n(1000)
+			clauses = append(clauses, fmt.Sprintf("MINVALUE %d", opts.MinValue))
+		}
+		if rng.Intn(2) == 0 {
+			opts.MaxValue = 1000 + rng.Int63n(min(1000000, hi-999))
+			clauses = append(clauses, fmt.Sprintf("MAXVALUE %d", opts.MaxValue))
+		}
+		if rng.Intn(2) == 0 {
+			clauses = append(clauses, "NO CYCLE")
+		}
 	}
-	columnName, err := og.randColumn(ctx, tx, *tableName, og.pctExisting(true))
-	if err != nil {
-		return nil, err
+	// A new minimum may exceed the maximum of a descending sequence.
+	if opts.MinValue >= opts.MaxValue {
+		opts.MaxValue = opts.MinValue + 1 + rng.Int63n(1000)
+		if opts.MaxValue > hi || opts.MaxValue <= opts.MinValue {
+			opts.MaxValue = hi
+		}
+		clauses = append(clauses, fmt.Sprintf("MAXVALUE %d", opts.MaxValue))
+	}
+	// The start value is validated against the bounds even if it isn't
+	// changed, so move it within the new bounds if needed. The range of the
+	// bounds overflows for sequences spanning all of INT8.
+	if opts.Start < opts.MinValue || opts.Start > opts.MaxValue || rng.Intn(3) == 0 {
+		span := int64(1000)
+		if r := opts.MaxValue - opts.MinValue; r >= 0 && r < span {
+			span = r
+		}
+		opts.Start = opts.MinValue + rng.Int63n(span+1)
+		clauses = append(clauses, fmt.Sprintf("START WITH %d", opts.Start))
 	}
-	columnExists, err := og.columnExistsOnTable(ctx, tx, tableName, columnName)
-	if err != nil {
-		return nil, err
+	return clauses, opts
+}
+
+func (og *operationGenerator) alterSequence(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	type sequence struct {
+		Name string
+		sequenceOptions
 	}
-	colIsPrimaryKey, err := og.colIsPrimaryKey(ctx, tx, tableName, columnName)
+
+	// Query for all sequences returning their escaped fully qualified names and
+	// current options. Fields that are zero are omitted by pb_to_json.
+	query := With([]CTE{
+		{"descriptors", descJSONQuery},
+		{"sequences", `SELECT
+				quote_ident(schema_id::REGNAMESPACE::TEXT) || '.' || quote_ident(name) AS name,
+				descriptor->'table'->'sequenceOpts' AS opts
+			FROM descriptors
+			WHERE descriptor->'table' ? 'sequenceOpts'
+			AND COALESCE(descriptor->'table'->>'state', 'PUBLIC') = 'PUBLIC'`},
+	}, `SELECT
+			name,
+			COALESCE((opts->>'increment')::INT8, 0),
+			COALESCE((opts->>'minValue')::INT8, 0),
+			COALESCE((opts->>'maxValue')::INT8, 0),
+			COALESCE((opts->>'start')::INT8, 0),
+			COALESCE(opts->>'asIntegerType', '')
+		FROM sequences
+	`)
+	sequences, err := Collect(ctx, og, tx, pgx.RowToStructByPos[sequence], query)
 	if err != nil {
-		return nil, err
+		return nil, og.checkAndAdjustForUnknownSchemaErrors(err)
 	}
 
-	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
+	// Query for the integer columns of tables, which may own sequences.
+	columns, err := Collect(ctx, og, tx, pgx.RowToMap, With([]CTE{
+		{"descriptors", descJSONQuery},
+	}, `SELECT
+			quote_ident(schema_id::REGNAMESPACE::TEXT) || '.' || quote_ident(name) || '.' || quote_ident(col->>'name') AS name
+		FROM descriptors, jsonb_array_elements(descriptor->'table'->'columns') AS col
+		WHERE name LIKE 'table%'
+		AND NOT descriptor->'table' ? 'viewQuery'
+		AND NOT descriptor->'table' ? 'sequenceOpts'
+		AND COALESCE(descriptor->'table'->>'state', 'PUBLIC') = 'PUBLIC'
+		AND col->'type'->>'family' = 'IntFamily'
+	`))
+	if err != nil {
+		return nil, og.checkAndAdjustForUnknownSchemaErrors(err)
+	}
+
+	stmt, code, err := Generate[*tree.AlterSequence](og.params.rng, og.produceError(), []GenerationCase{
+		// Fail to alter a sequence that doesn't exist.
+		{pgcode.UndefinedTable, `ALTER SEQUENCE "SequenceThatDoesntExist" INCREMENT BY 2`},
+		// Successful no-op on a sequence that doesn't exist.
+		{pgcode.SuccessfulCompletion, `ALTER SEQUENCE IF EXISTS "SequenceThatDoesntExist" INCREMENT BY 2`},
+		// Fail to set an increment of zero.
+		{pgcode.InvalidParameterValue, `{ with Sequence } ALTER SEQUENCE { .Name } INCREMENT BY 0 { end }`},
+		// Fail to set bounds that exclude the start value.
+		{pgcode.InvalidParameterValue, `{ with Sequence } ALTER SEQUENCE { .Name } MINVALUE { add .Start 1 } MAXVALUE { add .Start 10 } { end }`},
+		// Fail to make a sequence cycle, which is not supported.
+		{pgcode.FeatureNotSupported, `{ with Sequence } ALTER SEQUENCE { .Name } CYCLE { end }`},
+		// Fail to make a sequence owned by a column that doesn't exist.
+		{pgcode.UndefinedColumn, `{ with Sequence } ALTER SEQUENCE { .Name } OWNED BY { TableOf Column }."ColumnThatDoesntExist" { end }`},
+		// Successful change of the options of a sequence.
+		{pgcode.SuccessfulCompletion, `{ with Sequence } ALTER SEQUENCE { .Name } { Options . } { end }`},
+		// Successful change of the owner of a sequence.
+		{pgcode.SuccessfulCompletion, `{ with Sequence } ALTER SEQUENCE { .Name } OWNED BY { Column } { end }`},
+		// Successful removal of the owner of a sequence.
+		{pgcode.SuccessfulCompletion, `{ with Sequence } ALTER SEQUENCE { .Name } OWNED BY NONE { end }`},
+	}, template.FuncMap{
+		"Sequence": func() (sequence, error) {
+			return PickOne(og.params.rng, sequences)
+		},
+		"Column": func() (string, error) {
+			col, err := PickOne(og.params.rng, columns)
+			if err != nil {
+				return "", err
+			}
+			return col["name"].(string), nil
+		},
+		"TableOf": func(columnName string) string {
+			return columnName[:strings.LastIndex(columnName, ".")]
+		},
+		"Options": func(seq sequence) string {
+			clauses, _ := randAlterSequenceOptions(og.params.rng, seq.sequenceOptions)
+			return strings.Join(clauses, " ")
+		},
+		"add": func(a, b int64) int64 {
+			return a + b
+		},
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	stmt := makeOpStmt(OpStmtDDL)
-	stmt.expectedExecErrors.addAll(codesWithConditions{
-		{pgcode.UndefinedColumn, !columnExists},
-		{pgcode.InvalidTableDefinition, colIsPrimaryKey},
-	})
-	stmt.sql = fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN "%s" DROP NOT NULL`, tableName, columnName)
-	return stmt, nil
+	return newOpStmt(stmt, codesWithConditions{
+		{code, true},
+	}), nil
 }
 
-func (og *operationGenerator) dropColumnStored(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
-	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
-	if err != nil {
-		return nil, err
-	}
-	tableExists, err := og.tableExists(ctx, tx, tableName)
-	if err != nil {
-		return nil, err
-	}
-	if !tableExists {
-		return makeOpStmtForSingleError(OpStmtDDL,
-			fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN IrrelevantColumnName DROP STORED`, tableName),
-			pgcode.UndefinedTable), nil
+func (og *operationGenerator) createStats(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	// Statistics are collected by a job, which reads the table in its own
+	// transaction while the statement waits for it to complete. The job would
+	// wait forever on the writes of the current transaction, so statistics are
+	// only created by the first statement of a transaction.
+	if len(og.opsInTxn) > 0 {
+		return nil, pgx.ErrNoRows
 	}
-	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
+	// The job reads the table as of the timestamp of the transaction, so that
+	// it sees the same tables and columns as the statements generated below.
+	// Otherwise it would fail if they were dropped by a transaction that
+	// committed after this one started.
+	asOf, err := Scan[string](ctx, og, tx, `SELECT cluster_logical_timestamp()::STRING`)
 	if err != nil {
 		return nil, err
 	}
 
-	columnName, err := og.randColumn(ctx, tx, *tableName, og.pctExisting(true))
-	if err != nil {
-		return nil, err
-	}
-	columnExists, err := og.columnExistsOnTable(ctx, tx, tableName, columnName)
+	// Query for tables and the columns statistics can be explicitly created
+	// on, which excludes hidden and virtual columns.
+	tables, err := Collect(ctx, og, tx, pgx.RowToMap, With([]CTE{
+		{"descriptors", descJSONQuery},
+	}, `SELECT
+			quote_ident(schema_id::REGNAMESPACE::TEXT) || '.' || quote_ident(name) AS name,
+			ARRAY(
+				SELECT quote_ident(col->>'name')
+				FROM jsonb_array_elements(descriptor->'table'->'columns') AS col
+				WHERE NOT COALESCE((col->>'hidden')::BOOL, false)
+				AND NOT COALESCE((col->>'virtual')::BOOL, false)
+			) AS columns
+		FROM descriptors
+		WHERE name LIKE 'table%'
+		AND NOT descriptor->'table' ? 'viewQuery'
+		AND NOT descriptor->'table' ? 'sequenceOpts'
+		AND COALESCE(descriptor->'table'->>'state', 'PUBLIC') = 'PUBLIC'
+	`))
 	if err != nil {
-		return nil, err
+		return nil, og.checkAndAdjustForUnknownSchemaErrors(err)
 	}
 
-	columnIsStored, err := og.columnIsStoredComputed(ctx, tx, tableName, columnName)
+	views, err := Collect(ctx, og, tx, pgx.RowTo[string], With([]CTE{
+		{"descriptors", descJSONQuery},
+	}, `SELECT
+			quote_ident(schema_id::REGNAMESPACE::TEXT) || '.' || quote_ident(name)
+		FROM descriptors
+		WHERE descriptor->'table' ? 'viewQuery'
+		AND NOT COALESCE((descriptor->'table'->>'isMaterializedView')::BOOL, false)
+		AND COALESCE(descriptor->'table'->>'state', 'PUBLIC') = 'PUBLIC'
+	`))
+	if err != nil {
+		return nil, og.checkAndAdjustForUnknownSchemaErrors(err)
+	}
+
+	stmt, code, err := Generate[*tree.CreateStats](og.params.rng, og.produceError(), []GenerationCase{
+		// Fail to create statistics on a table that doesn't exist.
+		{pgcode.UndefinedTable, `CREATE STATISTICS { StatsName } FROM "TableThatDoesntExist"`},
+		// Fail to create statistics on a column that doesn't exist.
+		{pgcode.UndefinedColumn, `{ with Table } CREATE STATISTICS { StatsName } ON "ColumnThatDoesntExist" FROM { .name } { end }`},
+		// Fail to create statistics on a view.
+		{pgcode.WrongObjectType, `CREATE STATISTICS { StatsName } FROM { View }`},
+		// Fail to create statistics as of a time other than the timestamp of
+		// the transaction.
+		{pgcode.FeatureNotSupported, `{ with Table } CREATE STATISTICS { StatsName } FROM { .name } AS OF SYSTEM TIME '-1s' { end }`},
+		// Successful creation of statistics on the default columns of a table.
+		{pgcode.SuccessfulCompletion, `{ with Table } CREATE STATISTICS { StatsName } FROM { .name } { Options } { end }`},
+		// Successful creation of statistics on some columns of a table.
+		{pgcode.SuccessfulCompletion, `{ with Table } CREATE STATISTICS { StatsName } ON { Columns . } FROM { .name } { Options } { end }`},
+	}, template.FuncMap{
+		"StatsName": func() string {
+			return fmt.Sprintf("stats_%s", og.newUniqueSeqNumSuffix())
+		},
+		"Table": func() (map[string]any, error) {
+			return PickOne(og.params.rng, tables)
+		},
+		"View": func() (string, error) {
+			return PickOne(og.params.rng, views)
+		},
+		"Columns": func(table map[string]any) (string, error) {
+			columns, err := PickBetween(og.params.rng, 1, 3, table["columns"].([]any))
+			if err != nil {
+				return "", err
+			}
+			return strings.Join(util.Map(columns, func(col any) string {
+				return col.(string)
+			}), ", "), nil
+		},
+		"Options": func() string {
+			asOfClause := fmt.Sprintf("AS OF SYSTEM TIME '%s'", asOf)
+			if og.randIntn(2) == 0 {
+				return asOfClause
+			}
+			return fmt.Sprintf("WITH OPTIONS THROTTLING %.1f %s", og.randFloat64()*0.9, asOfClause)
+		},
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	stmt := makeOpStmt(OpStmtDDL)
-	stmt.expectedExecErrors.addAll(codesWithConditions{
-		{code: pgcode.InvalidColumnDefinition, condition: !columnIsStored},
-		{code: pgcode.UndefinedColumn, condition: !columnExists},
-	})
-
-	stmt.sql = fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN "%s" DROP STORED`, tableName, columnName)
-	return stmt, nil
+	return newOpStmt(stmt, codesWithConditions{
+		{code, true},
+	}), nil
 }
 
-func (og *operationGenerator) dropConstraint(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
-	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
+func (og *operationGenerator) createTable(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	tableName, err := og.randTable(ctx, tx, og.pctExisting(false), "")
 	if err != nil {
 		return nil, err
 	}
 
-	tableExists, err := og.tableExists(ctx, tx, tableName)
+	tableIdxStr := trailingDigits.FindString(tableName.Table())
+	tableIdx, err := strconv.Atoi(tableIdxStr)
 	if err != nil {
 		return nil, err
 	}
-	if !tableExists {
-		return makeOpStmtForSingleError(OpStmtDDL,
-			fmt.Sprintf(`ALTER TABLE %s DROP CONSTRAINT IrrelevantConstraintName`, tableName),
-			pgcode.UndefinedTable), nil
-	}
-	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
+	databaseHasMultiRegion, err := og.databaseIsMultiRegion(ctx, tx)
 	if err != nil {
 		return nil, err
 	}
 
-	constraintName, err := og.randConstraint(ctx, tx, tableName.String())
-	if err != nil {
-		return nil, err
+	stmt := randgen.RandCreateTableWithColumnIndexNumberGenerator(
+		ctx, og.params.rng, "table", tableIdx, databaseHasMultiRegion,
+		true /* allowPartiallyVisibleIndex */, og.newUniqueSeqNumSuffix,
+	)
+	stmt.Table = *tableName
+	stmt.IfNotExists = og.randIntn(2) == 0
+	// Occasionally drop the generated primary key so that the table starts out
+	// keyed on the implicit rowid column. Such tables can later be given an
+	// explicit primary key by alterTableAddConstraintPrimaryKey or
+	// alterTableAlterPrimaryKey.
+	//
+	// Alternatively, key the table on a UUID defaulting to gen_random_uuid(),
+	// which spreads writes across the keyspace instead of creating a hotspot.
+	// Inserts into such tables omit the key and rely on the default.
+	if randInt := og.randIntn(8); randInt < 4 {
+		stmt.Defs = util.Filter(stmt.Defs, func(def tree.TableDef) bool {
+			d, ok := def.(*tree.UniqueConstraintTableDef)
+			return !ok || !d.PrimaryKey
+		})
+		if randInt >= 2 {
+			uuidKey := &tree.ColumnTableDef{
+				Name: tree.Name(fmt.Sprintf("id_%s", og.newUniqueSeqNumSuffix())),
+				Type: types.Uuid,
+			}
+			uuidKey.PrimaryKey.IsPrimaryKey = true
+			uuidKey.DefaultExpr.Expr = &tree.FuncExpr{Func: tree.WrapFunction("gen_random_uuid")}
+			stmt.Defs = append([]tree.TableDef{uuidKey}, stmt.Defs...)
+		}
 	}
-
-	// Dropping the primary key of a table without adding a new primary key
-	// subsequently in the transaction is not supported. Since addConstraint is not implemented,
-	// a replacement primary key will not be created in the same transaction. Thus,
-	// dropping a primary key will always produce an error.
-	stmt := makeOpStmt(OpStmtDDL)
-	constraintIsPrimary, err := og.constraintIsPrimary(ctx, tx, tableName, constraintName)
-	if err != nil {
-		return nil, err
+	// Occasionally add a column of an existing enum type, and make it part of
+	// either the primary key or an inline secondary index so that enum values
+	// are encoded in index keys.
+	addEnumColumn := og.randIntn(5) == 0
+	if addEnumColumn {
+		addEnumColumn, err = og.workloadTypeExists(ctx, tx, true /* isEnum */)
+		if err != nil {
+			return nil, err
+		}
 	}
-	if constraintIsPrimary {
-		og.candidateExpectedCommitErrors.add(pgcode.FeatureNotSupported)
+	if addEnumColumn {
+		typName, _, err := og.randTypeName(ctx, tx, 100 /* pctExisting */, true /* isEnum */)
+		if err != nil {
+			return nil, err
+		}
+		enumCol := &tree.ColumnTableDef{
+			Name: tree.Name(fmt.Sprintf("enum_col_%s", og.newUniqueSeqNumSuffix())),
+			Type: typName,
+		}
+		stmt.Defs = append(stmt.Defs, enumCol)
+		elem := tree.IndexElem{
+			Column:    enumCol.Name,
+			Direction: tree.Direction(og.randIntn(1 + int(tree.Descending))),
+		}
+		var pk *tree.UniqueConstraintTableDef
+		for _, def := range stmt.Defs {
+			if d, ok := def.(*tree.UniqueConstraintTableDef); ok && d.PrimaryKey {
+				pk = d
+			}
+		}
+		if pk != nil && og.randIntn(2) == 0 {
+			pk.Columns = append(tree.IndexElemList{elem}, pk.Columns...)
+		} else {
+			stmt.Defs = append(stmt.Defs, &tree.IndexTableDef{
+				Name:    tree.Name(fmt.Sprintf("%s_idx_%s", tableName.Table(), og.newUniqueSeqNumSuffix())),
+				Columns: tree.IndexElemList{elem},
+			})
+		}
 	}
-
-	// DROP INDEX CASCADE is preferred for dropping unique constraints, and
-	// dropping the constraint with ALTER TABLE ... DROP CONSTRAINT is unsupported.
-	constraintIsUnique, err := og.constraintIsUnique(ctx, tx, tableName, constraintName)
-	if err != nil {
-		return nil, err
+	// Occasionally add a column of an existing composite type, along with a
+	// virtual computed column that accesses one of its fields. When an error is
+	// requested, the accessed field does not exist.
+	invalidCompositeField := false
+	addCompositeColumn := og.randIntn(5) == 0
+	if addCompositeColumn {
+		addCompositeColumn, err = og.workloadTypeExists(ctx, tx, false /* isEnum */)
+		if err != nil {
+			return nil, err
+		}
 	}
-	if constraintIsUnique {
-		stmt.expectedExecErrors.add(pgcode.FeatureNotSupported)
+	if addCompositeColumn {
+		typName, _, err := og.randTypeName(ctx, tx, 100 /* pctExisting */, false /* isEnum */)
+		if err != nil {
+			return nil, err
+		}
+		typ, err := og.typeFromTypeName(ctx, tx, typName.String())
+		if err != nil {
+			return nil, err
+		}
+		compositeCol := &tree.ColumnTableDef{
+			Name: tree.Name(fmt.Sprintf("composite_col_%s", og.newUniqueSeqNumSuffix())),
+			Type: typName,
+		}
+		fieldIdx := og.randIntn(len(typ.TupleLabels()))
+		field := tree.Name(typ.TupleLabels()[fieldIdx])
+		if og.produceError() {
+			field = tree.Name(fmt.Sprintf("missing_field_%s", og.newUniqueSeqNumSuffix()))
+			invalidCompositeField = true
+		}
+		fieldExpr, err := parser.ParseExpr(fmt.Sprintf("(%s).%s", compositeCol.Name.String(), field.String()))
+		if err != nil {
+			return nil, err
+		}
+		fieldCol := &tree.ColumnTableDef{
+			Name: tree.Name(fmt.Sprintf("%s_%s", compositeCol.Name, og.newUniqueSeqNumSuffix())),
+			Type: typ.TupleContents()[fieldIdx],
+		}
+		fieldCol.Computed.Computed = true
+		fieldCol.Computed.Virtual = true
+		fieldCol.Computed.Expr = fieldExpr
+		stmt.Defs = append(stmt.Defs, compositeCol, fieldCol)
+	}
+	// Occasionally add an ARRAY column with an inline inverted index, which
+	// selectStmt then queries for containment. When an error is requested, the
+	// array holds REFCURSORs, which cannot be inverted indexed.
+	invalidInvertedIndex := false
+	if og.randIntn(5) == 0 {
+		elemType := invertedArrayElemTypes[og.randIntn(len(invertedArrayElemTypes))]
+		if og.produceError() {
+			elemType = types.RefCursor
+			invalidInvertedIndex = true
+		}
+		arrayCol := &tree.ColumnTableDef{
+			Name: tree.Name(fmt.Sprintf("array_col_%s", og.newUniqueSeqNumSuffix())),
+			Type: types.MakeArray(elemType),
+		}
+		stmt.Defs = append(stmt.Defs, arrayCol, &tree.IndexTableDef{
+			Name:     tree.Name(fmt.Sprintf("%s_inverted_idx_%s", tableName.Table(), og.newUniqueSeqNumSuffix())),
+			Columns:  tree.IndexElemList{{Column: arrayCol.Name}},
+			Inverted: true,
+		})
 	}
-
-	constraintBeingDropped, err := og.constraintInDroppingState(ctx, tx, tableName, constraintName)
-	if err != nil {
-		return nil, err
+	// Occasionally add a SERIAL column, which is normalized according to the
+	// serial_normalization session variable.
+	if og.randIntn(5) == 0 {
+		stmt.Defs = append(stmt.Defs, og.randSerialColumnDef(
+			tree.Name(fmt.Sprintf("serial_col_%s", og.newUniqueSeqNumSuffix())),
+		))
 	}
-	if constraintBeingDropped {
-		stmt.expectedExecErrors.add(pgcode.FeatureNotSupported)
+	// Occasionally add a column that is kept up to date by an inline ON UPDATE
+	// expression. current_timestamp() can only be assigned to timestamp
+	// columns, so when an error is requested the expression is attached to a
+	// BOOL column instead.
+	incompatibleOnUpdate := false
+	if og.randIntn(5) == 0 {
+		onUpdateTypes := []*types.T{types.Timestamp, types.TimestampTZ}
+		colType := onUpdateTypes[og.randIntn(len(onUpdateTypes))]
+		if og.produceError() {
+			colType = types.Bool
+			incompatibleOnUpdate = true
+		}
+		col := &tree.ColumnTableDef{
+			Name: tree.Name(fmt.Sprintf("updated_at_%s", og.newUniqueSeqNumSuffix())),
+			Type: colType,
+		}
+		col.OnUpdateExpr.Expr = &tree.FuncExpr{Func: tree.WrapFunction("current_timestamp")}
+		stmt.Defs = append(stmt.Defs, col)
+	}
+	// Occasionally enable row-level TTL on the table, computing each row's
+	// expiration from a TIMESTAMPTZ column with ttl_expiration_expression.
+	// Dropping that column later fails while the expression refers to it. When
+	// an error is requested, the expression either has the wrong type or refers
+	// to a column that does not exist.
+	invalidTTLExpression := false
+	var invalidTTLExpressionCode pgcode.Code
+	if og.randIntn(5) == 0 {
+		ttlCol := &tree.ColumnTableDef{
+			Name: tree.Name(fmt.Sprintf("expires_at_%s", og.newUniqueSeqNumSuffix())),
+			Type: types.TimestampTZ,
+		}
+		ttlCol.DefaultExpr.Expr = &tree.FuncExpr{Func: tree.WrapFunction("now")}
+		stmt.Defs = append(stmt.Defs, ttlCol)
+		expirationExpr := fmt.Sprintf("%s + '%d days'::INTERVAL", ttlCol.Name.String(), 1+og.randIntn(30))
+		if og.produceError() {
+			invalidTTLExpression = true
+			if og.randIntn(2) == 0 {
+				expirationExpr = fmt.Sprintf("%s::STRING", ttlCol.Name.String())
+				invalidTTLExpressionCode = pgcode.InvalidParameterValue
+			} else {
+				expirationExpr = fmt.Sprintf("missing_col_%s + '1 day'::INTERVAL", og.newUniqueSeqNumSuffix())
+				invalidTTLExpressionCode = pgcode.UndefinedColumn
+			}
+		}
+		stmt.StorageParams = append(stmt.StorageParams, tree.StorageParam{
+			Key:   "ttl_expiration_expression",
+			Value: tree.NewStrVal(expirationExpr),
+		})
 	}
-
-	stmt.sql = fmt.Sprintf(`ALTER TABLE %s DROP CONSTRAINT "%s"`, tableName, constraintName)
-	return stmt, nil
-}
-
-func (og *operationGenerator) dropIndex(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
-	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
-	if err != nil {
-		return nil, err
+	// Occasionally make the table wide, stressing the size of its descriptor
+	// and of the rows written to it.
+	wideTable := og.randIntn(100) < og.params.wideTablePct
+	if wideTable {
+		stmt.Defs = append(stmt.Defs, og.randWideTableDefs(tableName)...)
+	}
+	// Occasionally give the table several more inline secondary indexes, a mix
+	// of unique and non-unique ones, so that later operations immediately have
+	// a rich index set to act on. Both STORED and VIRTUAL computed columns may
+	// be indexed. When an error is requested, two of them share a name.
+	duplicateIndexName := false
+	if og.randIntn(4) == 0 {
+		var indexableCols []tree.Name
+		for _, def := range stmt.Defs {
+			col, ok := def.(*tree.ColumnTableDef)
+			if !ok {
+				continue
+			}
+			if typ, ok := tree.GetStaticallyKnownType(col.Type); ok && colinfo.ColumnTypeIsIndexable(typ) {
+				indexableCols = append(indexableCols, col.Name)
+			}
+		}
+		if len(indexableCols) > 0 {
+			numIndexes := 2 + og.randIntn(3)
+			indexNames := make([]tree.Name, numIndexes)
+			for i := range indexNames {
+				indexNames[i] = tree.Name(fmt.Sprintf("%s_idx_%s", tableName.Table(), og.newUniqueSeqNumSuffix()))
+			}
+			if og.produceError() {
+				indexNames[numIndexes-1] = indexNames[0]
+				duplicateIndexName = true
+			}
+			for _, indexName := range indexNames {
+				og.params.rng.Shuffle(len(indexableCols), func(i, j int) {
+					indexableCols[i], indexableCols[j] = indexableCols[j], indexableCols[i]
+				})
+				idx := tree.IndexTableDef{Name: indexName}
+				for _, colName := range indexableCols[:1+og.randIntn(len(indexableCols))] {
+					idx.Columns = append(idx.Columns, tree.IndexElem{
+						Column:    colName,
+						Direction: tree.Direction(og.randIntn(1 + int(tree.Descending))),
+					})
+				}
+				if og.randIntn(2) == 0 {
+					stmt.Defs = append(stmt.Defs, &tree.UniqueConstraintTableDef{IndexTableDef: idx})
+				} else {
+					stmt.Defs = append(stmt.Defs, &idx)
+				}
+			}
+		}
+	}
+	// Occasionally make the table REGIONAL BY ROW, partitioned on an explicit
+	// STORED computed column of the database's region enum type rather than the
+	// implicit crdb_region column. The column derives its region from another
+	// column of the table. When an error is requested, the expression yields a
+	// value that is not a region of the database.
+	invalidRegionExpr := false
+	regionalByRowHasRegionChange := false
+	if databaseHasMultiRegion && og.randIntn(5) == 0 {
+		regionNames, err := og.getDatabaseRegionNames(ctx, tx)
+		if err != nil {
+			return nil, err
+		}
+		if len(regionNames) > 0 {
+			regionalByRowHasRegionChange, err = og.databaseHasRegionChange(ctx, tx)
+			if err != nil {
+				return nil, err
+			}
+			randRegion := func() tree.Expr {
+				return tree.NewStrVal(string(regionNames[og.randIntn(len(regionNames))]))
+			}
+			var regionExpr tree.Expr = randRegion()
+			for _, def := range stmt.Defs {
+				if col, ok := def.(*tree.ColumnTableDef); ok && !col.Computed.Computed {
+					regionExpr = &tree.CaseExpr{
+						Whens: []*tree.When{{
+							Cond: &tree.IsNullExpr{Expr: tree.NewUnresolvedName(string(col.Name))},
+							Val:  randRegion(),
+						}},
+						Else: randRegion(),
+					}
+					break
+				}
+			}
+			if og.produceError() {
+				regionExpr = tree.NewStrVal(fmt.Sprintf("invalid_region_%s", og.newUniqueSeqNumSuffix()))
+				invalidRegionExpr = true
+			}
+			regionCol := &tree.ColumnTableDef{
+				Name: tree.Name(fmt.Sprintf("region_col_%s", og.newUniqueSeqNumSuffix())),
+				Type: tree.NewUnqualifiedTypeName(tree.RegionEnum),
+			}
+			regionCol.Nullable.Nullability = tree.NotNull
+			regionCol.Computed.Computed = true
+			regionCol.Computed.Expr = regionExpr
+			stmt.Defs = append(stmt.Defs, regionCol)
+			stmt.Locality = &tree.Locality{
+				LocalityLevel:       tree.LocalityLevelRow,
+				RegionalByRowColumn: regionCol.Name,
+			}
+		}
 	}
+	hasVectorType := func() bool {
+		// Check if any of the indexes have PGVector types involved.
+		for _, def := range stmt.Defs {
+			if col, ok := def.(*tree.ColumnTableDef); ok && strings.HasPrefix(col.Type.SQLString(), "VECTOR") {
+				return true
+			}
+		}
+		return false
+	}()
+
 	tableExists, err := og.tableExists(ctx, tx, tableName)
 	if err != nil {
 		return nil, err
 	}
-	if !tableExists {
-		return makeOpStmtForSingleError(OpStmtDDL,
-			fmt.Sprintf(`DROP INDEX %s@"IrrelevantIndexName"`, tableName),
-			pgcode.UndefinedTable), nil
-	}
-	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
+	schemaExists, err := og.schemaExists(ctx, tx, tableName.Schema())
 	if err != nil {
 		return nil, err
 	}
+	opStmt := makeOpStmt(OpStmtDDL)
+	opStmt.expectedExecErrors.addAll(codesWithConditions{
+		{code: pgcode.DuplicateRelation, condition: tableExists && !stmt.IfNotExists},
+		{code: pgcode.UndefinedSchema, condition: !schemaExists},
+		// The column definitions are not validated if the table already exists.
+		{code: pgcode.Uncategorized, condition: incompatibleOnUpdate && !(tableExists && stmt.IfNotExists)},
+		{code: pgcode.DuplicateRelation, condition: duplicateIndexName && !(tableExists && stmt.IfNotExists)},
+		{code: pgcode.FeatureNotSupported, condition: invalidInvertedIndex && !(tableExists && stmt.IfNotExists)},
+		{code: pgcode.InvalidTextRepresentation, condition: invalidRegionExpr && !(tableExists && stmt.IfNotExists)},
+		{code: pgcode.UndefinedColumn, condition: invalidCompositeField && !(tableExists && stmt.IfNotExists)},
+		{code: invalidTTLExpressionCode, condition: invalidTTLExpression && !(tableExists && stmt.IfNotExists)},
+	})
+	// Compatibility errors aren't guaranteed since the cluster version update is not
+	// fully transaction aware.
+	opStmt.potentialExecErrors.addAll(codesWithConditions{
+		{code: pgcode.Syntax, condition: hasVectorType},
+		{code: pgcode.FeatureNotSupported, condition: hasVectorType},
+		// Regions that are being added or dropped are not yet usable values of
+		// the region enum.
+		{code: pgcode.InvalidTextRepresentation, condition: regionalByRowHasRegionChange},
+		{code: pgcode.InvalidParameterValue, condition: regionalByRowHasRegionChange},
+		{code: pgcode.ObjectNotInPrerequisiteState, condition: regionalByRowHasRegionChange},
+		// There is no fixed limit on the number of columns, but the descriptor
+		// of a wide table may still grow beyond what can be written.
+		{code: pgcode.TooManyColumns, condition: wideTable},
+		{code: pgcode.ProgramLimitExceeded, condition: wideTable},
+	})
+	opStmt.sql = tree.Serialize(stmt)
+	return opStmt, nil
+}
 
-	indexName, err := og.randIndex(ctx, tx, *tableName, og.pctExisting(true))
-	if err != nil {
-		return nil, err
-	}
+// wideTableColumnTypes are the types of the extra columns of wide tables.
+var wideTableColumnTypes = []*types.T{
+	types.Int, types.Int2, types.String, types.Bool, types.Float, types.Decimal,
+	types.Date, types.Timestamp, types.Bytes, types.Uuid, types.Jsonb,
+}
 
-	stmt := makeOpStmt(OpStmtDDL)
-	indexExists, err := og.indexExists(ctx, tx, tableName, indexName)
-	if err != nil {
-		return nil, err
+// randWideTableDefs returns the definitions of dozens to hundreds of extra
+// nullable columns for a wide table. The columns are spread over a few column
+// families, and an index is keyed on many of them.
+func (og *operationGenerator) randWideTableDefs(tableName *tree.TableName) []tree.TableDef {
+	numColumns := 50 + og.randIntn(250)
+	defs := make([]tree.TableDef, 0, numColumns+2)
+	families := make([]*tree.FamilyTableDef, 1+og.randIntn(4))
+	for i := range families {
+		families[i] = &tree.FamilyTableDef{
+			Name: tree.Name(fmt.Sprintf("wide_fam_%s", og.newUniqueSeqNumSuffix())),
+		}
 	}
-	if !indexExists {
-		stmt.expectedExecErrors.add(pgcode.UndefinedObject)
+	var indexableCols []tree.Name
+	for i := 0; i < numColumns; i++ {
+		typ := wideTableColumnTypes[og.randIntn(len(wideTableColumnTypes))]
+		col := &tree.ColumnTableDef{
+			Name: tree.Name(fmt.Sprintf("wide_col_%s", og.newUniqueSeqNumSuffix())),
+			Type: typ,
+		}
+		// Leave some of the columns out of the families, so that they are
+		// assigned to a family by default.
+		if og.randIntn(4) != 0 {
+			family := families[og.randIntn(len(families))]
+			family.Columns = append(family.Columns, col.Name)
+		}
+		if colinfo.ColumnTypeIsIndexable(typ) {
+			indexableCols = append(indexableCols, col.Name)
+		}
+		defs = append(defs, col)
 	}
-
-	hasAlterPKSchemaChange, err := og.tableHasOngoingAlterPKSchemaChanges(ctx, tx, tableName)
-	if err != nil {
-		return nil, err
+	for _, family := range families {
+		if len(family.Columns) > 0 {
+			defs = append(defs, family)
+		}
 	}
-	if hasAlterPKSchemaChange {
-		stmt.expectedExecErrors.add(pgcode.FeatureNotSupported)
+	if len(indexableCols) > 0 {
+		og.params.rng.Shuffle(len(indexableCols), func(i, j int) {
+			indexableCols[i], indexableCols[j] = indexableCols[j], indexableCols[i]
+		})
+		numKeyColumns := min(len(indexableCols), 16+og.randIntn(48))
+		idx := &tree.IndexTableDef{
+			Name: tree.Name(fmt.Sprintf("%s_wide_idx_%s", tableName.Table(), og.newUniqueSeqNumSuffix())),
+		}
+		for _, colName := range indexableCols[:numKeyColumns] {
+			idx.Columns = append(idx.Columns, tree.IndexElem{
+				Column:    colName,
+				Direction: tree.Direction(og.randIntn(1 + int(tree.Descending))),
+			})
+		}
+		defs = append(defs, idx)
 	}
+	return defs
+}
 
-	databaseHasRegionChange, err := og.databaseHasRegionChange(ctx, tx)
-	if err != nil {
-		return nil, err
-	}
-	tableIsRegionalByRow, err := og.tableIsRegionalByRow(ctx, tx, tableName)
-	if err != nil {
-		return nil, err
-	}
-	if databaseHasRegionChange && tableIsRegionalByRow {
-		stmt.expectedExecErrors.add(pgcode.ObjectNotInPrerequisiteState)
-	}
+// invertedArrayElemTypes are the element types of the ARRAY columns created
+// along with an inverted index. Their values can be written as literals
+// without resolving any user-defined type.
+var invertedArrayElemTypes = []*types.T{types.Int, types.String, types.Uuid, types.Date, types.Bool}
 
-	stmt.sql = fmt.Sprintf(`DROP INDEX %s@"%s" CASCADE`, tableName, indexName)
-	return stmt, nil
+func (og *operationGenerator) createEnum(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	return og.createType(ctx, tx, true)
 }
 
-func (og *operationGenerator) dropSequence(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
-	sequenceName, err := og.randSequence(ctx, tx, og.pctExisting(true), "")
-	if err != nil {
-		return nil, err
-	}
-	ifExists := og.randIntn(2) == 0
-	dropSeq := &tree.DropSequence{
-		Names:    tree.TableNames{*sequenceName},
-		IfExists: ifExists,
-	}
-
-	stmt := makeOpStmt(OpStmtDDL)
-	sequenceExists, err := og.sequenceExists(ctx, tx, sequenceName)
-	if err != nil {
-		return nil, err
-	}
-	if !sequenceExists && !ifExists {
-		stmt.expectedExecErrors.add(pgcode.UndefinedTable)
-	}
-	stmt.sql = tree.Serialize(dropSeq)
-	return stmt, nil
+func (og *operationGenerator) createCompositeType(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	return og.createType(ctx, tx, false)
 }
 
-func (og *operationGenerator) dropTable(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
-	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
-	if err != nil {
-		return nil, err
-	}
-	tableExists, err := og.tableExists(ctx, tx, tableName)
+func (og *operationGenerator) createType(
+	ctx context.Context, tx pgx.Tx, isEnum bool,
+) (*opStmt, error) {
+
+	typName, typeExists, err := og.randTypeName(ctx, tx, og.pctExisting(false), isEnum)
+
 	if err != nil {
 		return nil, err
 	}
-	tableHasDependencies, err := og.tableHasDependencies(ctx, tx, tableName)
+	schemaExists, err := og.schemaExists(ctx, tx, typName.Schema())
 	if err != nil {
 		return nil, err
 	}
+	opStmt := makeOpStmt(OpStmtDDL)
+	opStmt.expectedExecErrors.addAll(codesWithConditions{
+		{code: pgcode.DuplicateObject, condition: typeExists},
+		{code: pgcode.InvalidSchemaName, condition: !schemaExists},
+	})
 
-	dropBehavior := tree.DropBehavior(og.randIntn(3))
+	const letters = "abcdefghijklmnopqrstuvwxyz"
+	var statement tree.Statement
 
-	ifExists := og.randIntn(2) == 0
-	dropTable := tree.DropTable{
-		Names:        []tree.TableName{*tableName},
-		IfExists:     ifExists,
-		DropBehavior: dropBehavior,
+	if isEnum {
+		statement = randgen.RandCreateEnumType(og.params.rng, typName.Object(), letters)
+	} else {
+		statement = randgen.RandCreateCompositeType(og.params.rng, typName.Object(), letters)
 	}
 
-	stmt := makeOpStmt(OpStmtDDL)
-	stmt.expectedExecErrors.addAll(codesWithConditions{
-		{pgcode.UndefinedTable, !ifExists && !tableExists},
-		{pgcode.DependentObjectsStillExist, dropBehavior != tree.DropCascade && tableHasDependencies},
-	})
-	stmt.sql = dropTable.String()
-	return stmt, nil
+	statement.(*tree.CreateType).TypeName = typName.ToUnresolvedObjectName()
+	opStmt.sql = tree.Serialize(statement)
+	return opStmt, nil
 }
 
-func (og *operationGenerator) dropView(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
-	viewName, err := og.randView(ctx, tx, og.pctExisting(true), "")
-	if err != nil {
-		return nil, err
-	}
-	viewExists, err := og.tableExists(ctx, tx, viewName)
-	if err != nil {
-		return nil, err
-	}
-	viewHasDependencies, err := og.tableHasDependencies(ctx, tx, viewName)
-	if err != nil {
-		return nil, err
-	}
+func (og *operationGenerator) createTableAs(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	const MaxRowsToConsume = 300000
+	numSourceTables := og.randIntn(og.params.maxSourceTables) + 1
 
-	dropBehavior := tree.DropBehavior(og.randIntn(3))
+	sourceTableNames := make([]tree.TableExpr, numSourceTables)
+	sourceTableExistence := make([]bool, numSourceTables)
 
-	ifExists := og.randIntn(2) == 0
-	dropView := tree.DropView{
-		Names:        []tree.TableName{*viewName},
-		IfExists:     ifExists,
-		DropBehavior: dropBehavior,
-	}
+	// uniqueTableNames and duplicateSourceTables are used to track unique
+	// tables. If there are any duplicates, then a pgcode.DuplicateAlias error
+	// is expected on execution.
+	uniqueTableNames := map[string]bool{}
+	duplicateSourceTables := false
 
-	stmt := makeOpStmt(OpStmtDDL)
-	stmt.expectedExecErrors.addAll(codesWithConditions{
-		{pgcode.UndefinedTable, !ifExists && !viewExists},
-		{pgcode.DependentObjectsStillExist, dropBehavior != tree.DropCascade && viewHasDependencies},
-	})
-	stmt.sql = dropView.String()
-	return stmt, nil
-}
+	// Collect a random set of size numSourceTables that contains tables and views
+	// from which to use columns.
+	for i := 0; i < numSourceTables; i++ {
+		var tableName *tree.TableName
+		var err error
+		var sourceTableExists bool
 
-func (og *operationGenerator) alterTypeDropValue(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
-	// Query for all enum values returning:
-	// * name - the escaped fully qualified type name.
-	// * value - the escaped enum value.
-	// * droppping - a bool indicating if this value is being actively dropped.
-	//   has_references - a bool indicating if this *type* is referenced by other
-	//   descriptors.
-	query := With([]CTE{
-		{"descriptors", descJSONQuery},
-		{"enums", enumDescsQuery},
-		{"enum_members", enumMemberDescsQuery},
-	}, `SELECT
-				quote_ident(schema_id::REGNAMESPACE::TEXT) || '.' || quote_ident(name) AS name,
-				quote_literal(member->>'logicalRepresentation') AS value,
-				COALESCE(member->>'direction' = 'REMOVE', false) AS dropping,
-				COALESCE(json_array_length(descriptor->'referencingDescriptorIds') > 0, false) AS has_references
-			FROM enum_members
-	`)
-
-	enumMembers, err := Collect(ctx, og, tx, pgx.RowToMap, query)
-	if err != nil {
-		return nil, err
-	}
+		switch randInt := og.randIntn(1); randInt {
+		case 0:
+			tableName, err = og.randTable(ctx, tx, og.pctExisting(true), "")
+			if err != nil {
+				return nil, err
+			}
+			sourceTableExists, err = og.tableExists(ctx, tx, tableName)
+			if err != nil {
+				return nil, err
+			}
 
-	// TODO(chrisseto): We're currently missing cases around enum members being
-	// referenced as it's quite difficult to tell if an individual member is
-	// referenced. Unreferenced members can be dropped but referenced members may
-	// not. For now, we skip over all enums where the type itself is being
-	// referenced.
+		case 1:
+			tableName, err = og.randView(ctx, tx, og.pctExisting(true), "")
+			if err != nil {
+				return nil, err
+			}
+			sourceTableExists, err = og.viewExists(ctx, tx, tableName)
+			if err != nil {
+				return nil, err
+			}
+		}
 
-	stmt, code, err := Generate[*tree.AlterType](og.params.rng, og.produceError(), []GenerationCase{
-		// Fail to drop values from a type that doesn't exist.
-		{pgcode.UndefinedObject, `ALTER TYPE "EnumThatDoesntExist" DROP VALUE 'IrrelevantValue'`},
-		// Fail to drop a value that is in the process of being dropped.
-		{pgcode.ObjectNotInPrerequisiteState, `{ with (EnumValue true false) } ALTER TYPE { .name } DROP VALUE { .value } { end }`},
-		// Fail to drop values that don't exist.
-		{pgcode.UndefinedObject, `{ with (EnumValue false false) } ALTER TYPE { .name } DROP VALUE 'ValueThatDoesntExist' { end }`},
-		// Successful drop of an enum value.
-		{pgcode.SuccessfulCompletion, `{ with (EnumValue false false) } ALTER TYPE { .name } DROP VALUE { .value } { end }`},
-	}, template.FuncMap{
-		"EnumValue": func(dropping, referenced bool) (map[string]any, error) {
-			return PickOne(og.params.rng, util.Filter(enumMembers, func(enum map[string]any) bool {
-				return enum["has_references"].(bool) == referenced && enum["dropping"].(bool) == dropping
-			}))
-		},
-	})
-	if err != nil {
-		return nil, err
+		sourceTableNames[i] = tableName
+		sourceTableExistence[i] = sourceTableExists
+		if _, exists := uniqueTableNames[tableName.String()]; exists {
+			duplicateSourceTables = true
+		} else {
+			uniqueTableNames[tableName.String()] = true
+		}
 	}
 
-	return newOpStmt(stmt, codesWithConditions{
-		{code, true},
-	}), nil
-}
-
-func (og *operationGenerator) renameColumn(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
-	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
-	if err != nil {
-		return nil, err
+	selectStatement := tree.SelectClause{
+		From: tree.From{Tables: sourceTableNames},
 	}
 
-	srcTableExists, err := og.tableExists(ctx, tx, tableName)
-	if err != nil {
-		return nil, err
-	}
-	if !srcTableExists {
-		return makeOpStmtForSingleError(OpStmtDDL,
-			fmt.Sprintf(`ALTER TABLE %s RENAME COLUMN "IrrelevantColumnName" TO "OtherIrrelevantName"`,
-				tableName),
-			pgcode.UndefinedTable), nil
-	}
-	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
-	if err != nil {
-		return nil, err
-	}
+	// uniqueColumnNames and duplicateColumns are used to track unique
+	// columns. If there are any duplicates, then a pgcode.DuplicateColumn error
+	// is expected on execution.
+	opStmt := makeOpStmt(OpStmtDDL)
+	uniqueColumnNames := map[string]bool{}
+	duplicateColumns := false
+	for i := 0; i < numSourceTables; i++ {
+		tableName := sourceTableNames[i]
+		tableExists := sourceTableExistence[i]
 
-	srcColumnName, err := og.randColumn(ctx, tx, *tableName, og.pctExisting(true))
-	if err != nil {
-		return nil, err
-	}
+		// If the table does not exist, columns cannot be fetched from it. For this reason, the placeholder
+		// "IrrelevantColumnName" is used, and a pgcode.UndefinedTable error is expected on execution.
+		if tableExists {
+			columnNamesForTable, err := og.tableColumnsShuffled(ctx, tx, tableName.(*tree.TableName).String())
+			if err != nil {
+				return nil, err
+			}
+			columnNamesForTable = columnNamesForTable[:1+og.randIntn(len(columnNamesForTable))]
 
-	destColumnName, err := og.randColumn(ctx, tx, *tableName, og.pctExisting(false))
-	if err != nil {
-		return nil, err
+			for j := range columnNamesForTable {
+				colItem := tree.ColumnItem{
+					TableName:  tableName.(*tree.TableName).ToUnresolvedObjectName(),
+					ColumnName: tree.Name(columnNamesForTable[j]),
+				}
+				selectStatement.Exprs = append(selectStatement.Exprs, tree.SelectExpr{Expr: &colItem})
+
+				if _, exists := uniqueColumnNames[columnNamesForTable[j]]; exists {
+					duplicateColumns = true
+				} else {
+					uniqueColumnNames[columnNamesForTable[j]] = true
+				}
+			}
+		} else {
+			opStmt.expectedExecErrors.add(pgcode.UndefinedTable)
+			colItem := tree.ColumnItem{
+				ColumnName: tree.Name("IrrelevantColumnName"),
+			}
+			selectStatement.Exprs = append(selectStatement.Exprs, tree.SelectExpr{Expr: &colItem})
+		}
 	}
 
-	srcColumnExists, err := og.columnExistsOnTable(ctx, tx, tableName, srcColumnName)
+	destTableName, err := og.randTable(ctx, tx, og.pctExisting(false), "")
 	if err != nil {
 		return nil, err
 	}
-	destColumnExists, err := og.columnExistsOnTable(ctx, tx, tableName, destColumnName)
+	schemaExists, err := og.schemaExists(ctx, tx, destTableName.Schema())
 	if err != nil {
 		return nil, err
 	}
-	columnIsDependedOn, err := og.columnIsDependedOn(ctx, tx, tableName, srcColumnName)
+	tableExists, err := og.tableExists(ctx, tx, destTableName)
 	if err != nil {
 		return nil, err
 	}
 
-	stmt := makeOpStmt(OpStmtDDL)
-	stmt.expectedExecErrors.addAll(codesWithConditions{
-		{pgcode.UndefinedColumn, !srcColumnExists},
-		{pgcode.DuplicateColumn, destColumnExists && srcColumnName != destColumnName},
-		{pgcode.DependentObjectsStillExist, columnIsDependedOn},
+	opStmt.expectedExecErrors.addAll(codesWithConditions{
+		{code: pgcode.InvalidSchemaName, condition: !schemaExists},
+		{code: pgcode.DuplicateRelation, condition: tableExists},
+		{code: pgcode.Syntax, condition: len(selectStatement.Exprs) == 0},
+		{code: pgcode.DuplicateAlias, condition: duplicateSourceTables},
+		{code: pgcode.DuplicateColumn, condition: duplicateColumns},
 	})
+	// Confirm the select itself doesn't run into any column generation errors,
+	// by executing it independently first until we add validation when adding
+	// generated columns. See issue: #81698?, which will allow us to remove this
+	// logic in the future.
+	if opStmt.expectedExecErrors.empty() {
+		opStmt.potentialExecErrors.merge(getValidGenerationErrors())
+	}
 
-	stmt.sql = fmt.Sprintf(`ALTER TABLE %s RENAME COLUMN "%s" TO "%s"`,
-		tableName, srcColumnName, destColumnName)
-	return stmt, nil
+	opStmt.sql = fmt.Sprintf(`CREATE TABLE %s AS %s FETCH FIRST %d ROWS ONLY`,
+		destTableName, selectStatement.String(), MaxRowsToConsume)
+	return opStmt, nil
 }
 
-func (og *operationGenerator) renameIndex(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
-	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
-	if err != nil {
-		return nil, err
-	}
+func (og *operationGenerator) createView(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
 
-	srcTableExists, err := og.tableExists(ctx, tx, tableName)
-	if err != nil {
-		return nil, err
-	}
-	if !srcTableExists {
-		return makeOpStmtForSingleError(OpStmtDDL,
-			fmt.Sprintf(`ALTER INDEX %s@"IrrelevantConstraintName" RENAME TO "OtherConstraintName"`,
-				tableName),
-			pgcode.UndefinedTable), nil
-	}
-	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
-	if err != nil {
-		return nil, err
-	}
+	numSourceTables := og.randIntn(og.params.maxSourceTables) + 1
 
-	srcIndexName, err := og.randIndex(ctx, tx, *tableName, og.pctExisting(true))
-	if err != nil {
-		return nil, err
-	}
+	sourceTableNames := make([]tree.TableExpr, numSourceTables)
+	sourceTableExistence := make([]bool, numSourceTables)
 
-	destIndexName, err := og.randIndex(ctx, tx, *tableName, og.pctExisting(false))
-	if err != nil {
-		return nil, err
-	}
+	// uniqueTableNames and duplicateSourceTables are used to track unique
+	// tables. If there are any duplicates, then a pgcode.DuplicateColumn error
+	// is expected on execution.
+	uniqueTableNames := map[string]bool{}
+	duplicateSourceTables := false
 
-	srcIndexExists, err := og.indexExists(ctx, tx, tableName, srcIndexName)
-	if err != nil {
-		return nil, err
-	}
-	destIndexExists, err := og.indexExists(ctx, tx, tableName, destIndexName)
-	if err != nil {
-		return nil, err
-	}
+	// Collect a random set of size numSourceTables that contains tables and views
+	// from which to use columns.
+	for i := 0; i < numSourceTables; i++ {
+		var tableName *tree.TableName
+		var err error
+		var sourceTableExists bool
 
-	stmt := makeOpStmt(OpStmtDDL)
-	stmt.expectedExecErrors.addAll(codesWithConditions{
-		{code: pgcode.UndefinedObject, condition: !srcIndexExists},
-		{code: pgcode.DuplicateRelation, condition: destIndexExists && srcIndexName != destIndexName},
-	})
+		switch randInt := og.randIntn(1); randInt {
+		case 0:
+			tableName, err = og.randTable(ctx, tx, og.pctExisting(true), "")
+			if err != nil {
+				return nil, err
+			}
+			sourceTableExists, err = og.tableExists(ctx, tx, tableName)
+			if err != nil {
+				return nil, err
+			}
 
-	stmt.sql = fmt.Sprintf(`ALTER INDEX %s@"%s" RENAME TO "%s"`,
-		tableName, srcIndexName, destIndexName)
-	return stmt, nil
-}
+		case 1:
+			tableName, err = og.randView(ctx, tx, og.pctExisting(true), "")
+			if err != nil {
+				return nil, err
+			}
+			sourceTableExists, err = og.viewExists(ctx, tx, tableName)
+			if err != nil {
+				return nil, err
+			}
+		}
 
-func (og *operationGenerator) renameSequence(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
-	srcSequenceName, err := og.randSequence(ctx, tx, og.pctExisting(true), "")
-	if err != nil {
-		return nil, err
+		sourceTableNames[i] = tableName
+		sourceTableExistence[i] = sourceTableExists
+		if _, exists := uniqueTableNames[tableName.String()]; exists {
+			duplicateSourceTables = true
+		} else {
+			uniqueTableNames[tableName.String()] = true
+		}
 	}
 
-	// Decide whether or not to produce a 'cannot change schema of table with RENAME' error
-	desiredSchema := ""
-	if !og.produceError() {
-		desiredSchema = srcSequenceName.Schema()
+	selectStatement := tree.SelectClause{
+		From: tree.From{Tables: sourceTableNames},
 	}
 
-	destSequenceName, err := og.randSequence(ctx, tx, og.pctExisting(false), desiredSchema)
-	if err != nil {
-		return nil, err
+	// uniqueColumnNames and duplicateColumns are used to track unique
+	// columns. If there are any duplicates, then a pgcode.DuplicateColumn error
+	// is expected on execution.
+	opStmt := makeOpStmt(OpStmtDDL)
+	uniqueColumnNames := map[string]bool{}
+	duplicateColumns := false
+	for i := 0; i < numSourceTables; i++ {
+		tableName := sourceTableNames[i]
+		tableExists := sourceTableExistence[i]
+
+		// If the table does not exist, columns cannot be fetched from it. For this reason, the placeholder
+		// "IrrelevantColumnName" is used, and a pgcode.UndefinedTable error is expected on execution.
+		if tableExists {
+			columnNamesForTable, err := og.tableColumnsShuffled(ctx, tx, tableName.(*tree.TableName).String())
+			if err != nil {
+				return nil, err
+			}
+			columnNamesForTable = columnNamesForTable[:1+og.randIntn(len(columnNamesForTable))]
+
+			for j := range columnNamesForTable {
+				colItem := tree.ColumnItem{
+					TableName:  tableName.(*tree.TableName).ToUnresolvedObjectName(),
+					ColumnName: tree.Name(columnNamesForTable[j]),
+				}
+				selectStatement.Exprs = append(selectStatement.Exprs, tree.SelectExpr{Expr: &colItem})
+
+				if _, exists := uniqueColumnNames[columnNamesForTable[j]]; exists {
+					duplicateColumns = true
+				} else {
+					uniqueColumnNames[columnNamesForTable[j]] = true
+				}
+			}
+		} else {
+			opStmt.expectedExecErrors.add(pgcode.UndefinedTable)
+			colItem := tree.ColumnItem{
+				ColumnName: tree.Name("IrrelevantColumnName"),
+			}
+			selectStatement.Exprs = append(selectStatement.Exprs, tree.SelectExpr{Expr: &colItem})
+		}
 	}
 
-	srcSequenceExists, err := og.sequenceExists(ctx, tx, srcSequenceName)
+	// Occasionally add a window function over the first source table that
+	// exists. Its output column is derived from the function call, so it is
+	// named explicitly to keep the columns of the view unique.
+	invalidWindow := false
+	if firstExisting := slices.Index(sourceTableExistence, true); firstExisting >= 0 && og.randIntn(4) == 0 {
+		windowExpr, invalid, err := og.randWindowFunction(ctx, tx, sourceTableNames[firstExisting].(*tree.TableName))
+		if err != nil {
+			return nil, err
+		}
+		selectStatement.Exprs = append(selectStatement.Exprs, tree.SelectExpr{
+			Expr: windowExpr,
+			As:   tree.UnrestrictedName(fmt.Sprintf("window_%s", og.newUniqueSeqNumSuffix())),
+		})
+		invalidWindow = invalid
+	}
+
+	// Occasionally prefix the view body with a recursive CTE, which is planned
+	// and validated differently from a plain query. The CTE yields a bounded
+	// series that is cross joined with the source tables, so it does not change
+	// which columns the view exposes.
+	var withClause string
+	invalidRecursion := false
+	if og.randIntn(5) == 0 {
+		recursiveQuery := fmt.Sprintf(
+			`SELECT 1 UNION ALL SELECT depth + 1 FROM rec WHERE depth < %d`, 1+og.randIntn(10),
+		)
+		// A recursive reference is only allowed in the second term of a UNION.
+		if og.produceError() {
+			recursiveQuery = `SELECT depth FROM rec`
+			invalidRecursion = true
+		}
+		withClause = fmt.Sprintf(`WITH RECURSIVE rec (depth) AS (%s) `, recursiveQuery)
+		selectStatement.From.Tables = append(selectStatement.From.Tables, tree.NewUnqualifiedTableName("rec"))
+	}
+
+	destViewName, err := og.randView(ctx, tx, og.pctExisting(false), "")
 	if err != nil {
 		return nil, err
 	}
-
-	destSchemaExists, err := og.schemaExists(ctx, tx, destSequenceName.Schema())
+	schemaExists, err := og.schemaExists(ctx, tx, destViewName.Schema())
 	if err != nil {
 		return nil, err
 	}
-
-	destSequenceExists, err := og.sequenceExists(ctx, tx, destSequenceName)
+	viewExists, err := og.viewExists(ctx, tx, destViewName)
 	if err != nil {
 		return nil, err
 	}
+	// Occasionally materialize the view. Materialized views are named with a
+	// distinct prefix so that operations on views, such as DROP VIEW, never
+	// pick them unless they ask for materialized views.
+	materialized := og.randIntn(4) == 0
+	if materialized && !viewExists {
+		destViewName.ObjectName = tree.Name(fmt.Sprintf("mview_%s", og.newUniqueSeqNumSuffix()))
+	}
 
-	srcEqualsDest := srcSequenceName.String() == destSequenceName.String()
-	stmt := makeOpStmt(OpStmtDDL)
-	stmt.expectedExecErrors.addAll(codesWithConditions{
-		{code: pgcode.UndefinedTable, condition: !srcSequenceExists},
-		{code: pgcode.UndefinedSchema, condition: !destSchemaExists},
-		{code: pgcode.DuplicateRelation, condition: !srcEqualsDest && destSequenceExists},
-		{code: pgcode.InvalidName, condition: srcSequenceName.Schema() != destSequenceName.Schema()},
+	opStmt.expectedExecErrors.addAll(codesWithConditions{
+		{code: pgcode.InvalidSchemaName, condition: !schemaExists},
+		{code: pgcode.DuplicateRelation, condition: viewExists},
+		{code: pgcode.Syntax, condition: len(selectStatement.Exprs) == 0},
+		{code: pgcode.DuplicateAlias, condition: duplicateSourceTables},
+		{code: pgcode.DuplicateColumn, condition: duplicateColumns},
+		{code: pgcode.Syntax, condition: invalidRecursion},
+		{code: pgcode.Windowing, condition: invalidWindow},
 	})
+	// Descriptor ID generator may be temporarily unavailable, so
+	// allow uncategorized errors temporarily.
+	viewKind := "VIEW"
+	if materialized {
+		viewKind = "MATERIALIZED VIEW"
+	}
+	opStmt.sql = fmt.Sprintf(`CREATE %s %s AS %s%s`,
+		viewKind, destViewName, withClause, selectStatement.String())
+	return opStmt, nil
+}
 
-	stmt.sql = fmt.Sprintf(`ALTER SEQUENCE %s RENAME TO %s`, srcSequenceName, destSequenceName)
-	return stmt, nil
+// viewWindowFunctions are the window functions used in view bodies. None of
+// them take a column as an argument, so they apply to columns of any type.
+var viewWindowFunctions = []string{
+	"row_number()",
+	"rank()",
+	"dense_rank()",
+	"percent_rank()",
+	"cume_dist()",
+	"ntile(4)",
 }
 
-func (og *operationGenerator) renameTable(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
-	srcTableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
+// randWindowFunction returns a window function call whose window is
+// partitioned and/or ordered by a random column of the given table, when the
+// column type supports it. If an error is requested, the window is ordered by
+// another window function, and the returned bool is true, since window
+// function calls cannot be nested.
+func (og *operationGenerator) randWindowFunction(
+	ctx context.Context, tx pgx.Tx, tableName *tree.TableName,
+) (tree.Expr, bool, error) {
+	col, err := og.randColumnWithMeta(ctx, tx, *tableName, og.alwaysExisting())
+	if err != nil {
+		return nil, false, err
+	}
+
+	var window string
+	// Indexable types are the ones that can be compared for both equality and
+	// ordering.
+	if col.typ != nil && colinfo.ColumnTypeIsIndexable(col.typ) {
+		colName := fmt.Sprintf("%s.%s", tableName, tree.NameString(col.name))
+		switch og.randIntn(3) {
+		case 0:
+			window = fmt.Sprintf("ORDER BY %s", colName)
+		case 1:
+			window = fmt.Sprintf("PARTITION BY %s", colName)
+		case 2:
+			window = fmt.Sprintf("PARTITION BY %[1]s ORDER BY %[1]s", colName)
+		}
+	}
+	invalid := og.produceError()
+	if invalid {
+		window = "ORDER BY rank() OVER ()"
+	}
+
+	fn := viewWindowFunctions[og.randIntn(len(viewWindowFunctions))]
+	expr, err := parser.ParseExpr(fmt.Sprintf("%s OVER (%s)", fn, window))
+	if err != nil {
+		return nil, false, err
+	}
+	return expr, invalid, nil
+}
+
+func (og *operationGenerator) dropColumn(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
 	if err != nil {
 		return nil, err
 	}
 
-	// Decide whether or not to produce a 'cannot change schema of table with RENAME' error
-	desiredSchema := ""
-	if !og.produceError() {
-		desiredSchema = srcTableName.SchemaName.String()
+	tableExists, err := og.tableExists(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
 	}
-	destTableName, err := og.randTable(ctx, tx, og.pctExisting(false), desiredSchema)
+	if !tableExists {
+		return makeOpStmtForSingleError(OpStmtDDL,
+			fmt.Sprintf(`ALTER TABLE %s DROP COLUMN "IrrelevantColumnName"`, tableName),
+			pgcode.UndefinedTable), nil
+	}
+	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
 	if err != nil {
 		return nil, err
 	}
 
-	srcTableExists, err := og.tableExists(ctx, tx, srcTableName)
+	columnName, err := og.randColumn(ctx, tx, *tableName, og.pctExisting(true))
 	if err != nil {
 		return nil, err
 	}
-	if srcTableExists {
-		err = og.tableHasPrimaryKeySwapActive(ctx, tx, srcTableName)
+	columnExists, err := og.columnExistsOnTable(ctx, tx, tableName, columnName)
+	if err != nil {
+		return nil, err
+	}
+	colIsPrimaryKey, err := og.colIsPrimaryKey(ctx, tx, tableName, columnName)
+	if err != nil {
+		return nil, err
+	}
+	columnIsDependedOn, err := og.columnIsDependedOn(ctx, tx, tableName, columnName)
+	if err != nil {
+		return nil, err
+	}
+	// Sequences owned by the column are dropped along with it.
+	ownedSequencesAreDependedOn := false
+	if columnExists {
+		ownedSequencesAreDependedOn, err = og.ownedSequencesAreDependedOn(ctx, tx, tableName, columnName)
 		if err != nil {
 			return nil, err
 		}
 	}
-
-	destSchemaExists, err := og.schemaExists(ctx, tx, destTableName.Schema())
+	columnIsInDroppingIndex, err := og.columnIsInDroppingIndex(ctx, tx, tableName, columnName)
 	if err != nil {
 		return nil, err
 	}
-
-	destTableExists, err := og.tableExists(ctx, tx, destTableName)
+	columnIsInPartialIndexPredicate, err := og.columnIsInPartialIndexPredicate(ctx, tx, tableName, columnName)
 	if err != nil {
 		return nil, err
 	}
-
-	srcTableHasDependencies, err := og.tableHasDependencies(ctx, tx, srcTableName)
+	columnIsInTTLExpirationExpression, err := og.columnIsInTTLExpirationExpression(ctx, tx, tableName, columnName)
+	if err != nil {
+		return nil, err
+	}
+	columnIsTTLExpireAfterColumn, err := og.columnIsTTLExpireAfterColumn(ctx, tx, tableName, columnName)
+	if err != nil {
+		return nil, err
+	}
+	hasAlterPKSchemaChange, err := og.tableHasOngoingAlterPKSchemaChanges(ctx, tx, tableName)
 	if err != nil {
 		return nil, err
 	}
 
-	srcEqualsDest := destTableName.String() == srcTableName.String()
 	stmt := makeOpStmt(OpStmtDDL)
 	stmt.expectedExecErrors.addAll(codesWithConditions{
-		{code: pgcode.UndefinedTable, condition: !srcTableExists},
-		{code: pgcode.UndefinedSchema, condition: !destSchemaExists},
-		{code: pgcode.DuplicateRelation, condition: !srcEqualsDest && destTableExists},
-		{code: pgcode.DependentObjectsStillExist, condition: srcTableHasDependencies},
-		{code: pgcode.InvalidName, condition: srcTableName.Schema() != destTableName.Schema()},
+		{code: pgcode.ObjectNotInPrerequisiteState, condition: columnIsInDroppingIndex},
+		{code: pgcode.InvalidTableDefinition, condition: columnIsInTTLExpirationExpression},
+		{code: pgcode.InvalidTableDefinition, condition: columnIsTTLExpireAfterColumn},
+		{code: pgcode.UndefinedColumn, condition: !columnExists},
+		{code: pgcode.InvalidColumnReference, condition: colIsPrimaryKey},
+		{code: pgcode.InvalidColumnReference, condition: columnIsInPartialIndexPredicate},
+		{code: pgcode.DependentObjectsStillExist, condition: columnIsDependedOn},
+		{code: pgcode.DependentObjectsStillExist, condition: ownedSequencesAreDependedOn},
+		{code: pgcode.FeatureNotSupported, condition: hasAlterPKSchemaChange},
 	})
-
-	stmt.sql = fmt.Sprintf(`ALTER TABLE %s RENAME TO %s`, srcTableName, destTableName)
+	stmt.sql = fmt.Sprintf(`ALTER TABLE %s DROP COLUMN "%s"`, tableName, columnName)
 	return stmt, nil
 }
 
-func (og *operationGenerator) renameView(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
-	srcViewName, err := og.randView(ctx, tx, og.pctExisting(true), "")
+func (og *operationGenerator) dropColumnDefault(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
 	if err != nil {
 		return nil, err
 	}
-
-	// Decide whether or not to produce a 'cannot change schema of table with RENAME' error
-	desiredSchema := ""
-	if !og.produceError() {
-		desiredSchema = srcViewName.SchemaName.String()
-	}
-	destViewName, err := og.randView(ctx, tx, og.pctExisting(false), desiredSchema)
+	tableExists, err := og.tableExists(ctx, tx, tableName)
 	if err != nil {
 		return nil, err
 	}
-
-	srcViewExists, err := og.viewExists(ctx, tx, srcViewName)
+	if !tableExists {
+		return makeOpStmtForSingleError(OpStmtDDL,
+			fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN "IrrelevantColumnName" DROP DEFAULT`, tableName),
+			pgcode.UndefinedTable), nil
+	}
+	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
 	if err != nil {
 		return nil, err
 	}
-
-	destSchemaExists, err := og.schemaExists(ctx, tx, destViewName.Schema())
+	columnName, err := og.randColumn(ctx, tx, *tableName, og.pctExisting(true))
 	if err != nil {
 		return nil, err
 	}
-
-	destViewExists, err := og.viewExists(ctx, tx, destViewName)
+	columnExists, err := og.columnExistsOnTable(ctx, tx, tableName, columnName)
 	if err != nil {
 		return nil, err
 	}
-
-	srcTableHasDependencies, err := og.tableHasDependencies(ctx, tx, srcViewName)
+	columnIsTTLExpireAfterColumn, err := og.columnIsTTLExpireAfterColumn(ctx, tx, tableName, columnName)
 	if err != nil {
 		return nil, err
 	}
-
-	srcEqualsDest := destViewName.String() == srcViewName.String()
 	stmt := makeOpStmt(OpStmtDDL)
 	stmt.expectedExecErrors.addAll(codesWithConditions{
-		{code: pgcode.UndefinedTable, condition: !srcViewExists},
-		{code: pgcode.UndefinedSchema, condition: !destSchemaExists},
-		{code: pgcode.DuplicateRelation, condition: !srcEqualsDest && destViewExists},
-		{code: pgcode.DependentObjectsStillExist, condition: srcTableHasDependencies},
-		{code: pgcode.InvalidName, condition: srcViewName.Schema() != destViewName.Schema()},
+		{pgcode.UndefinedColumn, !columnExists},
+		{pgcode.InvalidTableDefinition, columnIsTTLExpireAfterColumn},
 	})
-
-	stmt.sql = fmt.Sprintf(`ALTER VIEW %s RENAME TO %s`, srcViewName, destViewName)
+	stmt.sql = fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN "%s" DROP DEFAULT`, tableName, columnName)
 	return stmt, nil
 }
 
-func (og *operationGenerator) setColumnDefault(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
-
+func (og *operationGenerator) dropColumnNotNull(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
 	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
 	if err != nil {
 		return nil, err
 	}
-
 	tableExists, err := og.tableExists(ctx, tx, tableName)
 	if err != nil {
 		return nil, err
 	}
 	if !tableExists {
 		return makeOpStmtForSingleError(OpStmtDDL,
-			fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN IrrelevantColumnName SET DEFAULT "IrrelevantValue"`,
-				tableName),
+			fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN "IrrelevantColumnName" DROP NOT NULL`, tableName),
 			pgcode.UndefinedTable), nil
 	}
 	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
 	if err != nil {
 		return nil, err
 	}
-
-	columnForDefault, err := og.randColumnWithMeta(ctx, tx, *tableName, og.pctExisting(true))
+	columnName, err := og.randColumn(ctx, tx, *tableName, og.pctExisting(true))
 	if err != nil {
 		return nil, err
 	}
-	columnExists, err := og.columnExistsOnTable(ctx, tx, tableName, columnForDefault.name)
+	columnExists, err := og.columnExistsOnTable(ctx, tx, tableName, columnName)
 	if err != nil {
 		return nil, err
 	}
-	if !columnExists {
-		return makeOpStmtForSingleError(OpStmtDDL,
-			fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN %s SET DEFAULT "IrrelevantValue"`,
-				tableName, columnForDefault.name),
-			pgcode.UndefinedColumn), nil
-	}
-
-	datumTyp := columnForDefault.typ
-	// Optionally change the incorrect type to potentially create errors.
-	if og.produceError() {
-		newTypeName, newTyp, err := og.randType(ctx, tx, og.pctExisting(true))
-		if err != nil {
-			return nil, err
-		}
-		if newTyp == nil {
-			return makeOpStmtForSingleError(OpStmtDDL,
-				fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN %s SET DEFAULT 'IrrelevantValue':::%s`, tableName, columnForDefault.name, newTypeName.SQLString()),
-				pgcode.UndefinedColumn), nil
-		}
-		datumTyp = newTyp
+	colIsPrimaryKey, err := og.colIsPrimaryKey(ctx, tx, tableName, columnName)
+	if err != nil {
+		return nil, err
 	}
-
-	defaultDatum := randgen.RandDatum(og.params.rng, datumTyp, columnForDefault.nullable)
-	stmt := makeOpStmt(OpStmtDDL)
-	if (!datumTyp.Equivalent(columnForDefault.typ)) && defaultDatum != tree.DNull {
-		stmt.expectedExecErrors.add(pgcode.DatatypeMismatch)
+	columnIsTTLExpireAfterColumn, err := og.columnIsTTLExpireAfterColumn(ctx, tx, tableName, columnName)
+	if err != nil {
+		return nil, err
 	}
 
-	// Generated columns cannot have default values.
-	if columnForDefault.generated {
-		stmt.expectedExecErrors.add(pgcode.InvalidTableDefinition)
+	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
 	}
 
-	stmt.sql = fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s`, tableName, columnForDefault.name, tree.AsStringWithFlags(defaultDatum, tree.FmtParsable))
+	stmt := makeOpStmt(OpStmtDDL)
+	stmt.expectedExecErrors.addAll(codesWithConditions{
+		{pgcode.UndefinedColumn, !columnExists},
+		{pgcode.InvalidTableDefinition, colIsPrimaryKey},
+		{pgcode.InvalidTableDefinition, columnIsTTLExpireAfterColumn},
+	})
+	stmt.sql = fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN "%s" DROP NOT NULL`, tableName, columnName)
 	return stmt, nil
 }
 
-func (og *operationGenerator) setColumnNotNull(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+// dropColumnOnUpdate removes the ON UPDATE expression of a column, if it has
+// one.
+func (og *operationGenerator) dropColumnOnUpdate(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
 	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
 	if err != nil {
 		return nil, err
 	}
-
 	tableExists, err := og.tableExists(ctx, tx, tableName)
 	if err != nil {
 		return nil, err
 	}
 	if !tableExists {
 		return makeOpStmtForSingleError(OpStmtDDL,
-			fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN IrrelevantColumnName SET NOT NULL`, tableName),
+			fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN "IrrelevantColumnName" DROP ON UPDATE`, tableName),
 			pgcode.UndefinedTable), nil
 	}
 	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
 	if err != nil {
 		return nil, err
 	}
-
 	columnName, err := og.randColumn(ctx, tx, *tableName, og.pctExisting(true))
 	if err != nil {
 		return nil, err
@@ -2320,51 +3822,86 @@ func (og *operationGenerator) setColumnNotNull(ctx context.Context, tx pgx.Tx) (
 	if err != nil {
 		return nil, err
 	}
-	constraintBeingAdded, err := og.columnNotNullConstraintInMutation(ctx, tx, tableName, columnName)
+	columnIsIdentity, err := og.columnIsIdentity(ctx, tx, tableName, columnName)
+	if err != nil {
+		return nil, err
+	}
+	columnIsTTLExpireAfterColumn, err := og.columnIsTTLExpireAfterColumn(ctx, tx, tableName, columnName)
 	if err != nil {
 		return nil, err
 	}
 	stmt := makeOpStmt(OpStmtDDL)
-	if constraintBeingAdded {
-		stmt.expectedExecErrors.add(pgcode.ObjectNotInPrerequisiteState)
+	stmt.expectedExecErrors.addAll(codesWithConditions{
+		{pgcode.UndefinedColumn, !columnExists},
+		// The expressions of identity columns can't be changed.
+		{pgcode.Syntax, columnIsIdentity},
+		{pgcode.InvalidTableDefinition, columnIsTTLExpireAfterColumn},
+	})
+	stmt.sql = fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN "%s" DROP ON UPDATE`, tableName, columnName)
+	return stmt, nil
+}
+
+func (og *operationGenerator) dropColumnStored(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
+	if err != nil {
+		return nil, err
+	}
+	tableExists, err := og.tableExists(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if !tableExists {
+		return makeOpStmtForSingleError(OpStmtDDL,
+			fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN IrrelevantColumnName DROP STORED`, tableName),
+			pgcode.UndefinedTable), nil
+	}
+	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
 	}
 
-	if !columnExists {
-		stmt.expectedExecErrors.add(pgcode.UndefinedColumn)
-	} else {
-		// If the column has null values, then a check violation will occur upon committing.
-		colContainsNull, err := og.columnContainsNull(ctx, tx, tableName, columnName)
-		if err != nil {
-			return nil, err
-		}
-		if colContainsNull {
-			og.candidateExpectedCommitErrors.add(pgcode.CheckViolation)
-		}
+	columnName, err := og.randColumn(ctx, tx, *tableName, og.pctExisting(true))
+	if err != nil {
+		return nil, err
+	}
+	columnExists, err := og.columnExistsOnTable(ctx, tx, tableName, columnName)
+	if err != nil {
+		return nil, err
 	}
 
-	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
+	columnIsStored, err := og.columnIsStoredComputed(ctx, tx, tableName, columnName)
 	if err != nil {
 		return nil, err
 	}
-	stmt.sql = fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN "%s" SET NOT NULL`, tableName, columnName)
+	columnIsTTLExpireAfterColumn, err := og.columnIsTTLExpireAfterColumn(ctx, tx, tableName, columnName)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := makeOpStmt(OpStmtDDL)
+	stmt.expectedExecErrors.addAll(codesWithConditions{
+		{code: pgcode.InvalidColumnDefinition, condition: !columnIsStored},
+		{code: pgcode.UndefinedColumn, condition: !columnExists},
+		{code: pgcode.InvalidTableDefinition, condition: columnIsTTLExpireAfterColumn},
+	})
+
+	stmt.sql = fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN "%s" DROP STORED`, tableName, columnName)
 	return stmt, nil
 }
 
-func (og *operationGenerator) setColumnType(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+func (og *operationGenerator) dropConstraint(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
 	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
 	if err != nil {
 		return nil, err
 	}
 
-	const setSessionVariableString = `SET enable_experimental_alter_column_type_general = true;`
-
 	tableExists, err := og.tableExists(ctx, tx, tableName)
 	if err != nil {
 		return nil, err
 	}
 	if !tableExists {
 		return makeOpStmtForSingleError(OpStmtDDL,
-			fmt.Sprintf(`%s ALTER TABLE %s ALTER COLUMN IrrelevantColumnName SET DATA TYPE IrrelevantDataType`, setSessionVariableString, tableName),
+			fmt.Sprintf(`ALTER TABLE %s DROP CONSTRAINT IrrelevantConstraintName`, tableName),
 			pgcode.UndefinedTable), nil
 	}
 	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
@@ -2372,347 +3909,4075 @@ func (og *operationGenerator) setColumnType(ctx context.Context, tx pgx.Tx) (*op
 		return nil, err
 	}
 
-	columnForTypeChange, err := og.randColumnWithMeta(ctx, tx, *tableName, og.pctExisting(true))
+	constraintName, err := og.randConstraint(ctx, tx, tableName.String())
 	if err != nil {
 		return nil, err
 	}
 
-	columnExists, err := og.columnExistsOnTable(ctx, tx, tableName, columnForTypeChange.name)
+	// Dropping the primary key of a table without adding a new primary key
+	// subsequently in the transaction is not supported. Since addConstraint is not implemented,
+	// a replacement primary key will not be created in the same transaction. Thus,
+	// dropping a primary key will always produce an error.
+	stmt := makeOpStmt(OpStmtDDL)
+	constraintIsPrimary, err := og.constraintIsPrimary(ctx, tx, tableName, constraintName)
 	if err != nil {
 		return nil, err
 	}
-	if !columnExists {
-		return makeOpStmtForSingleError(OpStmtDDL,
-			fmt.Sprintf(`%s ALTER TABLE %s ALTER COLUMN "%s" SET DATA TYPE IrrelevantTypeName`,
-				setSessionVariableString, tableName, columnForTypeChange.name),
-			pgcode.UndefinedColumn), nil
+	if constraintIsPrimary {
+		og.candidateExpectedCommitErrors.add(pgcode.FeatureNotSupported)
 	}
 
-	newTypeName, newType, err := og.randType(ctx, tx, og.pctExisting(true))
+	// DROP INDEX CASCADE is preferred for dropping unique constraints, and
+	// dropping the constraint with ALTER TABLE ... DROP CONSTRAINT is unsupported.
+	constraintIsUnique, err := og.constraintIsUnique(ctx, tx, tableName, constraintName)
 	if err != nil {
 		return nil, err
 	}
+	if constraintIsUnique {
+		stmt.expectedExecErrors.add(pgcode.FeatureNotSupported)
+	}
 
-	columnHasDependencies, err := og.columnIsDependedOn(ctx, tx, tableName, columnForTypeChange.name)
+	constraintBeingDropped, err := og.constraintInDroppingState(ctx, tx, tableName, constraintName)
 	if err != nil {
 		return nil, err
 	}
-
-	stmt := makeOpStmt(OpStmtDDL)
-	if newType != nil {
-		// Ignoring the error here intentionally, as we want to carry on with
-		// the operation and not fail it prematurely.
-		kind, _ := schemachange.ClassifyConversion(context.Background(), columnForTypeChange.typ, newType)
-		stmt.expectedExecErrors.addAll(codesWithConditions{
-			{code: pgcode.CannotCoerce, condition: kind == schemachange.ColumnConversionImpossible},
-			{code: pgcode.FeatureNotSupported, condition: kind != schemachange.ColumnConversionTrivial},
-		})
+	if constraintBeingDropped {
+		stmt.expectedExecErrors.add(pgcode.FeatureNotSupported)
 	}
 
-	stmt.expectedExecErrors.addAll(codesWithConditions{
-		{code: pgcode.UndefinedObject, condition: newType == nil},
-		{code: pgcode.DependentObjectsStillExist, condition: columnHasDependencies},
-	})
-
-	stmt.sql = fmt.Sprintf(`%s ALTER TABLE %s ALTER COLUMN "%s" SET DATA TYPE %s`,
-		setSessionVariableString, tableName, columnForTypeChange.name, newTypeName.SQLString())
+	stmt.sql = fmt.Sprintf(`ALTER TABLE %s DROP CONSTRAINT "%s"`, tableName, constraintName)
 	return stmt, nil
 }
 
-func (og *operationGenerator) alterTableAlterPrimaryKey(
-	ctx context.Context, tx pgx.Tx,
-) (*opStmt, error) {
-	// Primary Keys are backed by a unique index, therefore we can only use
-	// columns that are of an indexable type. This information is only available
-	// via the colinfo package (not SQL) and is subject to change across
-	// versions. To eliminate the chance of flakes, rely on this allow list to do
-	// the filtering. As this list is static and non-exhaustive, we're trading a
-	// bit of coverage for stability. It may be worth while to add index-ability
-	// information to `SHOW COLUMNS` or an internal SQL function in the future.
-	indexableFamilies := []string{
-		"DecimalFamily",
-		"IntFamily",
-		"StringFamily",
-		"UuidFamily",
+func (og *operationGenerator) dropIndex(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
+	if err != nil {
+		return nil, err
+	}
+	tableExists, err := og.tableExists(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if !tableExists {
+		return makeOpStmtForSingleError(OpStmtDDL,
+			fmt.Sprintf(`DROP INDEX %s@"IrrelevantIndexName"`, tableName),
+			pgcode.UndefinedTable), nil
+	}
+	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
 	}
 
-	q := With([]CTE{
-		{"descriptors", descJSONQuery},
-		{"tables", tableDescQuery},
-		{"columns", colDescQuery},
-	}, `
-		SELECT
-			json_array_length(table_descriptor->'mutation') > 0 AS table_undergoing_schema_change,
-			quote_ident(schema_id::REGNAMESPACE::TEXT) || '.' || quote_ident(table_name) AS table_name,
-			quote_ident("column"->>'name') AS column_name,
-			COALESCE(("column"->'nullable')::bool, false) AS is_nullable,
-			("column"->>'computedExpr' = '') AS is_computed,
-			(("column"->'type'->>'family') = ANY($1)) AS is_indexable,
-			(NOT EXISTS(
-				SELECT *
-				FROM crdb_internal.table_indexes
-				JOIN crdb_internal.index_columns USING (descriptor_id)
-				WHERE table_indexes.is_inverted
-				AND table_indexes.descriptor_id = columns.table_id
-				AND index_columns.column_id = (columns."column"->'id')::int8
-			)) AS is_in_inverted_index,
-			(EXISTS(
-				SELECT *
-				FROM crdb_internal.table_indexes
-				JOIN crdb_internal.index_columns USING (descriptor_id)
-				WHERE table_indexes.is_unique
-				AND table_indexes.descriptor_id = columns.table_id
-				AND index_columns.column_id = (columns."column"->'id')::int8
-			)) AS is_unique
-		FROM columns
-		WHERE NOT (
-			COALESCE(("column"->'hidden')::bool, false)
-			OR  COALESCE(("column"->'inaccessible')::bool, false)
-		)`)
-
-	columns, err := Collect(ctx, og, tx, pgx.RowToMap, q, indexableFamilies)
+	indexName, err := og.randIndex(ctx, tx, *tableName, og.pctExisting(true))
 	if err != nil {
 		return nil, err
 	}
 
-	// Group columns by table for convenience in our templates. This could have
-	// been done within SQL but I didn't want to fight with unmarshalling nested
-	// JSON fields.
-	byTable := map[string][]map[string]any{}
-	for i, col := range columns {
-		byTable[col["table_name"].(string)] = append(
-			byTable[col["table_name"].(string)],
-			columns[i],
-		)
+	// Sometimes target an index that is required by an inbound foreign key.
+	// Such an index is dropped along with the foreign key by CASCADE, like
+	// any other index, but cannot be dropped with RESTRICT. Either drop it
+	// with one of them, or drop the foreign key first, allowing the index to
+	// be dropped by a later operation.
+	dropBehavior := tree.DropCascade
+	indexBacksForeignKey := false
+	if og.randIntn(2) == 0 {
+		fkBackingIndexes, err := og.secondaryIndexesBackingForeignKeys(ctx, tx, tableName)
+		if err != nil {
+			return nil, err
+		}
+		if len(fkBackingIndexes) > 0 {
+			backing := fkBackingIndexes[og.randIntn(len(fkBackingIndexes))]
+			if og.randIntn(2) == 0 {
+				referencingTable := tree.MakeTableNameFromPrefix(tree.ObjectNamePrefix{
+					SchemaName:     tree.Name(backing[2]),
+					ExplicitSchema: true,
+				}, tree.Name(backing[3]))
+				return og.dropForeignKeyBackedByIndex(ctx, tx, &referencingTable, backing[1])
+			}
+			indexName = backing[0]
+			indexBacksForeignKey = true
+			if og.randIntn(2) == 0 {
+				dropBehavior = tree.DropRestrict
+			}
+		}
 	}
 
-	tables := make([]map[string]any, 0, len(byTable))
-	for table_name, grouped := range byTable {
-		tables = append(tables, map[string]any{
-			"table_name":                     table_name,
-			"table_undergoing_schema_change": grouped[0]["table_undergoing_schema_change"].(bool),
-			"columns":                        grouped,
-		})
+	stmt := makeOpStmt(OpStmtDDL)
+	if indexBacksForeignKey && dropBehavior == tree.DropRestrict {
+		stmt.expectedExecErrors.add(pgcode.DependentObjectsStillExist)
+	}
+	indexExists, err := og.indexExists(ctx, tx, tableName, indexName)
+	if err != nil {
+		return nil, err
+	}
+	if !indexExists {
+		stmt.expectedExecErrors.add(pgcode.UndefinedObject)
 	}
 
-	// Our big query can only check if there are any unique indexes on columns.
-	// We'll also want to check if any columns happen to be unique rather than
-	// being constrained to uniqueness.
-	fillIsUnique := func(table map[string]any) error {
-		// Cache uniqueness checks. They're expensive and might run twice in some
-		// weird cases with the Generate helper.
-		if _, ok := table["unique_check"]; ok {
-			return nil
-		}
+	hasAlterPKSchemaChange, err := og.tableHasOngoingAlterPKSchemaChanges(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if hasAlterPKSchemaChange {
+		stmt.expectedExecErrors.add(pgcode.FeatureNotSupported)
+	}
 
-		table["unique_check"] = true
+	databaseHasRegionChange, err := og.databaseHasRegionChange(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	tableIsRegionalByRow, err := og.tableIsRegionalByRow(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if databaseHasRegionChange && tableIsRegionalByRow {
+		stmt.expectedExecErrors.add(pgcode.ObjectNotInPrerequisiteState)
+	}
 
-		var b strings.Builder
-		fmt.Fprintf(&b, `SELECT * FROM VALUES (`)
-		for _, column := range table["columns"].([]map[string]any) {
-			// If this column is already known to be unique, don't bother checking
-			// it. This should only happen if there's a unique constraint on the
-			// column.
-			if column["is_unique"].(bool) {
-				fmt.Fprintf(&b, `(SELECT true)`)
-			} else {
-				fmt.Fprintf(&b, `(SELECT EXISTS(SELECT 1 FROM %s GROUP BY %s HAVING count(*) > 1))`, table["table_name"], column["column_name"])
-			}
-		}
-		fmt.Fprintf(&b, `)`)
+	stmt.sql = fmt.Sprintf(`DROP INDEX %s@"%s" %s`, tableName, indexName, dropBehavior)
+	return stmt, nil
+}
 
-		results, err := Collect(ctx, og, tx, pgx.RowTo[bool], b.String())
-		if err != nil {
+// dropForeignKeyBackedByIndex drops a foreign key that prevents the index
+// serving it from being dropped.
+func (og *operationGenerator) dropForeignKeyBackedByIndex(
+	ctx context.Context, tx pgx.Tx, tableName *tree.TableName, constraintName string,
+) (*opStmt, error) {
+	stmt := makeOpStmt(OpStmtDDL)
+	constraintBeingDropped, err := og.constraintInDroppingState(ctx, tx, tableName, constraintName)
+	if err != nil {
+		return nil, err
+	}
+	hasAlterPKSchemaChange, err := og.tableHasOngoingAlterPKSchemaChanges(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	stmt.expectedExecErrors.addAll(codesWithConditions{
+		{code: pgcode.FeatureNotSupported, condition: constraintBeingDropped},
+	})
+	stmt.potentialExecErrors.addAll(codesWithConditions{
+		{code: pgcode.FeatureNotSupported, condition: hasAlterPKSchemaChange},
+	})
+	stmt.sql = fmt.Sprintf(`ALTER TABLE %s DROP CONSTRAINT "%s"`, tableName, constraintName)
+	return stmt, nil
+}
+
+func (og *operationGenerator) dropSequence(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	sequenceName, err := og.randSequence(ctx, tx, og.pctExisting(true), "")
+	if err != nil {
+		return nil, err
+	}
+	ifExists := og.randIntn(2) == 0
+	dropBehavior := tree.DropBehavior(og.randIntn(3))
+	dropSeq := &tree.DropSequence{
+		Names:        tree.TableNames{*sequenceName},
+		IfExists:     ifExists,
+		DropBehavior: dropBehavior,
+	}
+
+	stmt := makeOpStmt(OpStmtDDL)
+	sequenceExists, err := og.sequenceExists(ctx, tx, sequenceName)
+	if err != nil {
+		return nil, err
+	}
+	if !sequenceExists && !ifExists {
+		stmt.expectedExecErrors.add(pgcode.UndefinedTable)
+	}
+	// Sequences backing SERIAL columns are used by the column default, which
+	// is removed along with the sequence by CASCADE.
+	if sequenceExists && dropBehavior != tree.DropCascade {
+		sequenceIsDependedOn, err := og.sequenceIsDependedOn(ctx, tx, sequenceName)
+		if err != nil {
+			return nil, err
+		}
+		if sequenceIsDependedOn {
+			stmt.expectedExecErrors.add(pgcode.DependentObjectsStillExist)
+		}
+	}
+	stmt.sql = tree.Serialize(dropSeq)
+	return stmt, nil
+}
+
+func (og *operationGenerator) dropTable(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
+	if err != nil {
+		return nil, err
+	}
+	tableExists, err := og.tableExists(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	tableHasDependencies, err := og.tableHasDependencies(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	// Sequences owned by columns of the table are dropped along with it.
+	if tableExists && !tableHasDependencies {
+		tableHasDependencies, err = og.ownedSequencesAreDependedOn(ctx, tx, tableName, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dropBehavior := tree.DropBehavior(og.randIntn(3))
+
+	ifExists := og.randIntn(2) == 0
+	dropTable := tree.DropTable{
+		Names:        []tree.TableName{*tableName},
+		IfExists:     ifExists,
+		DropBehavior: dropBehavior,
+	}
+
+	stmt := makeOpStmt(OpStmtDDL)
+	stmt.expectedExecErrors.addAll(codesWithConditions{
+		{pgcode.UndefinedTable, !ifExists && !tableExists},
+		{pgcode.DependentObjectsStillExist, dropBehavior != tree.DropCascade && tableHasDependencies},
+	})
+	stmt.sql = dropTable.String()
+	return stmt, nil
+}
+
+func (og *operationGenerator) dropView(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	// Materialized views are never picked by randView, and are dropped with
+	// DROP MATERIALIZED VIEW instead.
+	materialized := og.randIntn(4) == 0
+	var viewName *tree.TableName
+	var err error
+	if materialized {
+		viewName, err = og.randMaterializedView(ctx, tx, og.pctExisting(true))
+	} else {
+		viewName, err = og.randView(ctx, tx, og.pctExisting(true), "")
+	}
+	if err != nil {
+		return nil, err
+	}
+	viewExists, err := og.tableExists(ctx, tx, viewName)
+	if err != nil {
+		return nil, err
+	}
+	viewHasDependencies, err := og.tableHasDependencies(ctx, tx, viewName)
+	if err != nil {
+		return nil, err
+	}
+
+	dropBehavior := tree.DropBehavior(og.randIntn(3))
+
+	ifExists := og.randIntn(2) == 0
+	dropView := tree.DropView{
+		Names:          []tree.TableName{*viewName},
+		IfExists:       ifExists,
+		DropBehavior:   dropBehavior,
+		IsMaterialized: materialized,
+	}
+
+	stmt := makeOpStmt(OpStmtDDL)
+	stmt.expectedExecErrors.addAll(codesWithConditions{
+		{pgcode.UndefinedTable, !ifExists && !viewExists},
+		{pgcode.DependentObjectsStillExist, dropBehavior != tree.DropCascade && viewHasDependencies},
+	})
+	stmt.sql = dropView.String()
+	return stmt, nil
+}
+
+func (og *operationGenerator) refreshMaterializedView(
+	ctx context.Context, tx pgx.Tx,
+) (*opStmt, error) {
+	// Query for all views returning:
+	// * name - the escaped fully qualified view name.
+	// * materialized - a bool indicating if the view is materialized.
+	// * refreshing - a bool indicating if the view is already being
+	//   refreshed, in which case it can't be refreshed again.
+	// * has_unique_index - a bool indicating if the view has a unique index,
+	//   which is required to refresh it CONCURRENTLY.
+	query := With([]CTE{
+		{"descriptors", descJSONQuery},
+	}, `SELECT
+				quote_ident(schema_id::REGNAMESPACE::TEXT) || '.' || quote_ident(name) AS name,
+				COALESCE((descriptor->'table'->>'isMaterializedView')::BOOL, false) AS materialized,
+				EXISTS(
+					SELECT 1
+					FROM jsonb_array_elements(COALESCE(descriptor->'table'->'mutations', '[]'::JSONB)) AS m
+					WHERE m ? 'materializedViewRefresh'
+				) AS refreshing,
+				EXISTS(
+					SELECT 1
+					FROM jsonb_array_elements(COALESCE(descriptor->'table'->'indexes', '[]'::JSONB)) AS i
+					WHERE COALESCE((i->>'unique')::BOOL, false)
+				) AS has_unique_index
+			FROM descriptors
+			WHERE descriptor->'table' ? 'viewQuery'
+			AND COALESCE(descriptor->'table'->>'state', 'PUBLIC') = 'PUBLIC'
+	`)
+
+	type view struct {
+		Name         string
+		Materialized bool
+		Refreshing   bool
+		Unique       bool
+	}
+	views, err := Collect(ctx, og, tx, pgx.RowToStructByPos[view], query)
+	if err != nil {
+		return nil, og.checkAndAdjustForUnknownSchemaErrors(err)
+	}
+
+	var picked *view
+	stmt, code, err := Generate[*tree.RefreshMaterializedView](og.params.rng, og.produceError(), []GenerationCase{
+		// Fail to refresh a view that doesn't exist.
+		{pgcode.UndefinedTable, `REFRESH MATERIALIZED VIEW "ViewThatDoesntExist"`},
+		// Fail to refresh a view that is not materialized.
+		{pgcode.WrongObjectType, `REFRESH MATERIALIZED VIEW { View false }`},
+		// Refresh a materialized view.
+		{pgcode.SuccessfulCompletion, `REFRESH MATERIALIZED VIEW { Concurrently (View true) }`},
+	}, template.FuncMap{
+		"View": func(materialized bool) (string, error) {
+			v, err := PickOne(og.params.rng, util.Filter(views, func(v view) bool {
+				return v.Materialized == materialized
+			}))
+			if err != nil {
+				return "", err
+			}
+			picked = &v
+			return v.Name, nil
+		},
+		// Only views with a unique index are refreshed CONCURRENTLY, as
+		// required by PostgreSQL.
+		"Concurrently": func(name string) string {
+			if picked.Unique && og.randIntn(2) == 0 {
+				return "CONCURRENTLY " + name
+			}
+			return name
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newOpStmt(stmt, codesWithConditions{
+		{code, true},
+		{pgcode.ObjectNotInPrerequisiteState, code == pgcode.SuccessfulCompletion && picked.Refreshing},
+	}), nil
+}
+
+func (og *operationGenerator) dropType(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	// Query for all enums and composite types of the schemas that can be
+	// accessed, returning:
+	// * name - the escaped fully qualified type name.
+	// * type_name - the escaped type name, without its schema.
+	// * has_references - a bool indicating if this type, or its implicit array
+	//   type, is referenced by other descriptors, e.g. by the columns of a
+	//   table.
+	// The schemas are read from the descriptors rather than resolved by ID,
+	// so that the types of schemas being dropped are left out instead of
+	// failing the query.
+	query := With([]CTE{
+		{"descriptors", descJSONQuery},
+		{"enums", enumDescsQuery},
+	}, `SELECT
+				quote_ident(s.name) || '.' || quote_ident(enums.name) AS name,
+				quote_ident(enums.name) AS type_name,
+				COALESCE(json_array_length(enums.descriptor->'referencingDescriptorIds') > 0, false) OR EXISTS(
+					SELECT 1 FROM enums AS arr
+					WHERE arr.id = (enums.descriptor->>'arrayTypeId')::INT8
+					AND COALESCE(json_array_length(arr.descriptor->'referencingDescriptorIds') > 0, false)
+				) AS has_references
+			FROM enums
+			JOIN descriptors AS s ON s.id = enums.schema_id AND s.descriptor ? 'schema'
+			WHERE (enums.name LIKE 'enum\_%' OR enums.name LIKE 'composite\_%')
+				AND COALESCE(enums.descriptor->>'state', 'PUBLIC') = 'PUBLIC'
+				AND COALESCE(s.descriptor->'schema'->>'state', 'PUBLIC') = 'PUBLIC'
+	`)
+
+	userTypes, err := Collect(ctx, og, tx, pgx.RowToMap, query)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, code, err := Generate[*tree.DropType](og.params.rng, og.produceError(), []GenerationCase{
+		// Fail to drop a type that doesn't exist.
+		{pgcode.UndefinedObject, `DROP TYPE "EnumThatDoesntExist"`},
+		// Successful no-op drop of a type that doesn't exist.
+		{pgcode.SuccessfulCompletion, `DROP TYPE IF EXISTS "EnumThatDoesntExist"`},
+		// Fail to drop a type through a schema that doesn't exist, which
+		// doesn't resolve to the type of the same name in another schema.
+		{pgcode.UndefinedObject, `{ with (Type false) } DROP TYPE "SchemaThatDoesntExist".{ .type_name } { end }`},
+		// Fail to drop a type that is still referenced by a column.
+		{pgcode.DependentObjectsStillExist, `{ with (Type true) } DROP TYPE { .name } { end }`},
+		// Successful drop of an unreferenced type.
+		{pgcode.SuccessfulCompletion, `{ with (Type false) } DROP TYPE { .name } { end }`},
+	}, template.FuncMap{
+		"Type": func(referenced bool) (map[string]any, error) {
+			return PickOne(og.params.rng, util.Filter(userTypes, func(typ map[string]any) bool {
+				return typ["has_references"].(bool) == referenced
+			}))
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newOpStmt(stmt, codesWithConditions{
+		{code, true},
+	}), nil
+}
+
+// grantablePrivileges are the privileges that can be granted on each kind of
+// object targeted by grant and revoke, not counting ALL.
+var grantablePrivileges = map[string][]string{
+	"TABLE":    {"BACKUP", "CHANGEFEED", "CREATE", "DELETE", "DROP", "INSERT", "SELECT", "UPDATE", "ZONECONFIG"},
+	"SEQUENCE": {"CHANGEFEED", "CREATE", "DELETE", "DROP", "INSERT", "SELECT", "UPDATE", "USAGE", "ZONECONFIG"},
+	"SCHEMA":   {"CREATE", "USAGE"},
+	"TYPE":     {"USAGE"},
+}
+
+// allPrivileges are all the privileges that apply to objects. Those missing
+// from grantablePrivileges for a kind of object are rejected with
+// InvalidGrantOperation.
+var allPrivileges = []string{
+	"BACKUP", "CHANGEFEED", "CONNECT", "CREATE", "DELETE", "DROP", "EXECUTE",
+	"INSERT", "RESTORE", "SELECT", "UPDATE", "USAGE", "ZONECONFIG",
+}
+
+// privilegeTargets returns the objects of the current database that grant and
+// revoke may target, as maps with:
+// * kind - the kind of object, as used in the ON clause.
+// * name - the escaped, fully qualified name of the object.
+func (og *operationGenerator) privilegeTargets(
+	ctx context.Context, tx pgx.Tx,
+) ([]map[string]any, error) {
+	query := With([]CTE{
+		{"descriptors", descJSONQuery},
+	}, `SELECT
+				CASE
+					WHEN descriptor->'table' ? 'sequenceOpts' THEN 'SEQUENCE'
+					WHEN descriptor ? 'table' THEN 'TABLE'
+					WHEN descriptor ? 'schema' THEN 'SCHEMA'
+					ELSE 'TYPE'
+				END AS kind,
+				CASE
+					WHEN descriptor ? 'schema' THEN quote_ident(name)
+					ELSE quote_ident(schema_id::REGNAMESPACE::TEXT) || '.' || quote_ident(name)
+				END AS name
+			FROM descriptors
+			WHERE COALESCE(descriptor->'table'->>'state', descriptor->'schema'->>'state', descriptor->'type'->>'state', 'PUBLIC') = 'PUBLIC'
+			AND (
+				(descriptor ? 'table' AND NOT descriptor->'table' ? 'viewQuery')
+				OR descriptor ? 'schema'
+				OR (descriptor ? 'type' AND name LIKE 'enum\_%')
+			)
+	`)
+	targets, err := Collect(ctx, og, tx, pgx.RowToMap, query)
+	if err != nil {
+		// The schema of an object may be concurrently dropped by another
+		// transaction, in which case resolving its name fails.
+		return nil, og.checkAndAdjustForUnknownSchemaErrors(err)
+	}
+	return targets, nil
+}
+
+// privilegeRoles returns the escaped names of the roles that privileges may be
+// granted to or revoked from. The public role always exists, and is the only
+// role that cannot hold grant options.
+func (og *operationGenerator) privilegeRoles(ctx context.Context, tx pgx.Tx) ([]string, error) {
+	existing, err := og.existingRoles(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	var roles []string
+	for _, role := range existing {
+		roles = append(roles, tree.NameString(role))
+	}
+	return append(roles, "public"), nil
+}
+
+// privilegeFuncs returns the template functions shared by grant and revoke.
+func (og *operationGenerator) privilegeFuncs(
+	targets []map[string]any, roles []string,
+) template.FuncMap {
+	return template.FuncMap{
+		"Target": func() (map[string]any, error) {
+			return PickOne(og.params.rng, targets)
+		},
+		// Privileges returns a random list of privileges for the given kind of
+		// object. If valid is false, the list contains at least one privilege
+		// that cannot be granted on that kind of object.
+		"Privileges": func(kind string, valid bool) (string, error) {
+			grantable := grantablePrivileges[kind]
+			if !valid {
+				invalid, err := PickOne(og.params.rng, util.Filter(allPrivileges, func(privilege string) bool {
+					return !slices.Contains(grantable, privilege)
+				}))
+				if err != nil {
+					return "", err
+				}
+				privileges, err := PickBetween(og.params.rng, 0, len(grantable), grantable)
+				if err != nil {
+					return "", err
+				}
+				return strings.Join(append(privileges, invalid), ", "), nil
+			}
+			if og.randIntn(5) == 0 {
+				return "ALL", nil
+			}
+			privileges, err := PickAtLeast(og.params.rng, 1, grantable)
+			if err != nil {
+				return "", err
+			}
+			return strings.Join(privileges, ", "), nil
+		},
+		"Role": func() (string, error) {
+			return PickOne(og.params.rng, roles)
+		},
+		"NonPublicRole": func() (string, error) {
+			return PickOne(og.params.rng, util.Filter(roles, func(role string) bool {
+				return role != "public"
+			}))
+		},
+	}
+}
+
+func (og *operationGenerator) grant(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	targets, err := og.privilegeTargets(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := og.privilegeRoles(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, code, err := Generate[*tree.Grant](og.params.rng, og.produceError(), []GenerationCase{
+		// Fail to grant privileges on a table that doesn't exist.
+		{pgcode.UndefinedTable, `GRANT SELECT ON TABLE "TableThatDoesntExist" TO public`},
+		// Fail to grant privileges to a role that doesn't exist.
+		{pgcode.UndefinedObject, `{ with Target } GRANT { Privileges .kind true } ON { .kind } { .name } TO "RoleThatDoesntExist" { end }`},
+		// Fail to grant privileges that don't apply to the kind of object.
+		{pgcode.InvalidGrantOperation, `{ with Target } GRANT { Privileges .kind false } ON { .kind } { .name } TO { Role } { end }`},
+		// Fail to grant grant options to the public role.
+		{pgcode.InvalidGrantOperation, `{ with Target } GRANT { Privileges .kind true } ON { .kind } { .name } TO public WITH GRANT OPTION { end }`},
+		// Successful grant of privileges.
+		{pgcode.SuccessfulCompletion, `{ with Target } GRANT { Privileges .kind true } ON { .kind } { .name } TO { Role } { end }`},
+		// Successful grant of privileges along with their grant options.
+		{pgcode.SuccessfulCompletion, `{ with Target } GRANT { Privileges .kind true } ON { .kind } { .name } TO { NonPublicRole } WITH GRANT OPTION { end }`},
+	}, og.privilegeFuncs(targets, roles))
+	if err != nil {
+		return nil, err
+	}
+
+	return newOpStmt(stmt, codesWithConditions{
+		{code, true},
+	}), nil
+}
+
+func (og *operationGenerator) revoke(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	targets, err := og.privilegeTargets(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := og.privilegeRoles(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Revoking privileges that were never granted is a no-op, so any valid
+	// combination of privileges and role is expected to succeed.
+	stmt, code, err := Generate[*tree.Revoke](og.params.rng, og.produceError(), []GenerationCase{
+		// Fail to revoke privileges on a table that doesn't exist.
+		{pgcode.UndefinedTable, `REVOKE SELECT ON TABLE "TableThatDoesntExist" FROM public`},
+		// Fail to revoke privileges from a role that doesn't exist.
+		{pgcode.UndefinedObject, `{ with Target } REVOKE { Privileges .kind true } ON { .kind } { .name } FROM "RoleThatDoesntExist" { end }`},
+		// Fail to revoke privileges that don't apply to the kind of object.
+		{pgcode.InvalidGrantOperation, `{ with Target } REVOKE { Privileges .kind false } ON { .kind } { .name } FROM { Role } { end }`},
+		// Successful revoke of privileges.
+		{pgcode.SuccessfulCompletion, `{ with Target } REVOKE { Privileges .kind true } ON { .kind } { .name } FROM { Role } { end }`},
+		// Successful revoke of grant options only.
+		{pgcode.SuccessfulCompletion, `{ with Target } REVOKE GRANT OPTION FOR { Privileges .kind true } ON { .kind } { .name } FROM { Role } { end }`},
+	}, og.privilegeFuncs(targets, roles))
+	if err != nil {
+		return nil, err
+	}
+
+	return newOpStmt(stmt, codesWithConditions{
+		{code, true},
+	}), nil
+}
+
+func (og *operationGenerator) createRole(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	roles, err := og.existingRoles(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	newRole := fmt.Sprintf("role_%s", og.newUniqueSeqNumSuffix())
+
+	stmt, code, err := Generate[*tree.CreateRole](og.params.rng, og.produceError(), []GenerationCase{
+		// Fail to create a role that already exists.
+		{pgcode.DuplicateObject, `CREATE ROLE { Role }`},
+		// Fail to create a role with a reserved name.
+		{pgcode.ReservedName, `CREATE ROLE pg_role`},
+		// Successful no-op create of a role that already exists.
+		{pgcode.SuccessfulCompletion, `CREATE ROLE IF NOT EXISTS { Role }`},
+		// Successful create of a new role.
+		{pgcode.SuccessfulCompletion, `CREATE ROLE { NewRole }`},
+	}, template.FuncMap{
+		"Role": func() (string, error) {
+			role, err := PickOne(og.params.rng, roles)
+			return tree.NameString(role), err
+		},
+		"NewRole": func() string {
+			return tree.NameString(newRole)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if code == pgcode.SuccessfulCompletion && stmt.Name.Name == newRole {
+		og.rolesCreatedInTxn = append(og.rolesCreatedInTxn, newRole)
+	}
+	return newOpStmt(stmt, codesWithConditions{
+		{code, true},
+	}), nil
+}
+
+func (og *operationGenerator) dropRole(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	roles, err := og.existingRoles(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	dependentRoles, err := og.rolesWithDependencies(ctx, tx, roles)
+	if err != nil {
+		return nil, err
+	}
+	currentUser, err := Scan[string](ctx, og, tx, `SELECT current_user`)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, code, err := Generate[*tree.DropRole](og.params.rng, og.produceError(), []GenerationCase{
+		// Fail to drop a role that doesn't exist.
+		{pgcode.UndefinedObject, `DROP ROLE "RoleThatDoesntExist"`},
+		// Successful no-op drop of a role that doesn't exist.
+		{pgcode.SuccessfulCompletion, `DROP ROLE IF EXISTS "RoleThatDoesntExist"`},
+		// Fail to drop the current user through its role specifier.
+		{pgcode.InvalidParameterValue, `DROP ROLE CURRENT_USER`},
+		// Fail to drop the current user by name, since it owns the objects
+		// created by the workload.
+		{pgcode.DependentObjectsStillExist, `DROP ROLE { CurrentUser }`},
+		// Fail to drop a role that owns objects or holds privileges on them.
+		{pgcode.DependentObjectsStillExist, `DROP ROLE { Role true }`},
+		// Successful drop of a role without dependencies.
+		{pgcode.SuccessfulCompletion, `DROP ROLE { Role false }`},
+	}, template.FuncMap{
+		"Role": func(dependent bool) (string, error) {
+			role, err := PickOne(og.params.rng, util.Filter(roles, func(role string) bool {
+				return slices.Contains(dependentRoles, role) == dependent
+			}))
+			return tree.NameString(role), err
+		},
+		"CurrentUser": func() string {
+			return tree.NameString(currentUser)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if code == pgcode.SuccessfulCompletion && !stmt.IfExists {
+		og.rolesDroppedInTxn = append(og.rolesDroppedInTxn, stmt.Names[0].Name)
+	}
+	return newOpStmt(stmt, codesWithConditions{
+		{code, true},
+	}), nil
+}
+
+func (og *operationGenerator) reassignOwnedBy(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	roles, err := og.existingRoles(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	currentUser, err := Scan[string](ctx, og, tx, `SELECT current_user`)
+	if err != nil {
+		return nil, err
+	}
+
+	// Objects are only ever reassigned away from roles of the pool, since
+	// reassigning the objects of the current user would take over every
+	// object of the workload.
+	stmt, code, err := Generate[*tree.ReassignOwnedBy](og.params.rng, og.produceError(), []GenerationCase{
+		// Fail to reassign the objects of a role that doesn't exist.
+		{pgcode.UndefinedObject, `REASSIGN OWNED BY "RoleThatDoesntExist" TO { Role }`},
+		// Fail to reassign objects to a role that doesn't exist.
+		{pgcode.UndefinedObject, `REASSIGN OWNED BY { Role } TO "RoleThatDoesntExist"`},
+		// Successful reassign of the objects of a role to another role.
+		{pgcode.SuccessfulCompletion, `REASSIGN OWNED BY { Role } TO { Role }`},
+		// Successful reassign of the objects of a role to the current user.
+		{pgcode.SuccessfulCompletion, `REASSIGN OWNED BY { Role } TO { CurrentUser }`},
+	}, template.FuncMap{
+		"Role": func() (string, error) {
+			role, err := PickOne(og.params.rng, roles)
+			return tree.NameString(role), err
+		},
+		"CurrentUser": func() string {
+			return tree.NameString(currentUser)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reassign := newOpStmt(stmt, codesWithConditions{
+		{code, true},
+	})
+	if code == pgcode.SuccessfulCompletion && stmt.OldRoles[0].Name != stmt.NewRole.Name {
+		if err := og.recordOwnerReassignments(ctx, tx, reassign, stmt.OldRoles[0].Name, stmt.NewRole.Name); err != nil {
+			return nil, err
+		}
+	}
+	return reassign, nil
+}
+
+// recordOwnerReassignments records the objects of the current database owned
+// by the given role, which the given statement reassigns to a new owner.
+func (og *operationGenerator) recordOwnerReassignments(
+	ctx context.Context, tx pgx.Tx, stmt *opStmt, oldOwner, newOwner string,
+) error {
+	type ownedObject struct {
+		ID      int64
+		Version int64
+	}
+
+	objects, err := Collect(ctx, og, tx, pgx.RowToStructByPos[ownedObject], With([]CTE{
+		{"descriptors", descJSONQuery},
+		{"objects", `SELECT id, (json_each(descriptor)).@2 AS object FROM descriptors`},
+	}, `SELECT id, (object->>'version')::INT8
+		FROM objects
+		WHERE object->'privileges'->>'ownerProto' = $1
+	`), oldOwner)
+	if err != nil {
+		return err
+	}
+	for _, o := range objects {
+		og.ownerReassignmentsInTxn = append(og.ownerReassignmentsInTxn, ownerReassignment{
+			stmt:    stmt,
+			id:      o.ID,
+			version: o.Version,
+			owner:   newOwner,
+		})
+	}
+	return nil
+}
+
+func (og *operationGenerator) dropOwnedBy(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	type roleOwnership struct {
+		Role          string
+		OwnsObjects   bool
+		HasDependents bool
+	}
+
+	// Query for the ownership of the roles in the pool, returning:
+	// * owns_objects - a bool indicating if the role owns any object in the
+	//   current database.
+	// * has_dependents - a bool indicating if any of these objects is depended
+	//   on by an object that the role does not own, including objects of an
+	//   owned schema.
+	query := With([]CTE{
+		{"descriptors", descJSONQuery},
+		{"objects", `SELECT
+				id,
+				schema_id,
+				(json_each(descriptor)).@2 AS object
+			FROM descriptors`},
+		{"owners", `SELECT *, object->'privileges'->>'ownerProto' AS owner FROM objects`},
+	}, `SELECT
+			r.name,
+			EXISTS(SELECT * FROM owners WHERE owner = r.name) AS owns_objects,
+			EXISTS(
+				SELECT *
+				FROM owners AS o, owners AS dep
+				WHERE o.owner = r.name
+				AND dep.owner IS DISTINCT FROM r.name
+				AND (
+					dep.schema_id = o.id
+					OR dep.id IN (SELECT (d->>'id')::INT8 FROM jsonb_array_elements(COALESCE(o.object->'dependedOnBy', '[]'::JSONB)) AS d)
+					OR dep.id IN (SELECT (fk->>'originTableId')::INT8 FROM jsonb_array_elements(COALESCE(o.object->'inboundFks', '[]'::JSONB)) AS fk)
+					OR dep.id IN (SELECT ref::INT8 FROM jsonb_array_elements_text(COALESCE(o.object->'referencingDescriptorIds', '[]'::JSONB)) AS ref)
+				)
+			) AS has_dependents
+		FROM unnest($1::STRING[]) AS r (name)
+	`)
+	roles, err := og.existingRoles(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	ownership, err := Collect(ctx, og, tx, pgx.RowToStructByPos[roleOwnership], query, roles)
+	if err != nil {
+		return nil, err
+	}
+	currentUser, err := Scan[string](ctx, og, tx, `SELECT current_user`)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, code, err := Generate[*tree.DropOwnedBy](og.params.rng, og.produceError(), []GenerationCase{
+		// Fail to drop the objects of the root user, which are required by the
+		// database system.
+		{pgcode.DependentObjectsStillExist, `DROP OWNED BY { RootUser }`},
+		// Fail to drop objects that other objects depend on.
+		{pgcode.DependentObjectsStillExist, `DROP OWNED BY { OwnerWithDependents }`},
+		// Fail to cascade the drop of owned objects, which is not supported.
+		{pgcode.FeatureNotSupported, `DROP OWNED BY { Owner } CASCADE`},
+		// Successful drop of the privileges of a role that owns no objects.
+		{pgcode.SuccessfulCompletion, `DROP OWNED BY { NonOwner } { DropBehavior }`},
+	}, template.FuncMap{
+		"RootUser": func() (string, error) {
+			if currentUser != username.RootUser {
+				return "", ErrCaseNotPossible
+			}
+			return username.RootUser, nil
+		},
+		"OwnerWithDependents": func() (string, error) {
+			role, err := PickOne(og.params.rng, util.Filter(ownership, func(r roleOwnership) bool {
+				return r.HasDependents
+			}))
+			return tree.NameString(role.Role), err
+		},
+		"Owner": func() (string, error) {
+			role, err := PickOne(og.params.rng, util.Filter(ownership, func(r roleOwnership) bool {
+				return r.OwnsObjects
+			}))
+			return tree.NameString(role.Role), err
+		},
+		"NonOwner": func() (string, error) {
+			role, err := PickOne(og.params.rng, util.Filter(ownership, func(r roleOwnership) bool {
+				return !r.OwnsObjects
+			}))
+			return tree.NameString(role.Role), err
+		},
+		"DropBehavior": func() string {
+			return tree.DropBehavior(og.randIntn(3)).String()
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// DROP OWNED BY is only implemented by the declarative schema changer.
+	return newOpStmt(stmt, codesWithConditions{
+		{code, og.useDeclarativeSchemaChanger},
+		{pgcode.FeatureNotSupported, !og.useDeclarativeSchemaChanger},
+	}), nil
+}
+
+// maxEnumValueLength is the maximum length of the enum values generated by
+// addTypeValue. CockroachDB does not limit the length of enum values, but
+// Postgres rejects values longer than 63 bytes, so we stay within that limit.
+const maxEnumValueLength = 63
+
+// newEnumValue returns a quoted enum value that is not used by any existing
+// enum, with a random length of at most maxEnumValueLength. Values that would
+// exceed it are rejected with ErrCaseNotPossible, so that Generate falls back
+// to another case instead of emitting them.
+func (og *operationGenerator) newEnumValue() (string, error) {
+	value := fmt.Sprintf("value_%s", og.newUniqueSeqNumSuffix())
+	if len(value) > maxEnumValueLength {
+		return "", errors.Wrapf(ErrCaseNotPossible, "enum value %q is longer than %d bytes", value, maxEnumValueLength)
+	}
+	const letters = "abcdefghijklmnopqrstuvwxyz"
+	if padding := maxEnumValueLength - len(value); padding > 0 {
+		value += util.RandString(og.params.rng, og.randIntn(padding+1), letters)
+	}
+	return fmt.Sprintf("'%s'", value), nil
+}
+
+func (og *operationGenerator) addTypeValue(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	// Query for all enum values returning:
+	// * name - the escaped fully qualified type name.
+	// * value - the escaped enum value.
+	// * dropping - a bool indicating if this value is being actively dropped.
+	query := With([]CTE{
+		{"descriptors", descJSONQuery},
+		{"enums", enumDescsQuery},
+		{"enum_members", enumMemberDescsQuery},
+	}, `SELECT
+				quote_ident(schema_id::REGNAMESPACE::TEXT) || '.' || quote_ident(name) AS name,
+				quote_literal(member->>'logicalRepresentation') AS value,
+				COALESCE(member->>'direction' = 'REMOVE', false) AS dropping
+			FROM enum_members
+			WHERE COALESCE(descriptor->>'kind', 'ENUM') = 'ENUM'
+	`)
+
+	enumMembers, err := Collect(ctx, og, tx, pgx.RowToMap, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// New values are not tracked anywhere but the catalog, so any value added
+	// here is visible to later alterTypeDropValue operations. Values placed
+	// next to an existing value are recorded, so that validate can check that
+	// they stay in the requested order.
+	var newValue string
+	var placement *enumPlacement
+	stmt, code, err := Generate[*tree.AlterType](og.params.rng, og.produceError(), []GenerationCase{
+		// Fail to add values to a type that doesn't exist.
+		{pgcode.UndefinedObject, `ALTER TYPE "EnumThatDoesntExist" ADD VALUE 'IrrelevantValue'`},
+		// Fail to add a value that already exists.
+		{pgcode.DuplicateObject, `{ with (EnumValue false) } ALTER TYPE { .name } ADD VALUE { .value } { end }`},
+		// Fail to add a value that is in the process of being dropped, even if
+		// IF NOT EXISTS is specified.
+		{pgcode.ObjectNotInPrerequisiteState, `{ with (EnumValue true) } ALTER TYPE { .name } ADD VALUE IF NOT EXISTS { .value } { end }`},
+		// Fail to add a value next to a value that doesn't exist.
+		{pgcode.InvalidParameterValue, `{ with (EnumValue false) } ALTER TYPE { .name } ADD VALUE { NewValue } BEFORE 'ValueThatDoesntExist' { end }`},
+		// Successful no-op addition of a value that already exists.
+		{pgcode.SuccessfulCompletion, `{ with (EnumValue false) } ALTER TYPE { .name } ADD VALUE IF NOT EXISTS { .value } { end }`},
+		// Successful addition of a new value, optionally placed next to an
+		// existing one.
+		{pgcode.SuccessfulCompletion, `{ with (EnumValue false) } ALTER TYPE { .name } ADD VALUE { NewValue } { Placement .name .value } { end }`},
+	}, template.FuncMap{
+		"EnumValue": func(dropping bool) (map[string]any, error) {
+			return PickOne(og.params.rng, util.Filter(enumMembers, func(enum map[string]any) bool {
+				return enum["dropping"].(bool) == dropping
+			}))
+		},
+		"NewValue": func() (string, error) {
+			var err error
+			newValue, err = og.newEnumValue()
+			return newValue, err
+		},
+		"Placement": func(name string, existing string) string {
+			switch og.randIntn(3) {
+			case 0:
+				placement = &enumPlacement{typeName: name, value: newValue, neighbor: existing, before: true}
+				return "BEFORE " + existing
+			case 1:
+				placement = &enumPlacement{typeName: name, value: newValue, neighbor: existing}
+				return "AFTER " + existing
+			default:
+				return ""
+			}
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if placement != nil {
+		og.enumPlacementsInTxn = append(og.enumPlacementsInTxn, *placement)
+	}
+
+	return newOpStmt(stmt, codesWithConditions{
+		{code, true},
+	}), nil
+}
+
+func (og *operationGenerator) alterTypeRenameValue(
+	ctx context.Context, tx pgx.Tx,
+) (*opStmt, error) {
+	// Query for all enum values returning:
+	// * name - the escaped fully qualified type name.
+	// * value - the escaped enum value.
+	// * transitioning - a bool indicating if this value is being actively added
+	//   or dropped.
+	query := With([]CTE{
+		{"descriptors", descJSONQuery},
+		{"enums", enumDescsQuery},
+		{"enum_members", enumMemberDescsQuery},
+	}, `SELECT
+				quote_ident(schema_id::REGNAMESPACE::TEXT) || '.' || quote_ident(name) AS name,
+				quote_literal(member->>'logicalRepresentation') AS value,
+				COALESCE(member->>'direction', 'NONE') != 'NONE' AS transitioning
+			FROM enum_members
+			WHERE COALESCE(descriptor->>'kind', 'ENUM') = 'ENUM'
+	`)
+
+	enumMembers, err := Collect(ctx, og, tx, pgx.RowToMap, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Renaming a value only changes its logical representation. Stored data,
+	// as well as default expressions and views, refer to the physical
+	// representation, so renaming a value that is in use is expected to
+	// succeed.
+	stmt, code, err := Generate[*tree.AlterType](og.params.rng, og.produceError(), []GenerationCase{
+		// Fail to rename values of a type that doesn't exist.
+		{pgcode.UndefinedObject, `ALTER TYPE "EnumThatDoesntExist" RENAME VALUE 'IrrelevantValue' TO 'OtherIrrelevantValue'`},
+		// Fail to rename a value that doesn't exist. Unlike Postgres, this is
+		// reported as an invalid parameter rather than an undefined object.
+		{pgcode.InvalidParameterValue, `{ with (EnumValue false) } ALTER TYPE { .name } RENAME VALUE 'ValueThatDoesntExist' TO { NewValue } { end }`},
+		// Fail to rename a value to the name of another value of the same type,
+		// regardless of the state of either value.
+		{pgcode.DuplicateObject, `{ with EnumValuePair } ALTER TYPE { .name } RENAME VALUE { .value } TO { .other } { end }`},
+		// Fail to rename a value that is in the process of being added or
+		// dropped.
+		{pgcode.ObjectNotInPrerequisiteState, `{ with (EnumValue true) } ALTER TYPE { .name } RENAME VALUE { .value } TO { NewValue } { end }`},
+		// Successful rename of an enum value.
+		{pgcode.SuccessfulCompletion, `{ with (EnumValue false) } ALTER TYPE { .name } RENAME VALUE { .value } TO { NewValue } { end }`},
+	}, template.FuncMap{
+		"EnumValue": func(transitioning bool) (map[string]any, error) {
+			return PickOne(og.params.rng, util.Filter(enumMembers, func(enum map[string]any) bool {
+				return enum["transitioning"].(bool) == transitioning
+			}))
+		},
+		"EnumValuePair": func() (map[string]any, error) {
+			member, err := PickOne(og.params.rng, util.Filter(enumMembers, func(enum map[string]any) bool {
+				return slices.ContainsFunc(enumMembers, func(other map[string]any) bool {
+					return other["name"] == enum["name"] && other["value"] != enum["value"]
+				})
+			}))
+			if err != nil {
+				return nil, err
+			}
+			other, err := PickOne(og.params.rng, util.Filter(enumMembers, func(enum map[string]any) bool {
+				return enum["name"] == member["name"] && enum["value"] != member["value"]
+			}))
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{
+				"name":  member["name"],
+				"value": member["value"],
+				"other": other["value"],
+			}, nil
+		},
+		"NewValue": og.newEnumValue,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newOpStmt(stmt, codesWithConditions{
+		{code, true},
+	}), nil
+}
+
+func (og *operationGenerator) alterTypeDropValue(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	// Query for all enum values returning:
+	// * name - the escaped fully qualified type name.
+	// * value - the escaped enum value.
+	// * droppping - a bool indicating if this value is being actively dropped.
+	//   has_references - a bool indicating if this *type* is referenced by other
+	//   descriptors.
+	query := With([]CTE{
+		{"descriptors", descJSONQuery},
+		{"enums", enumDescsQuery},
+		{"enum_members", enumMemberDescsQuery},
+	}, `SELECT
+				quote_ident(schema_id::REGNAMESPACE::TEXT) || '.' || quote_ident(name) AS name,
+				quote_literal(member->>'logicalRepresentation') AS value,
+				COALESCE(member->>'direction' = 'REMOVE', false) AS dropping,
+				COALESCE(json_array_length(descriptor->'referencingDescriptorIds') > 0, false) AS has_references
+			FROM enum_members
+	`)
+
+	enumMembers, err := Collect(ctx, og, tx, pgx.RowToMap, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sometimes drop a value from a type that is referenced by a table. The
+	// value is only validated to be unused once the transaction commits, which
+	// is guaranteed to fail if a row stores it. Expressions are only known to
+	// refer to the type, not to which of its values.
+	referencedMembers := util.Filter(enumMembers, func(enum map[string]any) bool {
+		return enum["has_references"].(bool) && !enum["dropping"].(bool)
+	})
+	if len(referencedMembers) > 0 && og.randIntn(2) == 0 {
+		member := referencedMembers[og.randIntn(len(referencedMembers))]
+		typeName, value := member["name"].(string), member["value"].(string)
+		used, err := og.enumValueUsed(ctx, tx, typeName, value)
+		if err != nil {
+			return nil, err
+		}
+		usedInExpressions, err := og.enumTypeUsedInExpressions(ctx, tx, typeName)
+		if err != nil {
+			return nil, err
+		}
+		if used {
+			og.candidateExpectedCommitErrors.add(pgcode.DependentObjectsStillExist)
+		}
+		og.potentialCommitErrors.addAll(codesWithConditions{
+			{pgcode.DependentObjectsStillExist, !used && usedInExpressions},
+		})
+
+		stmt := makeOpStmt(OpStmtDDL)
+		stmt.sql = fmt.Sprintf(`ALTER TYPE %s DROP VALUE %s`, typeName, value)
+		return stmt, nil
+	}
+
+	// TODO(chrisseto): We're currently missing cases around enum members being
+	// referenced as it's quite difficult to tell if an individual member is
+	// referenced. Unreferenced members can be dropped but referenced members may
+	// not. Beyond the case above, we skip over all enums where the type itself is
+	// being referenced.
+
+	stmt, code, err := Generate[*tree.AlterType](og.params.rng, og.produceError(), []GenerationCase{
+		// Fail to drop values from a type that doesn't exist.
+		{pgcode.UndefinedObject, `ALTER TYPE "EnumThatDoesntExist" DROP VALUE 'IrrelevantValue'`},
+		// Fail to drop a value that is in the process of being dropped.
+		{pgcode.ObjectNotInPrerequisiteState, `{ with (EnumValue true false) } ALTER TYPE { .name } DROP VALUE { .value } { end }`},
+		// Fail to drop values that don't exist.
+		{pgcode.UndefinedObject, `{ with (EnumValue false false) } ALTER TYPE { .name } DROP VALUE 'ValueThatDoesntExist' { end }`},
+		// Successful drop of an enum value.
+		{pgcode.SuccessfulCompletion, `{ with (EnumValue false false) } ALTER TYPE { .name } DROP VALUE { .value } { end }`},
+	}, template.FuncMap{
+		"EnumValue": func(dropping, referenced bool) (map[string]any, error) {
+			return PickOne(og.params.rng, util.Filter(enumMembers, func(enum map[string]any) bool {
+				return enum["has_references"].(bool) == referenced && enum["dropping"].(bool) == dropping
+			}))
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newOpStmt(stmt, codesWithConditions{
+		{code, true},
+	}), nil
+}
+
+func (og *operationGenerator) renameColumn(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
+	if err != nil {
+		return nil, err
+	}
+
+	srcTableExists, err := og.tableExists(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if !srcTableExists {
+		return makeOpStmtForSingleError(OpStmtDDL,
+			fmt.Sprintf(`ALTER TABLE %s RENAME COLUMN "IrrelevantColumnName" TO "OtherIrrelevantName"`,
+				tableName),
+			pgcode.UndefinedTable), nil
+	}
+	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	srcColumnName, err := og.randColumn(ctx, tx, *tableName, og.pctExisting(true))
+	if err != nil {
+		return nil, err
+	}
+
+	destColumnName, err := og.randColumn(ctx, tx, *tableName, og.pctExisting(false))
+	if err != nil {
+		return nil, err
+	}
+
+	srcColumnExists, err := og.columnExistsOnTable(ctx, tx, tableName, srcColumnName)
+	if err != nil {
+		return nil, err
+	}
+	destColumnExists, err := og.columnExistsOnTable(ctx, tx, tableName, destColumnName)
+	if err != nil {
+		return nil, err
+	}
+	columnIsDependedOn, err := og.columnIsDependedOn(ctx, tx, tableName, srcColumnName)
+	if err != nil {
+		return nil, err
+	}
+	columnIsTTLExpireAfterColumn, err := og.columnIsTTLExpireAfterColumn(ctx, tx, tableName, srcColumnName)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := makeOpStmt(OpStmtDDL)
+	stmt.expectedExecErrors.addAll(codesWithConditions{
+		{pgcode.UndefinedColumn, !srcColumnExists},
+		{pgcode.DuplicateColumn, destColumnExists && srcColumnName != destColumnName},
+		{pgcode.DependentObjectsStillExist, columnIsDependedOn},
+		{pgcode.InvalidTableDefinition, columnIsTTLExpireAfterColumn},
+	})
+
+	stmt.sql = fmt.Sprintf(`ALTER TABLE %s RENAME COLUMN "%s" TO "%s"`,
+		tableName, srcColumnName, destColumnName)
+	return stmt, nil
+}
+
+// renameConstraint renames a constraint of a table. Constraints backed by an
+// index, i.e. the primary key and UNIQUE constraints, share their name with
+// that index, so renaming them renames the index too and the new name must
+// not be taken by any other index of the table.
+func (og *operationGenerator) renameConstraint(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	type tableConstraint struct {
+		Table       string
+		Name        string
+		Type        string
+		IndexBacked bool
+		SchemaName  string
+		TableName   string
+		RawName     string
+		// UsedByView is set for constraints backed by an index that a view
+		// refers to explicitly.
+		UsedByView bool
+	}
+	constraints, err := Collect(ctx, og, tx, pgx.RowToStructByPos[tableConstraint], `
+		SELECT con.conrelid::REGCLASS::STRING,
+		       quote_ident(con.conname),
+		       con.contype::STRING,
+		       con.conindid != 0,
+		       ns.nspname,
+		       cls.relname,
+		       con.conname,
+		       EXISTS (
+		        SELECT *
+		          FROM crdb_internal.forward_dependencies AS fd
+		          JOIN crdb_internal.table_indexes AS ti ON ti.descriptor_id = fd.descriptor_id
+		                                                 AND ti.index_id = fd.dependedonby_index_id
+		         WHERE fd.descriptor_id = con.conrelid
+		           AND fd.dependedonby_type = 'view'
+		           AND ti.index_name = con.conname
+		       )
+		  FROM pg_catalog.pg_constraint AS con
+		  JOIN pg_catalog.pg_class AS cls ON cls.oid = con.conrelid
+		  JOIN pg_catalog.pg_namespace AS ns ON ns.oid = cls.relnamespace
+		 WHERE cls.relname LIKE 'table%'
+	`)
+	if err != nil {
+		return nil, og.checkAndAdjustForUnknownSchemaErrors(err)
+	}
+	// Query for the names of every index that doesn't back a constraint.
+	indexes, err := Collect(ctx, og, tx, pgx.RowToMap, `
+		SELECT ti.descriptor_id::REGCLASS::STRING AS table_name,
+		       quote_ident(ti.index_name) AS index_name
+		  FROM crdb_internal.table_indexes AS ti
+		 WHERE ti.descriptor_name LIKE 'table%'
+		   AND NOT EXISTS (
+		        SELECT *
+		          FROM pg_catalog.pg_constraint AS con
+		         WHERE con.conrelid = ti.descriptor_id
+		           AND con.conname = ti.index_name
+		       )
+	`)
+	if err != nil {
+		return nil, og.checkAndAdjustForUnknownSchemaErrors(err)
+	}
+
+	byTable := map[string][]tableConstraint{}
+	for _, c := range constraints {
+		byTable[c.Table] = append(byTable[c.Table], c)
+	}
+	indexesByTable := map[string][]string{}
+	for _, idx := range indexes {
+		tableName := idx["table_name"].(string)
+		indexesByTable[tableName] = append(indexesByTable[tableName], idx["index_name"].(string))
+	}
+
+	// picked is the constraint the generated statement renames, if any.
+	var picked *tableConstraint
+	pick := func(constraints []tableConstraint) (tableConstraint, error) {
+		c, err := PickOne(og.params.rng, constraints)
+		if err == nil {
+			picked = &c
+		}
+		return c, err
+	}
+
+	stmt, code, err := Generate[*tree.AlterTable](og.params.rng, og.produceError(), []GenerationCase{
+		// Fail to rename a constraint of a table that doesn't exist.
+		{pgcode.UndefinedTable, `ALTER TABLE "TableThatDoesntExist" RENAME CONSTRAINT "IrrelevantConstraintName" TO "IrrelevantName"`},
+		// Fail to rename a constraint that doesn't exist.
+		{pgcode.UndefinedObject, `{ with Constraint } ALTER TABLE { .Table } RENAME CONSTRAINT "ConstraintThatDoesntExist" TO { UniqueName } { end }`},
+		// Fail to rename a constraint to the name of another constraint of the
+		// same table.
+		{pgcode.DuplicateObject, `{ with ConstraintWithSibling } ALTER TABLE { .Table } RENAME CONSTRAINT { .Name } TO { Sibling . } { end }`},
+		// Fail to rename a constraint backed by an index to the name of another
+		// index of the same table.
+		{pgcode.DuplicateRelation, `{ with IndexBackedConstraintWithIndexSibling } ALTER TABLE { .Table } RENAME CONSTRAINT { .Name } TO { IndexSibling . } { end }`},
+		// Successful no-op rename of a constraint to its own name.
+		{pgcode.SuccessfulCompletion, `{ with Constraint } ALTER TABLE { .Table } RENAME CONSTRAINT { .Name } TO { .Name } { end }`},
+		// Successful rename of a constraint.
+		{pgcode.SuccessfulCompletion, `{ with Constraint } ALTER TABLE { .Table } RENAME CONSTRAINT { .Name } TO { UniqueName } { end }`},
+		// Successful rename of a primary key, which renames the primary index.
+		{pgcode.SuccessfulCompletion, `{ with PrimaryKey } ALTER TABLE { .Table } RENAME CONSTRAINT { .Name } TO { UniqueName } { end }`},
+	}, template.FuncMap{
+		"Constraint": func() (tableConstraint, error) {
+			return pick(constraints)
+		},
+		"PrimaryKey": func() (tableConstraint, error) {
+			return pick(util.Filter(constraints, func(c tableConstraint) bool {
+				return c.Type == "p"
+			}))
+		},
+		"ConstraintWithSibling": func() (tableConstraint, error) {
+			return pick(util.Filter(constraints, func(c tableConstraint) bool {
+				return len(byTable[c.Table]) > 1
+			}))
+		},
+		"IndexBackedConstraintWithIndexSibling": func() (tableConstraint, error) {
+			return pick(util.Filter(constraints, func(c tableConstraint) bool {
+				return c.IndexBacked && len(indexesByTable[c.Table]) > 0
+			}))
+		},
+		"Sibling": func(c tableConstraint) (string, error) {
+			sibling, err := PickOne(og.params.rng, util.Filter(byTable[c.Table], func(other tableConstraint) bool {
+				return other.Name != c.Name
+			}))
+			return sibling.Name, err
+		},
+		"IndexSibling": func(c tableConstraint) (string, error) {
+			return PickOne(og.params.rng, indexesByTable[c.Table])
+		},
+		"UniqueName": func() *tree.Name {
+			name := tree.Name(fmt.Sprintf("constraint_%s", og.newUniqueSeqNumSuffix()))
+			return &name
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	opStmt := newOpStmt(stmt, codesWithConditions{
+		{code, true},
+	})
+	if picked != nil {
+		// Constraints cannot be renamed while they are being added or dropped,
+		// and an index backing a constraint cannot be renamed while views
+		// refer to it explicitly.
+		tableName := tree.MakeTableNameFromPrefix(tree.ObjectNamePrefix{
+			SchemaName:     tree.Name(picked.SchemaName),
+			ExplicitSchema: true,
+		}, tree.Name(picked.TableName))
+		inMutation, err := og.constraintInMutation(ctx, tx, &tableName, picked.RawName)
+		if err != nil {
+			return nil, err
+		}
+		opStmt.potentialExecErrors.addAll(codesWithConditions{
+			{pgcode.ObjectNotInPrerequisiteState, inMutation},
+			{pgcode.FeatureNotSupported, inMutation},
+			{pgcode.DependentObjectsStillExist, picked.UsedByView},
+		})
+	}
+	return opStmt, nil
+}
+
+func (og *operationGenerator) renameIndex(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
+	if err != nil {
+		return nil, err
+	}
+
+	srcTableExists, err := og.tableExists(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if !srcTableExists {
+		return makeOpStmtForSingleError(OpStmtDDL,
+			fmt.Sprintf(`ALTER INDEX %s@"IrrelevantConstraintName" RENAME TO "OtherConstraintName"`,
+				tableName),
+			pgcode.UndefinedTable), nil
+	}
+	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	srcIndexName, err := og.randIndex(ctx, tx, *tableName, og.pctExisting(true))
+	if err != nil {
+		return nil, err
+	}
+
+	destIndexName, err := og.randIndex(ctx, tx, *tableName, og.pctExisting(false))
+	if err != nil {
+		return nil, err
+	}
+
+	srcIndexExists, err := og.indexExists(ctx, tx, tableName, srcIndexName)
+	if err != nil {
+		return nil, err
+	}
+	destIndexExists, err := og.indexExists(ctx, tx, tableName, destIndexName)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := makeOpStmt(OpStmtDDL)
+	stmt.expectedExecErrors.addAll(codesWithConditions{
+		{code: pgcode.UndefinedObject, condition: !srcIndexExists},
+		{code: pgcode.DuplicateRelation, condition: destIndexExists && srcIndexName != destIndexName},
+	})
+
+	stmt.sql = fmt.Sprintf(`ALTER INDEX %s@"%s" RENAME TO "%s"`,
+		tableName, srcIndexName, destIndexName)
+	return stmt, nil
+}
+
+// alterIndexPartitionBy partitions an index by LIST or RANGE on a prefix of
+// its key columns, or removes its partitioning with PARTITION BY NOTHING.
+func (og *operationGenerator) alterIndexPartitionBy(
+	ctx context.Context, tx pgx.Tx,
+) (*opStmt, error) {
+	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
+	if err != nil {
+		return nil, err
+	}
+	tableExists, err := og.tableExists(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if !tableExists {
+		return makeOpStmtForSingleError(OpStmtDDL,
+			fmt.Sprintf(`ALTER INDEX %s@"IrrelevantIndexName" PARTITION BY NOTHING`, tableName),
+			pgcode.UndefinedTable), nil
+	}
+	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if og.randIntn(100) >= og.pctExisting(true) {
+		return makeOpStmtForSingleError(OpStmtDDL,
+			fmt.Sprintf(`ALTER INDEX %s@"IrrelevantIndexName" PARTITION BY NOTHING`, tableName),
+			pgcode.UndefinedObject), nil
+	}
+
+	type partitionableIndex struct {
+		Name       string
+		KeyColumns []string
+		Inverted   bool
+		Sharded    bool
+		// ImplicitlyPartitioned is set if the index is partitioned on columns
+		// that are not part of its definition, like the region column of a
+		// REGIONAL BY ROW table.
+		ImplicitlyPartitioned bool
+		// TablePartitioning is set if the partitioning of the indexes is
+		// controlled by the table, which is either multi-region or has PARTITION
+		// ALL BY.
+		TablePartitioning bool
+	}
+	indexes, err := Collect(ctx, og, tx, pgx.RowToStructByPos[partitionableIndex], `
+WITH tab AS (
+             SELECT crdb_internal.pb_to_json('desc', descriptor)->'table' AS t
+               FROM system.descriptor
+              WHERE id = $1::REGCLASS
+         )
+SELECT quote_ident(idx->>'name'),
+       ARRAY(
+        SELECT quote_ident(col)
+          FROM jsonb_array_elements_text(idx->'keyColumnNames') WITH ORDINALITY AS k (col, ord)
+      ORDER BY ord
+       ),
+       COALESCE(idx->>'type', 'FORWARD') != 'FORWARD',
+       COALESCE((idx->'sharded'->>'isSharded')::BOOL, false),
+       COALESCE((idx->'partitioning'->>'numImplicitColumns')::INT8, 0) > 0,
+       t ? 'localityConfig' OR COALESCE((t->>'partitionAllBy')::BOOL, false)
+  FROM tab,
+       jsonb_array_elements(
+        jsonb_build_array(t->'primaryIndex') || COALESCE(t->'indexes', '[]'::JSONB)
+       ) AS idx
+`, tableName.String())
+	if err != nil {
+		return nil, err
+	}
+	index, err := PickOne(og.params.rng, indexes)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := makeOpStmt(OpStmtDDL)
+	stmt.expectedExecErrors.addAll(codesWithConditions{
+		{pgcode.FeatureNotSupported, index.TablePartitioning || index.Sharded || index.ImplicitlyPartitioned},
+	})
+
+	// The partition columns are a prefix of the key columns, which excludes
+	// the inverted column of inverted indexes. Values of user-defined types may
+	// be dropped concurrently, so the prefix ends before the first column of
+	// such a type.
+	columns, err := og.getTableColumns(ctx, tx, tableName, false /* shuffle */)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	columnTypes := make(map[string]*types.T, len(columns))
+	for _, col := range columns {
+		columnTypes[col.name] = col.typ
+	}
+	keyColumns := index.KeyColumns
+	if index.Inverted && len(keyColumns) > 0 {
+		keyColumns = keyColumns[:len(keyColumns)-1]
+	}
+	var partitionColumns []string
+	var partitionTypes []*types.T
+	for _, colName := range keyColumns {
+		typ, ok := columnTypes[colName]
+		if !ok || typ.UserDefined() || !colinfo.ColumnTypeIsIndexable(typ) {
+			break
+		}
+		partitionColumns = append(partitionColumns, colName)
+		partitionTypes = append(partitionTypes, typ)
+	}
+	if len(partitionColumns) == 0 || og.randIntn(4) == 0 {
+		stmt.sql = fmt.Sprintf(`ALTER INDEX %s@%s PARTITION BY NOTHING`, tableName, index.Name)
+		return stmt, nil
+	}
+	numColumns := 1 + og.randIntn(len(partitionColumns))
+	partitionColumns = partitionColumns[:numColumns]
+	partitionTypes = partitionTypes[:numColumns]
+
+	supportsPartitioning, err := og.clusterSupportsPartitioning(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	hasArrayColumn := false
+	for _, typ := range partitionTypes {
+		hasArrayColumn = hasArrayColumn || typ.Family() == types.ArrayFamily
+	}
+	// Occasionally reference a column in a partition bound, which must be a
+	// constant.
+	malformedBound := og.produceError()
+	stmt.expectedExecErrors.addAll(codesWithConditions{
+		{pgcode.CCLRequired, !supportsPartitioning},
+		{pgcode.FeatureNotSupported, hasArrayColumn},
+		{pgcode.Syntax, malformedBound},
+	})
+
+	// Every partition has a single bound, so that the values of partitions
+	// can't overlap.
+	bound := make([]string, numColumns)
+	for i, typ := range partitionTypes {
+		bound[i] = tree.AsStringWithFlags(
+			randgen.RandDatum(og.params.rng, typ, false /* nullOk */), tree.FmtParsable)
+	}
+	if malformedBound {
+		i := og.randIntn(numColumns)
+		bound[i] = partitionColumns[i]
+	}
+	repeat := func(s string) []string {
+		vals := make([]string, numColumns)
+		for i := range vals {
+			vals[i] = s
+		}
+		return vals
+	}
+	tuple := func(vals []string) string {
+		return "(" + strings.Join(vals, ", ") + ")"
+	}
+
+	var partitions []string
+	var method string
+	if og.randIntn(2) == 0 {
+		method = "LIST"
+		listValue := func(vals []string) string {
+			if len(vals) == 1 {
+				return vals[0]
+			}
+			return tuple(vals)
+		}
+		partitions = append(partitions, fmt.Sprintf(`PARTITION p1 VALUES IN (%s)`, listValue(bound)))
+		if og.randIntn(2) == 0 {
+			partitions = append(partitions,
+				fmt.Sprintf(`PARTITION p2 VALUES IN (%s)`, listValue(repeat("DEFAULT"))))
+		}
+	} else {
+		method = "RANGE"
+		partitions = append(partitions,
+			fmt.Sprintf(`PARTITION p1 VALUES FROM %s TO %s`, tuple(repeat("MINVALUE")), tuple(bound)),
+			fmt.Sprintf(`PARTITION p2 VALUES FROM %s TO %s`, tuple(bound), tuple(repeat("MAXVALUE"))),
+		)
+	}
+	stmt.sql = fmt.Sprintf(`ALTER INDEX %s@%s PARTITION BY %s %s (%s)`,
+		tableName, index.Name, method, tuple(partitionColumns), strings.Join(partitions, ", "))
+	return stmt, nil
+}
+
+func (og *operationGenerator) alterIndexVisible(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	// Query for all indexes of tables returning:
+	// * name - the escaped fully qualified index name.
+	// * primary - a bool indicating if this is the primary index of the table.
+	query := With([]CTE{
+		{"descriptors", descJSONQuery},
+		{"tables", `SELECT
+				quote_ident(schema_id::REGNAMESPACE::TEXT) || '.' || quote_ident(name) AS name,
+				descriptor->'table' AS tbl
+			FROM descriptors
+			WHERE name LIKE 'table%'
+			AND descriptor->'table' ? 'primaryIndex'
+			AND COALESCE(descriptor->'table'->>'state', 'PUBLIC') = 'PUBLIC'`},
+	}, `SELECT name || '@' || quote_ident(tbl->'primaryIndex'->>'name') AS name, true AS primary
+			FROM tables
+		UNION ALL
+		SELECT name || '@' || quote_ident(idx->>'name') AS name, false AS primary
+			FROM tables, jsonb_array_elements(COALESCE(tbl->'indexes', '[]'::JSONB)) AS idx
+	`)
+
+	indexes, err := Collect(ctx, og, tx, pgx.RowToMap, query)
+	if err != nil {
+		return nil, og.checkAndAdjustForUnknownSchemaErrors(err)
+	}
+
+	stmt, code, err := Generate[*tree.AlterIndexVisible](og.params.rng, og.produceError(), []GenerationCase{
+		// Fail to alter the visibility of an index that doesn't exist.
+		{pgcode.UndefinedObject, `ALTER INDEX { TableOf (Index true) }@"IndexThatDoesntExist" { Visibility }`},
+		// Successful no-op on an index that doesn't exist.
+		{pgcode.SuccessfulCompletion, `ALTER INDEX IF EXISTS { TableOf (Index true) }@"IndexThatDoesntExist" { Visibility }`},
+		// Fail to make the primary index invisible.
+		{pgcode.FeatureNotSupported, `ALTER INDEX { Index true } { Invisible }`},
+		// Successful change of the visibility of a secondary index.
+		{pgcode.SuccessfulCompletion, `ALTER INDEX { Index false } { Visibility }`},
+	}, template.FuncMap{
+		"Index": func(primary bool) (string, error) {
+			idx, err := PickOne(og.params.rng, util.Filter(indexes, func(idx map[string]any) bool {
+				return idx["primary"].(bool) == primary
+			}))
+			if err != nil {
+				return "", err
+			}
+			return idx["name"].(string), nil
+		},
+		"TableOf": func(indexName string) string {
+			return indexName[:strings.LastIndex(indexName, "@")]
+		},
+		"Visibility": func() string {
+			switch og.randIntn(3) {
+			case 0:
+				return "VISIBLE"
+			case 1:
+				return "NOT VISIBLE"
+			default:
+				return fmt.Sprintf("VISIBILITY %.2f", og.params.rng.Float64())
+			}
+		},
+		"Invisible": func() string {
+			if og.randIntn(2) == 0 {
+				return "NOT VISIBLE"
+			}
+			// A visibility of 1 leaves the index fully visible, which is
+			// allowed on the primary index.
+			return fmt.Sprintf("VISIBILITY %.2f", 0.99*og.params.rng.Float64())
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newOpStmt(stmt, codesWithConditions{
+		{code, true},
+	}), nil
+}
+
+func (og *operationGenerator) renameSequence(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	srcSequenceName, err := og.randSequence(ctx, tx, og.pctExisting(true), "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Decide whether or not to produce a 'cannot change schema of table with RENAME' error
+	desiredSchema := ""
+	if !og.produceError() {
+		desiredSchema = srcSequenceName.Schema()
+	}
+
+	destSequenceName, err := og.randSequence(ctx, tx, og.pctExisting(false), desiredSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	srcSequenceExists, err := og.sequenceExists(ctx, tx, srcSequenceName)
+	if err != nil {
+		return nil, err
+	}
+
+	destSchemaExists, err := og.schemaExists(ctx, tx, destSequenceName.Schema())
+	if err != nil {
+		return nil, err
+	}
+
+	destSequenceExists, err := og.sequenceExists(ctx, tx, destSequenceName)
+	if err != nil {
+		return nil, err
+	}
+
+	srcEqualsDest := srcSequenceName.String() == destSequenceName.String()
+	stmt := makeOpStmt(OpStmtDDL)
+	stmt.expectedExecErrors.addAll(codesWithConditions{
+		{code: pgcode.UndefinedTable, condition: !srcSequenceExists},
+		{code: pgcode.UndefinedSchema, condition: !destSchemaExists},
+		{code: pgcode.DuplicateRelation, condition: !srcEqualsDest && destSequenceExists},
+		{code: pgcode.InvalidName, condition: srcSequenceName.Schema() != destSequenceName.Schema()},
+	})
+
+	stmt.sql = fmt.Sprintf(`ALTER SEQUENCE %s RENAME TO %s`, srcSequenceName, destSequenceName)
+	return stmt, nil
+}
+
+func (og *operationGenerator) renameTable(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	srcTableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Decide whether or not to produce a 'cannot change schema of table with RENAME' error
+	desiredSchema := ""
+	if !og.produceError() {
+		desiredSchema = srcTableName.SchemaName.String()
+	}
+	destTableName, err := og.randTable(ctx, tx, og.pctExisting(false), desiredSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	srcTableExists, err := og.tableExists(ctx, tx, srcTableName)
+	if err != nil {
+		return nil, err
+	}
+	if srcTableExists {
+		err = og.tableHasPrimaryKeySwapActive(ctx, tx, srcTableName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	destSchemaExists, err := og.schemaExists(ctx, tx, destTableName.Schema())
+	if err != nil {
+		return nil, err
+	}
+
+	destTableExists, err := og.tableExists(ctx, tx, destTableName)
+	if err != nil {
+		return nil, err
+	}
+
+	srcTableHasDependencies, err := og.tableHasDependencies(ctx, tx, srcTableName)
+	if err != nil {
+		return nil, err
+	}
+
+	srcEqualsDest := destTableName.String() == srcTableName.String()
+	stmt := makeOpStmt(OpStmtDDL)
+	stmt.expectedExecErrors.addAll(codesWithConditions{
+		{code: pgcode.UndefinedTable, condition: !srcTableExists},
+		{code: pgcode.UndefinedSchema, condition: !destSchemaExists},
+		{code: pgcode.DuplicateRelation, condition: !srcEqualsDest && destTableExists},
+		{code: pgcode.DependentObjectsStillExist, condition: srcTableHasDependencies},
+		{code: pgcode.InvalidName, condition: srcTableName.Schema() != destTableName.Schema()},
+	})
+
+	stmt.sql = fmt.Sprintf(`ALTER TABLE %s RENAME TO %s`, srcTableName, destTableName)
+	return stmt, nil
+}
+
+func (og *operationGenerator) renameView(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	srcViewName, err := og.randView(ctx, tx, og.pctExisting(true), "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Decide whether or not to produce a 'cannot change schema of table with RENAME' error
+	desiredSchema := ""
+	if !og.produceError() {
+		desiredSchema = srcViewName.SchemaName.String()
+	}
+	destViewName, err := og.randView(ctx, tx, og.pctExisting(false), desiredSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	srcViewExists, err := og.viewExists(ctx, tx, srcViewName)
+	if err != nil {
+		return nil, err
+	}
+
+	destSchemaExists, err := og.schemaExists(ctx, tx, destViewName.Schema())
+	if err != nil {
+		return nil, err
+	}
+
+	destViewExists, err := og.viewExists(ctx, tx, destViewName)
+	if err != nil {
+		return nil, err
+	}
+
+	srcTableHasDependencies, err := og.tableHasDependencies(ctx, tx, srcViewName)
+	if err != nil {
+		return nil, err
+	}
+
+	srcEqualsDest := destViewName.String() == srcViewName.String()
+	stmt := makeOpStmt(OpStmtDDL)
+	stmt.expectedExecErrors.addAll(codesWithConditions{
+		{code: pgcode.UndefinedTable, condition: !srcViewExists},
+		{code: pgcode.UndefinedSchema, condition: !destSchemaExists},
+		{code: pgcode.DuplicateRelation, condition: !srcEqualsDest && destViewExists},
+		{code: pgcode.DependentObjectsStillExist, condition: srcTableHasDependencies},
+		{code: pgcode.InvalidName, condition: srcViewName.Schema() != destViewName.Schema()},
+	})
+
+	stmt.sql = fmt.Sprintf(`ALTER VIEW %s RENAME TO %s`, srcViewName, destViewName)
+	return stmt, nil
+}
+
+func (og *operationGenerator) setColumnDefault(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+
+	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
+	if err != nil {
+		return nil, err
+	}
+
+	tableExists, err := og.tableExists(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if !tableExists {
+		return makeOpStmtForSingleError(OpStmtDDL,
+			fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN IrrelevantColumnName SET DEFAULT "IrrelevantValue"`,
+				tableName),
+			pgcode.UndefinedTable), nil
+	}
+	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	columnForDefault, err := og.randColumnWithMeta(ctx, tx, *tableName, og.pctExisting(true))
+	if err != nil {
+		return nil, err
+	}
+	columnExists, err := og.columnExistsOnTable(ctx, tx, tableName, columnForDefault.name)
+	if err != nil {
+		return nil, err
+	}
+	if !columnExists {
+		return makeOpStmtForSingleError(OpStmtDDL,
+			fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN %s SET DEFAULT "IrrelevantValue"`,
+				tableName, columnForDefault.name),
+			pgcode.UndefinedColumn), nil
+	}
+	columnIsTTLExpireAfterColumn, err := og.columnIsTTLExpireAfterColumn(ctx, tx, tableName, columnForDefault.name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Integer columns may default to the next value of a sequence, which makes
+	// the table depend on the sequence until the default is dropped.
+	if columnForDefault.typ.Family() == types.IntFamily && og.randIntn(4) == 0 {
+		sequenceName, err := og.randSequence(ctx, tx, og.pctExisting(true), "")
+		if err != nil {
+			return nil, err
+		}
+		sequenceExists, err := og.sequenceExists(ctx, tx, sequenceName)
+		if err != nil {
+			return nil, err
+		}
+		stmt := makeOpStmt(OpStmtDDL)
+		stmt.expectedExecErrors.addAll(codesWithConditions{
+			{code: pgcode.UndefinedTable, condition: !sequenceExists},
+			{code: pgcode.InvalidTableDefinition, condition: columnForDefault.generated},
+			{code: pgcode.InvalidTableDefinition, condition: columnIsTTLExpireAfterColumn},
+		})
+		stmt.sql = fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN %s SET DEFAULT nextval(%s)`,
+			tableName, columnForDefault.name, tree.NewDString(sequenceName.String()))
+		return stmt, nil
+	}
+
+	datumTyp := columnForDefault.typ
+	// Optionally change the incorrect type to potentially create errors.
+	if og.produceError() {
+		newTypeName, newTyp, err := og.randType(ctx, tx, og.pctExisting(true))
+		if err != nil {
+			return nil, err
+		}
+		if newTyp == nil {
+			return makeOpStmtForSingleError(OpStmtDDL,
+				fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN %s SET DEFAULT 'IrrelevantValue':::%s`, tableName, columnForDefault.name, newTypeName.SQLString()),
+				pgcode.UndefinedColumn), nil
+		}
+		datumTyp = newTyp
+	}
+
+	defaultDatum := randgen.RandDatum(og.params.rng, datumTyp, columnForDefault.nullable)
+	stmt := makeOpStmt(OpStmtDDL)
+	if (!datumTyp.Equivalent(columnForDefault.typ)) && defaultDatum != tree.DNull {
+		stmt.expectedExecErrors.add(pgcode.DatatypeMismatch)
+	}
+
+	// Generated columns cannot have default values, and neither can the
+	// column maintained by ttl_expire_after be given another one.
+	if columnForDefault.generated || columnIsTTLExpireAfterColumn {
+		stmt.expectedExecErrors.add(pgcode.InvalidTableDefinition)
+	}
+
+	stmt.sql = fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s`, tableName, columnForDefault.name, tree.AsStringWithFlags(defaultDatum, tree.FmtParsable))
+	return stmt, nil
+}
+
+func (og *operationGenerator) setColumnNotNull(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
+	if err != nil {
+		return nil, err
+	}
+
+	tableExists, err := og.tableExists(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if !tableExists {
+		return makeOpStmtForSingleError(OpStmtDDL,
+			fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN IrrelevantColumnName SET NOT NULL`, tableName),
+			pgcode.UndefinedTable), nil
+	}
+	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	columnName, err := og.randColumn(ctx, tx, *tableName, og.pctExisting(true))
+	if err != nil {
+		return nil, err
+	}
+	columnExists, err := og.columnExistsOnTable(ctx, tx, tableName, columnName)
+	if err != nil {
+		return nil, err
+	}
+	constraintBeingAdded, err := og.columnNotNullConstraintInMutation(ctx, tx, tableName, columnName)
+	if err != nil {
+		return nil, err
+	}
+	columnIsTTLExpireAfterColumn, err := og.columnIsTTLExpireAfterColumn(ctx, tx, tableName, columnName)
+	if err != nil {
+		return nil, err
+	}
+	stmt := makeOpStmt(OpStmtDDL)
+	stmt.expectedExecErrors.addAll(codesWithConditions{
+		{pgcode.ObjectNotInPrerequisiteState, constraintBeingAdded},
+		{pgcode.InvalidTableDefinition, columnIsTTLExpireAfterColumn},
+	})
+
+	if !columnExists {
+		stmt.expectedExecErrors.add(pgcode.UndefinedColumn)
+	} else {
+		// If the column has null values, then a check violation will occur upon committing.
+		colContainsNull, err := og.columnContainsNull(ctx, tx, tableName, columnName)
+		if err != nil {
+			return nil, err
+		}
+		if colContainsNull {
+			og.candidateExpectedCommitErrors.add(pgcode.CheckViolation)
+		}
+	}
+
+	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	stmt.sql = fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN "%s" SET NOT NULL`, tableName, columnName)
+	return stmt, nil
+}
+
+// setColumnOnUpdate sets the ON UPDATE expression of a column to a constant,
+// which is assigned to the column whenever a row is updated without
+// changing it.
+func (og *operationGenerator) setColumnOnUpdate(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
+	if err != nil {
+		return nil, err
+	}
+	tableExists, err := og.tableExists(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if !tableExists {
+		return makeOpStmtForSingleError(OpStmtDDL,
+			fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN "IrrelevantColumnName" SET ON UPDATE 'IrrelevantValue'`,
+				tableName),
+			pgcode.UndefinedTable), nil
+	}
+	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	col, err := og.randColumnWithMeta(ctx, tx, *tableName, og.pctExisting(true))
+	if err != nil {
+		return nil, err
+	}
+	columnExists, err := og.columnExistsOnTable(ctx, tx, tableName, col.name)
+	if err != nil {
+		return nil, err
+	}
+	if !columnExists {
+		return makeOpStmtForSingleError(OpStmtDDL,
+			fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN "%s" SET ON UPDATE 'IrrelevantValue'`,
+				tableName, col.name),
+			pgcode.UndefinedColumn), nil
+	}
+	columnIsIdentity, err := og.columnIsIdentity(ctx, tx, tableName, col.name)
+	if err != nil {
+		return nil, err
+	}
+	columnIsTTLExpireAfterColumn, err := og.columnIsTTLExpireAfterColumn(ctx, tx, tableName, col.name)
+	if err != nil {
+		return nil, err
+	}
+	columnHasForeignKeyUpdateAction, err := og.columnHasForeignKeyUpdateAction(ctx, tx, tableName, col.name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Occasionally use a value of another type, which can't be assigned to
+	// the column.
+	datumTyp := col.typ
+	if og.produceError() {
+		_, newTyp, err := og.randType(ctx, tx, og.pctExisting(true))
+		if err != nil {
+			return nil, err
+		}
+		if newTyp != nil {
+			datumTyp = newTyp
+		}
+	}
+	datum := randgen.RandDatum(og.params.rng, datumTyp, col.nullable)
+
+	stmt := makeOpStmt(OpStmtDDL)
+	stmt.expectedExecErrors.addAll(codesWithConditions{
+		{pgcode.DatatypeMismatch, !datumTyp.Equivalent(col.typ) && datum != tree.DNull},
+		// The expressions of identity columns can't be changed.
+		{pgcode.Syntax, columnIsIdentity},
+		{pgcode.InvalidTableDefinition, columnIsTTLExpireAfterColumn},
+		// Computed columns can't have an ON UPDATE expression, and neither can
+		// columns changed by the ON UPDATE action of a foreign key.
+		{pgcode.InvalidTableDefinition, col.generated},
+		{pgcode.InvalidColumnDefinition, columnHasForeignKeyUpdateAction},
+	})
+	stmt.sql = fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN "%s" SET ON UPDATE %s`,
+		tableName, col.name, tree.AsStringWithFlags(datum, tree.FmtParsable))
+	return stmt, nil
+}
+
+func (og *operationGenerator) alterTableSetSchema(
+	ctx context.Context, tx pgx.Tx,
+) (*opStmt, error) {
+	type relation struct {
+		Kind          string
+		Schema        string
+		Name          string
+		HasDependents bool
+		// InSchemaChange is set for relations with a schema change in
+		// progress, which prevents them from being moved.
+		InSchemaChange bool
+	}
+	// Query for every table, view and sequence, and whether another object
+	// refers to it by name, which prevents it from being moved.
+	relations, err := Collect(ctx, og, tx, pgx.RowToStructByPos[relation], With([]CTE{
+		{"descriptors", descJSONQuery},
+	}, `SELECT
+				CASE
+					WHEN descriptor->'table' ? 'sequenceOpts' THEN 'SEQUENCE'
+					WHEN (descriptor->'table'->>'isMaterializedView')::BOOL THEN 'MATERIALIZED VIEW'
+					WHEN descriptor->'table' ? 'viewQuery' THEN 'VIEW'
+					ELSE 'TABLE'
+				END,
+				quote_ident(schema_id::REGNAMESPACE::TEXT),
+				quote_ident(name),
+				EXISTS(
+					SELECT *
+					FROM jsonb_array_elements(COALESCE(descriptor->'table'->'dependedOnBy', '[]'::JSONB)) AS dep
+					WHERE NOT COALESCE((dep->>'byId')::BOOL, false)
+				),
+				jsonb_array_length(COALESCE(descriptor->'table'->'mutations', '[]'::JSONB)) > 0
+					OR descriptor->'table' ? 'declarativeSchemaChangerState'
+			FROM descriptors
+			WHERE descriptor ? 'table'
+			AND COALESCE(descriptor->'table'->>'state', 'PUBLIC') = 'PUBLIC'
+	`))
+	if err != nil {
+		return nil, og.checkAndAdjustForUnknownSchemaErrors(err)
+	}
+	// Relations and types share a namespace, so either occupies a name within
+	// a schema.
+	occupiedNames, err := Collect(ctx, og, tx, pgx.RowToMap, With([]CTE{
+		{"descriptors", descJSONQuery},
+	}, `SELECT
+				quote_ident(schema_id::REGNAMESPACE::TEXT) AS schema,
+				quote_ident(name) AS name
+			FROM descriptors
+			WHERE descriptor ? 'table' OR descriptor ? 'type'
+	`))
+	if err != nil {
+		return nil, og.checkAndAdjustForUnknownSchemaErrors(err)
+	}
+	schemas, err := Collect(ctx, og, tx, pgx.RowTo[string], With([]CTE{
+		{"descriptors", descJSONQuery},
+	}, `SELECT quote_ident(name) FROM descriptors
+			WHERE descriptor ? 'schema'
+			AND COALESCE(descriptor->'schema'->>'state', 'PUBLIC') = 'PUBLIC'`))
+	if err != nil {
+		return nil, err
+	}
+
+	occupied := map[string]map[string]bool{}
+	for _, o := range occupiedNames {
+		schema, name := o["schema"].(string), o["name"].(string)
+		if occupied[schema] == nil {
+			occupied[schema] = map[string]bool{}
+		}
+		occupied[schema][name] = true
+	}
+	// destinations returns the schemas other than the relation's own which
+	// either do or do not already contain an object of the same name.
+	destinations := func(r relation, duplicate bool) []string {
+		return util.Filter(schemas, func(schema string) bool {
+			return schema != r.Schema && occupied[schema][r.Name] == duplicate
+		})
+	}
+	movable := util.Filter(relations, func(r relation) bool {
+		return !r.HasDependents
+	})
+	// picked is the relation the generated statement moves, if any.
+	var picked *relation
+	pick := func(relations []relation) (relation, error) {
+		r, err := PickOne(og.params.rng, relations)
+		if err == nil {
+			picked = &r
+		}
+		return r, err
+	}
+
+	stmt, code, err := Generate[*tree.AlterTableSetSchema](og.params.rng, og.produceError(), []GenerationCase{
+		// Fail to move a table that doesn't exist.
+		{pgcode.UndefinedTable, `ALTER TABLE "TableThatDoesntExist" SET SCHEMA { Schema }`},
+		// Successful no-op move of a table that doesn't exist.
+		{pgcode.SuccessfulCompletion, `ALTER TABLE IF EXISTS "TableThatDoesntExist" SET SCHEMA { Schema }`},
+		// Fail to move a table using the statement of a different kind of
+		// relation.
+		{pgcode.WrongObjectType, `{ with Table } ALTER SEQUENCE { .Schema }.{ .Name } SET SCHEMA { Schema } { end }`},
+		// Fail to move a relation to a schema that doesn't exist.
+		{pgcode.InvalidSchemaName, `{ with MovableRelation } ALTER { .Kind } { .Schema }.{ .Name } SET SCHEMA "SchemaThatDoesntExist" { end }`},
+		// Fail to move a relation to a schema that already contains an object
+		// of the same name.
+		{pgcode.DuplicateRelation, `{ with RelationWithDuplicate } ALTER { .Kind } { .Schema }.{ .Name } SET SCHEMA { Duplicate . } { end }`},
+		// Fail to move a relation that other objects refer to by name.
+		{pgcode.DependentObjectsStillExist, `{ with RelationWithDependents } ALTER { .Kind } { .Schema }.{ .Name } SET SCHEMA { Schema } { end }`},
+		// Successful move of a relation to another schema.
+		{pgcode.SuccessfulCompletion, `{ with RelationWithDestination } ALTER { .Kind } { .Schema }.{ .Name } SET SCHEMA { Destination . } { end }`},
+	}, template.FuncMap{
+		"Schema": func() (string, error) {
+			return PickOne(og.params.rng, schemas)
+		},
+		"Table": func() (relation, error) {
+			return pick(util.Filter(relations, func(r relation) bool {
+				return r.Kind == "TABLE"
+			}))
+		},
+		"MovableRelation": func() (relation, error) {
+			return pick(movable)
+		},
+		"RelationWithDuplicate": func() (relation, error) {
+			return pick(util.Filter(movable, func(r relation) bool {
+				return len(destinations(r, true /* duplicate */)) > 0
+			}))
+		},
+		"RelationWithDependents": func() (relation, error) {
+			return pick(util.Filter(relations, func(r relation) bool {
+				return r.HasDependents
+			}))
+		},
+		"RelationWithDestination": func() (relation, error) {
+			return pick(util.Filter(movable, func(r relation) bool {
+				return len(destinations(r, false /* duplicate */)) > 0
+			}))
+		},
+		"Duplicate": func(r relation) (string, error) {
+			return PickOne(og.params.rng, destinations(r, true /* duplicate */))
+		},
+		"Destination": func(r relation) (string, error) {
+			return PickOne(og.params.rng, destinations(r, false /* duplicate */))
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	opStmt := newOpStmt(stmt, codesWithConditions{
+		{code, true},
+	})
+	// The relation cannot be moved while another schema change on it is in
+	// progress.
+	opStmt.potentialExecErrors.addAll(codesWithConditions{
+		{pgcode.ObjectNotInPrerequisiteState, picked != nil && picked.InSchemaChange},
+	})
+	return opStmt, nil
+}
+
+// tableStorageParam is a storage parameter set and reset by
+// alterTableSetStorageParams and alterTableResetStorageParams.
+type tableStorageParam struct {
+	key string
+	// value returns a valid value of the parameter, and invalidValue, if set,
+	// a value that is rejected with an InvalidParameterValue error.
+	value        func(rng *rand.Rand) tree.Expr
+	invalidValue func(rng *rand.Rand) tree.Expr
+}
+
+// tableStorageParams are the storage parameters of tables churned by the
+// workload. ttl_expire_after and ttl_expiration_expression are left out,
+// since they add and drop columns rather than just changing the descriptor,
+// and so are the TTL parameters which are no longer used. The expiration
+// duration is set by alterTableEnableRowLevelTTL instead.
+var tableStorageParams = func() []tableStorageParam {
+	randBool := func(rng *rand.Rand) tree.Expr {
+		return tree.MakeDBool(rng.Intn(2) == 0)
+	}
+	randBatchSize := func(rng *rand.Rand) tree.Expr {
+		return tree.NewDInt(tree.DInt(1 + rng.Intn(1000)))
+	}
+	// Batch sizes must be positive.
+	invalidBatchSize := func(rng *rand.Rand) tree.Expr {
+		return tree.NewDInt(tree.DInt(-rng.Intn(100)))
+	}
+	return []tableStorageParam{
+		{key: "exclude_data_from_backup", value: randBool},
+		{
+			key: "fillfactor",
+			value: func(rng *rand.Rand) tree.Expr {
+				return tree.NewDInt(tree.DInt(rng.Intn(101)))
+			},
+			// The fill factor is a percentage.
+			invalidValue: func(rng *rand.Rand) tree.Expr {
+				return tree.NewDInt(tree.DInt(101 + rng.Intn(100)))
+			},
+		},
+		{key: catpb.AutoStatsEnabledTableSettingName, value: randBool},
+		{
+			key: catpb.AutoStatsMinStaleTableSettingName,
+			value: func(rng *rand.Rand) tree.Expr {
+				return tree.NewDInt(tree.DInt(rng.Intn(10000)))
+			},
+		},
+		{
+			key: catpb.AutoStatsFractionStaleTableSettingName,
+			value: func(rng *rand.Rand) tree.Expr {
+				return tree.NewDFloat(tree.DFloat(float64(rng.Intn(200)) / 100))
+			},
+		},
+		{key: "sql_stats_forecasts_enabled", value: randBool},
+		{key: "ttl_delete_batch_size", value: randBatchSize, invalidValue: invalidBatchSize},
+		{key: "ttl_label_metrics", value: randBool},
+		{key: "ttl_pause", value: randBool},
+		{key: "ttl_select_batch_size", value: randBatchSize, invalidValue: invalidBatchSize},
+	}
+}()
+
+// storageParamTable is a table whose storage parameters may be set or reset.
+type storageParamTable struct {
+	Schema string
+	Name   string
+	// Params are the keys of the storage parameters currently set on the table.
+	Params        []string
+	HasMutations  bool
+	HasInboundFKs bool
+	// HasExpireAfter is set if the row-level TTL of the table has an expiration
+	// duration, and HasExpirationColumn if the table has a column named
+	// crdb_internal_expiration, which the duration is maintained in.
+	HasExpireAfter      bool
+	HasExpirationColumn bool
+}
+
+func (t storageParamTable) tableName() tree.TableName {
+	return tree.MakeTableNameFromPrefix(tree.ObjectNamePrefix{
+		SchemaName:     tree.Name(t.Schema),
+		ExplicitSchema: true,
+	}, tree.Name(t.Name))
+}
+
+// hasTTL returns whether row-level TTL is enabled on the table.
+func (t storageParamTable) hasTTL() bool {
+	return slices.Contains(t.Params, "ttl")
+}
+
+// hasConflictingExpirationColumn returns whether the table has a
+// crdb_internal_expiration column of its own, which prevents an expiration
+// duration from being set.
+func (t storageParamTable) hasConflictingExpirationColumn() bool {
+	return t.HasExpirationColumn && !t.HasExpireAfter
+}
+
+// storageParamTables returns the tables of the current database along with
+// their storage parameters.
+func (og *operationGenerator) storageParamTables(
+	ctx context.Context, tx pgx.Tx,
+) ([]storageParamTable, error) {
+	return Collect(ctx, og, tx, pgx.RowToStructByPos[storageParamTable], With([]CTE{
+		{"descriptors", descJSONQuery},
+		{"tables", tableDescQuery},
+	}, `SELECT
+				schema_id::REGNAMESPACE::TEXT,
+				name,
+				COALESCE((
+					SELECT array_agg(split_part(opt, '=', 1))
+					FROM unnest((SELECT reloptions FROM pg_class WHERE oid = tables.id::OID)) AS opt
+				), ARRAY[]::STRING[]),
+				COALESCE(jsonb_array_length(descriptor->'table'->'mutations'), 0) > 0,
+				COALESCE(jsonb_array_length(descriptor->'table'->'inboundFks'), 0) > 0,
+				COALESCE(descriptor->'table'->'rowLevelTtl'->>'durationExpr', '') != '',
+				EXISTS(
+					SELECT *
+					FROM jsonb_array_elements(COALESCE(descriptor->'table'->'columns', '[]')) AS c
+					WHERE c->>'name' = $1
+				) OR EXISTS(
+					SELECT *
+					FROM jsonb_array_elements(COALESCE(descriptor->'table'->'mutations', '[]')) AS m
+					WHERE m->'column'->>'name' = $1
+				)
+			FROM tables
+			WHERE COALESCE(descriptor->'table'->>'state', 'PUBLIC') = 'PUBLIC'
+	`), catpb.TTLDefaultExpirationColumnName)
+}
+
+func (og *operationGenerator) alterTableSetStorageParams(
+	ctx context.Context, tx pgx.Tx,
+) (*opStmt, error) {
+	tables, err := og.storageParamTables(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 || og.randIntn(100) >= og.pctExisting(true) {
+		return makeOpStmtForSingleError(OpStmtDDL,
+			`ALTER TABLE "TableThatDoesntExist" SET (fillfactor = 100)`,
+			pgcode.UndefinedTable), nil
+	}
+	table, err := PickOne(og.params.rng, tables)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set up to three distinct parameters. When an error is requested, the
+	// first one is given an out of range value.
+	numParams := 1 + og.randIntn(3)
+	params := make([]tableStorageParam, 0, numParams)
+	invalid := og.produceError()
+	if invalid {
+		invalidParams := util.Filter(tableStorageParams, func(p tableStorageParam) bool {
+			return p.invalidValue != nil
+		})
+		invalidParam, err := PickOne(og.params.rng, invalidParams)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, invalidParam)
+	}
+	for _, i := range og.params.rng.Perm(len(tableStorageParams)) {
+		if len(params) == numParams {
+			break
+		}
+		if p := tableStorageParams[i]; len(params) == 0 || p.key != params[0].key {
+			params = append(params, p)
+		}
+	}
+
+	setsTTLParam := false
+	setsExcludeDataFromBackup := false
+	storageParams := make(tree.StorageParams, len(params))
+	for i, p := range params {
+		value := p.value(og.params.rng)
+		if invalid && i == 0 {
+			value = p.invalidValue(og.params.rng)
+		}
+		storageParams[i] = tree.StorageParam{Key: p.key, Value: value}
+		setsTTLParam = setsTTLParam || strings.HasPrefix(p.key, "ttl_")
+		setsExcludeDataFromBackup = setsExcludeDataFromBackup || p.key == "exclude_data_from_backup"
+	}
+
+	stmt := makeOpStmt(OpStmtDDL)
+	stmt.expectedExecErrors.addAll(codesWithConditions{
+		{pgcode.InvalidParameterValue, invalid},
+		// TTL parameters can't be changed while another schema change on the
+		// table is in progress.
+		{pgcode.FeatureNotSupported, setsTTLParam && table.HasMutations},
+		// Setting TTL parameters on a table without row-level TTL enables it
+		// without an expiration, which is rejected.
+		{pgcode.InvalidParameterValue, setsTTLParam && !table.hasTTL()},
+		// Data referenced by foreign keys can't be excluded from backups.
+		{pgcode.Uncategorized, setsExcludeDataFromBackup && table.HasInboundFKs},
+	})
+	tableName := table.tableName()
+	stmt.sql = tree.Serialize(&tree.AlterTable{
+		Table: tableName.ToUnresolvedObjectName(),
+		Cmds: tree.AlterTableCmds{
+			&tree.AlterTableSetStorageParams{StorageParams: storageParams},
+		},
+	})
+	return stmt, nil
+}
+
+func (og *operationGenerator) alterTableResetStorageParams(
+	ctx context.Context, tx pgx.Tx,
+) (*opStmt, error) {
+	tables, err := og.storageParamTables(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	// Only parameters that are currently set are reset.
+	resettable := func(t storageParamTable) []string {
+		return util.Filter(t.Params, func(key string) bool {
+			return slices.ContainsFunc(tableStorageParams, func(p tableStorageParam) bool {
+				return p.key == key
+			})
+		})
+	}
+	tables = util.Filter(tables, func(t storageParamTable) bool {
+		return len(resettable(t)) > 0
+	})
+	table, err := PickOne(og.params.rng, tables)
+	if errors.Is(err, ErrCaseNotPossible) {
+		return nil, pgx.ErrNoRows
+	} else if err != nil {
+		return nil, err
+	}
+	params, err := PickBetween(og.params.rng, 1, 3, resettable(table))
+	if err != nil {
+		return nil, err
+	}
+	// Parameters that don't exist can't be reset.
+	invalid := og.produceError()
+	if invalid {
+		params = append(params, "no_such_param")
+	}
+
+	resetsTTLParam := slices.ContainsFunc(params, func(key string) bool {
+		return strings.HasPrefix(key, "ttl_")
+	})
+	stmt := makeOpStmt(OpStmtDDL)
+	stmt.expectedExecErrors.addAll(codesWithConditions{
+		{pgcode.InvalidParameterValue, invalid},
+		{pgcode.FeatureNotSupported, resetsTTLParam && table.HasMutations},
+	})
+	tableName := table.tableName()
+	stmt.sql = tree.Serialize(&tree.AlterTable{
+		Table: tableName.ToUnresolvedObjectName(),
+		Cmds: tree.AlterTableCmds{
+			&tree.AlterTableResetStorageParams{Params: params},
+		},
+	})
+	return stmt, nil
+}
+
+// alterTableEnableRowLevelTTL enables row-level TTL on a table by setting its
+// expiration duration, which adds a crdb_internal_expiration column to the
+// table, or changes the duration if it is already set.
+func (og *operationGenerator) alterTableEnableRowLevelTTL(
+	ctx context.Context, tx pgx.Tx,
+) (*opStmt, error) {
+	tables, err := og.storageParamTables(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	// Tables with a crdb_internal_expiration column of their own are left
+	// alone, since the column would conflict with the one added by TTL.
+	tables = util.Filter(tables, func(t storageParamTable) bool {
+		return !t.hasConflictingExpirationColumn()
+	})
+	table, err := PickOne(og.params.rng, tables)
+	if errors.Is(err, ErrCaseNotPossible) {
+		return nil, pgx.ErrNoRows
+	} else if err != nil {
+		return nil, err
+	}
+	tableName := table.tableName()
+	if err := og.tableHasPrimaryKeySwapActive(ctx, tx, &tableName); err != nil {
+		return nil, err
+	}
+
+	var expireAfter string
+	if og.produceError() {
+		expireAfter, err = PickOne(og.params.rng, []string{
+			fmt.Sprintf("-%d days", 1+og.randIntn(30)),
+			"forever",
+		})
+	} else {
+		expireAfter, err = PickOne(og.params.rng, []string{
+			fmt.Sprintf("%d minutes", 1+og.randIntn(60)),
+			fmt.Sprintf("%d hours", 1+og.randIntn(24)),
+			fmt.Sprintf("%d days", 1+og.randIntn(30)),
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return og.alterTableEnableRowLevelTTLStmt(table, expireAfter), nil
+}
+
+// alterTableEnableRowLevelTTLStmt returns a statement setting the
+// ttl_expire_after of the table, along with the errors it is expected to
+// return.
+func (og *operationGenerator) alterTableEnableRowLevelTTLStmt(
+	table storageParamTable, expireAfter string,
+) *opStmt {
+	d, err := tree.ParseDInterval(duration.IntervalStyle_POSTGRES, expireAfter)
+	validExpireAfter := err == nil && d.Duration.Compare(duration.MakeDuration(0, 0, 0)) >= 0
+
+	stmt := makeOpStmt(OpStmtDDL)
+	stmt.expectedExecErrors.addAll(codesWithConditions{
+		// The duration must be a non-negative interval.
+		{pgcode.InvalidParameterValue, !validExpireAfter},
+		{pgcode.FeatureNotSupported, table.HasMutations},
+		{pgcode.InvalidTableDefinition, table.hasConflictingExpirationColumn()},
+	})
+	tableName := table.tableName()
+	stmt.sql = tree.Serialize(&tree.AlterTable{
+		Table: tableName.ToUnresolvedObjectName(),
+		Cmds: tree.AlterTableCmds{
+			&tree.AlterTableSetStorageParams{StorageParams: tree.StorageParams{
+				{Key: "ttl_expire_after", Value: tree.NewStrVal(expireAfter)},
+			}},
+		},
+	})
+	return stmt
+}
+
+// alterTableDisableRowLevelTTL removes row-level TTL from a table which has it.
+func (og *operationGenerator) alterTableDisableRowLevelTTL(
+	ctx context.Context, tx pgx.Tx,
+) (*opStmt, error) {
+	tables, err := og.storageParamTables(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	tables = util.Filter(tables, storageParamTable.hasTTL)
+	table, err := PickOne(og.params.rng, tables)
+	if errors.Is(err, ErrCaseNotPossible) {
+		return nil, pgx.ErrNoRows
+	} else if err != nil {
+		return nil, err
+	}
+	tableName := table.tableName()
+	if err := og.tableHasPrimaryKeySwapActive(ctx, tx, &tableName); err != nil {
+		return nil, err
+	}
+	return og.alterTableDisableRowLevelTTLStmt(ctx, tx, table)
+}
+
+// alterTableDisableRowLevelTTLStmt returns a statement resetting the row-level
+// TTL of the table, along with the errors it is expected to return.
+func (og *operationGenerator) alterTableDisableRowLevelTTLStmt(
+	ctx context.Context, tx pgx.Tx, table storageParamTable,
+) (*opStmt, error) {
+	tableName := table.tableName()
+	stmt := makeOpStmt(OpStmtDDL)
+	// The crdb_internal_expiration column maintained by the expiration
+	// duration is dropped along with it, which fails like dropping the column
+	// would.
+	if table.HasExpireAfter {
+		columnName := catpb.TTLDefaultExpirationColumnName
+		colIsPrimaryKey, err := og.colIsPrimaryKey(ctx, tx, &tableName, columnName)
+		if err != nil {
+			return nil, err
+		}
+		columnIsDependedOn, err := og.columnIsDependedOn(ctx, tx, &tableName, columnName)
+		if err != nil {
+			return nil, err
+		}
+		columnIsInDroppingIndex, err := og.columnIsInDroppingIndex(ctx, tx, &tableName, columnName)
+		if err != nil {
+			return nil, err
+		}
+		columnIsInPartialIndexPredicate, err := og.columnIsInPartialIndexPredicate(ctx, tx, &tableName, columnName)
+		if err != nil {
+			return nil, err
+		}
+		hasAlterPKSchemaChange, err := og.tableHasOngoingAlterPKSchemaChanges(ctx, tx, &tableName)
+		if err != nil {
+			return nil, err
+		}
+		stmt.expectedExecErrors.addAll(codesWithConditions{
+			{pgcode.ObjectNotInPrerequisiteState, columnIsInDroppingIndex},
+			{pgcode.InvalidColumnReference, colIsPrimaryKey},
+			{pgcode.InvalidColumnReference, columnIsInPartialIndexPredicate},
+			{pgcode.DependentObjectsStillExist, columnIsDependedOn},
+			{pgcode.FeatureNotSupported, hasAlterPKSchemaChange},
+		})
+	}
+	// Some of the schema changes in progress on the table, like changes of
+	// column types, can't be followed by the removal of TTL.
+	stmt.potentialExecErrors.addAll(codesWithConditions{
+		{pgcode.FeatureNotSupported, table.HasMutations},
+	})
+	stmt.sql = tree.Serialize(&tree.AlterTable{
+		Table: tableName.ToUnresolvedObjectName(),
+		Cmds: tree.AlterTableCmds{
+			&tree.AlterTableResetStorageParams{Params: []string{"ttl"}},
+		},
+	})
+	return stmt, nil
+}
+
+// alterTableAlterRowLevelTTL changes the schedule of the row-level TTL job of
+// a table, or the number of rows it selects at a time.
+func (og *operationGenerator) alterTableAlterRowLevelTTL(
+	ctx context.Context, tx pgx.Tx,
+) (*opStmt, error) {
+	tables, err := og.storageParamTables(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	// Setting these parameters on a table without row-level TTL is an error,
+	// so such tables are only picked when an error is requested.
+	invalid := og.produceError()
+	if !invalid {
+		tables = util.Filter(tables, storageParamTable.hasTTL)
+	}
+	table, err := PickOne(og.params.rng, tables)
+	if errors.Is(err, ErrCaseNotPossible) {
+		return nil, pgx.ErrNoRows
+	} else if err != nil {
+		return nil, err
+	}
+	tableName := table.tableName()
+	if err := og.tableHasPrimaryKeySwapActive(ctx, tx, &tableName); err != nil {
+		return nil, err
+	}
+
+	var params tree.StorageParams
+	if og.randIntn(2) == 0 {
+		crons := []string{"@hourly", "@daily", "@weekly", "*/15 * * * *", "0 4 * * *"}
+		if invalid {
+			crons = []string{"@sometimes", "61 * * * *", "* * *"}
+		}
+		jobCron, err := PickOne(og.params.rng, crons)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, tree.StorageParam{Key: "ttl_job_cron", Value: tree.NewStrVal(jobCron)})
+	}
+	if len(params) == 0 || og.randIntn(2) == 0 {
+		batchSize := 1 + og.randIntn(1000)
+		if invalid {
+			batchSize = -og.randIntn(100)
+		}
+		params = append(params, tree.StorageParam{
+			Key:   "ttl_select_batch_size",
+			Value: tree.NewDInt(tree.DInt(batchSize)),
+		})
+	}
+	return og.alterTableAlterRowLevelTTLStmt(table, params), nil
+}
+
+// alterTableAlterRowLevelTTLStmt returns a statement setting the ttl_job_cron
+// and ttl_select_batch_size parameters of the table, along with the errors it
+// is expected to return.
+func (og *operationGenerator) alterTableAlterRowLevelTTLStmt(
+	table storageParamTable, params tree.StorageParams,
+) *opStmt {
+	invalidValue := false
+	for _, p := range params {
+		switch p.Key {
+		case "ttl_job_cron":
+			_, err := cron.ParseStandard(p.Value.(*tree.StrVal).RawString())
+			invalidValue = invalidValue || err != nil
+		case "ttl_select_batch_size":
+			invalidValue = invalidValue || *p.Value.(*tree.DInt) <= 0
+		}
+	}
+
+	stmt := makeOpStmt(OpStmtDDL)
+	stmt.expectedExecErrors.addAll(codesWithConditions{
+		{pgcode.InvalidParameterValue, invalidValue},
+		{pgcode.FeatureNotSupported, table.HasMutations},
+		// Setting TTL parameters on a table without row-level TTL enables it
+		// without an expiration, which is rejected.
+		{pgcode.InvalidParameterValue, !table.hasTTL()},
+	})
+	tableName := table.tableName()
+	stmt.sql = tree.Serialize(&tree.AlterTable{
+		Table: tableName.ToUnresolvedObjectName(),
+		Cmds: tree.AlterTableCmds{
+			&tree.AlterTableSetStorageParams{StorageParams: params},
+		},
+	})
+	return stmt
+}
+
+func (og *operationGenerator) setColumnType(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
+	if err != nil {
+		return nil, err
+	}
+
+	tableExists, err := og.tableExists(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if !tableExists {
+		return makeOpStmtForSingleError(OpStmtDDL,
+			fmt.Sprintf(`%s ALTER TABLE %s ALTER COLUMN IrrelevantColumnName SET DATA TYPE IrrelevantDataType`, setAlterColumnTypeGeneralString, tableName),
+			pgcode.UndefinedTable), nil
+	}
+	err = og.tableHasPrimaryKeySwapActive(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	columnForTypeChange, err := og.randColumnWithMeta(ctx, tx, *tableName, og.pctExisting(true))
+	if err != nil {
+		return nil, err
+	}
+
+	columnExists, err := og.columnExistsOnTable(ctx, tx, tableName, columnForTypeChange.name)
+	if err != nil {
+		return nil, err
+	}
+	if !columnExists {
+		return makeOpStmtForSingleError(OpStmtDDL,
+			fmt.Sprintf(`%s ALTER TABLE %s ALTER COLUMN "%s" SET DATA TYPE IrrelevantTypeName`,
+				setAlterColumnTypeGeneralString, tableName, columnForTypeChange.name),
+			pgcode.UndefinedColumn), nil
+	}
+
+	newTypeName, newType, err := og.randType(ctx, tx, og.pctExisting(true))
+	if err != nil {
+		return nil, err
+	}
+
+	// Use an explicit cast for conversions that require one, unless an error
+	// is requested.
+	useUsingExpr := false
+	if newType != nil {
+		_, needsUsing := classifyColumnTypeConversion(columnForTypeChange.typ, newType)
+		useUsingExpr = needsUsing && !og.produceError()
+	}
+	return og.setColumnTypeStmt(ctx, tx, tableName, &columnForTypeChange, newTypeName, newType, useUsingExpr)
+}
+
+// setAlterColumnTypeGeneralString allows ALTER COLUMN TYPE to be used for
+// conversions that rewrite the existing data.
+const setAlterColumnTypeGeneralString = `SET enable_experimental_alter_column_type_general = true;`
+
+// columnTypeConversion classifies a change of the type of a column.
+type columnTypeConversion int
+
+const (
+	// columnTypeConversionTrivial conversions only change the metadata of the
+	// column, since all existing values fit the new type as they are.
+	columnTypeConversionTrivial columnTypeConversion = iota
+	// columnTypeConversionValidated conversions need the existing values to be
+	// validated against the new type, or rewritten into it.
+	columnTypeConversionValidated
+	// columnTypeConversionDisallowed conversions are rejected, as there is no
+	// cast between the types.
+	columnTypeConversionDisallowed
+)
+
+// classifyColumnTypeConversion classifies the conversion of a column from one
+// type to another, following the conversion matrix of the schemachange
+// package. It also returns whether the conversion requires a USING
+// expression, because values can't be assigned to the new type without an
+// explicit cast.
+func classifyColumnTypeConversion(
+	from, to *types.T,
+) (conversion columnTypeConversion, needsUsing bool) {
+	kind, err := schemachange.ClassifyConversion(context.Background(), from, to)
+	switch {
+	case err != nil || kind == schemachange.ColumnConversionImpossible ||
+		kind == schemachange.ColumnConversionDangerous:
+		return columnTypeConversionDisallowed, false
+	case kind == schemachange.ColumnConversionTrivial:
+		return columnTypeConversionTrivial, false
+	default:
+		return columnTypeConversionValidated, !cast.ValidCast(from, to, cast.ContextAssignment)
+	}
+}
+
+// setColumnTypeStmt returns a statement changing the type of an existing
+// column, optionally converting the values with a USING expression, and sets
+// up the errors it is expected to produce. A nil newType stands for a type
+// that doesn't exist.
+func (og *operationGenerator) setColumnTypeStmt(
+	ctx context.Context,
+	tx pgx.Tx,
+	tableName *tree.TableName,
+	col *column,
+	newTypeName *tree.TypeName,
+	newType *types.T,
+	useUsingExpr bool,
+) (*opStmt, error) {
+	// Only views, computed columns and the row-level TTL block changing the
+	// type of a column outright. Foreign keys, indexes and constraints only
+	// block conversions that rewrite the data.
+	columnIsDependedOnByView, err := og.columnIsDependedOnByView(ctx, tx, tableName, col.name)
+	if err != nil {
+		return nil, err
+	}
+	columnIsInComputedExpression, err := og.columnIsInComputedExpression(ctx, tx, tableName, col.name)
+	if err != nil {
+		return nil, err
+	}
+	columnIsInTTLExpirationExpression, err := og.columnIsInTTLExpirationExpression(ctx, tx, tableName, col.name)
+	if err != nil {
+		return nil, err
+	}
+	columnIsInPartialIndexPredicate, err := og.columnIsInPartialIndexPredicate(ctx, tx, tableName, col.name)
+	if err != nil {
+		return nil, err
+	}
+	columnIsIdentity, err := og.columnIsIdentity(ctx, tx, tableName, col.name)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := makeOpStmt(OpStmtDDL)
+	stmt.expectedExecErrors.addAll(codesWithConditions{
+		{code: pgcode.UndefinedObject, condition: newType == nil},
+		{code: pgcode.DependentObjectsStillExist, condition: columnIsDependedOnByView},
+		{code: pgcode.DependentObjectsStillExist, condition: columnIsInComputedExpression},
+		{code: pgcode.InvalidTableDefinition, condition: columnIsInTTLExpirationExpression},
+		{code: pgcode.InvalidTableDefinition, condition: col.name == catpb.TTLDefaultExpirationColumnName},
+	})
+	// Only the declarative schema changer rejects changing the type of a
+	// column referenced by the predicate of a partial index.
+	stmt.potentialExecErrors.addAll(codesWithConditions{
+		{code: pgcode.InvalidColumnReference, condition: columnIsInPartialIndexPredicate},
+	})
+
+	using := ""
+	if newType != nil {
+		conversion, _ := classifyColumnTypeConversion(col.typ, newType)
+		if useUsingExpr {
+			using = fmt.Sprintf(" USING %s::%s", tree.NameString(col.name), newTypeName.SQLString())
+			// Conversions with a USING expression always rewrite the data.
+			conversion = columnTypeConversionValidated
+		}
+		assignable := cast.ValidCast(col.typ, newType, cast.ContextAssignment)
+		stmt.expectedExecErrors.addAll(codesWithConditions{
+			{code: pgcode.CannotCoerce, condition: conversion == columnTypeConversionDisallowed},
+			// Conversions that validate or rewrite the existing data are only
+			// supported by the legacy schema changer outside of explicit
+			// transactions, which are always used by this workload. The
+			// declarative schema changer doesn't support them at all.
+			{code: pgcode.FeatureNotSupported, condition: conversion == columnTypeConversionValidated},
+			{code: pgcode.InvalidParameterValue, condition: columnIsIdentity && newType.Family() != types.IntFamily},
+		})
+		// Unless a USING expression is given, the column, as well as its
+		// DEFAULT and ON UPDATE expressions, must be assignable to the new type.
+		// Which of these errors is returned depends on the order of the checks
+		// in either schema changer.
+		stmt.potentialExecErrors.addAll(codesWithConditions{
+			{code: pgcode.DatatypeMismatch, condition: !assignable},
+		})
+	}
+
+	stmt.sql = fmt.Sprintf(`%s ALTER TABLE %s ALTER COLUMN %s SET DATA TYPE %s%s`,
+		setAlterColumnTypeGeneralString, tableName, tree.NameString(col.name), newTypeName.SQLString(), using)
+	return stmt, nil
+}
+
+func (og *operationGenerator) alterTableAlterPrimaryKey(
+	ctx context.Context, tx pgx.Tx,
+) (*opStmt, error) {
+	// Primary Keys are backed by a unique index, therefore we can only use
+	// columns that are of an indexable type. This information is only available
+	// via the colinfo package (not SQL) and is subject to change across
+	// versions. To eliminate the chance of flakes, rely on this allow list to do
+	// the filtering. As this list is static and non-exhaustive, we're trading a
+	// bit of coverage for stability. It may be worth while to add index-ability
+	// information to `SHOW COLUMNS` or an internal SQL function in the future.
+	indexableFamilies := []string{
+		"DecimalFamily",
+		"IntFamily",
+		"StringFamily",
+		"UuidFamily",
+	}
+
+	q := With([]CTE{
+		{"descriptors", descJSONQuery},
+		{"tables", tableDescQuery},
+		{"columns", colDescQuery},
+	}, `
+		SELECT
+			json_array_length(table_descriptor->'mutation') > 0 AS table_undergoing_schema_change,
+			quote_ident(schema_id::REGNAMESPACE::TEXT) || '.' || quote_ident(table_name) AS table_name,
+			quote_ident("column"->>'name') AS column_name,
+			COALESCE(("column"->'nullable')::bool, false) AS is_nullable,
+			("column"->>'computedExpr' = '') AS is_computed,
+			(("column"->'type'->>'family') = ANY($1)) AS is_indexable,
+			(NOT EXISTS(
+				SELECT *
+				FROM crdb_internal.table_indexes
+				JOIN crdb_internal.index_columns USING (descriptor_id)
+				WHERE table_indexes.is_inverted
+				AND table_indexes.descriptor_id = columns.table_id
+				AND index_columns.column_id = (columns."column"->'id')::int8
+			)) AS is_in_inverted_index,
+			(EXISTS(
+				SELECT *
+				FROM crdb_internal.table_indexes
+				JOIN crdb_internal.index_columns USING (descriptor_id)
+				WHERE table_indexes.is_unique
+				AND table_indexes.descriptor_id = columns.table_id
+				AND index_columns.column_id = (columns."column"->'id')::int8
+			)) AS is_unique
+		FROM columns
+		WHERE NOT (
+			COALESCE(("column"->'hidden')::bool, false)
+			OR  COALESCE(("column"->'inaccessible')::bool, false)
+		)`)
+
+	columns, err := Collect(ctx, og, tx, pgx.RowToMap, q, indexableFamilies)
+	if err != nil {
+		return nil, err
+	}
+
+	// Group columns by table for convenience in our templates. This could have
+	// been done within SQL but I didn't want to fight with unmarshalling nested
+	// JSON fields.
+	byTable := map[string][]map[string]any{}
+	for i, col := range columns {
+		byTable[col["table_name"].(string)] = append(
+			byTable[col["table_name"].(string)],
+			columns[i],
+		)
+	}
+
+	tables := make([]map[string]any, 0, len(byTable))
+	for table_name, grouped := range byTable {
+		tables = append(tables, map[string]any{
+			"table_name":                     table_name,
+			"table_undergoing_schema_change": grouped[0]["table_undergoing_schema_change"].(bool),
+			"columns":                        grouped,
+		})
+	}
+
+	// Our big query can only check if there are any unique indexes on columns.
+	// We'll also want to check if any columns happen to be unique rather than
+	// being constrained to uniqueness.
+	fillIsUnique := func(table map[string]any) error {
+		// Cache uniqueness checks. They're expensive and might run twice in some
+		// weird cases with the Generate helper.
+		if _, ok := table["unique_check"]; ok {
+			return nil
+		}
+
+		table["unique_check"] = true
+
+		var b strings.Builder
+		fmt.Fprintf(&b, `SELECT * FROM VALUES (`)
+		for _, column := range table["columns"].([]map[string]any) {
+			// If this column is already known to be unique, don't bother checking
+			// it. This should only happen if there's a unique constraint on the
+			// column.
+			if column["is_unique"].(bool) {
+				fmt.Fprintf(&b, `(SELECT true)`)
+			} else {
+				fmt.Fprintf(&b, `(SELECT EXISTS(SELECT 1 FROM %s GROUP BY %s HAVING count(*) > 1))`, table["table_name"], column["column_name"])
+			}
+		}
+		fmt.Fprintf(&b, `)`)
+
+		results, err := Collect(ctx, og, tx, pgx.RowTo[bool], b.String())
+		if err != nil {
+			return err
+		}
+
+		for i, unique := range results {
+			table["columns"].([]map[string]any)[i]["is_unique"] = unique
+		}
+
+		return nil
+	}
+
+	stmt, code, err := Generate[*tree.AlterTable](og.params.rng, og.produceError(), []GenerationCase{
+		// IF EXISTS should noop if the table doesn't exist.
+		{pgcode.SuccessfulCompletion, `ALTER TABLE IF EXISTS "NonExistentTable" ALTER PRIMARY KEY USING COLUMNS ("IrrelevantColumn")`},
+		// Targeting a table that doesn't exist should error out.
+		{pgcode.UndefinedTable, `ALTER TABLE "NonExistentTable" ALTER PRIMARY KEY USING COLUMNS ("IrrelevantColumn")`},
+		// Targeting a column that doesn't exist should error out.
+		{pgcode.UndefinedColumn, `{ with TableNotUnderGoingSchemaChange } ALTER TABLE { .table_name } ALTER PRIMARY KEY USING COLUMNS ("NonExistentColumn") { end }`},
+		// NonUniqueColumns can't be used as PKs.
+		{pgcode.UniqueViolation, `{ with TableNotUnderGoingSchemaChange } ALTER TABLE { .table_name } ALTER PRIMARY KEY USING COLUMNS ({ . | Unique false | Nullable false | Generated false | Indexable true | InInvertedIndex false | Columns }) { end }`},
+		// NullableColumns can't be used as PKs.
+		{pgcode.InvalidSchemaDefinition, `{ with TableNotUnderGoingSchemaChange } ALTER TABLE { .table_name } ALTER PRIMARY KEY USING COLUMNS ({ . | Unique true | Nullable true | Generated false | Indexable true | InInvertedIndex false | Columns }) { end }`},
+		// UnindexableColumns can't be used as PKs.
+		{pgcode.InvalidSchemaDefinition, `{ with TableNotUnderGoingSchemaChange } ALTER TABLE { .table_name } ALTER PRIMARY KEY USING COLUMNS ({ . | Unique true | Nullable false | Generated false | Indexable false | InInvertedIndex false | Columns }) { end }`},
+		// TODO(sql-foundations): Columns that have an inverted index can't be used
+		// as a primary key. This check isn't 100% correct because we only care
+		// about the final column in an inverted index and we're checking if
+		// columns are in an inverted index at all.
+		// {pgcode.InvalidSchemaDefinition, `{ with TableNotUnderGoingSchemaChange } ALTER TABLE { .table_name } ALTER PRIMARY KEY USING COLUMNS ({ . | Unique true | Nullable false | Generated false | Indexable true | InInvertedIndex true | Columns }) { end }`},
+		// Tables undergoing a schema change may not have their PK changed.
+		// TODO(sql-foundations): This case doesn't cause errors as expected.
+		// {pgcode.Code{}, `ALTER TABLE {TableUnderGoingSchemaChange} ALTER PRIMARY KEY USING COLUMNS ({UniqueNotNullableColumns})`},
+		// Successful cases.
+		{pgcode.SuccessfulCompletion, `{ with TableNotUnderGoingSchemaChange } ALTER TABLE { .table_name } ALTER PRIMARY KEY USING COLUMNS ({ . | Unique true | Nullable false | Generated false | Indexable true | InInvertedIndex false | Columns }) { end }`},
+		{pgcode.SuccessfulCompletion, `{ with TableNotUnderGoingSchemaChange } ALTER TABLE { .table_name } ALTER PRIMARY KEY USING COLUMNS ({ . | Unique true | Nullable false | Generated false | Indexable true | InInvertedIndex false | Columns }) USING HASH { end }`},
+		// TODO(sql-foundations): Add support for hash parameters and storage parameters.
+	}, template.FuncMap{
+		"TableNotUnderGoingSchemaChange": func() (map[string]any, error) {
+			tbls := util.Filter(tables, func(table map[string]any) bool {
+				return !table["table_undergoing_schema_change"].(bool)
+			})
+			return PickOne(og.params.rng, tbls)
+		},
+		"TableUnderGoingSchemaChange": func() (map[string]any, error) {
+			tbls := util.Filter(tables, func(table map[string]any) bool {
+				return table["table_undergoing_schema_change"].(bool)
+			})
+			return PickOne(og.params.rng, tbls)
+		},
+		"Columns": func(table map[string]any) (string, error) {
+			selected, err := PickAtLeast(og.params.rng, 1, table["columns"].([]map[string]any))
+			names := util.Map(selected, func(col map[string]any) string { return col["column_name"].(string) })
+			return strings.Join(names, ", "), err
+		},
+		"Nullable": func(nullable bool, table map[string]any) map[string]any {
+			table["columns"] = util.Filter(table["columns"].([]map[string]any), func(col map[string]any) bool {
+				return col["is_nullable"].(bool) == nullable
+			})
+			return table
+		},
+		"Unique": func(unique bool, table map[string]any) (map[string]any, error) {
+			if err := fillIsUnique(table); err != nil {
+				return nil, err
+			}
+			table["columns"] = util.Filter(table["columns"].([]map[string]any), func(col map[string]any) bool {
+				return col["is_unique"].(bool) == unique
+			})
+			return table, nil
+		},
+		"Generated": func(unique bool, table map[string]any) map[string]any {
+			table["columns"] = util.Filter(table["columns"].([]map[string]any), func(col map[string]any) bool {
+				return col["is_unique"].(bool) == unique
+			})
+			return table
+		},
+		"Indexable": func(indexable bool, table map[string]any) map[string]any {
+			table["columns"] = util.Filter(table["columns"].([]map[string]any), func(col map[string]any) bool {
+				return col["is_indexable"].(bool) == indexable
+			})
+			return table
+		},
+		"InInvertedIndex": func(inIndex bool, table map[string]any) map[string]any {
+			table["columns"] = util.Filter(table["columns"].([]map[string]any), func(col map[string]any) bool {
+				return col["is_in_inverted_index"].(bool) == inIndex
+			})
+			return table
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The primary key of a table can't be changed while row-level TTL is being
+	// added to or removed from it.
+	tableName := stmt.Table.ToTableName()
+	tableExists, err := og.tableExists(ctx, tx, &tableName)
+	if err != nil {
+		return nil, err
+	}
+	if tableExists {
+		hasTTLChange, err := og.tableHasOngoingRowLevelTTLChange(ctx, tx, &tableName)
+		if err != nil {
+			return nil, err
+		}
+		if hasTTLChange {
+			return nil, ErrSchemaChangesDisallowedDueToTTLChange
+		}
+	}
+
+	return newOpStmt(stmt, codesWithConditions{
+		{code, true},
+	}), nil
+}
+
+func (og *operationGenerator) survive(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	dbRegions, err := og.getDatabaseRegionNames(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Choose a survival mode based on a coin toss.
+	needsAtLeastThreeRegions := false
+	survive := "ZONE FAILURE"
+	if coinToss := og.randIntn(2); coinToss == 1 {
+		survive = "REGION FAILURE"
+		needsAtLeastThreeRegions = true
+	}
+
+	// Expect 0 regions to fail, and less than three regions to fail
+	// if there are < 3 regions.
+	stmt := makeOpStmt(OpStmtDDL)
+	stmt.expectedExecErrors.addAll(codesWithConditions{
+		{
+			code:      pgcode.InvalidName,
+			condition: len(dbRegions) == 0,
+		},
+		{
+			code:      pgcode.InvalidParameterValue,
+			condition: needsAtLeastThreeRegions && len(dbRegions) < 3,
+		},
+	})
+
+	dbName, err := og.getDatabase(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	stmt.sql = fmt.Sprintf(`ALTER DATABASE %s SURVIVE %s`, dbName, survive)
+	return stmt, nil
+}
+
+func (og *operationGenerator) alterDatabaseOwner(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	// Only databases created by this workload are transferred, so that the
+	// database of the connection keeps its owner.
+	databases, err := Collect(ctx, og, tx, pgx.RowTo[string], `
+SELECT quote_ident(database_name)
+  FROM [SHOW DATABASES]
+ WHERE database_name LIKE 'database\_%'`)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := og.existingRoles(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, code, err := Generate[*tree.AlterDatabaseOwner](og.params.rng, og.produceError(), []GenerationCase{
+		// Fail to alter the owner of a database that doesn't exist.
+		{pgcode.InvalidCatalogName, `ALTER DATABASE "DatabaseThatDoesntExist" OWNER TO CURRENT_USER`},
+		// Fail to transfer a database to a role that doesn't exist.
+		{pgcode.UndefinedObject, `ALTER DATABASE { Database } OWNER TO "RoleThatDoesntExist"`},
+		// Successful transfer of a database to a role of the pool.
+		{pgcode.SuccessfulCompletion, `ALTER DATABASE { Database } OWNER TO { Role }`},
+		// Successful transfer of a database back to the current user.
+		{pgcode.SuccessfulCompletion, `ALTER DATABASE { Database } OWNER TO CURRENT_USER`},
+	}, template.FuncMap{
+		"Database": func() (string, error) {
+			return PickOne(og.params.rng, databases)
+		},
+		"Role": func() (string, error) {
+			role, err := PickOne(og.params.rng, roles)
+			return tree.NameString(role), err
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newOpStmt(stmt, codesWithConditions{
+		{code, true},
+	}), nil
+}
+
+func (og *operationGenerator) alterDatabasePlacement(
+	ctx context.Context, tx pgx.Tx,
+) (*opStmt, error) {
+	// Placement policies can only be set once they are enabled for the session.
+	if _, err := tx.Exec(ctx, `SET enable_multiregion_placement_policy = 'on'`); err != nil {
+		return nil, err
+	}
+
+	database, err := og.getDatabase(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	isMultiRegion, err := og.databaseIsMultiRegion(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	survivesRegionFailure, err := og.databaseSurvivesRegionFailure(ctx, tx, database)
+	if err != nil {
+		return nil, err
+	}
+
+	placement := tree.DataPlacementDefault
+	if og.randIntn(2) == 0 {
+		placement = tree.DataPlacementRestricted
+	}
+
+	// Only multi-region databases have a placement policy, and a restricted
+	// placement is incompatible with surviving region failures.
+	stmt := makeOpStmt(OpStmtDDL)
+	stmt.expectedExecErrors.addAll(codesWithConditions{
+		{pgcode.InvalidName, !isMultiRegion},
+		{pgcode.InvalidParameterValue, isMultiRegion && placement == tree.DataPlacementRestricted && survivesRegionFailure},
+	})
+	stmt.sql = tree.Serialize(&tree.AlterDatabasePlacement{
+		Name:      tree.Name(database),
+		Placement: placement,
+	})
+	return stmt, nil
+}
+
+func (og *operationGenerator) commentOn(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	var onType string
+
+	// COMMENT ON TYPE is only implemented in the declarative schema changer in v24.2.
+	commentOnTypeNotSupported, err := isClusterVersionLessThan(
+		ctx,
+		tx,
+		clusterversion.V24_2.Version())
+	if err != nil {
+		return nil, err
+	}
+	if og.useDeclarativeSchemaChanger && !commentOnTypeNotSupported {
+		onType = `UNION ALL
+	SELECT 'TYPE ' || quote_ident(schema) || '.' || quote_ident(name) FROM [SHOW TYPES]`
+	}
+
+	q := With([]CTE{
+		{"descriptors", descJSONQuery},
+		{"tables", tableDescQuery},
+		{"columns", `SELECT schema_id::REGNAMESPACE::TEXT as schema_name, name AS table_name, jsonb_array_elements(descriptor->'table'->'columns') AS column FROM tables`},
+		{"indexes", `SELECT schema_id::REGNAMESPACE::TEXT as schema_name, name AS table_name, jsonb_array_elements(descriptor->'table'->'indexes') AS index FROM tables`},
+		{"constraints", `SELECT schema_id::REGNAMESPACE::TEXT as schema_name, name AS table_name, jsonb_array_elements(descriptor->'table'->'checks') AS constraint FROM tables`},
+	}, fmt.Sprintf(`
+	SELECT 'DATABASE ' || quote_ident(database_name) FROM [SHOW DATABASES] WHERE database_name = current_database() OR database_name LIKE 'database\_%%'
+		UNION ALL
+	SELECT 'SCHEMA ' || quote_ident(schema_name) FROM [SHOW SCHEMAS] WHERE owner != 'node'
+		UNION ALL
+	SELECT 'TABLE ' || quote_ident(schema_name) || '.' || quote_ident(table_name) FROM [SHOW TABLES] WHERE type = 'table'
+		UNION ALL
+	SELECT 'COLUMN ' || quote_ident(schema_name) || '.' || quote_ident(table_name) || '.' || quote_ident("column"->>'name') FROM columns
+		UNION ALL
+	SELECT 'INDEX ' || quote_ident(schema_name) || '.' || quote_ident(table_name) || '@' || quote_ident("index"->>'name') FROM indexes
+		UNION ALL
+	SELECT 'CONSTRAINT ' || quote_ident("constraint"->>'name') || ' ON ' || quote_ident(schema_name) || '.' || quote_ident(table_name) FROM constraints
+		%s`, onType))
+
+	commentables, err := Collect(ctx, og, tx, pgx.RowTo[string], q)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := makeOpStmt(OpStmtDDL)
+	if og.produceError() {
+		missing := []struct {
+			object string
+			code   pgcode.Code
+		}{
+			{`TABLE "TableThatDoesntExist"`, pgcode.UndefinedTable},
+			{`INDEX "IndexThatDoesntExist"`, pgcode.UndefinedObject},
+		}[og.randIntn(2)]
+		stmt.expectedExecErrors.add(missing.code)
+		stmt.sql = fmt.Sprintf(`COMMENT ON %s IS 'comment from the RSW'`, missing.object)
+		return stmt, nil
+	}
+
+	picked, err := PickOne(og.params.rng, commentables)
+	if err != nil {
+		return nil, err
+	}
+
+	const letters = "abcdefghijklmnopqrstuvwxyz "
+	comment := fmt.Sprintf("'comment from the RSW: %s'", util.RandString(og.params.rng, og.randIntn(32), letters))
+	if og.params.rng.Float64() < 0.3 {
+		// Delete the comment with some probability, either explicitly or by
+		// setting it to the empty string.
+		comment = "NULL"
+		if og.randIntn(2) == 0 {
+			comment = "''"
+		}
+	}
+	stmt.sql = fmt.Sprintf(`COMMENT ON %s IS %s`, picked, comment)
+	return stmt, nil
+}
+
+func (og *operationGenerator) insertRow(ctx context.Context, tx pgx.Tx) (stmt *opStmt, err error) {
+	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
+	if err != nil {
+		return nil, errors.Wrapf(err, "error getting random table name")
+	}
+	tableExists, err := og.tableExists(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if !tableExists {
+		return makeOpStmtForSingleError(OpStmtDML,
+			fmt.Sprintf(
+				`INSERT INTO %s (IrrelevantColumnName) VALUES ("IrrelevantValue")`,
+				tableName,
+			),
+			pgcode.UndefinedTable), nil
+	}
+	allColumns, err := og.getTableColumns(ctx, tx, tableName, false)
+	nonGeneratedCols := allColumns
+	if err != nil {
+		return nil, errors.Wrapf(err, "error getting table columns for insert row")
+	}
+
+	// Filter out computed columns.
+	{
+		truncated := nonGeneratedCols[:0]
+		for _, c := range nonGeneratedCols {
+			if !c.generated {
+				truncated = append(truncated, c)
+			}
+		}
+		nonGeneratedCols = truncated
+	}
+	// Columns defaulting to gen_random_uuid() are left out of the INSERT, so
+	// that their values come from the default. They are still screened as if
+	// the default was inserted explicitly, which can never cause a violation.
+	isRandomUUIDDefault := func(c column) bool {
+		return c.typ.Family() == types.UuidFamily && c.defaultExpression == "gen_random_uuid()"
+	}
+	nonGeneratedColNames := []string{}
+	rows := [][]string{}
+	for _, col := range nonGeneratedCols {
+		nonGeneratedColNames = append(nonGeneratedColNames, col.name)
+	}
+	numRows := og.randIntn(3) + 1
+	// Columns defaulting to nextval() of a sequence are also left out of the
+	// INSERT, so that their values are drawn from the sequence. They are
+	// screened with the values the sequence is predicted to return, which is
+	// only done if those can be predicted and fit in the column. A sequence
+	// is only relied upon for one column, since the order in which the
+	// defaults of a row are evaluated is unspecified.
+	sequenceValues := map[string][]int64{}
+	sequencesUsed := map[string]bool{}
+	for _, col := range nonGeneratedCols {
+		m := sequenceDefaultRegex.FindStringSubmatch(col.defaultExpression)
+		if m == nil || col.typ.Family() != types.IntFamily || sequencesUsed[m[1]] {
+			continue
+		}
+		values, ok, err := og.nextSequenceValues(ctx, tx, m[1], numRows)
+		if err != nil {
+			return nil, err
+		}
+		if !ok || !intValuesFitType(values, col.typ) {
+			continue
+		}
+		sequenceValues[col.name] = values
+		sequencesUsed[m[1]] = true
+	}
+	usesDefault := func(c column) bool {
+		_, usesSequence := sequenceValues[c.name]
+		return usesSequence || isRandomUUIDDefault(c)
+	}
+	relations, err := og.regClassRelations(ctx, tx, nonGeneratedCols)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < numRows; i++ {
+		var row []string
+		for _, col := range nonGeneratedCols {
+			if isRandomUUIDDefault(col) {
+				row = append(row, col.defaultExpression)
+				continue
+			}
+			if values, ok := sequenceValues[col.name]; ok {
+				row = append(row, strconv.FormatInt(values[i], 10))
+				continue
+			}
+			row = append(row, og.randColumnValue(col, relations))
+		}
+
+		rows = append(rows, row)
+	}
+	stmt = makeOpStmt(OpStmtDML)
+	var injectedViolation pgcode.Code
+	if og.produceError() {
+		rows, injectedViolation = og.violateInsertConstraint(nonGeneratedCols, usesDefault, rows, len(sequenceValues) == 0)
+	}
+	// An invalid enum label, or the name of a relation that doesn't exist,
+	// fails to be cast while the first row is evaluated, before anything else
+	// about the insert can fail, so there is nothing else to screen for. The
+	// value would also fail the screening queries below.
+	if injectedViolation == pgcode.InvalidTextRepresentation || injectedViolation == pgcode.UndefinedTable {
+		stmt.expectedExecErrors.add(injectedViolation)
+		stmt.sql = formatInsertStmt(tableName, nonGeneratedCols, usesDefault, rows)
+		return stmt, nil
+	}
+	// The same goes for values that are still being added to or dropped from
+	// an enum, which can't be written.
+	for i, col := range nonGeneratedCols {
+		if col.typ.Family() != types.EnumFamily {
+			continue
+		}
+		for _, row := range rows {
+			readOnly, err := og.enumValueIsReadOnly(ctx, tx, col.typ, row[i])
+			if err != nil {
+				return nil, err
+			}
+			if readOnly {
+				stmt.expectedExecErrors.add(pgcode.InvalidParameterValue)
+				stmt.sql = formatInsertStmt(tableName, nonGeneratedCols, usesDefault, rows)
+				return stmt, nil
+			}
+		}
+	}
+	// Verify that none of the generated expressions will blow up on this insert.
+	anyInvalidInserts := false
+	for _, row := range rows {
+		invalidInsert, generatedErrors, potentialErrors, err := og.validateGeneratedExpressionsForInsert(ctx, tx, tableName, nonGeneratedColNames, allColumns, row)
+		if err != nil {
+			return nil, err
+		}
+		// We may have errors that are possible, but not guaranteed.
+		stmt.potentialExecErrors.addAll(potentialErrors)
+		if invalidInsert {
+			stmt.expectedExecErrors.addAll(generatedErrors)
+			// We will be pessimistic and assume that other column related errors can
+			// be hit, since the function above fails only on generated columns. But,
+			// there maybe index expressions with the exact same problem.
+			stmt.potentialExecErrors.add(pgcode.NumericValueOutOfRange)
+			stmt.potentialExecErrors.add(pgcode.FloatingPointException)
+			stmt.potentialExecErrors.add(pgcode.NotNullViolation)
+			anyInvalidInserts = true
+		}
+	}
+
+	// Only evaluate these if we know that the inserted values are sane, since
+	// we will need to evaluate generated expressions below.
+	uniqueConstraintViolation := false
+	fkViolation := false
+	if !anyInvalidInserts {
+		// Verify if the new row will violate unique constraints by checking the constraints and
+		// existing rows in the database.
+		var generatedErrors codesWithConditions
+		uniqueConstraintViolation, generatedErrors, err = og.valuesViolateUniqueConstraints(ctx, tx, tableName, nonGeneratedColNames, allColumns, rows)
+		if err != nil {
+			return nil, err
+		}
+		if !uniqueConstraintViolation {
+			stmt.expectedExecErrors.addAll(generatedErrors)
+		}
+		// Verify if the new row will violate fk constraints by checking the constraints and rows
+		// in the database.
+		fkViolation, err = og.violatesFkConstraints(ctx, tx, tableName, nonGeneratedColNames, rows)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Only single-column foreign keys are screened for above.
+	hasCompositeFks, err := og.tableHasCompositeForeignKeys(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	// NULLs are only rejected by a NOT NULL column definition, which the
+	// generated values already respect, or by an equivalent CHECK constraint,
+	// which is screened for along with the other CHECK constraints.
+	checkViolation, err := og.violatesCheckConstraints(ctx, tx, tableName, nonGeneratedCols, rows)
+	if err != nil {
+		return nil, err
+	}
+	// Check constraints that are still being added are enforced on writes
+	// before they show up as validated.
+	hasOngoingSchemaChanges, err := og.tableHasOngoingSchemaChanges(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	// TIMESTAMPTZ values near the edges of the supported range may overflow
+	// once converted to the session time zone.
+	insertsTimestampTZNearBounds := false
+	for i, col := range nonGeneratedCols {
+		if col.typ.Family() == types.TimestampTZFamily {
+			for _, row := range rows {
+				if timestampTZNearBounds(row[i]) {
+					insertsTimestampTZNearBounds = true
+				}
+			}
+		}
+	}
+
+	// Sequences are not transactional, so concurrent transactions may draw
+	// values from them after they were predicted. The values actually
+	// inserted may then violate constraints differently, or run out.
+	usesSequence := len(sequenceValues) > 0
+
+	// A NULL placed into a NOT NULL column is rejected before CHECK, unique
+	// and foreign key constraints are enforced, but after computed columns are
+	// evaluated, whose errors are screened for above. A NOT NULL constraint
+	// that is being dropped may no longer be enforced.
+	injectedNotNullViolation := injectedViolation == pgcode.NotNullViolation
+
+	stmt.expectedExecErrors.addAll(codesWithConditions{
+		{code: pgcode.UniqueViolation, condition: uniqueConstraintViolation && !usesSequence},
+		{code: pgcode.CheckViolation, condition: checkViolation},
+		{code: pgcode.NotNullViolation, condition: injectedNotNullViolation && !hasOngoingSchemaChanges},
+	})
+	stmt.potentialExecErrors.addAll(codesWithConditions{
+		{code: pgcode.NotNullViolation, condition: injectedNotNullViolation && hasOngoingSchemaChanges},
+		{code: pgcode.ForeignKeyViolation, condition: fkViolation || usesSequence || hasCompositeFks},
+		{code: pgcode.CheckViolation, condition: hasOngoingSchemaChanges},
+		{code: pgcode.DatetimeFieldOverflow, condition: insertsTimestampTZNearBounds},
+		{code: pgcode.UniqueViolation, condition: usesSequence},
+		{code: pgcode.SequenceGeneratorLimitExceeded, condition: usesSequence},
+		{code: pgcode.NumericValueOutOfRange, condition: usesSequence},
+	})
+	og.expectedCommitErrors.addAll(codesWithConditions{
+		{code: pgcode.ForeignKeyViolation, condition: fkViolation && !usesSequence},
+	})
+	og.potentialCommitErrors.addAll(codesWithConditions{
+		{code: pgcode.ForeignKeyViolation, condition: usesSequence},
+	})
+
+	stmt.sql = formatInsertStmt(tableName, nonGeneratedCols, usesDefault, rows)
+	return stmt, nil
+}
+
+// timestampTZNearBounds returns whether value, a TIMESTAMPTZ formatted by
+// randColumnValue, is within a day of the edges of the supported range, past
+// which it may be moved when converted to a session time zone.
+func timestampTZNearBounds(value string) bool {
+	s, ok := strings.CutSuffix(value, ":::TIMESTAMPTZ")
+	if !ok {
+		return false
+	}
+	t, _, err := tree.ParseTimestampTZ(nil /* ctx */, strings.Trim(s, "'"), time.Microsecond)
+	if err != nil {
+		// Values that can't be parsed, such as infinity, are at the edges.
+		return true
+	}
+	return t.Before(tree.MinSupportedTime.Add(24*time.Hour)) ||
+		t.After(tree.MaxSupportedTime.Add(-24*time.Hour))
+}
+
+// regClassRelations returns the quoted, qualified names of the relations
+// visible to the transaction, which the values of REGCLASS columns have to
+// resolve to, if any of the given columns is one.
+func (og *operationGenerator) regClassRelations(
+	ctx context.Context, tx pgx.Tx, cols []column,
+) ([]string, error) {
+	if !slices.ContainsFunc(cols, func(c column) bool { return c.typ.Identical(types.RegClass) }) {
+		return nil, nil
+	}
+	return og.scanStringArray(ctx, tx, `
+SELECT COALESCE(array_agg(quote_ident(n.nspname) || '.' || quote_ident(c.relname) ORDER BY c.oid), ARRAY[]::STRING[])
+  FROM pg_catalog.pg_class AS c
+  JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+ WHERE c.relkind IN ('r', 'v', 'm', 'S')
+   AND n.nspname NOT IN ('pg_catalog', 'pg_extension', 'information_schema', 'crdb_internal')
+`)
+}
+
+// randColumnValue returns a random value for the given column, formatted as
+// an expression that can be inserted into it. NULL is only returned for
+// nullable columns. Values of REGCLASS columns refer to one of the given
+// relations.
+func (og *operationGenerator) randColumnValue(col column, relations []string) string {
+	d := randgen.RandDatum(og.params.rng, col.typ, col.nullable)
+	// Unfortunately, RandDatum for OIDs only selects random values, which will
+	// always fail validation. So, for OIDs we will select a random known type
+	// instead.
+	if col.typ.Family() == types.Oid.Family() {
+		d = tree.NewDOid(randgen.RandColumnType(og.params.rng).Oid())
+	}
+	// We have seen cases where randomly generated ints easily hit an
+	// integer overflow in our workload when we allow large numbers.
+	// Since there is no real advantage to testing such numbers,
+	// limit the largeness by always setting the amount of bits to
+	// 8 (-128 to 127) - ensuring we won't overflow even with the
+	// smallest int (INT2, -32768 to 32767).
+	if col.typ.Family() == types.IntFamily {
+		d = tree.NewDInt(tree.DInt(int8(og.params.rng.Uint64())))
+	}
+	str := tree.AsStringWithFlags(d, tree.FmtParsable)
+	// For strings use the actual type, so that comparisons for NULL values are sane.
+	if col.typ.Family() == types.StringFamily {
+		str = strings.Replace(str, ":::STRING", fmt.Sprintf("::%s", col.typ.SQLString()), -1)
+	}
+	// Once stored, a REGCLASS value is just an OID, so it outlives the
+	// relation it refers to if that is later dropped.
+	if col.typ.Identical(types.RegClass) && len(relations) > 0 {
+		str = fmt.Sprintf("%s::REGCLASS", tree.NewDString(relations[og.randIntn(len(relations))]))
+	}
+	// Composite values are written as a row of their fields, cast to the
+	// composite type.
+	if tuple, ok := d.(*tree.DTuple); ok && col.typ.UserDefined() {
+		fields := make([]string, len(tuple.D))
+		for j, field := range tuple.D {
+			fields[j] = tree.AsStringWithFlags(field, tree.FmtParsable)
+		}
+		str = fmt.Sprintf("ROW(%s)::%s", strings.Join(fields, ", "), col.typ.SQLString())
+	}
+	return str
+}
+
+// violateInsertConstraint modifies the rows to be inserted into the given
+// columns, so that they deliberately violate a constraint of one of them. It
+// returns the rows along with the error code the violation is expected to
+// produce, or pgcode.SuccessfulCompletion if the violation is left to be
+// screened for. Values are only replaced in the first row, so that they are
+// evaluated before the values of any other row. Columns whose
+// values come from their defaults are never modified. Rows are only
+// duplicated if canDuplicate is set, since the values drawn from sequences
+// cannot be.
+func (og *operationGenerator) violateInsertConstraint(
+	cols []column, usesDefault func(column) bool, rows [][]string, canDuplicate bool,
+) ([][]string, pgcode.Code) {
+	var notNullCols, enumCols, regClassCols []int
+	for i, col := range cols {
+		if usesDefault(col) {
+			continue
+		}
+		if !col.nullable {
+			notNullCols = append(notNullCols, i)
+		}
+		if col.typ.Family() == types.EnumFamily {
+			enumCols = append(enumCols, i)
+		}
+		if col.typ.Identical(types.RegClass) {
+			regClassCols = append(regClassCols, i)
+		}
+	}
+	var violations []func() pgcode.Code
+	if len(notNullCols) > 0 {
+		violations = append(violations, func() pgcode.Code {
+			rows[0][notNullCols[og.randIntn(len(notNullCols))]] = "NULL"
+			return pgcode.NotNullViolation
+		})
+	}
+	if len(enumCols) > 0 {
+		violations = append(violations, func() pgcode.Code {
+			i := enumCols[og.randIntn(len(enumCols))]
+			rows[0][i] = fmt.Sprintf("'EnumValueThatDoesntExist'::%s", cols[i].typ.SQLString())
+			return pgcode.InvalidTextRepresentation
+		})
+	}
+	if len(regClassCols) > 0 {
+		violations = append(violations, func() pgcode.Code {
+			rows[0][regClassCols[og.randIntn(len(regClassCols))]] = `'public."TableThatDoesntExist"'::REGCLASS`
+			return pgcode.UndefinedTable
+		})
+	}
+	// Whether a duplicated row violates a unique constraint depends on the
+	// constraints of the table and on NULLs, which the screening of unique
+	// constraints already accounts for.
+	if canDuplicate {
+		violations = append(violations, func() pgcode.Code {
+			rows = append(rows, append([]string(nil), rows[0]...))
+			return pgcode.SuccessfulCompletion
+		})
+	}
+	if len(violations) == 0 {
+		return rows, pgcode.SuccessfulCompletion
+	}
+	code := violations[og.randIntn(len(violations))]()
+	return rows, code
+}
+
+// formatInsertStmt formats an INSERT of the given rows into the columns of a
+// table. Columns whose values come from their defaults are left out.
+func formatInsertStmt(
+	tableName *tree.TableName, cols []column, usesDefault func(column) bool, rows [][]string,
+) string {
+	var insertedColNames []string
+	for _, col := range cols {
+		if !usesDefault(col) {
+			insertedColNames = append(insertedColNames, col.name)
+		}
+	}
+	if len(insertedColNames) == 0 {
+		return fmt.Sprintf(`INSERT INTO %s DEFAULT VALUES`, tableName)
+	}
+	var formattedRows []string
+	for _, row := range rows {
+		var insertedValues []string
+		for i, col := range cols {
+			if !usesDefault(col) {
+				insertedValues = append(insertedValues, row[i])
+			}
+		}
+		formattedRows = append(formattedRows, fmt.Sprintf("(%s)", strings.Join(insertedValues, ",")))
+	}
+	return fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES %s`,
+		tableName,
+		strings.Join(insertedColNames, ","),
+		strings.Join(formattedRows, ","),
+	)
+}
+
+type opStmtType int
+
+const (
+	// OpStmtDDL statement is a data definition language statement.
+	OpStmtDDL opStmtType = 1
+	// OpStmtDML statement is a data manipulation language statement.
+	OpStmtDML opStmtType = 2
+)
+
+type opStmtQueryResultCallback func(ctx context.Context, rows pgx.Rows) error
+
+// opStmtExecutor runs statements, either in a transaction or, for the
+// statements of implicitTxnOps, directly on a connection.
+type opStmtExecutor interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// opStmt a generated statement that is either DDL or DML, including the potential
+// set of execution errors this statement can generate.
+type opStmt struct {
+	// sql the query being executed.
+	sql string
+	// queryType family of the query type being executed (DML or DDL).
+	queryType opStmtType
+	// expectedExecErrors expected set of execution errors.
+	expectedExecErrors errorCodeSet
+	// potentialExecErrors errors that could be potentially seen on execution.
+	potentialExecErrors errorCodeSet
+	queryResultCallback opStmtQueryResultCallback
+	// outcome the error code returned when the statement was executed, which is
+	// pgcode.SuccessfulCompletion if it succeeded. It is empty if the statement
+	// was not executed, or failed without an error code.
+	outcome pgcode.Code
+}
+
+// String implements Stringer
+func (s *opStmt) String() string {
+	return fmt.Sprintf("QUERY: %s, Expected Errors: %s, Potential Errors: %s",
+		s.sql,
+		s.expectedExecErrors,
+		s.potentialExecErrors)
+}
+
+func (s *opStmt) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		SQL              string `json:"sql"`
+		ExpectedExecErr  string `json:"expectedExecErr,omitempty"`
+		PotentialExecErr string `json:"potentialExecErr,omitempty"`
+	}{
+		SQL:              s.sql,
+		ExpectedExecErr:  s.expectedExecErrors.String(),
+		PotentialExecErr: s.potentialExecErrors.String(),
+	})
+}
+
+// makeOpStmtForSingleError constructs a statement that will only produce
+// an error.
+func makeOpStmtForSingleError(queryType opStmtType, sql string, codes ...pgcode.Code) *opStmt {
+	s := makeOpStmt(queryType)
+	s.sql = sql
+	for _, code := range codes {
+		s.expectedExecErrors.add(code)
+	}
+	return s
+}
+
+// makeOpStmt constructs an empty operation of a given type.
+func makeOpStmt(queryType opStmtType) *opStmt {
+	return &opStmt{
+		queryType:           queryType,
+		expectedExecErrors:  makeExpectedErrorSet(),
+		potentialExecErrors: makeExpectedErrorSet(),
+	}
+}
+
+// opStmtFromTree constructs an operation from the provide tree.Statement.
+//
+//lint:ignore U1000 Used in future commits. TODO(chrisseto): Remove the ignore.
+func newOpStmt(stmt tree.Statement, expectedExecErrors codesWithConditions) *opStmt {
+	var queryType opStmtType
+	switch stmt.StatementType() {
+	// Privilege changes are written to descriptors like any other schema
+	// change.
+	case tree.TypeDDL, tree.TypeDCL:
+		queryType = OpStmtDDL
+	case tree.TypeDML:
+		queryType = OpStmtDML
+	default:
+		panic("unhandled statement type")
+	}
+
+	expectedErrors := makeExpectedErrorSet()
+	expectedErrors.addAll(expectedExecErrors)
+
+	return &opStmt{
+		sql:                 tree.Serialize(stmt),
+		queryType:           queryType,
+		expectedExecErrors:  expectedErrors,
+		potentialExecErrors: makeExpectedErrorSet(),
+	}
+}
+
+// ErrorState wraps schemachange workload errors to have state information for
+// the purpose of dumping in our JSON log.
+type ErrorState struct {
+	cause                        error
+	ExpectedErrors               []string      `json:"expectedErrors,omitempty"`
+	PotentialErrors              []string      `json:"potentialErrors,omitempty"`
+	ExpectedCommitErrors         []string      `json:"expectedCommitErrors,omitempty"`
+	PotentialCommitErrors        []string      `json:"potentialCommitErrors,omitempty"`
+	QueriesForGeneratingErrors   []interface{} `json:"queriesForGeneratingErrors,omitempty"`
+	PreviousStatements           []string      `json:"previousStatements,omitempty"`
+	UsesDeclarativeSchemaChanger bool          `json:"usesDeclarativeSchemaChanger,omitempty"`
+}
+
+func (es *ErrorState) Unwrap() error {
+	return es.cause
+}
+
+func (es *ErrorState) Error() string {
+	return es.cause.Error()
+}
+
+// WrapWithErrorState dumps the object state when an error is hit
+func (og *operationGenerator) WrapWithErrorState(err error, op *opStmt) error {
+	previousStmts := make([]string, 0, len(og.stmtsInTxt))
+	for _, stmt := range og.stmtsInTxt {
+		previousStmts = append(previousStmts, stmt.sql)
+	}
+	return &ErrorState{
+		cause:                        err,
+		ExpectedErrors:               op.expectedExecErrors.StringSlice(),
+		PotentialErrors:              op.potentialExecErrors.StringSlice(),
+		ExpectedCommitErrors:         og.expectedCommitErrors.StringSlice(),
+		PotentialCommitErrors:        og.potentialCommitErrors.StringSlice(),
+		QueriesForGeneratingErrors:   og.GetOpGenLog(),
+		PreviousStatements:           previousStmts,
+		UsesDeclarativeSchemaChanger: og.useDeclarativeSchemaChanger,
+	}
+}
+
+// The results of executing the statement of an operation, as counted by
+// operationGeneratorParams.opResults.
+const (
+	opResultSuccess         = "success"
+	opResultExpectedError   = "expected_error"
+	opResultUnexpectedError = "unexpected_error"
+	// opResultMissingError is recorded when the statement succeeded while an
+	// error was expected.
+	opResultMissingError = "missing_error"
+)
+
+// recordOpResult records the result of executing stmt, given the error
+// returned by executeStmt. The statement is attributed to the last operation
+// generated in the transaction.
+func (og *operationGenerator) recordOpResult(stmt *opStmt, err error) {
+	if og.params.opResults == nil || len(og.opsInTxn) == 0 {
+		return
+	}
+	result := opResultSuccess
+	switch {
+	case err == nil:
+	case errors.Is(err, errRunInTxnRbkSentinel) || stmt.outcome == pgcode.SerializationFailure:
+		// Serialization failures are not marked, but make the transaction roll
+		// back like expected errors do.
+		result = opResultExpectedError
+	case stmt.outcome == pgcode.SuccessfulCompletion && !stmt.expectedExecErrors.empty():
+		result = opResultMissingError
+	default:
+		result = opResultUnexpectedError
+	}
+	og.params.opResults.WithLabelValues(
+		og.opsInTxn[len(og.opsInTxn)-1].String(), result, stmt.outcome.String(),
+	).Inc()
+}
+
+// executeStmt executes the given operation statement, and validates the result
+// of the execution. Note: Commit time failures will be handled separately from
+// statement specific logic.
+func (s *opStmt) executeStmt(
+	ctx context.Context, db opStmtExecutor, og *operationGenerator,
+) (retErr error) {
+	defer func() { og.recordOpResult(s, retErr) }()
+	var err error
+	var rows pgx.Rows
+	// Statement doesn't produce any result set that needs to be validated.
+	if s.queryResultCallback == nil {
+		_, err = db.Exec(ctx, s.sql)
+	} else {
+		rows, err = db.Query(ctx, s.sql)
+	}
+	s.outcome = opLogOutcome(err)
+	if err != nil {
+		// If the error not an instance of pgconn.PgError, then it is unexpected.
+		pgErr := new(pgconn.PgError)
+		if !errors.As(err, &pgErr) {
+			// Connections dropping with at the server side can be treated
+			// as rollback errors here.
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				return errors.Mark(err,
+					errRunInTxnRbkSentinel)
+			}
+			return errors.Mark(
+				og.WrapWithErrorState(errors.Wrap(err, "***UNEXPECTED ERROR; Received a non pg error."), s),
+				errRunInTxnFatalSentinel,
+			)
+		}
+		if pgcode.MakeCode(pgErr.Code) == pgcode.SerializationFailure {
+			return err
+		}
+		// TODO(fqazi): For the short term we are going to ignore any not implemented,
+		// errors in the declarative schema changer. Supported operations have edge
+		// cases, but later we should mark some of these are fully supported.
+		if og.useDeclarativeSchemaChanger && pgcode.MakeCode(pgErr.Code) == pgcode.Uncategorized &&
+			strings.Contains(pgErr.Message, " not implemented in the new schema changer") {
+			return errors.Mark(errors.Wrap(err, "ROLLBACK; Ignoring declarative schema changer not implemented error."),
+				errRunInTxnRbkSentinel,
+			)
+		}
+		if !s.expectedExecErrors.contains(pgcode.MakeCode(pgErr.Code)) &&
+			!s.potentialExecErrors.contains(pgcode.MakeCode(pgErr.Code)) {
+			return errors.Mark(
+				og.WrapWithErrorState(errors.Wrap(err, "***UNEXPECTED ERROR; Received an unexpected execution error."),
+					s),
+				errRunInTxnFatalSentinel,
+			)
+		}
+		return errors.Mark(errors.Wrap(err, "ROLLBACK; Successfully got expected execution error."),
+			errRunInTxnRbkSentinel,
+		)
+	}
+	if !s.expectedExecErrors.empty() {
+		// Clean up the result set, if we didn't encounter an expected error.
+		if rows != nil {
+			rows.Close()
+		}
+		return errors.Mark(
+			og.WrapWithErrorState(errors.New("***FAIL; Failed to receive an execution error when errors were expected"),
+				s),
+			errRunInTxnFatalSentinel,
+		)
+	}
+	// Next validate the result set.
+	if s.queryResultCallback != nil {
+		if err := s.queryResultCallback(ctx, rows); err != nil {
 			return err
 		}
+	}
+	return nil
+}
 
-		for i, unique := range results {
-			table["columns"].([]map[string]any)[i]["is_unique"] = unique
-		}
-
-		return nil
+// deleteCheckViolations deletes the rows of a table that violate one of its
+// CHECK constraints, preferring those added with NOT VALID. Such constraints
+// are enforced on writes, but the rows that existed when they were added are
+// only checked by ALTER TABLE ... VALIDATE CONSTRAINT, which is then able to
+// succeed. Deleting the rows that violate a validated constraint is a no-op.
+func (og *operationGenerator) deleteCheckViolations(
+	ctx context.Context, tx pgx.Tx,
+) (*opStmt, error) {
+	if err := og.setSeedInDB(ctx, tx); err != nil {
+		return nil, err
+	}
+	type checkConstraint struct {
+		Schema string
+		Table  string
+		Expr   string
+	}
+	constraints, err := Collect(ctx, og, tx, pgx.RowToStructByPos[checkConstraint], `
+		SELECT ns.nspname, cls.relname, con.consrc
+		  FROM pg_catalog.pg_constraint AS con
+		  JOIN pg_catalog.pg_class AS cls ON cls.oid = con.conrelid
+		  JOIN pg_catalog.pg_namespace AS ns ON ns.oid = cls.relnamespace
+		 WHERE con.contype = 'c'
+		   AND con.convalidated = $1
+		ORDER BY random()
+		 LIMIT 1
+	`, og.randIntn(4) == 0)
+	if err != nil {
+		return nil, og.checkAndAdjustForUnknownSchemaErrors(err)
 	}
+	if len(constraints) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	constraint := constraints[0]
+	tableName := tree.MakeTableNameFromPrefix(tree.ObjectNamePrefix{
+		SchemaName:     tree.Name(constraint.Schema),
+		ExplicitSchema: true,
+	}, tree.Name(constraint.Table))
 
-	stmt, code, err := Generate[*tree.AlterTable](og.params.rng, og.produceError(), []GenerationCase{
-		// IF EXISTS should noop if the table doesn't exist.
-		{pgcode.SuccessfulCompletion, `ALTER TABLE IF EXISTS "NonExistentTable" ALTER PRIMARY KEY USING COLUMNS ("IrrelevantColumn")`},
-		// Targeting a table that doesn't exist should error out.
-		{pgcode.UndefinedTable, `ALTER TABLE "NonExistentTable" ALTER PRIMARY KEY USING COLUMNS ("IrrelevantColumn")`},
-		// Targeting a column that doesn't exist should error out.
-		{pgcode.UndefinedColumn, `{ with TableNotUnderGoingSchemaChange } ALTER TABLE { .table_name } ALTER PRIMARY KEY USING COLUMNS ("NonExistentColumn") { end }`},
-		// NonUniqueColumns can't be used as PKs.
-		{pgcode.UniqueViolation, `{ with TableNotUnderGoingSchemaChange } ALTER TABLE { .table_name } ALTER PRIMARY KEY USING COLUMNS ({ . | Unique false | Nullable false | Generated false | Indexable true | InInvertedIndex false | Columns }) { end }`},
-		// NullableColumns can't be used as PKs.
-		{pgcode.InvalidSchemaDefinition, `{ with TableNotUnderGoingSchemaChange } ALTER TABLE { .table_name } ALTER PRIMARY KEY USING COLUMNS ({ . | Unique true | Nullable true | Generated false | Indexable true | InInvertedIndex false | Columns }) { end }`},
-		// UnindexableColumns can't be used as PKs.
-		{pgcode.InvalidSchemaDefinition, `{ with TableNotUnderGoingSchemaChange } ALTER TABLE { .table_name } ALTER PRIMARY KEY USING COLUMNS ({ . | Unique true | Nullable false | Generated false | Indexable false | InInvertedIndex false | Columns }) { end }`},
-		// TODO(sql-foundations): Columns that have an inverted index can't be used
-		// as a primary key. This check isn't 100% correct because we only care
-		// about the final column in an inverted index and we're checking if
-		// columns are in an inverted index at all.
-		// {pgcode.InvalidSchemaDefinition, `{ with TableNotUnderGoingSchemaChange } ALTER TABLE { .table_name } ALTER PRIMARY KEY USING COLUMNS ({ . | Unique true | Nullable false | Generated false | Indexable true | InInvertedIndex true | Columns }) { end }`},
-		// Tables undergoing a schema change may not have their PK changed.
-		// TODO(sql-foundations): This case doesn't cause errors as expected.
-		// {pgcode.Code{}, `ALTER TABLE {TableUnderGoingSchemaChange} ALTER PRIMARY KEY USING COLUMNS ({UniqueNotNullableColumns})`},
-		// Successful cases.
-		{pgcode.SuccessfulCompletion, `{ with TableNotUnderGoingSchemaChange } ALTER TABLE { .table_name } ALTER PRIMARY KEY USING COLUMNS ({ . | Unique true | Nullable false | Generated false | Indexable true | InInvertedIndex false | Columns }) { end }`},
-		{pgcode.SuccessfulCompletion, `{ with TableNotUnderGoingSchemaChange } ALTER TABLE { .table_name } ALTER PRIMARY KEY USING COLUMNS ({ . | Unique true | Nullable false | Generated false | Indexable true | InInvertedIndex false | Columns }) USING HASH { end }`},
-		// TODO(sql-foundations): Add support for hash parameters and storage parameters.
-	}, template.FuncMap{
-		"TableNotUnderGoingSchemaChange": func() (map[string]any, error) {
-			tbls := util.Filter(tables, func(table map[string]any) bool {
-				return !table["table_undergoing_schema_change"].(bool)
-			})
-			return PickOne(og.params.rng, tbls)
-		},
-		"TableUnderGoingSchemaChange": func() (map[string]any, error) {
-			tbls := util.Filter(tables, func(table map[string]any) bool {
-				return table["table_undergoing_schema_change"].(bool)
-			})
-			return PickOne(og.params.rng, tbls)
-		},
-		"Columns": func(table map[string]any) (string, error) {
-			selected, err := PickAtLeast(og.params.rng, 1, table["columns"].([]map[string]any))
-			names := util.Map(selected, func(col map[string]any) string { return col["column_name"].(string) })
-			return strings.Join(names, ", "), err
-		},
-		"Nullable": func(nullable bool, table map[string]any) map[string]any {
-			table["columns"] = util.Filter(table["columns"].([]map[string]any), func(col map[string]any) bool {
-				return col["is_nullable"].(bool) == nullable
-			})
-			return table
-		},
-		"Unique": func(unique bool, table map[string]any) (map[string]any, error) {
-			if err := fillIsUnique(table); err != nil {
-				return nil, err
-			}
-			table["columns"] = util.Filter(table["columns"].([]map[string]any), func(col map[string]any) bool {
-				return col["is_unique"].(bool) == unique
-			})
-			return table, nil
-		},
-		"Generated": func(unique bool, table map[string]any) map[string]any {
-			table["columns"] = util.Filter(table["columns"].([]map[string]any), func(col map[string]any) bool {
-				return col["is_unique"].(bool) == unique
-			})
-			return table
-		},
-		"Indexable": func(indexable bool, table map[string]any) map[string]any {
-			table["columns"] = util.Filter(table["columns"].([]map[string]any), func(col map[string]any) bool {
-				return col["is_indexable"].(bool) == indexable
-			})
-			return table
-		},
-		"InInvertedIndex": func(inIndex bool, table map[string]any) map[string]any {
-			table["columns"] = util.Filter(table["columns"].([]map[string]any), func(col map[string]any) bool {
-				return col["is_in_inverted_index"].(bool) == inIndex
-			})
-			return table
-		},
-	})
+	// Deleting rows referenced by a foreign key is rejected, unless the
+	// foreign key cascades, in which case the referencing rows may in turn
+	// violate their own constraints.
+	isReferenced, err := og.tableIsReferencedByForeignKeys(ctx, tx, &tableName)
 	if err != nil {
 		return nil, err
 	}
 
-	return newOpStmt(stmt, codesWithConditions{
-		{code, true},
-	}), nil
+	stmt := makeOpStmt(OpStmtDML)
+	stmt.potentialExecErrors.addAll(codesWithConditions{
+		{pgcode.ForeignKeyViolation, isReferenced},
+		{pgcode.NotNullViolation, isReferenced},
+		{pgcode.CheckViolation, isReferenced},
+	})
+	stmt.sql = fmt.Sprintf(`DELETE FROM %s WHERE NOT %s`, tableName.String(), constraint.Expr)
+	return stmt, nil
 }
 
-func (og *operationGenerator) survive(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
-	dbRegions, err := og.getDatabaseRegionNames(ctx, tx)
+// updateRow sets a column of some rows of a table to a random value. Only
+// columns that no constraint, unique index or expression depends on are
+// updated, so that the new value can't violate anything that is already
+// enforced.
+func (og *operationGenerator) updateRow(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
 	if err != nil {
 		return nil, err
 	}
-
-	// Choose a survival mode based on a coin toss.
-	needsAtLeastThreeRegions := false
-	survive := "ZONE FAILURE"
-	if coinToss := og.randIntn(2); coinToss == 1 {
-		survive = "REGION FAILURE"
-		needsAtLeastThreeRegions = true
+	tableExists, err := og.tableExists(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if !tableExists {
+		return makeOpStmtForSingleError(OpStmtDML,
+			fmt.Sprintf(`UPDATE %s SET IrrelevantColumnName = NULL`, tableName),
+			pgcode.UndefinedTable), nil
+	}
+	if og.produceError() && og.randIntn(4) == 0 {
+		return makeOpStmtForSingleError(OpStmtDML,
+			fmt.Sprintf(`UPDATE %s SET "ColumnThatDoesntExist" = NULL`, tableName),
+			pgcode.UndefinedColumn), nil
 	}
 
-	// Expect 0 regions to fail, and less than three regions to fail
-	// if there are < 3 regions.
-	stmt := makeOpStmt(OpStmtDDL)
-	stmt.expectedExecErrors.addAll(codesWithConditions{
-		{
-			code:      pgcode.InvalidName,
-			condition: len(dbRegions) == 0,
-		},
-		{
-			code:      pgcode.InvalidParameterValue,
-			condition: needsAtLeastThreeRegions && len(dbRegions) < 3,
-		},
-	})
-
-	dbName, err := og.getDatabase(ctx, tx)
+	cols, err := og.getTableColumns(ctx, tx, tableName, true /* shuffle */)
 	if err != nil {
 		return nil, err
 	}
-	stmt.sql = fmt.Sprintf(`ALTER DATABASE %s SURVIVE %s`, dbName, survive)
-	return stmt, nil
-}
-
-func (og *operationGenerator) commentOn(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
-	var onType string
-
-	// COMMENT ON TYPE is only implemented in the declarative schema changer in v24.2.
-	commentOnTypeNotSupported, err := isClusterVersionLessThan(
-		ctx,
-		tx,
-		clusterversion.V24_2.Version())
+	constrained, err := og.constrainedColumns(ctx, tx, tableName, cols)
 	if err != nil {
 		return nil, err
 	}
-	if og.useDeclarativeSchemaChanger && !commentOnTypeNotSupported {
-		onType = `UNION ALL
-	SELECT 'TYPE ' || quote_ident(schema) || '.' || quote_ident(name) FROM [SHOW TYPES]`
+	candidates := util.Filter(cols, func(c column) bool {
+		return !c.generated && !slices.Contains(constrained, c.name)
+	})
+	if len(candidates) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	col := candidates[0]
+	relations, err := og.regClassRelations(ctx, tx, []column{col})
+	if err != nil {
+		return nil, err
 	}
+	value := og.randColumnValue(col, relations)
 
-	q := With([]CTE{
+	// Columns with an ON UPDATE expression are set by every update of a row,
+	// so the constraints on them may still be violated by the update.
+	onUpdateConstrained, err := og.scanBool(ctx, tx, With([]CTE{
 		{"descriptors", descJSONQuery},
-		{"tables", tableDescQuery},
-		{"columns", `SELECT schema_id::REGNAMESPACE::TEXT as schema_name, name AS table_name, jsonb_array_elements(descriptor->'table'->'columns') AS column FROM tables`},
-		{"indexes", `SELECT schema_id::REGNAMESPACE::TEXT as schema_name, name AS table_name, jsonb_array_elements(descriptor->'table'->'indexes') AS index FROM tables`},
-		{"constraints", `SELECT schema_id::REGNAMESPACE::TEXT as schema_name, name AS table_name, jsonb_array_elements(descriptor->'table'->'checks') AS constraint FROM tables`},
-	}, fmt.Sprintf(`
-	SELECT 'SCHEMA ' || quote_ident(schema_name) FROM [SHOW SCHEMAS] WHERE owner != 'node'
-		UNION ALL
-	SELECT 'TABLE ' || quote_ident(schema_name) || '.' || quote_ident(table_name) FROM [SHOW TABLES] WHERE type = 'table'
-		UNION ALL
-	SELECT 'COLUMN ' || quote_ident(schema_name) || '.' || quote_ident(table_name) || '.' || quote_ident("column"->>'name') FROM columns
-		UNION ALL
-	SELECT 'INDEX ' || quote_ident(schema_name) || '.' || quote_ident(table_name) || '@' || quote_ident("index"->>'name') FROM indexes
-		UNION ALL
-	SELECT 'CONSTRAINT ' || quote_ident("constraint"->>'name') || ' ON ' || quote_ident(schema_name) || '.' || quote_ident(table_name) FROM constraints
-		%s`, onType))
-
-	commentables, err := Collect(ctx, og, tx, pgx.RowTo[string], q)
+	}, `SELECT EXISTS(
+			SELECT *
+			FROM descriptors, jsonb_array_elements(descriptor->'table'->'columns') AS col
+			WHERE id = $1::REGCLASS::INT8
+			AND col ? 'onUpdateExpr'
+			AND quote_ident(col->>'name') = ANY ($2::STRING[])
+		)`), tableName.String(), constrained)
 	if err != nil {
 		return nil, err
 	}
-
-	picked, err := PickOne(og.params.rng, commentables)
+	// Constraints and indexes that are still being added are enforced on
+	// writes before they show up in the catalog.
+	hasOngoingSchemaChanges, err := og.tableHasOngoingSchemaChanges(ctx, tx, tableName)
 	if err != nil {
 		return nil, err
 	}
-
-	stmt := makeOpStmt(OpStmtDDL)
-	comment := "comment from the RSW"
-	if og.params.rng.Float64() < 0.3 {
-		// Delete the comment with some probability.
-		comment = ""
+	// Values that are still being added to or dropped from an enum can't be
+	// written.
+	readOnlyEnumValue := false
+	if col.typ.Family() == types.EnumFamily {
+		readOnlyEnumValue, err = og.enumValueIsReadOnly(ctx, tx, col.typ, value)
+		if err != nil {
+			return nil, err
+		}
 	}
-	stmt.sql = fmt.Sprintf(`COMMENT ON %s IS '%s'`, picked, comment)
+
+	stmt := makeOpStmt(OpStmtDML)
+	stmt.expectedExecErrors.addAll(codesWithConditions{
+		{code: pgcode.InvalidParameterValue, condition: readOnlyEnumValue},
+	})
+	stmt.potentialExecErrors.addAll(codesWithConditions{
+		{code: pgcode.CheckViolation, condition: hasOngoingSchemaChanges || onUpdateConstrained},
+		{code: pgcode.NotNullViolation, condition: hasOngoingSchemaChanges},
+		{code: pgcode.UniqueViolation, condition: hasOngoingSchemaChanges || onUpdateConstrained},
+		{code: pgcode.ForeignKeyViolation, condition: onUpdateConstrained},
+		{code: pgcode.DatetimeFieldOverflow, condition: timestampTZNearBounds(value)},
+	})
+	stmt.sql = fmt.Sprintf(`UPDATE %s SET %s = %s LIMIT %d`, tableName, col.name, value, 1+og.randIntn(10))
 	return stmt, nil
 }
 
-func (og *operationGenerator) insertRow(ctx context.Context, tx pgx.Tx) (stmt *opStmt, err error) {
+// deleteRow deletes some rows of a table.
+func (og *operationGenerator) deleteRow(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
 	tableName, err := og.randTable(ctx, tx, og.pctExisting(true), "")
 	if err != nil {
-		return nil, errors.Wrapf(err, "error getting random table name")
+		return nil, err
 	}
 	tableExists, err := og.tableExists(ctx, tx, tableName)
 	if err != nil {
@@ -2720,365 +7985,653 @@ func (og *operationGenerator) insertRow(ctx context.Context, tx pgx.Tx) (stmt *o
 	}
 	if !tableExists {
 		return makeOpStmtForSingleError(OpStmtDML,
-			fmt.Sprintf(
-				`INSERT INTO %s (IrrelevantColumnName) VALUES ("IrrelevantValue")`,
-				tableName,
-			),
+			fmt.Sprintf(`DELETE FROM %s LIMIT 1`, tableName),
 			pgcode.UndefinedTable), nil
 	}
-	allColumns, err := og.getTableColumns(ctx, tx, tableName, false)
-	nonGeneratedCols := allColumns
+
+	// Deleting rows referenced by a foreign key is rejected, unless the
+	// foreign key cascades, in which case the referencing rows may in turn
+	// violate their own constraints.
+	isReferenced, err := og.tableIsReferencedByForeignKeys(ctx, tx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := makeOpStmt(OpStmtDML)
+	stmt.potentialExecErrors.addAll(codesWithConditions{
+		{pgcode.ForeignKeyViolation, isReferenced},
+		{pgcode.NotNullViolation, isReferenced},
+		{pgcode.CheckViolation, isReferenced},
+	})
+	stmt.sql = fmt.Sprintf(`DELETE FROM %s LIMIT %d`, tableName, 1+og.randIntn(10))
+	return stmt, nil
+}
+
+func (og *operationGenerator) validate(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	// Finish validation off by validating multi region zone configs are as expected.
+	// Configs can be invalid if a user decides to override a multi-region field, but
+	// this is not performed by the schemachange workload.
+	validateStmt := makeOpStmt(OpStmtDML)
+	validateStmt.sql = "SELECT 'validating all objects', crdb_internal.validate_multi_region_zone_configs()"
+	rows, err := tx.Query(ctx, `SELECT * FROM "".crdb_internal.invalid_objects ORDER BY id`)
+	if err != nil {
+		return validateStmt, err
+	}
+	defer rows.Close()
+
+	var errs []string
+	for rows.Next() {
+		var id int64
+		var dbName, schemaName, objName, errStr string
+		if err := rows.Scan(&id, &dbName, &schemaName, &objName, &errStr); err != nil {
+			return validateStmt, err
+		}
+		errs = append(
+			errs,
+			fmt.Sprintf("id %d, db %s, schema %s, name %s: %s", id, dbName, schemaName, objName, errStr),
+		)
+	}
+
+	if rows.Err() != nil {
+		return nil, errors.Wrap(rows.Err(), "querying for validation errors failed")
+	}
+	rows.Close()
+
+	indexErrs, err := og.validateIndexKeySuffixColumns(ctx, tx)
+	if err != nil {
+		return validateStmt, err
+	}
+	errs = append(errs, indexErrs...)
+
+	partitioningErrs, err := og.validateRegionalByRowIndexPartitioning(ctx, tx)
+	if err != nil {
+		return validateStmt, err
+	}
+	errs = append(errs, partitioningErrs...)
+
+	windowErrs, err := og.validateViewWindowColumns(ctx, tx)
+	if err != nil {
+		return validateStmt, err
+	}
+	errs = append(errs, windowErrs...)
+
+	ownerErrs, err := og.validateObjectOwners(ctx, tx)
+	if err != nil {
+		return validateStmt, err
+	}
+	errs = append(errs, ownerErrs...)
+
+	reassignmentErrs, err := og.validateOwnerReassignments(ctx, tx)
+	if err != nil {
+		return validateStmt, err
+	}
+	errs = append(errs, reassignmentErrs...)
+
+	sequenceErrs, err := og.validateSequenceOwnership(ctx, tx)
+	if err != nil {
+		return validateStmt, err
+	}
+	errs = append(errs, sequenceErrs...)
+
+	visibilityErrs, err := og.validateInvisibleIndexes(ctx, tx)
 	if err != nil {
-		return nil, errors.Wrapf(err, "error getting table columns for insert row")
+		return validateStmt, err
 	}
+	errs = append(errs, visibilityErrs...)
 
-	// Filter out computed columns.
-	{
-		truncated := nonGeneratedCols[:0]
-		for _, c := range nonGeneratedCols {
-			if !c.generated {
-				truncated = append(truncated, c)
-			}
-		}
-		nonGeneratedCols = truncated
+	fkErrs, err := og.validateForeignKeyReferencedConstraints(ctx, tx)
+	if err != nil {
+		return validateStmt, err
 	}
-	nonGeneratedColNames := []string{}
-	rows := [][]string{}
-	for _, col := range nonGeneratedCols {
-		nonGeneratedColNames = append(nonGeneratedColNames, col.name)
+	errs = append(errs, fkErrs...)
+
+	spatialErrs, err := og.validateSpatialIndexConfigs(ctx, tx)
+	if err != nil {
+		return validateStmt, err
 	}
-	numRows := og.randIntn(3) + 1
-	for i := 0; i < numRows; i++ {
-		var row []string
-		for _, col := range nonGeneratedCols {
-			d := randgen.RandDatum(og.params.rng, col.typ, col.nullable)
-			// Unfortunately, RandDatum for OIDs only selects random values, which will
-			// always fail validation. So, for OIDs we will select a random known type
-			// instead.
-			if col.typ.Family() == types.Oid.Family() {
-				d = tree.NewDOid(randgen.RandColumnType(og.params.rng).Oid())
-			}
-			// We have seen cases where randomly generated ints easily hit an
-			// integer overflow in our workload when we allow large numbers.
-			// Since there is no real advantage to testing such numbers,
-			// limit the largeness by always setting the amount of bits to
-			// 8 (-128 to 127) - ensuring we won't overflow even with the
-			// smallest int (INT2, -32768 to 32767).
-			if col.typ.Family() == types.IntFamily {
-				d = tree.NewDInt(tree.DInt(int8(og.params.rng.Uint64())))
-			}
-			str := tree.AsStringWithFlags(d, tree.FmtParsable)
-			// For strings use the actual type, so that comparisons for NULL values are sane.
-			if col.typ.Family() == types.StringFamily {
-				str = strings.Replace(str, ":::STRING", fmt.Sprintf("::%s", col.typ.SQLString()), -1)
-			}
-			row = append(row, str)
-		}
+	errs = append(errs, spatialErrs...)
 
-		rows = append(rows, row)
+	enumOrderErrs, err := og.validateEnumPlacements(ctx, tx)
+	if err != nil {
+		return validateStmt, err
 	}
-	// Verify that none of the generated expressions will blow up on this insert.
-	anyInvalidInserts := false
-	stmt = makeOpStmt(OpStmtDML)
-	for _, row := range rows {
-		invalidInsert, generatedErrors, potentialErrors, err := og.validateGeneratedExpressionsForInsert(ctx, tx, tableName, nonGeneratedColNames, allColumns, row)
-		if err != nil {
-			return nil, err
-		}
-		// We may have errors that are possible, but not guaranteed.
-		stmt.potentialExecErrors.addAll(potentialErrors)
-		if invalidInsert {
-			stmt.expectedExecErrors.addAll(generatedErrors)
-			// We will be pessimistic and assume that other column related errors can
-			// be hit, since the function above fails only on generated columns. But,
-			// there maybe index expressions with the exact same problem.
-			stmt.potentialExecErrors.add(pgcode.NumericValueOutOfRange)
-			stmt.potentialExecErrors.add(pgcode.FloatingPointException)
-			stmt.potentialExecErrors.add(pgcode.NotNullViolation)
-			anyInvalidInserts = true
-		}
+	errs = append(errs, enumOrderErrs...)
+
+	if len(errs) == 0 {
+		return validateStmt, nil
 	}
+	return validateStmt, errors.Errorf("Validation FAIL:\n%s", strings.Join(errs, "\n"))
+}
 
-	// Only evaluate these if we know that the inserted values are sane, since
-	// we will need to evaluate generated expressions below.
-	uniqueConstraintViolation := false
-	fkViolation := false
-	if !anyInvalidInserts {
-		// Verify if the new row will violate unique constraints by checking the constraints and
-		// existing rows in the database.
-		var generatedErrors codesWithConditions
-		uniqueConstraintViolation, generatedErrors, err = og.valuesViolateUniqueConstraints(ctx, tx, tableName, nonGeneratedColNames, allColumns, rows)
-		if err != nil {
-			return nil, err
-		}
-		if !uniqueConstraintViolation {
-			stmt.expectedExecErrors.addAll(generatedErrors)
-		}
-		// Verify if the new row will violate fk constraints by checking the constraints and rows
-		// in the database.
-		fkViolation, err = og.violatesFkConstraints(ctx, tx, tableName, nonGeneratedColNames, rows)
-		if err != nil {
-			return nil, err
-		}
+// validateIndexKeySuffixColumns reconciles the implicit columns of every
+// secondary index against the primary key of its table. Indexes using the
+// secondary index encoding must be suffixed with exactly those primary key
+// columns which are not already part of the index key, in primary key order.
+func (og *operationGenerator) validateIndexKeySuffixColumns(
+	ctx context.Context, tx pgx.Tx,
+) ([]string, error) {
+	type indexColumns struct {
+		TableName        string
+		IndexName        string
+		PrimaryKey       []int64
+		KeyColumns       []int64
+		KeySuffixColumns []int64
 	}
 
-	stmt.expectedExecErrors.addAll(codesWithConditions{
-		{code: pgcode.UniqueViolation, condition: uniqueConstraintViolation},
-	})
-	stmt.potentialExecErrors.addAll(codesWithConditions{
-		{code: pgcode.ForeignKeyViolation, condition: fkViolation},
-	})
-	og.expectedCommitErrors.addAll(codesWithConditions{
-		{code: pgcode.ForeignKeyViolation, condition: fkViolation},
-	})
+	query := With([]CTE{
+		{"descriptors", descJSONQuery},
+		{"tables", tableDescQuery},
+		{"indexes", `SELECT schema_id, name AS table_name, descriptor->'table'->'primaryIndex' AS primary_index, jsonb_array_elements(descriptor->'table'->'indexes') AS index FROM tables`},
+	}, `SELECT
+			quote_ident(schema_id::REGNAMESPACE::TEXT) || '.' || quote_ident(table_name),
+			index->>'name',
+			ARRAY(SELECT jsonb_array_elements_text(primary_index->'keyColumnIds')::INT8),
+			ARRAY(SELECT jsonb_array_elements_text(COALESCE(index->'keyColumnIds', '[]'::JSONB))::INT8),
+			ARRAY(SELECT jsonb_array_elements_text(COALESCE(index->'keySuffixColumnIds', '[]'::JSONB))::INT8)
+		FROM indexes
+		WHERE COALESCE(index->>'encodingType', 'SecondaryIndexEncoding') = 'SecondaryIndexEncoding'
+	`)
 
-	var formattedRows []string
-	for _, row := range rows {
-		formattedRows = append(formattedRows, fmt.Sprintf("(%s)", strings.Join(row, ",")))
+	indexes, err := Collect(ctx, og, tx, pgx.RowToStructByPos[indexColumns], query)
+	if err != nil {
+		return nil, og.checkAndAdjustForUnknownSchemaErrors(err)
 	}
 
-	stmt.sql = fmt.Sprintf(
-		`INSERT INTO %s (%s) VALUES %s`,
-		tableName,
-		strings.Join(nonGeneratedColNames, ","),
-		strings.Join(formattedRows, ","),
-	)
-	return stmt, nil
+	var errs []string
+	for _, index := range indexes {
+		expected := expectedKeySuffixColumns(index.PrimaryKey, index.KeyColumns)
+		if !slices.Equal(expected, index.KeySuffixColumns) {
+			errs = append(errs, fmt.Sprintf(
+				"table %s, index %s: expected key suffix columns %v, found %v",
+				index.TableName, index.IndexName, expected, index.KeySuffixColumns,
+			))
+		}
+	}
+	return errs, nil
 }
 
-type opStmtType int
-
-const (
-	// OpStmtDDL statement is a data definition language statement.
-	OpStmtDDL opStmtType = 1
-	// OpStmtDML statement is a data manipulation language statement.
-	OpStmtDML opStmtType = 2
-)
+// validateRegionalByRowIndexPartitioning verifies that every index of a
+// REGIONAL BY ROW table, including the indexes backing unique constraints, is
+// partitioned by the region column of the table. Unless the region column was
+// explicitly included as the first column of the index, the partitioning is
+// implicit. Tables with ongoing schema changes are skipped, as their locality
+// may be in the process of changing.
+func (og *operationGenerator) validateRegionalByRowIndexPartitioning(
+	ctx context.Context, tx pgx.Tx,
+) ([]string, error) {
+	type indexPartitioning struct {
+		TableName          string
+		IndexName          string
+		RegionColumn       string
+		FirstKeyColumn     string
+		NumPartitionedCols int64
+	}
 
-type opStmtQueryResultCallback func(ctx context.Context, rows pgx.Rows) error
+	query := With([]CTE{
+		{"descriptors", descJSONQuery},
+		{"tables", tableDescQuery},
+		{"indexes", `SELECT
+				schema_id,
+				name AS table_name,
+				descriptor->'table'->'localityConfig'->'regionalByRow'->>'as' AS region_column,
+				jsonb_array_elements(
+					jsonb_build_array(descriptor->'table'->'primaryIndex') || COALESCE(descriptor->'table'->'indexes', '[]'::JSONB)
+				) AS index
+			FROM tables
+			WHERE descriptor->'table'->'localityConfig' ? 'regionalByRow'
+			AND COALESCE(jsonb_array_length(descriptor->'table'->'mutations'), 0) = 0`},
+	}, fmt.Sprintf(`SELECT
+			quote_ident(schema_id::REGNAMESPACE::TEXT) || '.' || quote_ident(table_name),
+			index->>'name',
+			COALESCE(region_column, '%s'),
+			COALESCE(index->'keyColumnNames'->>0, ''),
+			COALESCE((index->'partitioning'->>'numColumns')::INT8, 0)
+		FROM indexes
+	`, tree.RegionalByRowRegionDefaultCol))
+
+	indexes, err := Collect(ctx, og, tx, pgx.RowToStructByPos[indexPartitioning], query)
+	if err != nil {
+		return nil, og.checkAndAdjustForUnknownSchemaErrors(err)
+	}
 
-// opStmt a generated statement that is either DDL or DML, including the potential
-// set of execution errors this statement can generate.
-type opStmt struct {
-	// sql the query being executed.
-	sql string
-	// queryType family of the query type being executed (DML or DDL).
-	queryType opStmtType
-	// expectedExecErrors expected set of execution errors.
-	expectedExecErrors errorCodeSet
-	// potentialExecErrors errors that could be potentially seen on execution.
-	potentialExecErrors errorCodeSet
-	queryResultCallback opStmtQueryResultCallback
+	var errs []string
+	for _, index := range indexes {
+		if index.FirstKeyColumn != index.RegionColumn || index.NumPartitionedCols != 1 {
+			errs = append(errs, fmt.Sprintf(
+				"table %s, index %s: expected partitioning by region column %s, found first key column %s partitioned by %d column(s)",
+				index.TableName, index.IndexName, index.RegionColumn, index.FirstKeyColumn, index.NumPartitionedCols,
+			))
+		}
+	}
+	return errs, nil
 }
 
-// String implements Stringer
-func (s *opStmt) String() string {
-	return fmt.Sprintf("QUERY: %s, Expected Errors: %s, Potential Errors: %s",
-		s.sql,
-		s.expectedExecErrors,
-		s.potentialExecErrors)
-}
+// validateViewWindowColumns checks the output columns that views derive from
+// the window functions generated by createView. They are all ranking
+// functions, which return either an INT8 or a FLOAT8.
+func (og *operationGenerator) validateViewWindowColumns(
+	ctx context.Context, tx pgx.Tx,
+) ([]string, error) {
+	type viewColumn struct {
+		ViewName   string
+		ColumnName string
+		Family     string
+	}
 
-func (s *opStmt) MarshalJSON() ([]byte, error) {
-	return json.Marshal(&struct {
-		SQL              string `json:"sql"`
-		ExpectedExecErr  string `json:"expectedExecErr,omitempty"`
-		PotentialExecErr string `json:"potentialExecErr,omitempty"`
-	}{
-		SQL:              s.sql,
-		ExpectedExecErr:  s.expectedExecErrors.String(),
-		PotentialExecErr: s.potentialExecErrors.String(),
-	})
-}
+	query := With([]CTE{
+		{"descriptors", descJSONQuery},
+	}, `SELECT
+			quote_ident(schema_id::REGNAMESPACE::TEXT) || '.' || quote_ident(name),
+			col->>'name',
+			COALESCE(col->'type'->>'family', '')
+		FROM descriptors, jsonb_array_elements(descriptor->'table'->'columns') AS col
+		WHERE descriptor->'table' ? 'viewQuery'
+		AND col->>'name' LIKE 'window\_%'
+	`)
 
-// makeOpStmtForSingleError constructs a statement that will only produce
-// an error.
-func makeOpStmtForSingleError(queryType opStmtType, sql string, codes ...pgcode.Code) *opStmt {
-	s := makeOpStmt(queryType)
-	s.sql = sql
-	for _, code := range codes {
-		s.expectedExecErrors.add(code)
+	columns, err := Collect(ctx, og, tx, pgx.RowToStructByPos[viewColumn], query)
+	if err != nil {
+		return nil, og.checkAndAdjustForUnknownSchemaErrors(err)
 	}
-	return s
-}
 
-// makeOpStmt constructs an empty operation of a given type.
-func makeOpStmt(queryType opStmtType) *opStmt {
-	return &opStmt{
-		queryType:           queryType,
-		expectedExecErrors:  makeExpectedErrorSet(),
-		potentialExecErrors: makeExpectedErrorSet(),
+	var errs []string
+	for _, col := range columns {
+		if col.Family != "IntFamily" && col.Family != "FloatFamily" {
+			errs = append(errs, fmt.Sprintf(
+				"view %s, column %s: expected a window function result of type INT8 or FLOAT8, found %s",
+				col.ViewName, col.ColumnName, col.Family,
+			))
+		}
 	}
+	return errs, nil
 }
 
-// opStmtFromTree constructs an operation from the provide tree.Statement.
-//
-//lint:ignore U1000 Used in future commits. TODO(chrisseto): Remove the ignore.
-func newOpStmt(stmt tree.Statement, expectedExecErrors codesWithConditions) *opStmt {
-	var queryType opStmtType
-	switch stmt.StatementType() {
-	case tree.TypeDDL:
-		queryType = OpStmtDDL
-	case tree.TypeDML:
-		queryType = OpStmtDML
-	default:
-		panic("unhandled statement type")
+// validateObjectOwners checks that every object of the current database is
+// owned by a role that exists, which REASSIGN OWNED BY and DROP ROLE must
+// preserve.
+func (og *operationGenerator) validateObjectOwners(
+	ctx context.Context, tx pgx.Tx,
+) ([]string, error) {
+	type objectOwner struct {
+		ID    int64
+		Name  string
+		Owner string
 	}
 
-	expectedErrors := makeExpectedErrorSet()
-	expectedErrors.addAll(expectedExecErrors)
+	query := With([]CTE{
+		{"descriptors", descJSONQuery},
+		{"owners", `SELECT
+				id,
+				name,
+				(json_each(descriptor)).@2->'privileges'->>'ownerProto' AS owner
+			FROM descriptors`},
+	}, `SELECT id, name, owner
+		FROM owners
+		WHERE owner IS NOT NULL
+		AND owner NOT IN (SELECT username FROM system.users)
+	`)
 
-	return &opStmt{
-		sql:                 tree.Serialize(stmt),
-		queryType:           queryType,
-		expectedExecErrors:  expectedErrors,
-		potentialExecErrors: makeExpectedErrorSet(),
+	owners, err := Collect(ctx, og, tx, pgx.RowToStructByPos[objectOwner], query)
+	if err != nil {
+		return nil, og.checkAndAdjustForUnknownSchemaErrors(err)
 	}
-}
 
-// ErrorState wraps schemachange workload errors to have state information for
-// the purpose of dumping in our JSON log.
-type ErrorState struct {
-	cause                        error
-	ExpectedErrors               []string      `json:"expectedErrors,omitempty"`
-	PotentialErrors              []string      `json:"potentialErrors,omitempty"`
-	ExpectedCommitErrors         []string      `json:"expectedCommitErrors,omitempty"`
-	PotentialCommitErrors        []string      `json:"potentialCommitErrors,omitempty"`
-	QueriesForGeneratingErrors   []interface{} `json:"queriesForGeneratingErrors,omitempty"`
-	PreviousStatements           []string      `json:"previousStatements,omitempty"`
-	UsesDeclarativeSchemaChanger bool          `json:"usesDeclarativeSchemaChanger,omitempty"`
+	var errs []string
+	for _, o := range owners {
+		errs = append(errs, fmt.Sprintf(
+			"object %s (%d): owned by role %s, which does not exist", o.Name, o.ID, o.Owner,
+		))
+	}
+	return errs, nil
 }
 
-func (es *ErrorState) Unwrap() error {
-	return es.cause
-}
+// validateOwnerReassignments checks that the objects committed by
+// reassignOwnedBy are owned by their new owner. Only the objects whose
+// descriptor is still at the version written by the reassignment are checked,
+// and the others are forgotten, since their owner may have changed since.
+func (og *operationGenerator) validateOwnerReassignments(
+	ctx context.Context, tx pgx.Tx,
+) ([]string, error) {
+	if len(og.ownerReassignments) == 0 {
+		return nil, nil
+	}
 
-func (es *ErrorState) Error() string {
-	return es.cause.Error()
+	type objectOwner struct {
+		ID      int64
+		Name    string
+		Version int64
+		Owner   string
+	}
+
+	owners, err := Collect(ctx, og, tx, pgx.RowToStructByPos[objectOwner], With([]CTE{
+		{"descriptors", descJSONQuery},
+		{"objects", `SELECT id, name, (json_each(descriptor)).@2 AS object FROM descriptors`},
+	}, `SELECT id, name, (object->>'version')::INT8, object->'privileges'->>'ownerProto'
+		FROM objects
+		WHERE id = ANY ($1::INT8[])
+	`), util.Map(og.ownerReassignments, func(r ownerReassignment) int64 { return r.id }))
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]objectOwner, len(owners))
+	for _, o := range owners {
+		byID[o.ID] = o
+	}
+
+	var errs []string
+	reassignments := og.ownerReassignments[:0]
+	for _, r := range og.ownerReassignments {
+		o, ok := byID[r.id]
+		if !ok || o.Version != r.version+1 {
+			continue
+		}
+		reassignments = append(reassignments, r)
+		if o.Owner != r.owner {
+			errs = append(errs, fmt.Sprintf(
+				"object %s (%d): reassigned to role %s, but owned by role %s", o.Name, o.ID, r.owner, o.Owner,
+			))
+		}
+	}
+	og.ownerReassignments = reassignments
+	return errs, nil
 }
 
-// WrapWithErrorState dumps the object state when an error is hit
-func (og *operationGenerator) WrapWithErrorState(err error, op *opStmt) error {
-	previousStmts := make([]string, 0, len(og.stmtsInTxt))
-	for _, stmt := range og.stmtsInTxt {
-		previousStmts = append(previousStmts, stmt.sql)
+// validateInvisibleIndexes checks that every index that is not fully visible
+// is still listed by crdb_internal.table_indexes, and reported as not visible
+// there. Partially visible indexes only have their invisibility set. Invisible indexes are ignored by the optimizer but remain part of
+// the schema.
+func (og *operationGenerator) validateInvisibleIndexes(
+	ctx context.Context, tx pgx.Tx,
+) ([]string, error) {
+	type invisibleIndex struct {
+		TableName string
+		IndexName string
+		Listed    bool
 	}
-	return &ErrorState{
-		cause:                        err,
-		ExpectedErrors:               op.expectedExecErrors.StringSlice(),
-		PotentialErrors:              op.potentialExecErrors.StringSlice(),
-		ExpectedCommitErrors:         og.expectedCommitErrors.StringSlice(),
-		PotentialCommitErrors:        og.potentialCommitErrors.StringSlice(),
-		QueriesForGeneratingErrors:   og.GetOpGenLog(),
-		PreviousStatements:           previousStmts,
-		UsesDeclarativeSchemaChanger: og.useDeclarativeSchemaChanger,
+
+	query := With([]CTE{
+		{"descriptors", descJSONQuery},
+	}, `SELECT
+			quote_ident(schema_id::REGNAMESPACE::TEXT) || '.' || quote_ident(name),
+			idx->>'name',
+			ti.index_id IS NOT NULL
+		FROM descriptors
+		JOIN jsonb_array_elements(COALESCE(descriptor->'table'->'indexes', '[]'::JSONB)) AS idx ON true
+		LEFT JOIN crdb_internal.table_indexes AS ti
+			ON ti.descriptor_id = descriptors.id
+			AND ti.index_id = (idx->>'id')::INT8
+			AND NOT ti.is_visible
+		WHERE COALESCE((idx->>'notVisible')::BOOL, false)
+			OR COALESCE((idx->>'invisibility')::FLOAT8, 0) > 0
+	`)
+
+	indexes, err := Collect(ctx, og, tx, pgx.RowToStructByPos[invisibleIndex], query)
+	if err != nil {
+		return nil, og.checkAndAdjustForUnknownSchemaErrors(err)
+	}
+
+	var errs []string
+	for _, idx := range indexes {
+		if !idx.Listed {
+			errs = append(errs, fmt.Sprintf(
+				"index %s@%s: not visible, but missing from crdb_internal.table_indexes or listed as visible",
+				idx.TableName, idx.IndexName,
+			))
+		}
 	}
+	return errs, nil
 }
 
-// executeStmt executes the given operation statement, and validates the result
-// of the execution. Note: Commit time failures will be handled separately from
-// statement specific logic.
-func (s *opStmt) executeStmt(ctx context.Context, tx pgx.Tx, og *operationGenerator) error {
-	var err error
-	var rows pgx.Rows
-	// Statement doesn't produce any result set that needs to be validated.
-	if s.queryResultCallback == nil {
-		_, err = tx.Exec(ctx, s.sql)
-	} else {
-		rows, err = tx.Query(ctx, s.sql)
+// validateEnumPlacements checks that the enum values committed by addTypeValue
+// with BEFORE or AFTER are still ordered that way relative to their neighbor
+// in pg_enum. Values added by other workers may have been placed between the
+// two since. Placements of which the type or either value no longer exists are
+// forgotten, as enum values are never reused once they are renamed or dropped.
+func (og *operationGenerator) validateEnumPlacements(
+	ctx context.Context, tx pgx.Tx,
+) ([]string, error) {
+	if len(og.enumPlacements) == 0 {
+		return nil, nil
+	}
+
+	type enumLabel struct {
+		TypeName  string
+		Label     string
+		SortOrder float64
 	}
+
+	labels, err := Collect(ctx, og, tx, pgx.RowToStructByPos[enumLabel], `SELECT
+			quote_ident(n.nspname) || '.' || quote_ident(t.typname),
+			quote_literal(e.enumlabel),
+			e.enumsortorder
+		FROM pg_catalog.pg_enum AS e
+		JOIN pg_catalog.pg_type AS t ON t.oid = e.enumtypid
+		JOIN pg_catalog.pg_namespace AS n ON n.oid = t.typnamespace
+	`)
 	if err != nil {
-		// If the error not an instance of pgconn.PgError, then it is unexpected.
-		pgErr := new(pgconn.PgError)
-		if !errors.As(err, &pgErr) {
-			// Connections dropping with at the server side can be treated
-			// as rollback errors here.
-			if errors.Is(err, io.ErrUnexpectedEOF) {
-				return errors.Mark(err,
-					errRunInTxnRbkSentinel)
-			}
-			return errors.Mark(
-				og.WrapWithErrorState(errors.Wrap(err, "***UNEXPECTED ERROR; Received a non pg error."), s),
-				errRunInTxnFatalSentinel,
-			)
-		}
-		if pgcode.MakeCode(pgErr.Code) == pgcode.SerializationFailure {
-			return err
+		return nil, err
+	}
+
+	type typeLabel struct{ typeName, label string }
+	sortOrders := make(map[typeLabel]float64, len(labels))
+	for _, l := range labels {
+		sortOrders[typeLabel{l.TypeName, l.Label}] = l.SortOrder
+	}
+
+	var errs []string
+	placements := og.enumPlacements[:0]
+	for _, p := range og.enumPlacements {
+		value, ok := sortOrders[typeLabel{p.typeName, p.value}]
+		if !ok {
+			continue
 		}
-		// TODO(fqazi): For the short term we are going to ignore any not implemented,
-		// errors in the declarative schema changer. Supported operations have edge
-		// cases, but later we should mark some of these are fully supported.
-		if og.useDeclarativeSchemaChanger && pgcode.MakeCode(pgErr.Code) == pgcode.Uncategorized &&
-			strings.Contains(pgErr.Message, " not implemented in the new schema changer") {
-			return errors.Mark(errors.Wrap(err, "ROLLBACK; Ignoring declarative schema changer not implemented error."),
-				errRunInTxnRbkSentinel,
-			)
+		neighbor, ok := sortOrders[typeLabel{p.typeName, p.neighbor}]
+		if !ok {
+			continue
 		}
-		if !s.expectedExecErrors.contains(pgcode.MakeCode(pgErr.Code)) &&
-			!s.potentialExecErrors.contains(pgcode.MakeCode(pgErr.Code)) {
-			return errors.Mark(
-				og.WrapWithErrorState(errors.Wrap(err, "***UNEXPECTED ERROR; Received an unexpected execution error."),
-					s),
-				errRunInTxnFatalSentinel,
-			)
+		placements = append(placements, p)
+		if p.before && value >= neighbor {
+			errs = append(errs, fmt.Sprintf(
+				"enum %s: value %s was added BEFORE %s, but is sorted after it", p.typeName, p.value, p.neighbor,
+			))
+		} else if !p.before && value <= neighbor {
+			errs = append(errs, fmt.Sprintf(
+				"enum %s: value %s was added AFTER %s, but is sorted before it", p.typeName, p.value, p.neighbor,
+			))
 		}
-		return errors.Mark(errors.Wrap(err, "ROLLBACK; Successfully got expected execution error."),
-			errRunInTxnRbkSentinel,
-		)
 	}
-	if !s.expectedExecErrors.empty() {
-		// Clean up the result set, if we didn't encounter an expected error.
-		if rows != nil {
-			rows.Close()
-		}
-		return errors.Mark(
-			og.WrapWithErrorState(errors.New("***FAIL; Failed to receive an execution error when errors were expected"),
-				s),
-			errRunInTxnFatalSentinel,
+	og.enumPlacements = placements
+	return errs, nil
+}
+
+// validateSequenceOwnership checks that every sequence owned by a column is
+// also recorded as owned by that column, so that dropping the column or its
+// table drops the sequence with it. A sequence left behind by such a drop
+// refers to an owner that no longer exists. Columns that are being added or
+// dropped are still considered owners.
+func (og *operationGenerator) validateSequenceOwnership(
+	ctx context.Context, tx pgx.Tx,
+) ([]string, error) {
+	type sequenceOwner struct {
+		SequenceName string
+		OwnerTableID int64
+		OwnerColumn  int64
+	}
+
+	query := With([]CTE{
+		{"descriptors", descJSONQuery},
+		{"owners", `SELECT
+				id,
+				quote_ident(schema_id::REGNAMESPACE::TEXT) || '.' || quote_ident(name) AS name,
+				(descriptor->'table'->'sequenceOpts'->'sequenceOwner'->>'ownerTableId')::INT8 AS owner_table_id,
+				(descriptor->'table'->'sequenceOpts'->'sequenceOwner'->>'ownerColumnId')::INT8 AS owner_column_id
+			FROM descriptors
+			WHERE descriptor->'table' ? 'sequenceOpts'`},
+		// The owner may live in another database, so it is looked up in
+		// system.descriptor rather than in descriptors.
+		{"owner_tables", `SELECT
+				id,
+				crdb_internal.pb_to_json('desc', descriptor)->'table' AS tbl
+			FROM system.descriptor
+			WHERE id IN (SELECT owner_table_id FROM owners)`},
+	}, `SELECT name, owner_table_id, owner_column_id
+		FROM owners
+		WHERE COALESCE(owner_table_id, 0) != 0
+		AND NOT EXISTS(
+			SELECT *
+			FROM owner_tables AS t,
+				jsonb_array_elements(
+					COALESCE(t.tbl->'columns', '[]'::JSONB) || COALESCE((
+						SELECT jsonb_agg(m->'column')
+						FROM jsonb_array_elements(t.tbl->'mutations') AS m
+						WHERE m ? 'column'
+					), '[]'::JSONB)
+				) AS col
+			WHERE t.id = owners.owner_table_id
+			AND COALESCE(t.tbl->>'state', 'PUBLIC') != 'DROP'
+			AND (col->>'id')::INT8 = owners.owner_column_id
+			AND COALESCE(col->'ownsSequenceIds', '[]'::JSONB) @> jsonb_build_array(owners.id)
 		)
+	`)
+
+	owners, err := Collect(ctx, og, tx, pgx.RowToStructByPos[sequenceOwner], query)
+	if err != nil {
+		return nil, og.checkAndAdjustForUnknownSchemaErrors(err)
 	}
-	// Next validate the result set.
-	if s.queryResultCallback != nil {
-		if err := s.queryResultCallback(ctx, rows); err != nil {
-			return err
-		}
+
+	var errs []string
+	for _, o := range owners {
+		errs = append(errs, fmt.Sprintf(
+			"sequence %s: owned by column %d of table %d, which does not own it",
+			o.SequenceName, o.OwnerColumn, o.OwnerTableID,
+		))
 	}
-	return nil
+	return errs, nil
 }
 
-func (og *operationGenerator) validate(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
-	// Finish validation off by validating multi region zone configs are as expected.
-	// Configs can be invalid if a user decides to override a multi-region field, but
-	// this is not performed by the schemachange workload.
-	validateStmt := makeOpStmt(OpStmtDML)
-	validateStmt.sql = "SELECT 'validating all objects', crdb_internal.validate_multi_region_zone_configs()"
-	rows, err := tx.Query(ctx, `SELECT * FROM "".crdb_internal.invalid_objects ORDER BY id`)
+// validateForeignKeyReferencedConstraints checks that the referenced columns
+// of every foreign key are exactly the columns of the primary key or of a
+// UNIQUE constraint of the referenced table. Foreign keys may reference
+// either, and the referenced constraint cannot be dropped while the foreign
+// key exists.
+func (og *operationGenerator) validateForeignKeyReferencedConstraints(
+	ctx context.Context, tx pgx.Tx,
+) ([]string, error) {
+	type foreignKey struct {
+		TableName           string
+		ConstraintName      string
+		ReferencedTableName string
+	}
+
+	const query = `SELECT
+			fk.conrelid::REGCLASS::STRING,
+			fk.conname,
+			fk.confrelid::REGCLASS::STRING
+		FROM pg_catalog.pg_constraint AS fk
+		WHERE fk.contype = 'f'
+			AND NOT EXISTS(
+				SELECT 1
+					FROM pg_catalog.pg_constraint AS uc
+				 WHERE uc.conrelid = fk.confrelid
+					 AND uc.contype IN ('p', 'u')
+					 AND ARRAY(SELECT unnest(uc.conkey) AS k ORDER BY k) = ARRAY(SELECT unnest(fk.confkey) AS k ORDER BY k)
+			)
+	`
+
+	fks, err := Collect(ctx, og, tx, pgx.RowToStructByPos[foreignKey], query)
 	if err != nil {
-		return validateStmt, err
+		return nil, og.checkAndAdjustForUnknownSchemaErrors(err)
 	}
-	defer rows.Close()
 
 	var errs []string
-	for rows.Next() {
-		var id int64
-		var dbName, schemaName, objName, errStr string
-		if err := rows.Scan(&id, &dbName, &schemaName, &objName, &errStr); err != nil {
-			return validateStmt, err
-		}
-		errs = append(
-			errs,
-			fmt.Sprintf("id %d, db %s, schema %s, name %s: %s", id, dbName, schemaName, objName, errStr),
-		)
+	for _, fk := range fks {
+		errs = append(errs, fmt.Sprintf(
+			"foreign key %s on %s: referenced columns of %s are not covered by its primary key or a unique constraint",
+			fk.ConstraintName, fk.TableName, fk.ReferencedTableName,
+		))
 	}
+	return errs, nil
+}
 
-	if rows.Err() != nil {
-		return nil, errors.Wrap(rows.Err(), "querying for validation errors failed")
+// validateSpatialIndexConfigs checks that the S2 configuration of every
+// spatial index is one that storage parameters could have set: its levels and
+// cells are in range, its maximum level is a multiple of its level mod and,
+// for GEOMETRY indexes, its bounds are not empty.
+func (og *operationGenerator) validateSpatialIndexConfigs(
+	ctx context.Context, tx pgx.Tx,
+) ([]string, error) {
+	type spatialIndex struct {
+		TableName  string
+		IndexName  string
+		IsGeometry bool
+		MaxLevel   int64
+		LevelMod   int64
+		MaxCells   int64
+		MinX       float64
+		MaxX       float64
+		MinY       float64
+		MaxY       float64
 	}
 
-	if len(errs) == 0 {
-		return validateStmt, nil
+	query := With([]CTE{
+		{"descriptors", descJSONQuery},
+		{"spatial_indexes", `SELECT
+				quote_ident(schema_id::REGNAMESPACE::TEXT) || '.' || quote_ident(name) AS table_name,
+				idx->>'name' AS index_name,
+				idx->'geoConfig' ? 's2Geometry' AS is_geometry,
+				COALESCE(idx->'geoConfig'->'s2Geometry', idx->'geoConfig'->'s2Geography') AS cfg
+			FROM descriptors
+			JOIN jsonb_array_elements(COALESCE(descriptor->'table'->'indexes', '[]'::JSONB)) AS idx ON true`},
+	}, `SELECT
+			table_name,
+			index_name,
+			is_geometry,
+			COALESCE((cfg->'s2Config'->>'maxLevel')::INT8, 0),
+			COALESCE((cfg->'s2Config'->>'levelMod')::INT8, 0),
+			COALESCE((cfg->'s2Config'->>'maxCells')::INT8, 0),
+			COALESCE((cfg->>'minX')::FLOAT8, 0),
+			COALESCE((cfg->>'maxX')::FLOAT8, 0),
+			COALESCE((cfg->>'minY')::FLOAT8, 0),
+			COALESCE((cfg->>'maxY')::FLOAT8, 0)
+		FROM spatial_indexes
+		WHERE cfg IS NOT NULL
+	`)
+
+	indexes, err := Collect(ctx, og, tx, pgx.RowToStructByPos[spatialIndex], query)
+	if err != nil {
+		return nil, og.checkAndAdjustForUnknownSchemaErrors(err)
 	}
-	return validateStmt, errors.Errorf("Validation FAIL:\n%s", strings.Join(errs, "\n"))
+
+	var errs []string
+	for _, idx := range indexes {
+		switch {
+		case idx.LevelMod < 1 || idx.LevelMod > 3:
+			errs = append(errs, fmt.Sprintf("index %s@%s: s2_level_mod %d out of range",
+				idx.TableName, idx.IndexName, idx.LevelMod))
+		case idx.MaxLevel < 0 || idx.MaxLevel > 30 || idx.MaxLevel%idx.LevelMod != 0:
+			errs = append(errs, fmt.Sprintf("index %s@%s: invalid s2_max_level %d with s2_level_mod %d",
+				idx.TableName, idx.IndexName, idx.MaxLevel, idx.LevelMod))
+		case idx.MaxCells < 1 || idx.MaxCells > 32:
+			errs = append(errs, fmt.Sprintf("index %s@%s: s2_max_cells %d out of range",
+				idx.TableName, idx.IndexName, idx.MaxCells))
+		case idx.IsGeometry && (idx.MaxX <= idx.MinX || idx.MaxY <= idx.MinY):
+			errs = append(errs, fmt.Sprintf("index %s@%s: empty geometry bounds [%f, %f] x [%f, %f]",
+				idx.TableName, idx.IndexName, idx.MinX, idx.MaxX, idx.MinY, idx.MaxY))
+		}
+	}
+	return errs, nil
+}
+
+// expectedKeySuffixColumns returns the primary key columns that a secondary
+// index with the given key columns implicitly includes.
+func expectedKeySuffixColumns(primaryKey, keyColumns []int64) []int64 {
+	var suffix []int64
+	for _, colID := range primaryKey {
+		if !slices.Contains(keyColumns, colID) {
+			suffix = append(suffix, colID)
+		}
+	}
+	return suffix
 }
 
 type column struct {
@@ -3087,6 +8640,7 @@ type column struct {
 	nullable            bool
 	generated           bool
 	generatedExpression string
+	defaultExpression   string
 	ordinal             int
 }
 
@@ -3116,6 +8670,7 @@ func (og *operationGenerator) getTableColumns(
          show_columns.is_nullable,
          columns.generation_expression IS NOT NULL AS is_generated,
          COALESCE(columns.generation_expression, '') AS generated_expression,
+         COALESCE(show_columns.column_default, '') AS default_expression,
 			   columns.ordinal::INT-1
     FROM [SHOW COLUMNS FROM %s] AS show_columns, columns
    WHERE show_columns.column_name != 'rowid'
@@ -3132,7 +8687,7 @@ func (og *operationGenerator) getTableColumns(
 	for rows.Next() {
 		var c column
 		var typName string
-		err := rows.Scan(&c.name, &typName, &c.nullable, &c.generated, &c.generatedExpression, &c.ordinal)
+		err := rows.Scan(&c.name, &typName, &c.nullable, &c.generated, &c.generatedExpression, &c.defaultExpression, &c.ordinal)
 		if err != nil {
 			return nil, err
 		}
@@ -3233,76 +8788,170 @@ ORDER BY random()
 	return col, nil
 }
 
-// randChildColumnForFkRelation gets a column to use as the child column in a foreign key relation.
-// To successfully use a column as the child, the column must have the same type as the parent and must not be computed.
-func (og *operationGenerator) randChildColumnForFkRelation(
-	ctx context.Context, tx pgx.Tx, isNotComputed bool, typ string,
-) (*tree.TableName, *column, error) {
-
+// randChildColumnsForFkRelation gets the columns to use as the child columns
+// in a foreign key relation, one for each of the given types, all of them in
+// the same table. To successfully use the columns as the children, they must
+// have the same types as the parent columns and must not be computed. If table
+// is not nil, the columns are picked from that table. If no table has a
+// distinct column for every type, pgx.ErrNoRows is returned.
+func (og *operationGenerator) randChildColumnsForFkRelation(
+	ctx context.Context, tx pgx.Tx, isNotComputed bool, typs []string, table *tree.TableName,
+) (*tree.TableName, []*column, error) {
+	if err := og.setSeedInDB(ctx, tx); err != nil {
+		return nil, nil, err
+	}
 	query := strings.Builder{}
 	query.WriteString(`
-    SELECT table_schema, table_name, column_name, crdb_sql_type, is_nullable
+    SELECT table_schema, table_name, column_name, crdb_sql_type, is_nullable = 'YES',
+           COALESCE(column_default, '')
       FROM information_schema.columns
 		 WHERE table_name SIMILAR TO 'table_w[0-9]_+%' AND column_name <> 'rowid'
+		   AND crdb_sql_type = ANY ($1::STRING[])
   `)
-	query.WriteString(fmt.Sprintf(`
-			AND crdb_sql_type = '%s'
-	`, typ))
-
 	if isNotComputed {
 		query.WriteString(`AND is_generated = 'NEVER'`)
 	} else {
 		query.WriteString(`AND is_generated = 'ALWAYS'`)
 	}
+	args := []any{typs}
+	if table != nil {
+		query.WriteString(`
+		   AND table_schema = $2 AND table_name = $3`)
+		args = append(args, table.Schema(), table.Object())
+	}
+	query.WriteString(`
+	ORDER BY random()`)
 
-	var tableSchema string
-	var tableName string
-	var columnName string
-	var typName string
-	var nullable string
-
-	err := tx.QueryRow(ctx, query.String()).Scan(&tableSchema, &tableName, &columnName, &typName, &nullable)
+	type childColumn struct {
+		TableSchema       string
+		TableName         string
+		ColumnName        string
+		TypeName          string
+		Nullable          bool
+		DefaultExpression string
+	}
+	candidates, err := Collect(ctx, og, tx, pgx.RowToStructByPos[childColumn], query.String(), args...)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	columnToReturn := column{
-		name:     columnName,
-		nullable: nullable == "YES",
+	// Tables are tried in the random order in which their columns were
+	// returned, and the first one with a distinct column for every type is
+	// used.
+	var tables []tree.TableName
+	candidatesByTable := make(map[tree.TableName][]childColumn)
+	for _, c := range candidates {
+		table := tree.MakeTableNameFromPrefix(tree.ObjectNamePrefix{
+			SchemaName:     tree.Name(c.TableSchema),
+			ExplicitSchema: true,
+		}, tree.Name(c.TableName))
+		if _, ok := candidatesByTable[table]; !ok {
+			tables = append(tables, table)
+		}
+		candidatesByTable[table] = append(candidatesByTable[table], c)
 	}
-	table := tree.MakeTableNameFromPrefix(tree.ObjectNamePrefix{
-		SchemaName:     tree.Name(tableSchema),
-		ExplicitSchema: true,
-	}, tree.Name(tableName))
+	for _, table := range tables {
+		used := make(map[string]bool)
+		var picked []childColumn
+		for _, typ := range typs {
+			for _, c := range candidatesByTable[table] {
+				if c.TypeName == typ && !used[c.ColumnName] {
+					used[c.ColumnName] = true
+					picked = append(picked, c)
+					break
+				}
+			}
+		}
+		if len(picked) != len(typs) {
+			continue
+		}
 
-	columnToReturn.typ, err = og.typeFromTypeName(ctx, tx, typName)
-	if err != nil {
-		return nil, nil, err
+		columns := make([]*column, len(picked))
+		for i, c := range picked {
+			typ, err := og.typeFromTypeName(ctx, tx, c.TypeName)
+			if err != nil {
+				return nil, nil, err
+			}
+			columns[i] = &column{
+				name:              c.ColumnName,
+				typ:               typ,
+				nullable:          c.Nullable,
+				defaultExpression: c.DefaultExpression,
+			}
+		}
+		return &table, columns, nil
 	}
+	return nil, nil, pgx.ErrNoRows
+}
 
-	return &table, &columnToReturn, nil
+// referenceActions are the actions a foreign key may take when the
+// referenced row is deleted or updated.
+var referenceActions = []tree.ReferenceAction{
+	tree.NoAction, tree.Restrict, tree.SetNull, tree.SetDefault, tree.Cascade,
 }
 
-// randReferenceActions returns a ReferenceActions suitable for the column types
-// that are in the FK.
+// randReferenceActions returns random ON DELETE and ON UPDATE actions for a
+// foreign key on the given child columns. Unless allowInvalid is set, the
+// actions can always be added for the columns.
 func (og *operationGenerator) randReferenceActions(
-	childColumnIsComputed bool,
+	childColumns []*column, childIsComputed bool, allowInvalid bool,
 ) tree.ReferenceActions {
-	acts := tree.ReferenceActions{
-		Update: tree.Cascade,
-		Delete: tree.Cascade,
+	for {
+		acts := tree.ReferenceActions{
+			Delete: referenceActions[og.randIntn(len(referenceActions))],
+			Update: referenceActions[og.randIntn(len(referenceActions))],
+		}
+		if allowInvalid || !invalidReferenceActions(acts, childColumns, childIsComputed) {
+			return acts
+		}
 	}
-	// Computed columns cannot be changed when an update to the parent occurs.
-	if childColumnIsComputed {
-		acts.Update = tree.NoAction
+}
+
+// invalidReferenceActions returns whether the actions of a foreign key cannot
+// be applied to the child columns, in which case adding the foreign key fails.
+// Columns can only be set to NULL if they are nullable, and only be set to
+// their default if that isn't NULL for a NOT NULL column. Computed columns
+// cannot be changed at all.
+func invalidReferenceActions(
+	acts tree.ReferenceActions, childColumns []*column, childIsComputed bool,
+) bool {
+	if childIsComputed && acts.HasDisallowedActionForComputedFKCol() {
+		return true
+	}
+	setNull := acts.Delete == tree.SetNull || acts.Update == tree.SetNull
+	setDefault := acts.Delete == tree.SetDefault || acts.Update == tree.SetDefault
+	for _, col := range childColumns {
+		if setNull && !col.nullable {
+			return true
+		}
+		if setDefault && !col.nullable && col.defaultExpression == "" {
+			return true
+		}
 	}
-	return acts
+	return false
 }
 
+// fkParentColumnKind describes the constraint that must cover the parent
+// column of a generated foreign key.
+type fkParentColumnKind int
+
+const (
+	// fkParentAnyColumn allows any column, which is likely not to be unique.
+	fkParentAnyColumn fkParentColumnKind = iota
+	// fkParentUniqueColumn requires the column to be the only column of the
+	// primary key or of a UNIQUE constraint.
+	fkParentUniqueColumn
+	// fkParentUniqueConstraintColumn requires the column to be the only column
+	// of a UNIQUE constraint, so that the foreign key is resolved against that
+	// constraint rather than the primary key.
+	fkParentUniqueConstraintColumn
+)
+
 // randParentColumnForFkRelation fetches a column and table to use as the parent in a single-column foreign key relation.
 // To successfully use a column as the parent, the column must be unique and must not be generated.
+// If no column of the requested kind exists, pgx.ErrNoRows is returned.
 func (og *operationGenerator) randParentColumnForFkRelation(
-	ctx context.Context, tx pgx.Tx, unique bool,
+	ctx context.Context, tx pgx.Tx, kind fkParentColumnKind,
 ) (*tree.TableName, *column, error) {
 	if err := og.setSeedInDB(ctx, tx); err != nil {
 		return nil, nil, err
@@ -3322,12 +8971,19 @@ func (og *operationGenerator) randParentColumnForFkRelation(
 		       ) AS cons ON cons.conrelid = cols.tableid
 		 WHERE table_name SIMILAR TO 'table_w[0-9]_+%'
   `)
-	if unique {
+	switch kind {
+	case fkParentUniqueColumn:
 		subQuery.WriteString(`
 		 AND (contype = 'u' OR contype = 'p')
 		 AND array_length(conkey, 1) = 1
 		 AND conkey[1] = ordinal_position
 		`)
+	case fkParentUniqueConstraintColumn:
+		subQuery.WriteString(`
+		 AND contype = 'u'
+		 AND array_length(conkey, 1) = 1
+		 AND conkey[1] = ordinal_position
+		`)
 	}
 
 	subQuery.WriteString(`
@@ -3376,6 +9032,65 @@ func (og *operationGenerator) randParentColumnForFkRelation(
 	return &table, &columnToReturn, nil
 }
 
+// randParentColumnsForCompositeFkRelation fetches a table and the columns of
+// one of its multi-column primary keys or UNIQUE constraints, to use as the
+// parent in a composite foreign key relation. If no such constraint exists,
+// pgx.ErrNoRows is returned.
+func (og *operationGenerator) randParentColumnsForCompositeFkRelation(
+	ctx context.Context, tx pgx.Tx,
+) (*tree.TableName, []*column, error) {
+	if err := og.setSeedInDB(ctx, tx); err != nil {
+		return nil, nil, err
+	}
+	type parentColumn struct {
+		TableSchema string
+		TableName   string
+		ColumnName  string
+		TypeName    string
+		Nullable    bool
+	}
+	parentColumns, err := Collect(ctx, og, tx, pgx.RowToStructByPos[parentColumn], `
+WITH con AS (
+        SELECT c.conrelid, c.conkey
+          FROM pg_catalog.pg_constraint AS c
+          JOIN pg_catalog.pg_class AS t ON t.oid = c.conrelid
+         WHERE c.contype IN ('u', 'p')
+           AND array_length(c.conkey, 1) > 1
+           AND t.relname SIMILAR TO 'table_w[0-9]_+%'
+      ORDER BY random()
+         LIMIT 1
+     )
+SELECT cols.table_schema, cols.table_name, cols.column_name, cols.crdb_sql_type,
+       cols.is_nullable = 'YES'
+  FROM con
+  CROSS JOIN LATERAL generate_series(1, array_length(con.conkey, 1)) AS k (i)
+  CROSS JOIN information_schema.columns AS cols
+ WHERE concat(cols.table_schema, '.', cols.table_name)::REGCLASS::INT8 = con.conrelid::INT8
+   AND cols.ordinal_position = con.conkey[k.i]
+ORDER BY k.i
+`)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(parentColumns) == 0 {
+		return nil, nil, pgx.ErrNoRows
+	}
+
+	table := tree.MakeTableNameFromPrefix(tree.ObjectNamePrefix{
+		SchemaName:     tree.Name(parentColumns[0].TableSchema),
+		ExplicitSchema: true,
+	}, tree.Name(parentColumns[0].TableName))
+	columns := make([]*column, len(parentColumns))
+	for i, c := range parentColumns {
+		typ, err := og.typeFromTypeName(ctx, tx, c.TypeName)
+		if err != nil {
+			return nil, nil, err
+		}
+		columns[i] = &column{name: c.ColumnName, typ: typ, nullable: c.Nullable}
+	}
+	return &table, columns, nil
+}
+
 func (og *operationGenerator) randConstraint(
 	ctx context.Context, tx pgx.Tx, tableName string,
 ) (string, error) {
@@ -3500,12 +9215,7 @@ func (og *operationGenerator) randSequence(
 func (og *operationGenerator) randTypeName(
 	ctx context.Context, tx pgx.Tx, pctExisting int, isEnum bool,
 ) (name *tree.TypeName, exists bool, _ error) {
-	var prefix string
-	if isEnum {
-		prefix = "enum_"
-	} else {
-		prefix = "composite_"
-	}
+	prefix := typeNamePrefix(isEnum)
 
 	if og.randIntn(100) >= pctExisting {
 		// Most of the time, this case is for creating enums, so it
@@ -3534,6 +9244,15 @@ func (og *operationGenerator) randTypeName(
 	return &typeName, true, nil
 }
 
+// typeNamePrefix returns the prefix of the names of the enums, or of the
+// composite types if isEnum isn't set, created by the workload.
+func typeNamePrefix(isEnum bool) string {
+	if isEnum {
+		return "enum_"
+	}
+	return "composite_"
+}
+
 // randTable returns a schema name along with a table name
 func (og *operationGenerator) randTable(
 	ctx context.Context, tx pgx.Tx, pctExisting int, desiredSchema string,
@@ -3655,7 +9374,46 @@ func (og *operationGenerator) randView(
 		treeViewName := tree.MakeTableNameFromPrefix(tree.ObjectNamePrefix{
 			SchemaName:     tree.Name(randSchema),
 			ExplicitSchema: true,
-		}, tree.Name(fmt.Sprintf("view_%s", og.newUniqueSeqNumSuffix())))
+		}, tree.Name(fmt.Sprintf("view_%s", og.newUniqueSeqNumSuffix())))
+		return &treeViewName, nil
+	}
+	if err := og.setSeedInDB(ctx, tx); err != nil {
+		return nil, err
+	}
+	const q = `
+  SELECT schema_name, table_name
+    FROM [SHOW TABLES]
+   WHERE table_name LIKE 'view%'
+ORDER BY random()
+   LIMIT 1;
+`
+	var schemaName string
+	var viewName string
+	if err := tx.QueryRow(ctx, q).Scan(&schemaName, &viewName); err != nil {
+		treeViewName := tree.MakeTableNameFromPrefix(tree.ObjectNamePrefix{}, "")
+		return &treeViewName, err
+	}
+	treeViewName := tree.MakeTableNameFromPrefix(tree.ObjectNamePrefix{
+		SchemaName:     tree.Name(schemaName),
+		ExplicitSchema: true,
+	}, tree.Name(viewName))
+	return &treeViewName, nil
+}
+
+// randMaterializedView returns the name of a random materialized view, which
+// doesn't exist with probability 100 - pctExisting.
+func (og *operationGenerator) randMaterializedView(
+	ctx context.Context, tx pgx.Tx, pctExisting int,
+) (*tree.TableName, error) {
+	if og.randIntn(100) >= pctExisting {
+		randSchema, err := og.randSchema(ctx, tx, og.pctExisting(true))
+		if err != nil {
+			return nil, err
+		}
+		treeViewName := tree.MakeTableNameFromPrefix(tree.ObjectNamePrefix{
+			SchemaName:     tree.Name(randSchema),
+			ExplicitSchema: true,
+		}, tree.Name(fmt.Sprintf("mview_%s", og.newUniqueSeqNumSuffix())))
 		return &treeViewName, nil
 	}
 	if err := og.setSeedInDB(ctx, tx); err != nil {
@@ -3664,15 +9422,14 @@ func (og *operationGenerator) randView(
 	const q = `
   SELECT schema_name, table_name
     FROM [SHOW TABLES]
-   WHERE table_name LIKE 'view%'
+   WHERE table_name LIKE 'mview\_%'
 ORDER BY random()
    LIMIT 1;
 `
 	var schemaName string
 	var viewName string
 	if err := tx.QueryRow(ctx, q).Scan(&schemaName, &viewName); err != nil {
-		treeViewName := tree.MakeTableNameFromPrefix(tree.ObjectNamePrefix{}, "")
-		return &treeViewName, err
+		return nil, err
 	}
 	treeViewName := tree.MakeTableNameFromPrefix(tree.ObjectNamePrefix{
 		SchemaName:     tree.Name(schemaName),
@@ -3746,6 +9503,16 @@ func (og *operationGenerator) randType(
 		return nil, nil, err
 	}
 
+	n := og.randIntn(100)
+	for _, choice := range randTypeChoices {
+		if n < choice.weight {
+			typ := choice.types[og.randIntn(len(choice.types))]
+			typeName := tree.MakeUnqualifiedTypeName(typ.SQLString())
+			return &typeName, typ, nil
+		}
+		n -= choice.weight
+	}
+
 	typ := randgen.RandSortingType(og.params.rng)
 	for pgVectorNotSupported && typ.Family() == types.PGVectorFamily {
 		typ = randgen.RandSortingType(og.params.rng)
@@ -3754,27 +9521,166 @@ func (og *operationGenerator) randType(
 	return &typeName, typ, nil
 }
 
+// randTypeChoices are the types randType picks explicitly, each group with
+// the given weight out of 100. The remaining weight goes to the types picked
+// by randgen.RandSortingType.
+var randTypeChoices = []struct {
+	weight int
+	types  []*types.T
+}{
+	// FLOAT4 and FLOAT8 are picked explicitly so that columns with special
+	// values (NaN, +/-Infinity) are common enough to matter.
+	{weight: 5, types: []*types.T{types.Float4, types.Float}},
+	// TIMESTAMPTZ values are displayed and cast according to the session time
+	// zone, which is randomized per worker.
+	{weight: 5, types: []*types.T{types.TimestampTZ}},
+	// The OID family types are references into the catalog. They are rare in
+	// practice, so they are only picked occasionally.
+	{weight: 2, types: []*types.T{types.Oid, types.RegClass, types.RegType}},
+	// INET has its own encoding and comparison rules, but RandSortingType
+	// picks it rarely.
+	{weight: 5, types: []*types.T{types.INet}},
+}
+
 func (og *operationGenerator) createSchema(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
 	schemaName, err := og.randSchema(ctx, tx, og.pctExisting(false))
 	if err != nil {
 		return nil, err
 	}
 	ifNotExists := og.randIntn(2) == 0
+	opStmt := makeOpStmt(OpStmtDDL)
+	stmt := randgen.MakeSchemaName(ifNotExists, schemaName, tree.MakeRoleSpecWithRoleName(username.RootUserName().Normalized()))
+
+	// Occasionally create a new schema inside of a database created by this
+	// workload instead, which may have only just been created within this
+	// transaction.
+	if og.randIntn(4) == 0 {
+		databaseName, err := og.randDatabase(ctx, tx, og.alwaysExisting())
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		if err == nil {
+			stmt.Schema.SchemaName = tree.Name(fmt.Sprintf("schema_%s", og.newUniqueSeqNumSuffix()))
+			stmt.Schema.CatalogName = tree.Name(databaseName)
+			stmt.Schema.ExplicitCatalog = true
+			opStmt.sql = tree.Serialize(stmt)
+			return opStmt, nil
+		}
+	}
 
 	schemaExists, err := og.schemaExists(ctx, tx, schemaName)
 	if err != nil {
 		return nil, err
 	}
-	opStmt := makeOpStmt(OpStmtDDL)
 	if schemaExists && !ifNotExists {
 		opStmt.expectedExecErrors.add(pgcode.DuplicateSchema)
 	}
 
-	stmt := randgen.MakeSchemaName(ifNotExists, schemaName, tree.MakeRoleSpecWithRoleName(username.RootUserName().Normalized()))
 	opStmt.sql = tree.Serialize(stmt)
 	return opStmt, nil
 }
 
+func (og *operationGenerator) randDatabase(
+	ctx context.Context, tx pgx.Tx, pctExisting int,
+) (string, error) {
+	if err := og.setSeedInDB(ctx, tx); err != nil {
+		return "", err
+	}
+	if og.randIntn(100) >= pctExisting {
+		return fmt.Sprintf("database_%s", og.newUniqueSeqNumSuffix()), nil
+	}
+	// Only databases created by this workload are ever returned, which
+	// guarantees that the database of the connection is never picked.
+	const q = `
+  SELECT database_name
+    FROM [SHOW DATABASES]
+   WHERE database_name
+    LIKE 'database\_%'
+ORDER BY random()
+   LIMIT 1;
+`
+	var name string
+	if err := tx.QueryRow(ctx, q).Scan(&name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+func (og *operationGenerator) createDatabase(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	databaseName, err := og.randDatabase(ctx, tx, og.pctExisting(false))
+	if err != nil {
+		return nil, err
+	}
+	ifNotExists := og.randIntn(2) == 0
+
+	databaseExists, err := og.databaseExists(ctx, tx, databaseName)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := makeOpStmt(OpStmtDDL)
+	if databaseExists && !ifNotExists {
+		stmt.expectedExecErrors.add(pgcode.DuplicateDatabase)
+	}
+
+	createDatabase := tree.CreateDatabase{
+		Name:        tree.Name(databaseName),
+		IfNotExists: ifNotExists,
+	}
+	stmt.sql = tree.Serialize(&createDatabase)
+	return stmt, nil
+}
+
+func (og *operationGenerator) dropDatabase(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	// Never drop the database of the connection. Since sql_safe_updates is not
+	// enabled, doing so would succeed instead of producing an error and break
+	// every subsequent operation. randDatabase only returns databases created
+	// by this workload, so there is no need to screen for it here.
+	databaseName, err := og.randDatabase(ctx, tx, og.pctExisting(true))
+	if err != nil {
+		return nil, err
+	}
+
+	databaseExists, err := og.databaseExists(ctx, tx, databaseName)
+	if err != nil {
+		return nil, err
+	}
+	hasObjects := false
+	hasUserSchemas := false
+	if databaseExists {
+		hasObjects, err = og.databaseHasObjects(ctx, tx, databaseName)
+		if err != nil {
+			return nil, err
+		}
+		hasUserSchemas, err = og.databaseHasUserSchemas(ctx, tx, databaseName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Without an explicit drop behavior, DROP DATABASE cascades unless
+	// sql_safe_updates is enabled, so only RESTRICT can fail due to the
+	// contents of the database. The declarative schema changer also considers
+	// empty user defined schemas to be dependent objects.
+	ifExists := og.randIntn(2) == 0
+	dropBehavior := tree.DropBehavior(og.randIntn(3))
+
+	stmt := makeOpStmt(OpStmtDDL)
+	stmt.expectedExecErrors.addAll(codesWithConditions{
+		{pgcode.InvalidCatalogName, !databaseExists && !ifExists},
+		{pgcode.DependentObjectsStillExist, dropBehavior == tree.DropRestrict &&
+			(hasObjects || (hasUserSchemas && og.useDeclarativeSchemaChanger))},
+	})
+
+	dropDatabase := tree.DropDatabase{
+		Name:         tree.Name(databaseName),
+		IfExists:     ifExists,
+		DropBehavior: dropBehavior,
+	}
+	stmt.sql = tree.Serialize(&dropDatabase)
+	return stmt, nil
+}
+
 func (og *operationGenerator) randSchema(
 	ctx context.Context, tx pgx.Tx, pctExisting int,
 ) (string, error) {
@@ -3835,6 +9741,142 @@ func (og *operationGenerator) dropSchema(ctx context.Context, tx pgx.Tx) (*opStm
 	return stmt, nil
 }
 
+// schemaInfo describes a schema of the current database, as returned by
+// schemasForAlter.
+type schemaInfo struct {
+	Name string
+	// HasDependents is true if another object depends on a table or view of
+	// the schema, which prevents the schema from being renamed.
+	HasDependents bool
+}
+
+// schemasForAlter returns every schema of the current database, including the
+// public schema, which ALTER SCHEMA refuses to modify.
+func (og *operationGenerator) schemasForAlter(
+	ctx context.Context, tx pgx.Tx,
+) ([]schemaInfo, error) {
+	schemas, err := Collect(ctx, og, tx, pgx.RowToStructByPos[schemaInfo], With([]CTE{
+		{"descriptors", descJSONQuery},
+	}, `SELECT
+				quote_ident(s.name),
+				EXISTS(
+					SELECT *
+					FROM descriptors AS t
+					WHERE t.schema_id = s.id
+					AND t.descriptor ? 'table'
+					AND NOT t.descriptor->'table' ? 'sequenceOpts'
+					AND jsonb_array_length(COALESCE(t.descriptor->'table'->'dependedOnBy', '[]'::JSONB)) > 0
+				)
+			FROM descriptors AS s
+			WHERE s.descriptor ? 'schema'
+			AND COALESCE(s.descriptor->'schema'->>'state', 'PUBLIC') = 'PUBLIC'
+	`))
+	if err != nil {
+		return nil, og.checkAndAdjustForUnknownSchemaErrors(err)
+	}
+	return schemas, nil
+}
+
+func (og *operationGenerator) alterSchemaRename(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	schemas, err := og.schemasForAlter(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	userDefined := util.Filter(schemas, func(s schemaInfo) bool {
+		return s.Name != catconstants.PublicSchemaName
+	})
+
+	// New names keep the prefix of the schemas created by the workload, so
+	// that subsequent operations keep finding the renamed schema and the
+	// objects within it.
+	stmt, code, err := Generate[*tree.AlterSchema](og.params.rng, og.produceError(), []GenerationCase{
+		// Fail to rename a schema that doesn't exist.
+		{pgcode.InvalidSchemaName, `ALTER SCHEMA "SchemaThatDoesntExist" RENAME TO { UniqueName }`},
+		// Fail to rename the public schema.
+		{pgcode.InvalidSchemaName, `ALTER SCHEMA public RENAME TO { UniqueName }`},
+		// Fail to rename a schema to a name reserved for system schemas.
+		{pgcode.ReservedName, `ALTER SCHEMA { Schema } RENAME TO pg_schema`},
+		// Fail to rename a schema to the name of another schema.
+		{pgcode.DuplicateSchema, `{ with Schema } ALTER SCHEMA { . } RENAME TO { Sibling . } { end }`},
+		// Fail to rename a schema with tables or views that other objects
+		// refer to.
+		{pgcode.DependentObjectsStillExist, `ALTER SCHEMA { SchemaWithDependents true } RENAME TO { UniqueName }`},
+		// Successful rename of a schema.
+		{pgcode.SuccessfulCompletion, `ALTER SCHEMA { SchemaWithDependents false } RENAME TO { UniqueName }`},
+	}, template.FuncMap{
+		"Schema": func() (string, error) {
+			schema, err := PickOne(og.params.rng, userDefined)
+			return schema.Name, err
+		},
+		"SchemaWithDependents": func(hasDependents bool) (string, error) {
+			schema, err := PickOne(og.params.rng, util.Filter(userDefined, func(s schemaInfo) bool {
+				return s.HasDependents == hasDependents
+			}))
+			return schema.Name, err
+		},
+		"Sibling": func(name string) (string, error) {
+			sibling, err := PickOne(og.params.rng, util.Filter(schemas, func(s schemaInfo) bool {
+				return s.Name != name
+			}))
+			return sibling.Name, err
+		},
+		"UniqueName": func() *tree.Name {
+			name := tree.Name(fmt.Sprintf("schema_%s", og.newUniqueSeqNumSuffix()))
+			return &name
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newOpStmt(stmt, codesWithConditions{
+		{code, true},
+	}), nil
+}
+
+func (og *operationGenerator) alterSchemaOwner(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+	schemas, err := og.schemasForAlter(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	userDefined := util.Filter(schemas, func(s schemaInfo) bool {
+		return s.Name != catconstants.PublicSchemaName
+	})
+	roles, err := og.existingRoles(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, code, err := Generate[*tree.AlterSchema](og.params.rng, og.produceError(), []GenerationCase{
+		// Fail to alter the owner of a schema that doesn't exist.
+		{pgcode.InvalidSchemaName, `ALTER SCHEMA "SchemaThatDoesntExist" OWNER TO CURRENT_USER`},
+		// Fail to alter the owner of the public schema.
+		{pgcode.InvalidSchemaName, `ALTER SCHEMA public OWNER TO CURRENT_USER`},
+		// Fail to transfer a schema to a role that doesn't exist.
+		{pgcode.UndefinedObject, `ALTER SCHEMA { Schema } OWNER TO "RoleThatDoesntExist"`},
+		// Successful transfer of a schema to a role of the pool.
+		{pgcode.SuccessfulCompletion, `ALTER SCHEMA { Schema } OWNER TO { Role }`},
+		// Successful transfer of a schema back to the current user.
+		{pgcode.SuccessfulCompletion, `ALTER SCHEMA { Schema } OWNER TO CURRENT_USER`},
+	}, template.FuncMap{
+		"Schema": func() (string, error) {
+			schema, err := PickOne(og.params.rng, userDefined)
+			return schema.Name, err
+		},
+		"Role": func() (string, error) {
+			role, err := PickOne(og.params.rng, roles)
+			return tree.NameString(role), err
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newOpStmt(stmt, codesWithConditions{
+		{code, true},
+	}), nil
+}
+
 func (og *operationGenerator) createFunction(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
 	// TODO(chrisseto): Allow referencing sequences as well. Currently, `DROP
 	// SEQUENCE CASCADE` will break if we allow sequences. It may also be good to
@@ -4133,7 +10175,9 @@ func (og *operationGenerator) dropFunction(ctx context.Context, tx pgx.Tx) (*opS
 		{"functions", functionDescsQuery},
 	}, `SELECT
 			quote_ident(schema_id::REGNAMESPACE::TEXT) || '.' || quote_ident(name) || '(' || array_to_string(funcargs, ', ') || ')' as name,
-			(id + 100000) as func_oid
+			-- Functions are depended on by the functions calling them, and by
+			-- the tables and views referring to them in expressions.
+			COALESCE(jsonb_array_length(descriptor->'dependedOnBy'), 0) > 0 AS has_deps
 			FROM functions
 			JOIN LATERAL (
 				SELECT
@@ -4151,25 +10195,10 @@ func (og *operationGenerator) dropFunction(ctx context.Context, tx pgx.Tx) (*opS
 		return nil, err
 	}
 
-	functionDeps, err := Collect(ctx, og, tx, pgx.RowToMap,
-		With([]CTE{
-			{"function_deps", functionDepsQuery},
-		},
-			`SELECT DISTINCT to_oid::INT8 FROM function_deps;`,
-		))
-	if err != nil {
-		return nil, err
-	}
-
-	functionDepsMap := make(map[int64]struct{})
-	for _, f := range functionDeps {
-		functionDepsMap[f["to_oid"].(int64)] = struct{}{}
-	}
-
 	functionWithDeps := make([]map[string]any, 0, len(functions))
 	functionWithoutDeps := make([]map[string]any, 0, len(functions))
 	for _, f := range functions {
-		if _, ok := functionDepsMap[f["func_oid"].(int64)]; ok {
+		if f["has_deps"].(bool) {
 			functionWithDeps = append(functionWithDeps, f)
 		} else {
 			functionWithoutDeps = append(functionWithoutDeps, f)
@@ -4181,6 +10210,10 @@ func (og *operationGenerator) dropFunction(ctx context.Context, tx pgx.Tx) (*opS
 		{pgcode.SuccessfulCompletion, `DROP FUNCTION IF EXISTS "NoSuchFunction"`},
 		{pgcode.SuccessfulCompletion, `DROP FUNCTION { FunctionWithoutDeps }`},
 		{pgcode.DependentObjectsStillExist, `DROP FUNCTION { FunctionWithDeps }`},
+		// Dropping functions with CASCADE isn't supported yet, whether or not
+		// they have dependents.
+		{pgcode.FeatureNotSupported, `DROP FUNCTION { FunctionWithoutDeps } CASCADE`},
+		{pgcode.FeatureNotSupported, `DROP FUNCTION { FunctionWithDeps } CASCADE`},
 	}, template.FuncMap{
 		"FunctionWithoutDeps": func() (string, error) {
 			one, err := PickOne(og.params.rng, functionWithoutDeps)
@@ -4206,6 +10239,108 @@ func (og *operationGenerator) dropFunction(ctx context.Context, tx pgx.Tx) (*opS
 	}), nil
 }
 
+func (og *operationGenerator) alterFunctionOptions(
+	ctx context.Context, tx pgx.Tx,
+) (*opStmt, error) {
+	q := With([]CTE{
+		{"descriptors", descJSONQuery},
+		{"functions", functionDescsQuery},
+	}, `SELECT
+			quote_ident(schema_id::REGNAMESPACE::TEXT) || '.' || quote_ident(name) || '(' || array_to_string(funcargs, ', ') || ')' AS qualified_name,
+			COALESCE(descriptor->>'volatility', 'VOLATILE') AS volatility,
+			COALESCE((descriptor->'leakProof')::BOOL, false) AS leak_proof,
+			COALESCE(jsonb_array_length(descriptor->'dependsOn'), 0) > 0 OR
+				COALESCE(jsonb_array_length(descriptor->'dependsOnTypes'), 0) > 0 OR
+				COALESCE(jsonb_array_length(descriptor->'dependsOnFunctions'), 0) > 0 AS has_deps
+			FROM functions
+			JOIN LATERAL (
+				SELECT
+					COALESCE(array_agg(replace(quote_ident(typnamespace::REGNAMESPACE::TEXT) || '.' || quote_ident(typname), 'pg_catalog.', '')), '{}') AS funcargs
+				FROM pg_catalog.pg_type
+				JOIN LATERAL (
+					SELECT unnest(proargtypes) AS oid FROM pg_catalog.pg_proc WHERE oid = (id + 100000)
+				) args ON args.oid = pg_type.oid
+			) funcargs ON TRUE
+	`)
+
+	functions, err := Collect(ctx, og, tx, pgx.RowToMap, q)
+	if err != nil {
+		return nil, err
+	}
+
+	// The function and the options picked by the template, from which the
+	// volatility and leakproofness of the altered function are derived.
+	var function map[string]any
+	var volatility string
+	var leakProof *bool
+
+	stmt, expectedCode, err := Generate[*tree.AlterFunctionOptions](og.params.rng, og.produceError(), []GenerationCase{
+		{pgcode.UndefinedFunction, `ALTER FUNCTION "NoSuchFunction" IMMUTABLE`},
+		// Only immutable functions may be leakproof.
+		{pgcode.InvalidFunctionDefinition, `ALTER FUNCTION { (Function).qualified_name } VOLATILE LEAKPROOF`},
+		{pgcode.InvalidFunctionDefinition, `ALTER FUNCTION { (Function).qualified_name } STABLE LEAKPROOF`},
+		{pgcode.SuccessfulCompletion, `ALTER FUNCTION { (Function).qualified_name } { Options }`},
+	}, template.FuncMap{
+		"Function": func() (map[string]any, error) {
+			var err error
+			function, err = PickOne(og.params.rng, functions)
+			return function, err
+		},
+		"Options": func() string {
+			var opts []string
+			if og.randIntn(2) == 0 {
+				volatility = []string{"IMMUTABLE", "STABLE", "VOLATILE"}[og.randIntn(3)]
+				opts = append(opts, volatility)
+			}
+			if len(opts) == 0 || og.randIntn(2) == 0 {
+				leakProof = new(bool)
+				*leakProof = og.randIntn(2) == 0
+				if *leakProof {
+					opts = append(opts, "LEAKPROOF")
+				} else {
+					opts = append(opts, "NOT LEAKPROOF")
+				}
+			}
+			og.params.rng.Shuffle(len(opts), func(i, j int) {
+				opts[i], opts[j] = opts[j], opts[i]
+			})
+			return strings.Join(opts, " ")
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	opStmt := newOpStmt(stmt, codesWithConditions{
+		{expectedCode, true},
+	})
+	if expectedCode != pgcode.SuccessfulCompletion {
+		return opStmt, nil
+	}
+
+	// Options that aren't given keep their current value.
+	newVolatility := function["volatility"].(string)
+	if volatility != "" {
+		newVolatility = volatility
+	}
+	newLeakProof := function["leak_proof"].(bool)
+	if leakProof != nil {
+		newLeakProof = *leakProof
+	}
+	invalidLeakProof := newLeakProof && newVolatility != "IMMUTABLE"
+	opStmt.expectedExecErrors.addAll(codesWithConditions{
+		{pgcode.InvalidFunctionDefinition, invalidLeakProof},
+	})
+	// Making a function immutable or stable re-validates its body, which may
+	// then neither refer to relations nor call more volatile functions. The
+	// references of a function don't tell whether they are in its body or in
+	// its signature, so the error is only potential.
+	opStmt.potentialExecErrors.addAll(codesWithConditions{
+		{pgcode.InvalidParameterValue, !invalidLeakProof && (volatility == "IMMUTABLE" || volatility == "STABLE") && function["has_deps"].(bool)},
+	})
+	return opStmt, nil
+}
+
 func (og *operationGenerator) alterFunctionRename(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
 	q := With([]CTE{
 		{"descriptors", descJSONQuery},
@@ -4297,6 +10432,87 @@ func (og *operationGenerator) alterFunctionRename(ctx context.Context, tx pgx.Tx
 	}), nil
 }
 
+func (og *operationGenerator) alterFunctionSetOwner(
+	ctx context.Context, tx pgx.Tx,
+) (*opStmt, error) {
+	q := With([]CTE{
+		{"descriptors", descJSONQuery},
+		{"functions", functionDescsQuery},
+	}, `SELECT
+			quote_ident(schema_id::REGNAMESPACE::TEXT) || '.' || quote_ident(name) || '(' || array_to_string(funcargs, ', ') || ')' AS qualified_name,
+			schema_id,
+			schema_id::REGNAMESPACE::TEXT = 'public' AS in_public_schema,
+			descriptor->'privileges'->>'ownerProto' AS owner
+			FROM functions
+			JOIN LATERAL (
+				SELECT
+					COALESCE(array_agg(replace(quote_ident(typnamespace::REGNAMESPACE::TEXT) || '.' || quote_ident(typname), 'pg_catalog.', '')), '{}') AS funcargs
+				FROM pg_catalog.pg_type
+				JOIN LATERAL (
+					SELECT unnest(proargtypes) AS oid FROM pg_catalog.pg_proc WHERE oid = (id + 100000)
+				) args ON args.oid = pg_type.oid
+			) funcargs ON TRUE
+	`)
+
+	functions, err := Collect(ctx, og, tx, pgx.RowToMap, q)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := og.existingRoles(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The function and the role picked by the template.
+	var function map[string]any
+	var role string
+
+	stmt, expectedCode, err := Generate[*tree.AlterRoutineSetOwner](og.params.rng, og.produceError(), []GenerationCase{
+		{pgcode.UndefinedFunction, `ALTER FUNCTION "NoSuchFunction" OWNER TO CURRENT_USER`},
+		// Fail to transfer a function to a role that doesn't exist.
+		{pgcode.UndefinedObject, `ALTER FUNCTION { (Function).qualified_name } OWNER TO "RoleThatDoesntExist"`},
+		// Successful transfer of a function to a role of the pool.
+		{pgcode.SuccessfulCompletion, `ALTER FUNCTION { (Function).qualified_name } OWNER TO { Role }`},
+		// Successful transfer of a function back to the current user.
+		{pgcode.SuccessfulCompletion, `ALTER FUNCTION { (Function).qualified_name } OWNER TO CURRENT_USER`},
+	}, template.FuncMap{
+		"Function": func() (map[string]any, error) {
+			var err error
+			function, err = PickOne(og.params.rng, functions)
+			return function, err
+		},
+		"Role": func() (string, error) {
+			var err error
+			role, err = PickOne(og.params.rng, roles)
+			return tree.NameString(role), err
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The new owner of a function must be allowed to create it, unless it
+	// already owns it.
+	missingCreatePrivilege := false
+	if expectedCode == pgcode.SuccessfulCompletion && role != "" && role != function["owner"] {
+		if function["in_public_schema"].(bool) {
+			missingCreatePrivilege, err = Scan[bool](ctx, og, tx,
+				`SELECT NOT has_database_privilege($1, current_database(), 'CREATE')`, role)
+		} else {
+			missingCreatePrivilege, err = Scan[bool](ctx, og, tx,
+				`SELECT NOT has_schema_privilege($1, $2::OID, 'CREATE')`, role, function["schema_id"])
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return newOpStmt(stmt, codesWithConditions{
+		{expectedCode, true},
+		{pgcode.InsufficientPrivilege, missingCreatePrivilege},
+	}), nil
+}
+
 func (og *operationGenerator) alterFunctionSetSchema(
 	ctx context.Context, tx pgx.Tx,
 ) (*opStmt, error) {
@@ -4457,6 +10673,24 @@ func (og *operationGenerator) selectStmt(ctx context.Context, tx pgx.Tx) (stmt *
 		selectQuery.WriteString(" AS ")
 		selectQuery.WriteString(fmt.Sprintf("t%d ", idx))
 	}
+	// Occasionally filter on the containment of an array, which can be served
+	// by an inverted index on the array column.
+	if len(colInfos[0]) > 0 && og.randIntn(2) == 0 {
+		var arrayCols []column
+		for _, col := range colInfos[0] {
+			if col.typ.Family() == types.ArrayFamily &&
+				slices.ContainsFunc(invertedArrayElemTypes, col.typ.ArrayContents().Identical) {
+				arrayCols = append(arrayCols, col)
+			}
+		}
+		if len(arrayCols) > 0 {
+			col := arrayCols[og.randIntn(len(arrayCols))]
+			d := randgen.RandDatum(og.params.rng, col.typ, false /* nullOk */)
+			containmentOps := []string{"@>", "<@"}
+			selectQuery.WriteString(fmt.Sprintf("WHERE t0.%s %s %s ",
+				col.name, containmentOps[og.randIntn(len(containmentOps))], tree.AsStringWithFlags(d, tree.FmtParsable)))
+		}
+	}
 	if maxRowsToConsume > 0 {
 		selectQuery.WriteString(fmt.Sprintf("FETCH FIRST %d ROWS ONLY", maxRowsToConsume))
 	}
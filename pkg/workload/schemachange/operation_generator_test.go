@@ -0,0 +1,2483 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package schemachange
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils/datapathutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/skip"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/datadriven"
+	"github.com/cockroachdb/errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpectedKeySuffixColumns(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		primaryKey []int64
+		keyColumns []int64
+		expected   []int64
+	}{
+		{
+			name:       "disjoint key",
+			primaryKey: []int64{1},
+			keyColumns: []int64{2},
+			expected:   []int64{1},
+		},
+		{
+			name:       "key contains primary key",
+			primaryKey: []int64{1, 2},
+			keyColumns: []int64{3, 2, 1},
+			expected:   nil,
+		},
+		{
+			name:       "primary key order is preserved",
+			primaryKey: []int64{3, 1, 2},
+			keyColumns: []int64{1, 4},
+			expected:   []int64{3, 2},
+		},
+		{
+			name:       "implicit partitioning column",
+			primaryKey: []int64{5, 1},
+			keyColumns: []int64{5, 2},
+			expected:   []int64{1},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, expectedKeySuffixColumns(tc.primaryKey, tc.keyColumns))
+		})
+	}
+}
+
+// TestGrantablePrivileges ensures that the privileges generated by grant and
+// revoke agree with the privileges that can actually be granted on each kind of
+// object.
+func TestGrantablePrivileges(t *testing.T) {
+	objectTypes := map[string]privilege.ObjectType{
+		"TABLE":    privilege.Table,
+		"SEQUENCE": privilege.Sequence,
+		"SCHEMA":   privilege.Schema,
+		"TYPE":     privilege.Type,
+	}
+	require.Len(t, grantablePrivileges, len(objectTypes))
+	for kind, objectType := range objectTypes {
+		t.Run(kind, func(t *testing.T) {
+			valid, err := privilege.GetValidPrivilegesForObject(objectType)
+			require.NoError(t, err)
+			var expected []string
+			for _, name := range allPrivileges {
+				k, ok := privilege.ByDisplayName[privilege.KindDisplayName(name)]
+				require.True(t, ok, "unknown privilege %s", name)
+				if valid.Contains(k) {
+					expected = append(expected, name)
+				}
+			}
+			require.Equal(t, expected, grantablePrivileges[kind])
+		})
+	}
+}
+
+// TestRandAlterSequenceOptions ensures that the options generated by
+// alterSequence parse, and are consistent with each other and with the integer
+// type of ascending and descending sequences, so that the statement is
+// expected to succeed.
+func TestRandAlterSequenceOptions(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts sequenceOptions
+	}{
+		{
+			name: "ascending",
+			opts: sequenceOptions{Increment: 1, MinValue: 1, MaxValue: math.MaxInt64, Start: 1},
+		},
+		{
+			name: "descending",
+			opts: sequenceOptions{Increment: -1, MinValue: math.MinInt64, MaxValue: -1, Start: -1},
+		},
+		{
+			name: "int2",
+			opts: sequenceOptions{
+				Increment: 1, MinValue: 1, MaxValue: math.MaxInt16, Start: 1, AsIntegerType: "INT2",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, quick.Check(func(seed int64) bool {
+				rng := rand.New(rand.NewSource(seed))
+				clauses, opts := randAlterSequenceOptions(rng, tc.opts)
+				require.NotEmpty(t, clauses)
+
+				stmt, err := parser.ParseOne("ALTER SEQUENCE s " + strings.Join(clauses, " "))
+				require.NoError(t, err)
+				require.IsType(t, &tree.AlterSequence{}, stmt.AST)
+
+				require.NotZero(t, opts.Increment)
+				require.Less(t, opts.MinValue, opts.MaxValue)
+				require.GreaterOrEqual(t, opts.Start, opts.MinValue)
+				require.LessOrEqual(t, opts.Start, opts.MaxValue)
+				lo, hi := tc.opts.typeBounds()
+				require.GreaterOrEqual(t, opts.MinValue, lo)
+				require.LessOrEqual(t, opts.MaxValue, hi)
+				return true
+			}, nil))
+		})
+	}
+}
+
+// TestRandWideTableDefs ensures that the definitions generated for wide tables
+// parse, assign every column to at most one family, and only key the index on
+// indexable columns.
+func TestRandWideTableDefs(t *testing.T) {
+	tableName := tree.MakeUnqualifiedTableName("table_w0_0")
+	require.NoError(t, quick.Check(func(seed int64) bool {
+		og := makeOperationGenerator(&operationGeneratorParams{
+			rng: rand.New(rand.NewSource(seed)),
+		})
+		defs := og.randWideTableDefs(&tableName)
+		stmt, err := parser.ParseOne(tree.Serialize(&tree.CreateTable{Table: tableName, Defs: defs}))
+		require.NoError(t, err)
+		require.IsType(t, &tree.CreateTable{}, stmt.AST)
+
+		columnTypes := make(map[tree.Name]*types.T)
+		families := make(map[tree.Name]tree.Name)
+		var index *tree.IndexTableDef
+		for _, def := range defs {
+			switch d := def.(type) {
+			case *tree.ColumnTableDef:
+				columnTypes[d.Name] = d.Type.(*types.T)
+			case *tree.FamilyTableDef:
+				require.NotEmpty(t, d.Columns)
+				for _, col := range d.Columns {
+					_, ok := families[col]
+					require.False(t, ok, "column %s is in several families", col)
+					families[col] = d.Name
+				}
+			case *tree.IndexTableDef:
+				index = d
+			}
+		}
+		require.GreaterOrEqual(t, len(columnTypes), 50)
+		require.Less(t, len(columnTypes), 300)
+		for col := range families {
+			require.Contains(t, columnTypes, col)
+		}
+		require.NotNil(t, index)
+		require.GreaterOrEqual(t, len(index.Columns), 16)
+		for _, elem := range index.Columns {
+			require.True(t, colinfo.ColumnTypeIsIndexable(columnTypes[elem.Column]))
+		}
+		return true
+	}, nil))
+}
+
+func TestRandS2IndexStorageParams(t *testing.T) {
+	for _, typ := range []*types.T{types.Geometry, types.Geography} {
+		t.Run(typ.Name(), func(t *testing.T) {
+			require.NoError(t, quick.Check(func(seed int64, invalid bool) bool {
+				rng := rand.New(rand.NewSource(seed))
+				params := randS2IndexStorageParams(rng, typ, invalid)
+
+				def := &tree.CreateIndex{
+					Name:          "idx",
+					Table:         tree.MakeUnqualifiedTableName("t"),
+					Inverted:      true,
+					Columns:       tree.IndexElemList{{Column: "g"}},
+					StorageParams: params,
+				}
+				stmt, err := parser.ParseOne(tree.Serialize(def))
+				require.NoError(t, err)
+				require.Len(t, stmt.AST.(*tree.CreateIndex).StorageParams, len(params))
+
+				values := make(map[string]float64)
+				for _, p := range params {
+					switch v := p.Value.(type) {
+					case *tree.DInt:
+						values[p.Key] = float64(*v)
+					case *tree.DFloat:
+						values[p.Key] = float64(*v)
+					default:
+						t.Fatalf("unexpected value %T for %s", p.Value, p.Key)
+					}
+				}
+				levelMod, maxLevel, maxCells := values["s2_level_mod"], values["s2_max_level"], values["s2_max_cells"]
+				valid := levelMod >= 1 && levelMod <= 3 &&
+					maxLevel >= 0 && maxLevel <= 30 && int(maxLevel)%int(levelMod) == 0 &&
+					maxCells >= 1 && maxCells <= 32
+				if _, ok := values["geometry_min_x"]; ok {
+					valid = valid && typ.Family() == types.GeometryFamily &&
+						values["geometry_max_x"] > values["geometry_min_x"] &&
+						values["geometry_max_y"] > values["geometry_min_y"]
+				}
+				require.Equal(t, !invalid, valid, "%s", tree.Serialize(def))
+				return true
+			}, nil))
+		})
+	}
+}
+
+// TestRandMultiColumnPredicate ensures that the predicates generated for
+// partial indexes refer to the columns by their names, which getTableColumns
+// returns quoted, and never refer to generated columns.
+func TestRandMultiColumnPredicate(t *testing.T) {
+	columns := []column{
+		{name: `"Col A"`},
+		{name: `col_b`},
+		{name: `col_c`, generated: true},
+	}
+	require.NoError(t, quick.Check(func(seed int64) bool {
+		og := makeOperationGenerator(&operationGeneratorParams{
+			rng: rand.New(rand.NewSource(seed)),
+		})
+		pred, err := og.randMultiColumnPredicate(columns)
+		require.NoError(t, err)
+		sql := tree.Serialize(pred)
+		require.Contains(t, sql, `"Col A"`)
+		require.Contains(t, sql, `col_b`)
+		require.NotContains(t, sql, `"""`)
+		require.NotContains(t, sql, `col_c`)
+		return true
+	}, nil))
+
+	og := makeOperationGenerator(&operationGeneratorParams{rng: rand.New(rand.NewSource(0))})
+	pred, err := og.randMultiColumnPredicate(columns[1:])
+	require.NoError(t, err)
+	require.Nil(t, pred)
+}
+
+func TestSequenceDefaults(t *testing.T) {
+	for _, tc := range []struct {
+		defaultExpr string
+		sequence    string
+	}{
+		{defaultExpr: `nextval('public.seq_w0_1'::REGCLASS)`, sequence: "public.seq_w0_1"},
+		{defaultExpr: `nextval('schema_w1_2.seq_w1_3'::REGCLASS)`, sequence: "schema_w1_2.seq_w1_3"},
+		{defaultExpr: `unique_rowid()`},
+		{defaultExpr: `gen_random_uuid()`},
+		{defaultExpr: `nextval('public.seq_w0_1'::REGCLASS) + 1`},
+		{defaultExpr: ``},
+	} {
+		m := sequenceDefaultRegex.FindStringSubmatch(tc.defaultExpr)
+		if tc.sequence == "" {
+			require.Nil(t, m, tc.defaultExpr)
+			continue
+		}
+		require.Len(t, m, 2, tc.defaultExpr)
+		require.Equal(t, tc.sequence, m[1])
+	}
+
+	require.True(t, intValuesFitType([]int64{math.MinInt64, math.MaxInt64}, types.Int))
+	require.True(t, intValuesFitType([]int64{math.MinInt32, math.MaxInt32}, types.Int4))
+	require.False(t, intValuesFitType([]int64{1, math.MaxInt32 + 1}, types.Int4))
+	require.True(t, intValuesFitType([]int64{math.MinInt16, math.MaxInt16}, types.Int2))
+	require.False(t, intValuesFitType([]int64{math.MinInt16 - 1}, types.Int2))
+}
+
+// TestInsertRowErrorPrediction checks that the errors predicted for the
+// inserts generated by insertRow, including the constraint violations it
+// deliberately introduces, match the errors that are actually returned. The
+// tables have enum columns, computed columns and unique indexes.
+func TestInsertRowErrorPrediction(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{errorRate: 30})
+	defer cleanup()
+	h.tdb.Exec(t, `CREATE TYPE enum_w0_0 AS ENUM ('a', 'b', 'c')`)
+	h.tdb.Exec(t, `CREATE TABLE table_w0_1 (a INT8 PRIMARY KEY, b enum_w0_0 NOT NULL, c enum_w0_0)`)
+	h.tdb.Exec(t, `CREATE TABLE table_w0_2 (
+		a INT8 NOT NULL,
+		b INT8,
+		c INT8 AS (a + b) STORED,
+		d INT8 NOT NULL AS (a * 2) VIRTUAL
+	)`)
+	h.tdb.Exec(t, `CREATE TABLE table_w0_3 (
+		a INT2 NOT NULL,
+		b STRING,
+		c enum_w0_0,
+		UNIQUE (a),
+		UNIQUE (c, b),
+		UNIQUE INDEX ((lower(b)))
+	)`)
+
+	var succeeded, failed int
+	for i := 0; i < 200; i++ {
+		if stmt := h.run(h.og.insertRow); stmt.outcome == pgcode.SuccessfulCompletion {
+			succeeded++
+		} else {
+			failed++
+		}
+	}
+	require.NotZero(t, succeeded)
+	require.NotZero(t, failed)
+}
+
+// TestAddForeignKeyConstraint checks the errors predicted for foreign keys
+// added by addForeignKeyConstraintStmt against the errors actually returned
+// when executing and committing them.
+//
+// The following directives are supported:
+//
+//   - exec: runs the SQL statements in the input.
+//   - add-foreign-key child=<table> child-columns=(<col>,...) parent=<table>
+//     parent-columns=(<col>,...) [on-delete=<action>] [on-update=<action>]
+//     [not-valid] [schema-changer=legacy|declarative]: adds a foreign key in
+//     its own transaction, and prints the statement, the predicted errors and
+//     the outcome of executing and committing it.
+func TestAddForeignKeyConstraint(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{})
+	defer cleanup()
+	ctx, og := h.ctx, h.og
+
+	actions := map[string]tree.ReferenceAction{
+		"no-action":   tree.NoAction,
+		"restrict":    tree.Restrict,
+		"set-null":    tree.SetNull,
+		"set-default": tree.SetDefault,
+		"cascade":     tree.Cascade,
+	}
+	// underlyingErrorCode matches the code of the error that caused a schema
+	// change to fail after its transaction committed.
+	underlyingErrorCode := regexp.MustCompile(`\(([A-Z0-9]{5})\)`)
+	errorCode := func(err error) pgcode.Code {
+		pgErr := new(pgconn.PgError)
+		require.Truef(t, errors.As(err, &pgErr), "%+v", err)
+		if pgErr.Code == pgcode.TransactionCommittedWithSchemaChangeFailure.String() {
+			if m := underlyingErrorCode.FindStringSubmatch(pgErr.Error()); m != nil {
+				return pgcode.MakeCode(m[1])
+			}
+		}
+		return pgcode.MakeCode(pgErr.Code)
+	}
+	tableColumns := func(tx pgx.Tx, tableName *tree.TableName, names []string) []*column {
+		columns, err := og.getTableColumns(ctx, tx, tableName, false /* shuffle */)
+		require.NoError(t, err)
+		var ret []*column
+		for _, name := range names {
+			for i := range columns {
+				if columns[i].name == name {
+					ret = append(ret, &columns[i])
+				}
+			}
+		}
+		require.Lenf(t, ret, len(names), "columns %v of %s", names, tableName)
+		return ret
+	}
+
+	datadriven.RunTest(t, datapathutils.TestDataPath(t, "add_foreign_key"), func(t *testing.T, d *datadriven.TestData) string {
+		switch d.Cmd {
+		case "exec":
+			h.tdb.Exec(t, d.Input)
+			return ""
+
+		case "add-foreign-key":
+			var child, parent string
+			var childColumnNames, parentColumnNames []string
+			d.ScanArgs(t, "child", &child)
+			d.ScanArgs(t, "child-columns", &childColumnNames)
+			d.ScanArgs(t, "parent", &parent)
+			d.ScanArgs(t, "parent-columns", &parentColumnNames)
+			var acts tree.ReferenceActions
+			for _, arg := range []struct {
+				key    string
+				action *tree.ReferenceAction
+			}{{"on-delete", &acts.Delete}, {"on-update", &acts.Update}} {
+				if d.HasArg(arg.key) {
+					var name string
+					d.ScanArgs(t, arg.key, &name)
+					action, ok := actions[name]
+					require.Truef(t, ok, "unknown action %q", name)
+					*arg.action = action
+				}
+			}
+			validationBehavior := tree.ValidationDefault
+			if d.HasArg("not-valid") {
+				validationBehavior = tree.ValidationSkip
+			}
+			useDeclarativeSchemaChanger := true
+			if d.HasArg("schema-changer") {
+				var schemaChanger string
+				d.ScanArgs(t, "schema-changer", &schemaChanger)
+				useDeclarativeSchemaChanger = schemaChanger == "declarative"
+			}
+
+			og.resetTxnState()
+			og.resetOpState(useDeclarativeSchemaChanger)
+			tx, err := h.conn.Begin(ctx)
+			require.NoError(t, err)
+			if useDeclarativeSchemaChanger {
+				_, err = tx.Exec(ctx, `SET use_declarative_schema_changer = 'unsafe_always'`)
+			} else {
+				_, err = tx.Exec(ctx, `SET use_declarative_schema_changer = 'off'`)
+			}
+			require.NoError(t, err)
+
+			childTable, parentTable := publicTableName(child), publicTableName(parent)
+			stmt, err := og.addForeignKeyConstraintStmt(
+				ctx, tx,
+				parentTable, tableColumns(tx, parentTable, parentColumnNames),
+				childTable, tableColumns(tx, childTable, childColumnNames),
+				acts, validationBehavior,
+			)
+			require.NoError(t, err)
+
+			var sb strings.Builder
+			fmt.Fprintf(&sb, "%s\n", stmt.sql)
+			fmt.Fprintf(&sb, "expected exec errors: %v\n", stmt.expectedExecErrors.StringSlice())
+			fmt.Fprintf(&sb, "expected commit errors: %v\n", og.candidateExpectedCommitErrors.StringSlice())
+
+			if _, err := tx.Exec(ctx, stmt.sql); err != nil {
+				code := errorCode(err)
+				require.Truef(t, stmt.expectedExecErrors.contains(code) || stmt.potentialExecErrors.contains(code),
+					"unexpected exec error: %+v", err)
+				require.NoError(t, tx.Rollback(ctx))
+				fmt.Fprintf(&sb, "exec: %s\n", code)
+				return sb.String()
+			}
+			require.True(t, stmt.expectedExecErrors.empty(), "expected an exec error")
+			sb.WriteString("exec: ok\n")
+
+			if err := tx.Commit(ctx); err != nil {
+				code := errorCode(err)
+				require.Truef(t, og.candidateExpectedCommitErrors.contains(code) || og.potentialCommitErrors.contains(code),
+					"unexpected commit error: %+v", err)
+				fmt.Fprintf(&sb, "commit: %s\n", code)
+				return sb.String()
+			}
+			require.True(t, og.candidateExpectedCommitErrors.empty(), "expected a commit error")
+			sb.WriteString("commit: ok\n")
+			return sb.String()
+
+		default:
+			t.Fatalf("unknown directive %q", d.Cmd)
+		}
+		return ""
+	})
+}
+
+// TestSetColumnTypeErrorPrediction checks how setColumnTypeStmt classifies
+// conversions between types, and that the errors it predicts match the errors
+// returned by both schema changers.
+func TestSetColumnTypeErrorPrediction(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{})
+	defer cleanup()
+	ctx, og := h.ctx, h.og
+	h.tdb.Exec(t, `CREATE TYPE enum_w0_0 AS ENUM ('a', 'b')`)
+	h.tdb.Exec(t, `CREATE TABLE table_w0_1 (i INT2, n INT8, s STRING, e enum_w0_0)`)
+	h.tdb.Exec(t, `INSERT INTO table_w0_1 VALUES (1, 1, 'not a number', 'a')`)
+	tableName := publicTableName("table_w0_1")
+
+	for _, tc := range []struct {
+		name         string
+		column       string
+		newType      *types.T
+		useUsingExpr bool
+		conversion   columnTypeConversion
+		needsUsing   bool
+		expected     []string
+	}{
+		{
+			name:       "int2 to int8",
+			column:     "i",
+			newType:    types.Int,
+			conversion: columnTypeConversionTrivial,
+		},
+		{
+			// INT values can be assigned to a STRING column, but they have to be
+			// rewritten, which isn't supported in explicit transactions.
+			name:       "int to string",
+			column:     "n",
+			newType:    types.String,
+			conversion: columnTypeConversionValidated,
+			expected:   []string{pgcode.FeatureNotSupported.String()},
+		},
+		{
+			name:         "string to int with bad data",
+			column:       "s",
+			newType:      types.Int,
+			useUsingExpr: true,
+			conversion:   columnTypeConversionValidated,
+			needsUsing:   true,
+			expected:     []string{pgcode.FeatureNotSupported.String()},
+		},
+		{
+			name:       "string to int without using",
+			column:     "s",
+			newType:    types.Int,
+			conversion: columnTypeConversionValidated,
+			needsUsing: true,
+			expected:   []string{pgcode.FeatureNotSupported.String()},
+		},
+		{
+			name:       "enum to int",
+			column:     "e",
+			newType:    types.Int,
+			conversion: columnTypeConversionDisallowed,
+			expected:   []string{pgcode.CannotCoerce.String()},
+		},
+	} {
+		for _, useDeclarativeSchemaChanger := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/declarative=%t", tc.name, useDeclarativeSchemaChanger), func(t *testing.T) {
+				og.resetTxnState()
+				og.resetOpState(useDeclarativeSchemaChanger)
+				tx, err := h.conn.Begin(ctx)
+				require.NoError(t, err)
+				defer func() { _ = tx.Rollback(ctx) }()
+				if useDeclarativeSchemaChanger {
+					_, err = tx.Exec(ctx, `SET use_declarative_schema_changer = 'unsafe_always'`)
+				} else {
+					_, err = tx.Exec(ctx, `SET use_declarative_schema_changer = 'off'`)
+				}
+				require.NoError(t, err)
+
+				columns, err := og.getTableColumns(ctx, tx, tableName, false /* shuffle */)
+				require.NoError(t, err)
+				var col *column
+				for i := range columns {
+					if columns[i].name == tc.column {
+						col = &columns[i]
+					}
+				}
+				require.NotNil(t, col)
+
+				conversion, needsUsing := classifyColumnTypeConversion(col.typ, tc.newType)
+				require.Equal(t, tc.conversion, conversion)
+				require.Equal(t, tc.needsUsing, needsUsing)
+
+				typeName := tree.MakeUnqualifiedTypeName(tc.newType.SQLString())
+				stmt, err := og.setColumnTypeStmt(ctx, tx, tableName, col, &typeName, tc.newType, tc.useUsingExpr)
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, stmt.expectedExecErrors.StringSlice())
+
+				if err := stmt.executeStmt(ctx, tx, og); err != nil {
+					require.Truef(t, errors.Is(err, errRunInTxnRbkSentinel), "%+v", err)
+				} else {
+					require.Empty(t, tc.expected)
+				}
+			})
+		}
+	}
+}
+
+// TestCheckConstraintValidation walks through the online addition of a CHECK
+// constraint: the constraint is added with NOT VALID over rows that violate
+// it, violating writes are rejected, validating it is predicted to fail, and,
+// once deleteCheckViolations removed the offending rows, validating it is
+// predicted to succeed.
+func TestCheckConstraintValidation(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{},
+		`SET CLUSTER SETTING sql.defaults.use_declarative_schema_changer = 'off'`,
+	)
+	defer cleanup()
+	ctx, og := h.ctx, h.og
+	h.tdb.Exec(t, `CREATE TABLE table_w0_1 (a INT8 PRIMARY KEY, b INT8)`)
+	h.tdb.Exec(t, `INSERT INTO table_w0_1 VALUES (1, NULL), (2, 2)`)
+	h.tdb.Exec(t, `ALTER TABLE table_w0_1 ADD CONSTRAINT check_b CHECK (b IS NOT NULL) NOT VALID`)
+
+	// run runs a statement generated by gen, which must be expected to fail
+	// with the given errors.
+	run := func(gen func(context.Context, pgx.Tx) (*opStmt, error), expected []string) *opStmt {
+		stmt := h.run(gen)
+		require.Equal(t, expected, stmt.expectedExecErrors.StringSlice(), stmt.sql)
+		require.Equal(t, len(expected) == 0, stmt.outcome == pgcode.SuccessfulCompletion, stmt.sql)
+		return stmt
+	}
+	// validateCheck generates a VALIDATE CONSTRAINT statement for check_b.
+	// validateConstraint may also pick the primary key, so statements for
+	// other constraints are discarded.
+	validateCheck := func(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+		for {
+			stmt, err := og.validateConstraint(ctx, tx)
+			if err != nil || strings.HasSuffix(stmt.sql, "VALIDATE CONSTRAINT check_b") {
+				return stmt, err
+			}
+			og.resetOpState(false /* useDeclarativeSchemaChanger */)
+		}
+	}
+
+	// The constraint is enforced on new rows, even though it wasn't validated.
+	func() {
+		tx := h.begin()
+		defer func() { require.NoError(t, tx.Rollback(ctx)) }()
+		violated, err := og.violatesCheckConstraints(ctx, tx, publicTableName("table_w0_1"),
+			[]column{{name: "a", typ: types.Int}, {name: "b", typ: types.Int}}, [][]string{{"3", "NULL"}},
+		)
+		require.NoError(t, err)
+		require.True(t, violated)
+	}()
+	_, err := h.conn.Exec(ctx, `INSERT INTO table_w0_1 VALUES (3, NULL)`)
+	var pgErr *pgconn.PgError
+	require.True(t, errors.As(err, &pgErr))
+	require.Equal(t, pgcode.CheckViolation.String(), pgErr.Code)
+
+	// The existing rows violate the constraint, so it cannot be validated.
+	run(validateCheck, []string{pgcode.CheckViolation.String()})
+
+	// Deleting the offending rows lets it be validated.
+	// deleteCheckViolations occasionally looks for a validated constraint, of
+	// which there are none.
+	stmt := run(func(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+		for {
+			stmt, err := og.deleteCheckViolations(ctx, tx)
+			if !errors.Is(err, pgx.ErrNoRows) {
+				return stmt, err
+			}
+		}
+	}, nil)
+	require.Contains(t, stmt.sql, "DELETE FROM public.table_w0_1")
+	run(validateCheck, nil)
+	h.tdb.CheckQueryResults(t,
+		`SELECT convalidated FROM pg_catalog.pg_constraint WHERE conname = 'check_b'`,
+		[][]string{{"true"}},
+	)
+	h.tdb.CheckQueryResults(t, `SELECT a, b FROM table_w0_1`, [][]string{{"2", "2"}})
+}
+
+// TestOpResultCounts runs a fixed sequence of statements and checks that the
+// results recorded for each operation match them.
+func TestOpResultCounts(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	reg := prometheus.NewRegistry()
+	counter := setupSchemaChangePromCounter(reg)
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{opResults: counter.opResults})
+	defer cleanup()
+
+	for _, s := range []struct {
+		op       opType
+		sql      string
+		expected []pgcode.Code
+	}{
+		{op: createTable, sql: `CREATE TABLE t (a INT8 PRIMARY KEY)`},
+		{op: insertRow, sql: `INSERT INTO t VALUES (1)`},
+		{op: insertRow, sql: `INSERT INTO t VALUES (1)`, expected: []pgcode.Code{pgcode.UniqueViolation}},
+		{op: insertRow, sql: `INSERT INTO t VALUES (2)`, expected: []pgcode.Code{pgcode.UniqueViolation}},
+		{op: createIndex, sql: `CREATE INDEX ON missing (a)`},
+		{op: selectStmt, sql: `SELECT * FROM t`},
+	} {
+		// Each statement runs in its own transaction, like the operations
+		// generated by randOp.
+		tx := h.begin()
+		h.og.opsInTxn = append(h.og.opsInTxn, s.op)
+		stmt := makeOpStmtForSingleError(OpStmtDDL, s.sql, s.expected...)
+		if err := stmt.executeStmt(h.ctx, tx, h.og); err != nil {
+			require.NoError(t, tx.Rollback(h.ctx))
+			continue
+		}
+		require.NoError(t, tx.Commit(h.ctx))
+	}
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	counts := make(map[string]float64)
+	for _, family := range families {
+		if !strings.HasSuffix(family.GetName(), "schema_change_op_results") {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			key := fmt.Sprintf("%s/%s/%s", labels["op"], labels["result"], labels["code"])
+			counts[key] = m.GetCounter().GetValue()
+		}
+	}
+	require.Equal(t, map[string]float64{
+		"createTable/success/00000":          1,
+		"insertRow/success/00000":            1,
+		"insertRow/expected_error/23505":     1,
+		"insertRow/missing_error/00000":      1,
+		"createIndex/unexpected_error/42P01": 1,
+		"selectStmt/success/00000":           1,
+	}, counts)
+}
+
+// TestFunctionDependencies checks the errors predicted when creating and
+// dropping functions, including the functions that other functions call.
+func TestFunctionDependencies(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{errorRate: 50})
+	defer cleanup()
+	og := h.og
+	h.tdb.Exec(t, `CREATE TABLE t (a INT8 PRIMARY KEY)`)
+	h.tdb.Exec(t, `CREATE FUNCTION callee() RETURNS INT8 LANGUAGE SQL AS $$ SELECT count(*) FROM t $$`)
+	h.tdb.Exec(t, `CREATE FUNCTION caller() RETURNS INT8 LANGUAGE SQL AS $$ SELECT callee() $$`)
+
+	// callee is called by caller, so it can only be dropped once caller is.
+	seen := make(map[string]struct{})
+	for i := 0; i < 100; i++ {
+		stmt := h.runInTxn(og.dropFunction, false /* commit */)
+		if stmt == nil {
+			continue
+		}
+		expected := stmt.expectedExecErrors.StringSlice()
+		switch {
+		case strings.HasSuffix(stmt.sql, " CASCADE"):
+			require.Equal(t, []string{pgcode.FeatureNotSupported.String()}, expected, stmt.sql)
+		case strings.Contains(stmt.sql, "callee()"):
+			require.Equal(t, []string{pgcode.DependentObjectsStillExist.String()}, expected, stmt.sql)
+		case strings.Contains(stmt.sql, "caller()"):
+			require.Empty(t, expected, stmt.sql)
+		}
+		seen[stmt.sql] = struct{}{}
+	}
+	require.Contains(t, seen, `DROP FUNCTION public.callee()`)
+	require.Contains(t, seen, `DROP FUNCTION public.caller()`)
+
+	// Functions created by the workload can be dropped in turn.
+	for i := 0; i < 20; i++ {
+		h.run(og.createFunction)
+	}
+	h.tdb.Exec(t, `DROP FUNCTION caller`)
+	og.params.errorRate = 0
+	numFunctions := func() (n int) {
+		h.tdb.QueryRow(t, `SELECT count(*) FROM [SHOW FUNCTIONS]`).Scan(&n)
+		return n
+	}
+	for i := 0; i < 1000 && numFunctions() > 0; i++ {
+		h.run(og.dropFunction)
+	}
+	require.Zero(t, numFunctions())
+}
+
+// TestAlterFunction checks the errors predicted when altering the options,
+// the owner and the name of functions.
+func TestAlterFunction(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t,
+		&operationGeneratorParams{errorRate: 50, roles: newRolePool("creator", "reader")},
+		`SET CLUSTER SETTING sql.defaults.use_declarative_schema_changer = 'off'`,
+	)
+	defer cleanup()
+	og := h.og
+	h.tdb.Exec(t, `CREATE TABLE t (a INT8 PRIMARY KEY)`)
+	h.tdb.Exec(t, `CREATE FUNCTION callee() RETURNS INT8 LANGUAGE SQL AS $$ SELECT count(*) FROM t $$`)
+	h.tdb.Exec(t, `CREATE FUNCTION caller() RETURNS INT8 LANGUAGE SQL AS $$ SELECT callee() $$`)
+	h.tdb.Exec(t, `CREATE FUNCTION pure(i INT8) RETURNS INT8 LANGUAGE SQL AS $$ SELECT i $$`)
+	h.tdb.Exec(t, `CREATE ROLE creator`)
+	h.tdb.Exec(t, `CREATE ROLE reader`)
+	h.tdb.Exec(t, `GRANT CREATE ON DATABASE defaultdb TO creator`)
+
+	// generate generates statements with the given function, each in its own
+	// transaction which is rolled back, and returns the errors expected for
+	// each distinct statement.
+	generate := func(gen func(context.Context, pgx.Tx) (*opStmt, error)) map[string][]string {
+		expected := make(map[string][]string)
+		for i := 0; i < 500; i++ {
+			stmt := h.runInTxn(gen, false /* commit */)
+			expected[stmt.sql] = stmt.expectedExecErrors.StringSlice()
+		}
+		return expected
+	}
+
+	// Only immutable functions may be leakproof.
+	for sql, expected := range generate(og.alterFunctionOptions) {
+		if strings.Contains(sql, "NoSuchFunction") {
+			continue
+		}
+		leakProof := strings.Contains(sql, " LEAKPROOF") && !strings.Contains(sql, "NOT LEAKPROOF")
+		if leakProof && !strings.Contains(sql, "IMMUTABLE") {
+			require.Equal(t, []string{pgcode.InvalidFunctionDefinition.String()}, expected, sql)
+		} else {
+			require.Empty(t, expected, sql)
+		}
+	}
+
+	// The new owner of a function must be allowed to create it.
+	owners := generate(og.alterFunctionSetOwner)
+	require.Equal(t,
+		[]string{pgcode.InsufficientPrivilege.String()},
+		owners[`ALTER FUNCTION public.pure(INT8) OWNER TO reader`],
+	)
+	require.Contains(t, owners, `ALTER FUNCTION public.pure(INT8) OWNER TO creator`)
+	require.Empty(t, owners[`ALTER FUNCTION public.pure(INT8) OWNER TO creator`])
+
+	// Functions that are called by other functions cannot be renamed, while
+	// the others can, and still resolve under their new name.
+	var renamePure string
+	for sql, expected := range generate(og.alterFunctionRename) {
+		switch {
+		case strings.HasPrefix(sql, `ALTER FUNCTION public.callee() RENAME TO`):
+			require.Equal(t, []string{pgcode.FeatureNotSupported.String()}, expected, sql)
+		case strings.HasPrefix(sql, `ALTER FUNCTION public.pure(INT8) RENAME TO`):
+			require.Empty(t, expected, sql)
+			renamePure = sql
+		}
+	}
+	require.NotEmpty(t, renamePure)
+	h.tdb.Exec(t, renamePure)
+	name := strings.TrimPrefix(renamePure, `ALTER FUNCTION public.pure(INT8) RENAME TO `)
+	h.tdb.CheckQueryResults(t, fmt.Sprintf(`SELECT %s(7)`, name), [][]string{{"7"}})
+	h.tdb.CheckQueryResults(t, `SELECT caller()`, [][]string{{"0"}})
+}
+
+// TestCreateStats checks the errors predicted when creating statistics, and
+// that statistics created by the workload are visible and leave the
+// descriptors valid.
+func TestCreateStats(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{errorRate: 50},
+		`SET CLUSTER SETTING sql.stats.automatic_collection.enabled = false`,
+	)
+	defer cleanup()
+	ctx, og := h.ctx, h.og
+	h.tdb.Exec(t, `CREATE TABLE table_w0_1 (a INT8 PRIMARY KEY, b STRING, c INT8 AS (a + 1) VIRTUAL)`)
+	h.tdb.Exec(t, `INSERT INTO table_w0_1 SELECT i, i::STRING FROM generate_series(1, 100) AS g(i)`)
+	h.tdb.Exec(t, `CREATE VIEW view_w0_1 AS SELECT a FROM table_w0_1`)
+
+	// Statistics are only created by the first statement of a transaction.
+	func() {
+		tx := h.begin()
+		defer func() { require.NoError(t, tx.Rollback(ctx)) }()
+		og.opsInTxn = append(og.opsInTxn, insertRow)
+		_, err := og.createStats(ctx, tx)
+		require.ErrorIs(t, err, pgx.ErrNoRows)
+	}()
+
+	// Each statement runs in its own transaction, which fails if the errors
+	// predicted for it are wrong.
+	var created []string
+	for i := 0; i < 20; i++ {
+		stmt := h.run(og.createStats)
+		// Virtual columns are never picked.
+		if target, _, _ := strings.Cut(stmt.sql, " FROM "); strings.Contains(target, " ON ") {
+			_, columns, _ := strings.Cut(target, " ON ")
+			require.NotContains(t, strings.Split(columns, ", "), "c", stmt.sql)
+		}
+		if stmt.outcome == pgcode.SuccessfulCompletion && strings.Contains(stmt.sql, "FROM public.table_w0_1") {
+			// The job reads the table as of the timestamp of the transaction.
+			require.Contains(t, stmt.sql, "AS OF SYSTEM TIME '", stmt.sql)
+			created = append(created, strings.Fields(stmt.sql)[2])
+		}
+	}
+	require.NotEmpty(t, created)
+
+	// The statistics show up for the table, and the descriptors are still
+	// valid.
+	var names []string
+	for _, row := range h.tdb.QueryStr(t,
+		`SELECT DISTINCT statistics_name FROM [SHOW STATISTICS FOR TABLE table_w0_1]`,
+	) {
+		names = append(names, row[0])
+	}
+	require.Subset(t, names, created)
+	require.NoError(t, h.validate())
+}
+
+// TestAlterDatabase checks that alterDatabaseOwner transfers databases created
+// by the workload to roles of the pool, and that alterDatabasePlacement
+// predicts that a database without regions can't have a placement policy.
+func TestAlterDatabase(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t,
+		&operationGeneratorParams{errorRate: 50, roles: newRolePool("creator", "reader")},
+	)
+	defer cleanup()
+	og := h.og
+	h.tdb.Exec(t, `CREATE DATABASE database_w0_1`)
+	h.tdb.Exec(t, `CREATE ROLE creator`)
+	h.tdb.Exec(t, `CREATE ROLE reader`)
+
+	// Each statement runs in its own transaction, which fails if the errors
+	// predicted for it are wrong. Successful transfers are committed and must
+	// show up in the catalog.
+	owners := map[string]bool{}
+	for i := 0; i < 50; i++ {
+		stmt := h.run(og.alterDatabaseOwner)
+		if stmt.outcome != pgcode.SuccessfulCompletion {
+			continue
+		}
+		owner := strings.ToLower(strings.TrimPrefix(stmt.sql, "ALTER DATABASE database_w0_1 OWNER TO "))
+		if owner == "current_user" {
+			owner = username.RootUser
+		}
+		h.tdb.CheckQueryResults(t,
+			`SELECT owner FROM [SHOW DATABASES] WHERE database_name = 'database_w0_1'`,
+			[][]string{{owner}},
+		)
+		owners[owner] = true
+	}
+	require.NotEmpty(t, owners)
+
+	// The database of the connection has no regions.
+	for i := 0; i < 10; i++ {
+		stmt := h.runInTxn(og.alterDatabasePlacement, false /* commit */)
+		require.Equal(t, []string{pgcode.InvalidName.String()}, stmt.expectedExecErrors.StringSlice())
+		require.Equal(t, pgcode.InvalidName, stmt.outcome)
+	}
+}
+
+// TestTableStorageParams sets and resets storage parameters of tables with
+// and without row-level TTL, and checks that the TTL parameters set on a table
+// show up in its reloptions.
+func TestTableStorageParams(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{errorRate: 50},
+		`SET CLUSTER SETTING sql.defaults.use_declarative_schema_changer = 'off'`,
+	)
+	defer cleanup()
+	og := h.og
+	h.tdb.Exec(t, `CREATE TABLE table_w0_1 (a INT8 PRIMARY KEY)`)
+	h.tdb.Exec(t, `CREATE TABLE table_w0_2 (
+		a INT8 PRIMARY KEY,
+		expires_at TIMESTAMPTZ DEFAULT now()
+	) WITH (ttl_expiration_expression = 'expires_at + ''1 day''::INTERVAL')`)
+	h.tdb.Exec(t, `CREATE TABLE table_w0_3 (a INT8 PRIMARY KEY REFERENCES table_w0_1 (a))`)
+
+	for i := 0; i < 100; i++ {
+		h.run(og.alterTableSetStorageParams)
+		if stmt := h.run(og.alterTableResetStorageParams); stmt != nil {
+			require.Contains(t, stmt.sql, " RESET (")
+		}
+	}
+
+	// A batch size set on the table with row-level TTL shows up in its
+	// reloptions, along with the TTL itself.
+	og.params.errorRate = 0
+	batchSizeRE := regexp.MustCompile(`ttl_select_batch_size'? = (\d+)`)
+	for i := 0; ; i++ {
+		require.Less(t, i, 1000, "no batch size was set on table_w0_2")
+		stmt := h.run(og.alterTableSetStorageParams)
+		m := batchSizeRE.FindStringSubmatch(stmt.sql)
+		if m == nil || !strings.Contains(stmt.sql, "table_w0_2") || !stmt.expectedExecErrors.empty() {
+			continue
+		}
+		var reloptions []string
+		require.NoError(t, h.conn.QueryRow(h.ctx,
+			`SELECT reloptions FROM pg_class WHERE oid = 'table_w0_2'::REGCLASS`,
+		).Scan(&reloptions))
+		require.Contains(t, reloptions, "ttl='on'")
+		require.Contains(t, reloptions, "ttl_select_batch_size="+m[1])
+		break
+	}
+}
+
+// TestRowLevelTTL checks the errors predicted for enabling, changing and
+// disabling row-level TTL against the errors actually returned when executing
+// and committing the statements.
+//
+// The following directives are supported:
+//
+//   - exec: runs the SQL statements in the input.
+//   - query: runs the query in the input and prints its rows.
+//   - enable-ttl table=<table>: sets the ttl_expire_after of the table to the
+//     interval in the input.
+//   - alter-ttl table=<table> [select-batch-size=<size>]: sets the
+//     ttl_job_cron of the table to the input, if any, and its
+//     ttl_select_batch_size.
+//   - disable-ttl table=<table>: resets the row-level TTL of the table.
+//   - validate: validates the descriptors, like the validate operation.
+//
+// Each statement runs in its own transaction with the legacy schema changer,
+// and the statement, the predicted errors and the outcome of executing and
+// committing it are printed.
+func TestRowLevelTTL(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{})
+	defer cleanup()
+	ctx, og := h.ctx, h.og
+	_, err := h.conn.Exec(ctx, `SET use_declarative_schema_changer = 'off'`)
+	require.NoError(t, err)
+
+	// table returns the table with the given name, as seen by the operations.
+	table := func(tx pgx.Tx, d *datadriven.TestData) storageParamTable {
+		var name string
+		d.ScanArgs(t, "table", &name)
+		tables, err := og.storageParamTables(ctx, tx)
+		require.NoError(t, err)
+		for _, table := range tables {
+			if table.Name == name {
+				return table
+			}
+		}
+		t.Fatalf("table %q not found", name)
+		return storageParamTable{}
+	}
+	// run generates a statement in its own transaction and executes it.
+	run := func(gen func(tx pgx.Tx) *opStmt) string {
+		tx := h.begin()
+		stmt := gen(tx)
+
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "%s\n", stmt.sql)
+		fmt.Fprintf(&sb, "expected exec errors: %v\n", stmt.expectedExecErrors.StringSlice())
+		if err := stmt.executeStmt(ctx, tx, og); err != nil {
+			require.Truef(t, errors.Is(err, errRunInTxnRbkSentinel), "%+v", err)
+			require.NoError(t, tx.Rollback(ctx))
+			fmt.Fprintf(&sb, "exec: %s\n", stmt.outcome)
+			return sb.String()
+		}
+		sb.WriteString("exec: ok\n")
+		require.NoError(t, tx.Commit(ctx))
+		sb.WriteString("commit: ok\n")
+		return sb.String()
+	}
+
+	datadriven.RunTest(t, datapathutils.TestDataPath(t, "row_level_ttl"), func(t *testing.T, d *datadriven.TestData) string {
+		switch d.Cmd {
+		case "exec":
+			h.tdb.Exec(t, d.Input)
+			return ""
+
+		case "query":
+			var sb strings.Builder
+			for _, row := range h.tdb.QueryStr(t, d.Input) {
+				fmt.Fprintf(&sb, "%s\n", strings.Join(row, " "))
+			}
+			return sb.String()
+
+		case "enable-ttl":
+			return run(func(tx pgx.Tx) *opStmt {
+				return og.alterTableEnableRowLevelTTLStmt(table(tx, d), strings.TrimSpace(d.Input))
+			})
+
+		case "alter-ttl":
+			var params tree.StorageParams
+			if jobCron := strings.TrimSpace(d.Input); jobCron != "" {
+				params = append(params, tree.StorageParam{Key: "ttl_job_cron", Value: tree.NewStrVal(jobCron)})
+			}
+			if d.HasArg("select-batch-size") {
+				var batchSize int
+				d.ScanArgs(t, "select-batch-size", &batchSize)
+				params = append(params, tree.StorageParam{
+					Key:   "ttl_select_batch_size",
+					Value: tree.NewDInt(tree.DInt(batchSize)),
+				})
+			}
+			return run(func(tx pgx.Tx) *opStmt {
+				return og.alterTableAlterRowLevelTTLStmt(table(tx, d), params)
+			})
+
+		case "disable-ttl":
+			return run(func(tx pgx.Tx) *opStmt {
+				stmt, err := og.alterTableDisableRowLevelTTLStmt(ctx, tx, table(tx, d))
+				require.NoError(t, err)
+				return stmt
+			})
+
+		case "validate":
+			if err := h.validate(); err != nil {
+				return err.Error()
+			}
+			return "ok"
+
+		default:
+			t.Fatalf("unknown directive %q", d.Cmd)
+		}
+		return ""
+	})
+}
+
+// TestColumnOnUpdate checks the errors predicted for setting and dropping the
+// ON UPDATE expressions of columns, and that an expression which was set is
+// assigned to the column when a row is updated.
+func TestColumnOnUpdate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{errorRate: 50},
+		`SET CLUSTER SETTING sql.defaults.use_declarative_schema_changer = 'off'`,
+	)
+	defer cleanup()
+	og := h.og
+	h.tdb.Exec(t, `CREATE TABLE table_w0_1 (a INT8 PRIMARY KEY, b STRING, c INT8)`)
+	h.tdb.Exec(t, `INSERT INTO table_w0_1 VALUES (1, 'a', 1), (2, 'b', 2)`)
+	h.tdb.Exec(t, `CREATE TABLE table_w0_2 (
+		a INT8 PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+		b INT8 REFERENCES table_w0_1 (a) ON UPDATE CASCADE,
+		c INT8 AS (b + 1) STORED
+	)`)
+
+	for i := 0; i < 100; i++ {
+		h.run(og.setColumnOnUpdate)
+		h.run(og.dropColumnOnUpdate)
+	}
+
+	// Updating a row without changing the column assigns the expression to
+	// it.
+	og.params.errorRate = 0
+	onUpdateRE := regexp.MustCompile(`table_w0_1 ALTER COLUMN "b" SET ON UPDATE (.+)$`)
+	for i := 0; ; i++ {
+		require.Less(t, i, 1000, "no ON UPDATE expression was set on table_w0_1.b")
+		stmt := h.run(og.setColumnOnUpdate)
+		m := onUpdateRE.FindStringSubmatch(stmt.sql)
+		if m == nil || !stmt.expectedExecErrors.empty() {
+			continue
+		}
+		h.tdb.Exec(t, `UPDATE table_w0_1 SET c = c + 1`)
+		h.tdb.CheckQueryResults(t,
+			fmt.Sprintf(`SELECT count(*) FROM table_w0_1 WHERE b IS DISTINCT FROM (%s)`, m[1]),
+			[][]string{{"0"}},
+		)
+		break
+	}
+}
+
+// TestAlterIndexPartitionBy partitions and unpartitions indexes, and checks
+// that the partitions of an index show up in crdb_internal.partitions.
+func TestAlterIndexPartitionBy(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	defer ccl.TestingEnableEnterprise()()
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{errorRate: 50},
+		`SET CLUSTER SETTING sql.defaults.use_declarative_schema_changer = 'off'`,
+	)
+	defer cleanup()
+	og := h.og
+	h.tdb.Exec(t, `CREATE TABLE table_w0_1 (
+		a INT8,
+		b STRING,
+		c INT8,
+		PRIMARY KEY (a, b),
+		INDEX index_w0_1 (c, a)
+	)`)
+	h.tdb.Exec(t, `INSERT INTO table_w0_1 VALUES (1, 'a', 1), (2, 'b', 2)`)
+	h.tdb.Exec(t, `CREATE TABLE table_w0_2 (a INT8 PRIMARY KEY, b INT8, INDEX index_w0_2 (b) USING HASH)`)
+
+	for i := 0; i < 100; i++ {
+		stmt := h.run(og.alterIndexPartitionBy)
+		// The partitioning of hash sharded indexes can't be changed.
+		if strings.Contains(stmt.sql, "table_w0_2@index_w0_2 ") {
+			require.Contains(t, stmt.expectedExecErrors.StringSlice(),
+				pgcode.FeatureNotSupported.String())
+		}
+	}
+
+	// The partitions of an index show up once it is partitioned, and go away
+	// once its partitioning is removed.
+	og.params.errorRate = 0
+	partitionByRE := regexp.MustCompile(`table_w0_1@(\S+) PARTITION BY (LIST|RANGE)`)
+	partitionRE := regexp.MustCompile(`PARTITION (p\d) VALUES`)
+	partitionsQuery := `
+SELECT p.name
+  FROM crdb_internal.partitions AS p
+  JOIN crdb_internal.table_indexes AS i ON i.descriptor_id = p.table_id
+                                       AND i.index_id = p.index_id
+ WHERE p.table_id = 'table_w0_1'::REGCLASS::INT8
+   AND i.index_name = '%s'
+ ORDER BY p.name`
+	for i := 0; ; i++ {
+		require.Less(t, i, 1000, "no index of table_w0_1 was partitioned")
+		stmt := h.run(og.alterIndexPartitionBy)
+		m := partitionByRE.FindStringSubmatch(stmt.sql)
+		if m == nil || !stmt.expectedExecErrors.empty() {
+			continue
+		}
+		var expected [][]string
+		for _, p := range partitionRE.FindAllStringSubmatch(stmt.sql, -1) {
+			expected = append(expected, []string{p[1]})
+		}
+		h.tdb.CheckQueryResults(t, fmt.Sprintf(partitionsQuery, m[1]), expected)
+		h.tdb.Exec(t, fmt.Sprintf(`ALTER INDEX table_w0_1@%s PARTITION BY NOTHING`, m[1]))
+		require.Empty(t, h.tdb.QueryStr(t, fmt.Sprintf(partitionsQuery, m[1])))
+		break
+	}
+}
+
+// TestDropSequence checks that dropping a sequence used by a column default is
+// only expected to fail without CASCADE, which removes the default instead.
+// The statements are executed, so the predictions are checked against the
+// actual outcomes.
+func TestDropSequence(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{errorRate: 0})
+	defer cleanup()
+	h.tdb.Exec(t, `CREATE SEQUENCE seq_w0_1`)
+	h.tdb.Exec(t, `CREATE TABLE table_w0_2 (a INT8 PRIMARY KEY DEFAULT nextval('seq_w0_1'))`)
+
+	var cascade, restrict bool
+	for i := 0; i < 100 && !(cascade && restrict); i++ {
+		stmt := h.runInTxn(h.og.dropSequence, false /* commit */)
+		if !strings.HasPrefix(stmt.sql, `DROP SEQUENCE public.seq_w0_1`) &&
+			!strings.HasPrefix(stmt.sql, `DROP SEQUENCE IF EXISTS public.seq_w0_1`) {
+			continue
+		}
+		if strings.HasSuffix(stmt.sql, " CASCADE") {
+			require.Empty(t, stmt.expectedExecErrors.StringSlice(), stmt.sql)
+			cascade = true
+		} else {
+			require.Equal(t,
+				[]string{pgcode.DependentObjectsStillExist.String()},
+				stmt.expectedExecErrors.StringSlice(), stmt.sql,
+			)
+			restrict = true
+		}
+	}
+	require.True(t, cascade && restrict)
+}
+
+// TestAddPrimaryKeyConstraint adds explicit primary keys to tables keyed on
+// the implicit rowid column, and checks that the rowid column is only dropped
+// by the declarative schema changer, and only if it isn't part of the new key.
+func TestAddPrimaryKeyConstraint(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{errorRate: 0})
+	defer cleanup()
+	for _, tableName := range []string{"table_w0_1", "table_w0_2"} {
+		h.tdb.Exec(t, fmt.Sprintf(`CREATE TABLE %s (a INT8 NOT NULL, b STRING)`, tableName))
+		h.tdb.Exec(t, fmt.Sprintf(`INSERT INTO %s VALUES (1, 'x'), (2, 'y')`, tableName))
+	}
+	h.tdb.Exec(t, `CREATE TABLE table_w0_3 (a INT8 PRIMARY KEY)`)
+	// table_w0_4 has no column to key on besides rowid.
+	h.tdb.Exec(t, `CREATE TABLE table_w0_4 ()`)
+
+	setSchemaChanger := func(mode string) {
+		_, err := h.conn.Exec(h.ctx, fmt.Sprintf(`SET use_declarative_schema_changer = '%s'`, mode))
+		require.NoError(t, err)
+	}
+	setSchemaChanger("off")
+	for i := 0; i < 50; i++ {
+		stmt := h.runInTxn(h.og.addPrimaryKeyConstraint, false /* commit */)
+		if stmt == nil {
+			continue
+		}
+		require.NotContains(t, stmt.sql, "table_w0_4")
+		if strings.HasPrefix(stmt.sql, `ALTER TABLE public.table_w0_3 `) {
+			require.Equal(t,
+				[]string{pgcode.InvalidTableDefinition.String()},
+				stmt.expectedExecErrors.StringSlice(), stmt.sql,
+			)
+		}
+	}
+
+	// addKey adds a primary key to the given table, and returns whether it
+	// includes the rowid column. Statements for other tables are discarded.
+	addKey := func(tableName string) bool {
+		keyRE := regexp.MustCompile(fmt.Sprintf(
+			`^ALTER TABLE public.%s ADD CONSTRAINT \S+ PRIMARY KEY \((.*)\)$`, tableName,
+		))
+		gen := func(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+			stmt, err := h.og.addPrimaryKeyConstraint(ctx, tx)
+			if err == nil && !keyRE.MatchString(stmt.sql) {
+				return nil, pgx.ErrNoRows
+			}
+			return stmt, err
+		}
+		for i := 0; i < 1000; i++ {
+			if stmt := h.run(gen); stmt != nil && stmt.outcome == pgcode.SuccessfulCompletion {
+				return strings.Contains(keyRE.FindStringSubmatch(stmt.sql)[1], "rowid")
+			}
+		}
+		t.Fatalf("no primary key was added to %s", tableName)
+		return false
+	}
+	hasRowID := func(tableName string) bool {
+		var n int
+		h.tdb.QueryRow(t, fmt.Sprintf(
+			`SELECT count(*) FROM [SHOW COLUMNS FROM %s] WHERE column_name = 'rowid'`, tableName,
+		)).Scan(&n)
+		return n == 1
+	}
+
+	// The legacy schema changer always keeps the rowid column.
+	addKey("table_w0_1")
+	require.True(t, hasRowID("table_w0_1"))
+
+	// The declarative schema changer drops it unless it is part of the new
+	// key.
+	setSchemaChanger("unsafe_always")
+	require.Equal(t, addKey("table_w0_2"), hasRowID("table_w0_2"))
+}
+
+// TestAddPrimaryKeyUnindexableColumn checks that keying a table on a column
+// that can't be indexed is predicted to fail.
+func TestAddPrimaryKeyUnindexableColumn(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{errorRate: 0},
+		`CREATE TABLE table_w0_1 (a TSVECTOR NOT NULL)`,
+	)
+	defer cleanup()
+
+	require.Equal(t,
+		map[pgcode.Code]int{pgcode.FeatureNotSupported: 20},
+		h.outcomes(h.og.addPrimaryKeyConstraint, 20),
+	)
+}
+
+// TestCreateTableUserDefinedTypes checks that tables are created whether or
+// not there are types to give their columns, and that the composite types
+// used by the columns of tables are predicted to be in use when dropping them.
+func TestCreateTableUserDefinedTypes(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{errorRate: 0},
+		`SET CLUSTER SETTING sql.defaults.use_declarative_schema_changer = 'off'`,
+	)
+	defer cleanup()
+	for i := 0; i < 20; i++ {
+		require.NotNil(t, h.run(h.og.createTable))
+	}
+
+	h.tdb.Exec(t, `CREATE TYPE enum_w0_1 AS ENUM ('a', 'b')`)
+	h.tdb.Exec(t, `CREATE TYPE composite_w0_2 AS (a INT8, b STRING)`)
+	h.tdb.Exec(t, `CREATE TYPE composite_w0_3 AS (a INT8)`)
+	h.tdb.Exec(t, `CREATE TABLE table_w0_4 (a INT8 PRIMARY KEY, c composite_w0_2)`)
+	var enumColumn, compositeColumn bool
+	for i := 0; i < 1000 && !(enumColumn && compositeColumn); i++ {
+		stmt := h.run(h.og.createTable)
+		enumColumn = enumColumn || strings.Contains(stmt.sql, "enum_w0_1")
+		compositeColumn = compositeColumn || strings.Contains(stmt.sql, "composite_w0_")
+	}
+	require.True(t, enumColumn && compositeColumn)
+
+	// The types of columns can't be dropped.
+	h.og.params.errorRate = 50
+	var dropped bool
+	for i := 0; i < 100 && !dropped; i++ {
+		stmt := h.runInTxn(h.og.dropType, false /* commit */)
+		if stmt.sql == `DROP TYPE public.composite_w0_2` {
+			require.Equal(t,
+				[]string{pgcode.DependentObjectsStillExist.String()},
+				stmt.expectedExecErrors.StringSlice(),
+			)
+			dropped = true
+		}
+	}
+	require.True(t, dropped)
+}
+
+// TestAlterSequence alters the options of an INT2 sequence, and checks that
+// the values it returns afterwards follow its new increment.
+func TestAlterSequence(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{errorRate: 50})
+	defer cleanup()
+	h.tdb.Exec(t, `CREATE SEQUENCE seq_w0_1 AS INT2`)
+	h.tdb.Exec(t, `CREATE TABLE table_w0_2 (a INT2 PRIMARY KEY)`)
+
+	for i := 0; i < 100; i++ {
+		h.run(h.og.alterSequence)
+	}
+
+	h.og.params.errorRate = 0
+	incrementRE := regexp.MustCompile(`^ALTER SEQUENCE public.seq_w0_1 .*INCREMENT BY (-?\d+)`)
+	for i := 0; ; i++ {
+		require.Less(t, i, 1000, "the increment of seq_w0_1 wasn't changed")
+		stmt := h.run(h.og.alterSequence)
+		m := incrementRE.FindStringSubmatch(stmt.sql)
+		if m == nil || stmt.outcome != pgcode.SuccessfulCompletion {
+			continue
+		}
+		// The bounds and the current value are reset, so that two values
+		// can be drawn from the sequence.
+		h.tdb.Exec(t, `ALTER SEQUENCE seq_w0_1 MINVALUE -100 MAXVALUE 100 RESTART WITH 0`)
+		h.tdb.CheckQueryResults(t,
+			`SELECT nextval('seq_w0_1'), nextval('seq_w0_1')`,
+			[][]string{{"0", m[1]}},
+		)
+		break
+	}
+}
+
+// TestCreateSchemaInNewDatabase creates a database and, within the same
+// transaction, a schema inside of it, and checks that the schema exists once
+// the transaction commits.
+func TestCreateSchemaInNewDatabase(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{errorRate: 0})
+	defer cleanup()
+	ctx, og := h.ctx, h.og
+
+	tx := h.begin()
+	stmt, err := og.createDatabase(ctx, tx)
+	require.NoError(t, err)
+	require.NoError(t, stmt.executeStmt(ctx, tx, og))
+	fields := strings.Fields(stmt.sql)
+	database := fields[len(fields)-1]
+
+	// Most schemas are created in the database of the connection, so only
+	// the statement creating one in the new database is executed.
+	schemaRE := regexp.MustCompile(`^CREATE SCHEMA (?:IF NOT EXISTS )?` + database + `\.(\w+)`)
+	var schema string
+	for i := 0; i < 100 && schema == ""; i++ {
+		stmt, err := og.createSchema(ctx, tx)
+		require.NoError(t, err)
+		m := schemaRE.FindStringSubmatch(stmt.sql)
+		if m == nil {
+			continue
+		}
+		require.Empty(t, stmt.expectedExecErrors.StringSlice(), stmt.sql)
+		require.NoError(t, stmt.executeStmt(ctx, tx, og))
+		schema = m[1]
+	}
+	require.NotEmpty(t, schema)
+	require.NoError(t, tx.Commit(ctx))
+
+	h.tdb.CheckQueryResults(t,
+		fmt.Sprintf(`SELECT count(*) FROM [SHOW SCHEMAS FROM %s] WHERE schema_name = '%s'`, database, schema),
+		[][]string{{"1"}},
+	)
+}
+
+// TestRefreshMaterializedView checks that a materialized view refreshed on
+// its own, as workers run implicitTxnOps, reflects the rows inserted since it
+// was created, that only views with a unique index are refreshed
+// CONCURRENTLY, and that dropView drops materialized views.
+func TestRefreshMaterializedView(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{errorRate: 0},
+		`CREATE TABLE t (k INT PRIMARY KEY)`,
+		`CREATE MATERIALIZED VIEW mview_1 AS SELECT k FROM t`,
+	)
+	defer cleanup()
+	ctx, og := h.ctx, h.og
+
+	h.tdb.Exec(t, `INSERT INTO t VALUES (1), (2)`)
+	h.tdb.CheckQueryResults(t, `SELECT count(*) FROM mview_1`, [][]string{{"0"}})
+
+	tx := h.begin()
+	stmt, err := og.refreshMaterializedView(ctx, tx)
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback(ctx))
+	require.Contains(t, stmt.sql, "mview_1")
+	require.Empty(t, stmt.expectedExecErrors.StringSlice(), stmt.sql)
+	require.NoError(t, stmt.executeStmt(ctx, h.conn, og))
+	h.tdb.CheckQueryResults(t, `SELECT count(*) FROM mview_1`, [][]string{{"2"}})
+
+	// Views are only refreshed CONCURRENTLY once they have a unique index.
+	refresh := func() string {
+		tx := h.begin()
+		defer func() { require.NoError(t, tx.Rollback(ctx)) }()
+		stmt, err := og.refreshMaterializedView(ctx, tx)
+		require.NoError(t, err)
+		return stmt.sql
+	}
+	for i := 0; i < 20; i++ {
+		require.NotContains(t, refresh(), "CONCURRENTLY")
+	}
+	h.tdb.Exec(t, `CREATE UNIQUE INDEX ON mview_1 (k)`)
+	concurrently := ""
+	for i := 0; i < 50 && !strings.Contains(concurrently, "CONCURRENTLY"); i++ {
+		concurrently = refresh()
+	}
+	require.Contains(t, concurrently, "CONCURRENTLY")
+	h.tdb.Exec(t, concurrently)
+
+	for i := 0; i < 100; i++ {
+		stmt := h.run(og.dropView)
+		if stmt != nil && stmt.outcome == pgcode.SuccessfulCompletion {
+			require.True(t, strings.HasPrefix(stmt.sql, "DROP MATERIALIZED VIEW"), stmt.sql)
+			break
+		}
+	}
+	h.tdb.CheckQueryResults(t,
+		`SELECT count(*) FROM [SHOW TABLES] WHERE table_name = 'mview_1'`, [][]string{{"0"}},
+	)
+}
+
+// TestSecondaryIndexesBackingForeignKeys checks that the implicit key columns
+// of an index don't prevent it from backing a foreign key, and that partial
+// indexes never back one.
+func TestSecondaryIndexesBackingForeignKeys(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{},
+		`CREATE TABLE parent (
+			k INT PRIMARY KEY,
+			a INT,
+			b INT,
+			UNIQUE INDEX parent_a_idx (a) USING HASH,
+			UNIQUE INDEX parent_b_partial_idx (b) WHERE b > 0,
+			UNIQUE INDEX parent_b_idx (b)
+		)`,
+		`CREATE TABLE child (k INT PRIMARY KEY, a INT REFERENCES parent (a), b INT REFERENCES parent (b))`,
+	)
+	defer cleanup()
+
+	tx := h.begin()
+	defer func() { require.NoError(t, tx.Rollback(h.ctx)) }()
+	backing, err := h.og.secondaryIndexesBackingForeignKeys(h.ctx, tx, publicTableName("parent"))
+	require.NoError(t, err)
+	require.ElementsMatch(t, [][]string{
+		{"parent_a_idx", "child_a_fkey", "public", "child"},
+		{"parent_b_idx", "child_b_fkey", "public", "child"},
+	}, backing)
+}
+
+// TestTimestampTZNearBounds checks that only TIMESTAMPTZ values close to the
+// edges of the supported range are predicted to overflow.
+func TestTimestampTZNearBounds(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	format := func(ts time.Time) string {
+		d, err := tree.MakeDTimestampTZ(ts, time.Microsecond)
+		require.NoError(t, err)
+		return tree.AsStringWithFlags(d, tree.FmtParsable)
+	}
+	for _, tc := range []struct {
+		value    string
+		expected bool
+	}{
+		{value: format(time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)), expected: false},
+		{value: format(tree.MinSupportedTime), expected: true},
+		{value: format(tree.MaxSupportedTime.Add(-time.Hour)), expected: true},
+		{value: format(tree.MaxSupportedTime.Add(-48 * time.Hour)), expected: false},
+		{value: "NULL", expected: false},
+	} {
+		require.Equal(t, tc.expected, timestampTZNearBounds(tc.value), tc.value)
+	}
+}
+
+// TestViolatesCheckConstraints checks that the CHECK constraints of a table
+// are evaluated against the rows to be inserted, whatever their expression.
+func TestViolatesCheckConstraints(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{},
+		`CREATE TABLE t (
+			k INT PRIMARY KEY,
+			a INT CHECK (a IS NOT NULL AND random() >= 0),
+			s STRING CHECK (s ~ '^[a-z]+$')
+		)`,
+	)
+	defer cleanup()
+
+	cols := []column{{name: "k", typ: types.Int}, {name: "a", typ: types.Int}, {name: "s", typ: types.String}}
+	for _, tc := range []struct {
+		rows     [][]string
+		violated bool
+	}{
+		{rows: [][]string{{"1", "1", "'abc':::STRING"}}, violated: false},
+		// NULLs pass the regular expression, but not IS NOT NULL.
+		{rows: [][]string{{"1", "1", "NULL"}}, violated: false},
+		{rows: [][]string{{"1", "1", "NULL"}, {"2", "NULL", "'abc':::STRING"}}, violated: true},
+		{rows: [][]string{{"1", "1", "'ABC':::STRING"}}, violated: true},
+	} {
+		func() {
+			tx := h.begin()
+			defer func() { require.NoError(t, tx.Rollback(h.ctx)) }()
+			violated, err := h.og.violatesCheckConstraints(h.ctx, tx, publicTableName("t"), cols, tc.rows)
+			require.NoError(t, err)
+			require.Equal(t, tc.violated, violated, "%v", tc.rows)
+		}()
+	}
+}
+
+// TestEnumPlacements checks that the enum values added BEFORE or AFTER an
+// existing value are validated against their position in pg_enum.
+func TestEnumPlacements(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{errorRate: 0})
+	defer cleanup()
+	og := h.og
+	h.tdb.Exec(t, `CREATE TYPE enum_w0_1 AS ENUM ('a', 'b', 'c')`)
+
+	// Each statement runs in its own transaction, which fails if the errors
+	// predicted for it are wrong. Only committed placements are validated.
+	for i := 0; i < 50 && len(og.enumPlacements) < 5; i++ {
+		h.runInTxn(og.addTypeValue, i%2 == 0 /* commit */)
+	}
+	require.NotEmpty(t, og.enumPlacements)
+	require.NoError(t, h.validate())
+
+	// A placement that doesn't match pg_enum fails validation.
+	og.enumPlacements = append(og.enumPlacements, enumPlacement{
+		typeName: "public.enum_w0_1", value: "'c'", neighbor: "'a'", before: true,
+	})
+	require.ErrorContains(t, h.validate(), "value 'c' was added BEFORE 'a'")
+
+	// Placements are forgotten once either value is dropped.
+	h.tdb.Exec(t, `ALTER TYPE enum_w0_1 DROP VALUE 'a'`)
+	require.NoError(t, h.validate())
+}
+
+// TestImmutableCheckPredicateTerms checks that the terms of stable and
+// volatile functions are filtered out of CHECK constraints.
+func TestImmutableCheckPredicateTerms(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{})
+	defer cleanup()
+	ctx, og := h.ctx, h.og
+
+	tx := h.begin()
+	defer func() { require.NoError(t, tx.Rollback(ctx)) }()
+	terms, err := og.immutableCheckPredicateTerms(ctx, tx)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{`length('check') = 5`, `abs(-1) = 1`}, terms)
+}
+
+// TestINetColumns checks that the defaults of INET columns are valid, and
+// that indexes led by INET columns are created as predicted.
+func TestINetColumns(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{errorRate: 30})
+	defer cleanup()
+	og := h.og
+	h.tdb.Exec(t, `CREATE TABLE table_w0_1 (a INT8 PRIMARY KEY, b STRING)`)
+	h.tdb.Exec(t, `INSERT INTO table_w0_1 SELECT i, i::STRING FROM generate_series(1, 10) AS g(i)`)
+	for i, value := range inetDefaults {
+		h.tdb.Exec(t, fmt.Sprintf(`ALTER TABLE table_w0_1 ADD COLUMN col_%d INET DEFAULT %s`, i, tree.NewDString(value)))
+	}
+
+	// Each statement runs in its own transaction, which fails if the errors
+	// predicted for it are wrong.
+	var ledByINet []string
+	for i := 0; i < 50; i++ {
+		stmt := h.run(og.createIndex)
+		if stmt.outcome != pgcode.SuccessfulCompletion {
+			continue
+		}
+		if _, columns, _ := strings.Cut(stmt.sql, " ON public.table_w0_1 ("); strings.HasPrefix(columns, "col_") {
+			ledByINet = append(ledByINet, stmt.sql)
+		}
+	}
+	require.NotEmpty(t, ledByINet)
+	require.NoError(t, h.validate())
+}
+
+// TestJSONInvertedIndexes checks that inverted indexes over several JSONB
+// columns, or over a path within the last one, are created as predicted.
+func TestJSONInvertedIndexes(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{})
+	defer cleanup()
+	h.tdb.Exec(t, `CREATE TABLE table_w0_1 (a INT8 PRIMARY KEY, b JSONB, c JSONB, d JSONB)`)
+	h.tdb.Exec(t, `INSERT INTO table_w0_1 VALUES
+		(1, '{"x": 1}', '{"key_0": {"y": 1}}', '{"z": [1, 2]}'),
+		(2, '{"x": 2}', '{"key_1": {"y": 2}}', '{"z": [3]}')`)
+
+	// jsonInvertedIndex returns the index created by stmt if it is an
+	// inverted index over several columns or a path of its last column.
+	jsonInvertedIndex := func(stmt *opStmt) *tree.CreateIndex {
+		parsed, err := parser.ParseOne(stmt.sql)
+		require.NoError(t, err)
+		def := parsed.AST.(*tree.CreateIndex)
+		if !def.Inverted || (len(def.Columns) < 2 && def.Columns[0].Expr == nil) {
+			return nil
+		}
+		return def
+	}
+	// Each statement runs in its own transaction, which fails if the errors
+	// predicted for it are wrong.
+	var multiColumn, path, unsupportedOpClass bool
+	for i := 0; i < 2000 && !(multiColumn && path && unsupportedOpClass); i++ {
+		stmt := h.runInTxn(h.og.createIndex, false /* commit */)
+		if stmt == nil {
+			continue
+		}
+		def := jsonInvertedIndex(stmt)
+		if def == nil {
+			continue
+		}
+		last := def.Columns[len(def.Columns)-1]
+		switch {
+		case last.OpClass == "jsonb_path_ops":
+			unsupportedOpClass = unsupportedOpClass || stmt.outcome == pgcode.FeatureNotSupported
+		case stmt.outcome != pgcode.SuccessfulCompletion:
+		case last.Expr != nil:
+			path = true
+		case len(def.Columns) > 1:
+			multiColumn = true
+		}
+	}
+	require.True(t, multiColumn, "no inverted index over several JSONB columns")
+	require.True(t, path, "no inverted index over a JSONB path")
+	require.True(t, unsupportedOpClass, "no inverted index with jsonb_path_ops")
+
+	// Indexes created with IF NOT EXISTS may already have existed.
+	var created *tree.CreateIndex
+	for i := 0; i < 2000 && created == nil; i++ {
+		stmt := h.run(h.og.createIndex)
+		if stmt == nil || stmt.outcome != pgcode.SuccessfulCompletion {
+			continue
+		}
+		if def := jsonInvertedIndex(stmt); def != nil && !def.IfNotExists {
+			created = def
+		}
+	}
+	require.NotNil(t, created)
+	keyColumns := fmt.Sprintf(
+		`SELECT count(*) FROM [SHOW INDEXES FROM table_w0_1] WHERE index_name = '%s' AND NOT storing AND NOT implicit`,
+		created.Name,
+	)
+	h.tdb.CheckQueryResults(t, keyColumns, [][]string{{strconv.Itoa(len(created.Columns))}})
+	require.NoError(t, h.validate())
+}
+
+// TestRegClassColumns checks that the values inserted into REGCLASS columns
+// refer to the relations of the database, and that referring to a relation
+// that doesn't exist is predicted to fail.
+func TestRegClassColumns(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{errorRate: 30})
+	defer cleanup()
+	og := h.og
+	h.tdb.Exec(t, `CREATE TABLE table_w0_1 (a INT8 PRIMARY KEY, b REGCLASS)`)
+	h.tdb.Exec(t, `CREATE VIEW view_w0_1 AS SELECT 1`)
+	h.tdb.Exec(t, `CREATE SEQUENCE seq_w0_1`)
+
+	// Each statement runs in its own transaction, which fails if the errors
+	// predicted for it are wrong.
+	for i := 0; i < 30; i++ {
+		h.run(og.insertRow)
+	}
+	var relations []string
+	for _, row := range h.tdb.QueryStr(t, `SELECT DISTINCT b::STRING FROM table_w0_1 WHERE b IS NOT NULL`) {
+		relations = append(relations, row[0])
+	}
+	require.Subset(t, []string{"table_w0_1", "view_w0_1", "seq_w0_1"}, relations)
+	require.Greater(t, len(relations), 1)
+}
+
+func TestConstrainedColumns(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{},
+		`CREATE TABLE table_w0_0 (a INT8 PRIMARY KEY)`,
+		`CREATE TABLE table_w0_1 (
+			a INT8 PRIMARY KEY,
+			b INT8 UNIQUE,
+			c INT8 CHECK (c > 0),
+			d INT8 REFERENCES table_w0_0 (a),
+			e INT8,
+			f INT8 AS (e + 1) STORED,
+			g INT8,
+			h INT8,
+			INDEX (g) WHERE g > 0
+		)`,
+	)
+	defer cleanup()
+	og := h.og
+
+	tx := h.begin()
+	defer func() { require.NoError(t, tx.Rollback(h.ctx)) }()
+	for _, tc := range []struct {
+		table    string
+		expected []string
+	}{
+		// The primary key of the parent table is referenced by the foreign key.
+		{table: "table_w0_0", expected: []string{"a"}},
+		{table: "table_w0_1", expected: []string{"a", "b", "c", "d", "e", "g"}},
+	} {
+		tableName := publicTableName(tc.table)
+		cols, err := og.getTableColumns(h.ctx, tx, tableName, false /* shuffle */)
+		require.NoError(t, err)
+		constrained, err := og.constrainedColumns(h.ctx, tx, tableName, cols)
+		require.NoError(t, err)
+		require.ElementsMatch(t, tc.expected, constrained, tc.table)
+	}
+}
+
+func TestUpdateAndDeleteRows(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{},
+		`CREATE TABLE table_w0_0 (a INT8 PRIMARY KEY)`,
+		`INSERT INTO table_w0_0 SELECT generate_series(1, 100)`,
+		`CREATE TABLE table_w0_1 (
+			a INT8 PRIMARY KEY,
+			b INT8 UNIQUE,
+			c INT8 CHECK (c > 0),
+			d INT8 REFERENCES table_w0_0 (a),
+			e INT8 NOT NULL DEFAULT 0,
+			f INT8 AS (e + 1) STORED
+		)`,
+		`INSERT INTO table_w0_1 (a, b, c, d) SELECT i, i, i, i FROM generate_series(1, 100) AS i`,
+	)
+	defer cleanup()
+	og := h.og
+
+	// Only the column that isn't constrained is updated, so the updates
+	// succeed, while the deletions of referenced rows of table_w0_0 are
+	// predicted to fail.
+	const constrainedValues = `SELECT count(*) FROM table_w0_1 WHERE a = b AND a = c AND a = d AND f = e + 1`
+	for i := 0; i < 20; i++ {
+		if stmt := h.run(og.updateRow); stmt != nil {
+			require.Regexp(t, `^UPDATE public.table_w0_1 SET e = `, stmt.sql)
+		}
+	}
+	h.tdb.CheckQueryResults(t, constrainedValues, [][]string{{"100"}})
+	require.NotEqual(t, [][]string{{"0"}}, h.tdb.QueryStr(t, `SELECT count(*) FROM table_w0_1 WHERE e != 0`))
+
+	for i := 0; i < 20; i++ {
+		h.run(og.deleteRow)
+	}
+	var remaining int
+	h.tdb.QueryRow(t, `SELECT count(*) FROM table_w0_1`).Scan(&remaining)
+	h.tdb.CheckQueryResults(t, constrainedValues, [][]string{{strconv.Itoa(remaining)}})
+	h.tdb.CheckQueryResults(t,
+		`SELECT count(*) FROM table_w0_1 WHERE d NOT IN (SELECT a FROM table_w0_0)`, [][]string{{"0"}})
+	require.NoError(t, h.validate())
+}
+
+// TestWriteReadOnlyEnumValues checks that updating and inserting enum values
+// that are still being added to their type is predicted to fail.
+func TestWriteReadOnlyEnumValues(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{},
+		`SET CLUSTER SETTING sql.defaults.use_declarative_schema_changer = 'off'`,
+		`CREATE TYPE enum_w0_1 AS ENUM ('a')`,
+		`CREATE TABLE table_w0_2 (a INT8 PRIMARY KEY, b enum_w0_1 NOT NULL)`,
+		`INSERT INTO table_w0_2 VALUES (1, 'a')`,
+	)
+	defer cleanup()
+	ctx := h.ctx
+
+	// outcomes generates and executes n statements with gen, each in a
+	// transaction that adds the value 'b' to enum_w0_1 first, which can't be
+	// written before the transaction commits.
+	outcomes := func(gen func(context.Context, pgx.Tx) (*opStmt, error), n int) map[pgcode.Code]int {
+		counts := map[pgcode.Code]int{}
+		for i := 0; i < n; i++ {
+			tx := h.begin()
+			_, err := tx.Exec(ctx, `ALTER TYPE enum_w0_1 ADD VALUE 'b'`)
+			require.NoError(t, err)
+			stmt, err := gen(ctx, tx)
+			require.NoError(t, err)
+			if err := stmt.executeStmt(ctx, tx, h.og); err != nil {
+				require.Truef(t, errors.Is(err, errRunInTxnRbkSentinel), "%+v", err)
+			}
+			require.NoError(t, tx.Rollback(ctx))
+			counts[stmt.outcome]++
+		}
+		return counts
+	}
+	for _, gen := range []func(context.Context, pgx.Tx) (*opStmt, error){
+		h.og.updateRow, h.og.insertRow,
+	} {
+		counts := outcomes(gen, 50)
+		require.Contains(t, counts, pgcode.SuccessfulCompletion)
+		require.Contains(t, counts, pgcode.InvalidParameterValue)
+	}
+}
+
+// TestDropType checks the errors predicted for dropping types in use, in
+// another schema or through a schema that doesn't exist, and that the types
+// of schemas being dropped are left out.
+func TestDropType(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{errorRate: 50},
+		`SET CLUSTER SETTING sql.defaults.use_declarative_schema_changer = 'off'`,
+		`CREATE SCHEMA schema_w0_0`,
+		`CREATE TYPE schema_w0_0.enum_w0_1 AS ENUM ('a', 'b')`,
+		`CREATE TYPE enum_w0_2 AS ENUM ('a', 'b')`,
+		`CREATE TABLE table_w0_3 (a INT8 PRIMARY KEY, b enum_w0_2)`,
+	)
+	defer cleanup()
+
+	expected := map[string][]string{
+		`DROP TYPE public.enum_w0_2`:                  {pgcode.DependentObjectsStillExist.String()},
+		`DROP TYPE schema_w0_0.enum_w0_1`:             nil,
+		`DROP TYPE "SchemaThatDoesntExist".enum_w0_1`: {pgcode.UndefinedObject.String()},
+		`DROP TYPE "EnumThatDoesntExist"`:             {pgcode.UndefinedObject.String()},
+		`DROP TYPE IF EXISTS "EnumThatDoesntExist"`:   nil,
+	}
+	seen := map[string]bool{}
+	for i := 0; i < 500 && len(seen) < len(expected); i++ {
+		// The statements are rolled back, so that every case stays possible.
+		stmt := h.runInTxn(h.og.dropType, false /* commit */)
+		codes, ok := expected[stmt.sql]
+		require.True(t, ok, stmt.sql)
+		require.Equal(t, codes, stmt.expectedExecErrors.StringSlice(), stmt.sql)
+		seen[stmt.sql] = true
+	}
+	require.Len(t, seen, len(expected))
+
+	// Once its schema is dropped, a type is no longer picked, even though
+	// the drop has not been committed.
+	tx := h.begin()
+	defer func() { require.NoError(t, tx.Rollback(h.ctx)) }()
+	_, err := tx.Exec(h.ctx, `DROP SCHEMA schema_w0_0 CASCADE`)
+	require.NoError(t, err)
+	for i := 0; i < 50; i++ {
+		stmt, err := h.og.dropType(h.ctx, tx)
+		require.NoError(t, err)
+		require.NotContains(t, stmt.sql, "schema_w0_0")
+	}
+}
+
+// TestAlterTypeRenameValueInUse checks that renaming the values of a type
+// used by a column is predicted correctly, and that the rows keep their values
+// under the new names.
+func TestAlterTypeRenameValueInUse(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{errorRate: 30},
+		`CREATE TYPE enum_w0_0 AS ENUM ('a', 'b', 'c')`,
+		`CREATE TABLE table_w0_1 (a INT8 PRIMARY KEY, b enum_w0_0 NOT NULL DEFAULT 'b')`,
+		`INSERT INTO table_w0_1 (a, b) VALUES (1, 'a'), (2, 'b'), (3, 'c'), (4, 'c')`,
+		`INSERT INTO table_w0_1 (a) VALUES (5)`,
+	)
+	defer cleanup()
+
+	const values = `SELECT b::STRING FROM table_w0_1 ORDER BY a`
+	const positions = `SELECT array_position(enum_range(NULL::enum_w0_0), b) FROM table_w0_1 ORDER BY a`
+	before := h.tdb.QueryStr(t, values)
+	beforePositions := h.tdb.QueryStr(t, positions)
+	for i := 0; i < 20; i++ {
+		h.run(h.og.alterTypeRenameValue)
+	}
+	require.NotEqual(t, before, h.tdb.QueryStr(t, values), "no value in use was renamed")
+	h.tdb.CheckQueryResults(t, positions, beforePositions)
+
+	// The default expression refers to the value it had, whatever its name.
+	h.tdb.Exec(t, `INSERT INTO table_w0_1 (a) VALUES (6)`)
+	h.tdb.CheckQueryResults(t,
+		`SELECT array_position(enum_range(NULL::enum_w0_0), b) FROM table_w0_1 WHERE a = 6`,
+		[][]string{{"2"}},
+	)
+	require.NoError(t, h.validate())
+}
+
+// TestGrantRevoke grants and then revokes privileges on every kind of object
+// grant and revoke target, and checks that the descriptors stay valid.
+func TestGrantRevoke(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t,
+		&operationGeneratorParams{errorRate: 30, roles: newRolePool("role_w0_0")},
+		`CREATE ROLE role_w0_0`,
+		`CREATE SCHEMA schema_w0_1`,
+		`CREATE TABLE schema_w0_1.table_w0_2 (a INT8 PRIMARY KEY)`,
+		`CREATE SEQUENCE seq_w0_3`,
+		`CREATE TYPE enum_w0_4 AS ENUM ('a')`,
+	)
+	defer cleanup()
+
+	const numGrants = `SELECT count(*) FROM [SHOW GRANTS FOR role_w0_0, public]`
+	var before, granted, revoked int
+	h.tdb.QueryRow(t, numGrants).Scan(&before)
+	for i := 0; i < 20; i++ {
+		h.run(h.og.grant)
+	}
+	require.NoError(t, h.validate())
+	h.tdb.QueryRow(t, numGrants).Scan(&granted)
+	require.Greater(t, granted, before)
+
+	for i := 0; i < 20; i++ {
+		h.run(h.og.revoke)
+	}
+	require.NoError(t, h.validate())
+	h.tdb.QueryRow(t, numGrants).Scan(&revoked)
+	require.LessOrEqual(t, revoked, granted)
+}
+
+// TestReassignOwnedBy checks that the objects reassigned by reassignOwnedBy
+// are checked by validate to be owned by their new owner.
+func TestReassignOwnedBy(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t,
+		&operationGeneratorParams{roles: newRolePool("role_w0_0", "role_w0_1")},
+		`CREATE ROLE role_w0_0`,
+		`CREATE ROLE role_w0_1`,
+		`CREATE TABLE table_w0_2 (a INT8 PRIMARY KEY)`,
+		`ALTER TABLE table_w0_2 OWNER TO role_w0_0`,
+	)
+	defer cleanup()
+
+	const owner = `SELECT tableowner FROM pg_catalog.pg_tables WHERE tablename = 'table_w0_2'`
+	var newOwner string
+	for i := 0; i < 100 && newOwner == ""; i++ {
+		stmt := h.run(h.og.reassignOwnedBy)
+		if strings.HasPrefix(stmt.sql, "REASSIGN OWNED BY role_w0_0 TO ") {
+			newOwner = strings.TrimPrefix(stmt.sql, "REASSIGN OWNED BY role_w0_0 TO ")
+		}
+	}
+	require.NotEmpty(t, newOwner)
+	require.NotEqual(t, "role_w0_0", newOwner)
+	h.tdb.CheckQueryResults(t, owner, [][]string{{newOwner}})
+	require.NotEmpty(t, h.og.ownerReassignments)
+	require.NoError(t, h.validate())
+
+	// validate fails if the object isn't owned by the role it was reassigned
+	// to.
+	reassignments := slices.Clone(h.og.ownerReassignments)
+	for i := range h.og.ownerReassignments {
+		h.og.ownerReassignments[i].owner = "role_w0_0"
+	}
+	require.ErrorContains(t, h.validate(), "reassigned to role role_w0_0")
+	h.og.ownerReassignments = reassignments
+	require.NoError(t, h.validate())
+
+	// Once the owner changes again, the reassignment is no longer checked.
+	h.tdb.Exec(t, `ALTER TABLE table_w0_2 OWNER TO role_w0_0`)
+	require.NoError(t, h.validate())
+	require.Empty(t, h.og.ownerReassignments)
+}
+
+// TestAlterIndexVisible checks that the indexes made invisible or partially
+// visible by alterIndexVisible are still listed by the catalog, and that
+// validate passes with them.
+func TestAlterIndexVisible(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{errorRate: 20},
+		`SET CLUSTER SETTING sql.defaults.use_declarative_schema_changer = 'off'`,
+		`CREATE TABLE table_w0_0 (a INT8 PRIMARY KEY, b INT8, c INT8, INDEX index_w0_1 (b), INDEX index_w0_2 (c))`,
+	)
+	defer cleanup()
+
+	const visibility = `SELECT index_name, is_visible, visibility
+		FROM crdb_internal.table_indexes
+		WHERE descriptor_name = 'table_w0_0' AND index_type = 'secondary'
+		ORDER BY index_name`
+	var invisible, partial bool
+	for i := 0; i < 200 && !(invisible && partial); i++ {
+		h.run(h.og.alterIndexVisible)
+		rows := h.tdb.QueryStr(t, visibility)
+		// Whatever their visibility, both indexes are still listed.
+		require.Len(t, rows, 2)
+		for _, row := range rows {
+			switch {
+			case row[2] == "0":
+				require.Equal(t, "false", row[1], row[0])
+				invisible = true
+			case row[2] != "1":
+				require.Equal(t, "false", row[1], row[0])
+				partial = true
+			}
+		}
+		require.NoError(t, h.validate())
+	}
+	require.True(t, invisible, "no index was made invisible")
+	require.True(t, partial, "no index was made partially visible")
+}
+
+// TestRenameConstraint checks the errors predicted for renaming constraints,
+// and that a renamed constraint is only known under its new name.
+func TestRenameConstraint(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{errorRate: 50},
+		`SET CLUSTER SETTING sql.defaults.use_declarative_schema_changer = 'off'`,
+		`CREATE TABLE table_w0_0 (
+			a INT8 PRIMARY KEY,
+			b INT8,
+			CONSTRAINT check_w0_1 CHECK (b > 0),
+			CONSTRAINT fk_w0_2 FOREIGN KEY (b) REFERENCES table_w0_0 (a),
+			CONSTRAINT unique_w0_3 UNIQUE (b),
+			INDEX index_w0_4 (b)
+		)`,
+	)
+	defer cleanup()
+
+	outcomes := h.outcomes(h.og.renameConstraint, 200)
+	for _, code := range []pgcode.Code{
+		pgcode.SuccessfulCompletion,
+		pgcode.UndefinedTable,
+		pgcode.UndefinedObject,
+		pgcode.DuplicateObject,
+		pgcode.DuplicateRelation,
+	} {
+		require.Contains(t, outcomes, code)
+	}
+
+	const constraints = `SELECT conname FROM pg_catalog.pg_constraint
+		WHERE conrelid = 'table_w0_0'::REGCLASS AND conname LIKE 'constraint\_%'`
+	h.og.params.errorRate = 0
+	for i := 0; i < 100 && len(h.tdb.QueryStr(t, constraints)) == 0; i++ {
+		h.run(h.og.renameConstraint)
+	}
+	renamed := h.tdb.QueryStr(t, constraints)
+	require.Len(t, renamed, 1)
+	h.tdb.CheckQueryResults(t,
+		`SELECT count(*) FROM pg_catalog.pg_constraint WHERE conrelid = 'table_w0_0'::REGCLASS`,
+		[][]string{{"4"}},
+	)
+	require.NoError(t, h.validate())
+}
+
+// TestValidateForeignKeyConstraint checks that validating a foreign key added
+// with NOT VALID is predicted to fail while rows violate it, and to succeed
+// once they are removed.
+func TestValidateForeignKeyConstraint(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{},
+		`SET CLUSTER SETTING sql.defaults.use_declarative_schema_changer = 'off'`,
+		`CREATE TABLE table_w0_0 (a INT8 PRIMARY KEY)`,
+		`CREATE TABLE table_w0_1 (a INT8 PRIMARY KEY, b INT8)`,
+		`INSERT INTO table_w0_0 VALUES (1)`,
+		`INSERT INTO table_w0_1 VALUES (1, 1), (2, 2), (3, NULL)`,
+		`ALTER TABLE table_w0_1 ADD CONSTRAINT fk_b FOREIGN KEY (b) REFERENCES table_w0_0 (a) NOT VALID`,
+	)
+	defer cleanup()
+
+	// validateFK generates a VALIDATE CONSTRAINT statement for fk_b, discarding
+	// the statements generated for other constraints or tables.
+	validateFK := func(ctx context.Context, tx pgx.Tx) (*opStmt, error) {
+		for {
+			stmt, err := h.og.validateConstraint(ctx, tx)
+			if err != nil || stmt.sql == "ALTER TABLE public.table_w0_1 VALIDATE CONSTRAINT fk_b" {
+				return stmt, err
+			}
+			h.og.resetOpState(false /* useDeclarativeSchemaChanger */)
+		}
+	}
+
+	require.Equal(t,
+		map[pgcode.Code]int{pgcode.ForeignKeyViolation: 5},
+		h.outcomes(validateFK, 5),
+	)
+
+	h.tdb.Exec(t, `DELETE FROM table_w0_1 WHERE a = 2`)
+	stmt := h.run(validateFK)
+	require.Equal(t, pgcode.SuccessfulCompletion, stmt.outcome)
+	h.tdb.CheckQueryResults(t,
+		`SELECT convalidated FROM pg_catalog.pg_constraint WHERE conname = 'fk_b'`,
+		[][]string{{"true"}},
+	)
+	require.NoError(t, h.validate())
+}
+
+// TestAlterTableSetSchema checks the errors predicted for moving relations to
+// another schema, and that the moved relations stay valid.
+func TestAlterTableSetSchema(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t, &operationGeneratorParams{errorRate: 50},
+		`SET CLUSTER SETTING sql.defaults.use_declarative_schema_changer = 'off'`,
+		`CREATE SCHEMA schema_w0_0`,
+		`CREATE SCHEMA schema_w0_1`,
+		`CREATE SEQUENCE schema_w0_0.seq_w0_2`,
+		`CREATE TABLE schema_w0_0.table_w0_3 (a INT8 PRIMARY KEY)`,
+		`CREATE TABLE schema_w0_0.table_w0_4 (
+			a INT8 PRIMARY KEY DEFAULT nextval('schema_w0_0.seq_w0_2'),
+			b INT8 REFERENCES schema_w0_0.table_w0_3 (a),
+			c INT8 CHECK (c > 0),
+			INDEX (c)
+		)`,
+		// The view refers to table_w0_3 by name, which prevents moving it.
+		`CREATE VIEW schema_w0_0.view_w0_5 AS SELECT a FROM schema_w0_0.table_w0_3`,
+		// table_w0_4 can't be moved to schema_w0_1, which has a table of the
+		// same name.
+		`CREATE TABLE schema_w0_1.table_w0_4 (a INT8 PRIMARY KEY)`,
+	)
+	defer cleanup()
+
+	outcomes := h.outcomes(h.og.alterTableSetSchema, 300)
+	for _, code := range []pgcode.Code{
+		pgcode.SuccessfulCompletion,
+		pgcode.UndefinedTable,
+		pgcode.WrongObjectType,
+		pgcode.InvalidSchemaName,
+		pgcode.DuplicateRelation,
+		pgcode.DependentObjectsStillExist,
+	} {
+		require.Contains(t, outcomes, code)
+	}
+
+	h.og.params.errorRate = 0
+	for i := 0; i < 50; i++ {
+		h.run(h.og.alterTableSetSchema)
+	}
+	// Whichever schema the relations were moved to, they still work together.
+	var schema string
+	h.tdb.QueryRow(t,
+		`SELECT schema_name FROM [SHOW TABLES] WHERE table_name = 'table_w0_3'`,
+	).Scan(&schema)
+	h.tdb.Exec(t, fmt.Sprintf(`INSERT INTO %s.table_w0_3 VALUES (1)`, schema))
+	h.tdb.QueryRow(t, `SELECT table_schema FROM information_schema.columns
+		WHERE table_name = 'table_w0_4' AND column_name = 'c'`,
+	).Scan(&schema)
+	h.tdb.Exec(t, fmt.Sprintf(`INSERT INTO %s.table_w0_4 (b, c) VALUES (1, 1)`, schema))
+	h.tdb.CheckQueryResults(t, `SELECT count(*) FROM crdb_internal.invalid_objects`, [][]string{{"0"}})
+	require.NoError(t, h.validate())
+}
+
+// TestAlterSchema checks the errors predicted for renaming schemas and
+// altering their owner, and that the objects of a renamed schema are found
+// under its new name.
+func TestAlterSchema(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	h, cleanup := newGeneratorTestHarness(t,
+		&operationGeneratorParams{errorRate: 50, roles: newRolePool("role_w0_0")},
+		`SET CLUSTER SETTING sql.defaults.use_declarative_schema_changer = 'off'`,
+		`CREATE ROLE role_w0_0`,
+		`CREATE SCHEMA schema_w0_1`,
+		`CREATE TYPE schema_w0_1.enum_w0_2 AS ENUM ('a')`,
+		`CREATE SEQUENCE schema_w0_1.seq_w0_3`,
+		`CREATE TABLE schema_w0_1.table_w0_4 (a INT8 PRIMARY KEY)`,
+		`CREATE TABLE schema_w0_1.table_w0_5 (
+			a INT8 PRIMARY KEY DEFAULT nextval('schema_w0_1.seq_w0_3'),
+			b INT8 REFERENCES schema_w0_1.table_w0_4 (a),
+			c schema_w0_1.enum_w0_2
+		)`,
+		// The view refers to a table of schema_w0_6 by name, which prevents
+		// renaming the schema.
+		`CREATE SCHEMA schema_w0_6`,
+		`CREATE TABLE schema_w0_6.table_w0_7 (a INT8 PRIMARY KEY)`,
+		`CREATE VIEW view_w0_8 AS SELECT a FROM schema_w0_6.table_w0_7`,
+	)
+	defer cleanup()
+
+	outcomes := h.outcomes(h.og.alterSchemaRename, 200)
+	for _, code := range []pgcode.Code{
+		pgcode.SuccessfulCompletion,
+		pgcode.InvalidSchemaName,
+		pgcode.ReservedName,
+		pgcode.DuplicateSchema,
+		pgcode.DependentObjectsStillExist,
+	} {
+		require.Contains(t, outcomes, code)
+	}
+	outcomes = h.outcomes(h.og.alterSchemaOwner, 100)
+	for _, code := range []pgcode.Code{
+		pgcode.SuccessfulCompletion,
+		pgcode.InvalidSchemaName,
+		pgcode.UndefinedObject,
+	} {
+		require.Contains(t, outcomes, code)
+	}
+
+	// Only schema_w0_1 can be renamed.
+	h.og.params.errorRate = 0
+	h.run(h.og.alterSchemaRename)
+	var schema string
+	h.tdb.QueryRow(t,
+		`SELECT schema_name FROM [SHOW SCHEMAS] WHERE schema_name LIKE 'schema\_%' AND schema_name != 'schema_w0_6'`,
+	).Scan(&schema)
+	require.NotEqual(t, "schema_w0_1", schema)
+	h.tdb.Exec(t, fmt.Sprintf(`INSERT INTO %s.table_w0_4 VALUES (1)`, schema))
+	h.tdb.Exec(t, fmt.Sprintf(`INSERT INTO %s.table_w0_5 (b, c) VALUES (1, 'a')`, schema))
+
+	const owners = `SELECT count(*) FROM [SHOW SCHEMAS] WHERE owner = 'role_w0_0'`
+	for i := 0; i < 50 && h.tdb.QueryStr(t, owners)[0][0] == "0"; i++ {
+		h.run(h.og.alterSchemaOwner)
+	}
+	h.tdb.CheckQueryResults(t, owners, [][]string{{"1"}})
+	require.NoError(t, h.validate())
+}
+
+// TestAlterDatabaseDropRegion drops regions and the secondary region of a
+// database, before and after it is made multi-region.
+func TestAlterDatabaseDropRegion(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	skip.UnderRace(t, "multi-region cluster is too heavy")
+
+	h, cleanup := newMultiRegionGeneratorTestHarness(t, 3, /* numRegions */
+		&operationGeneratorParams{errorRate: 50},
+		`SET CLUSTER SETTING sql.defaults.use_declarative_schema_changer = 'off'`,
+	)
+	defer cleanup()
+
+	requireOutcomes := func(
+		gen func(context.Context, pgx.Tx) (*opStmt, error), codes ...pgcode.Code,
+	) {
+		outcomes := h.outcomes(gen, 100)
+		for _, code := range codes {
+			require.Contains(t, outcomes, code)
+		}
+	}
+	requireOutcomes(h.og.alterDatabaseDropRegion, pgcode.InvalidDatabaseDefinition)
+	requireOutcomes(h.og.alterDatabaseDropSecondaryRegion, pgcode.InvalidDatabaseDefinition)
+
+	h.tdb.Exec(t, `ALTER DATABASE defaultdb PRIMARY REGION "us-east1"`)
+	h.tdb.Exec(t, `ALTER DATABASE defaultdb ADD REGION "us-east2"`)
+	requireOutcomes(h.og.alterDatabaseDropRegion,
+		pgcode.SuccessfulCompletion,
+		pgcode.InvalidDatabaseDefinition,
+		pgcode.UndefinedObject,
+	)
+	requireOutcomes(h.og.alterDatabaseDropSecondaryRegion,
+		pgcode.SuccessfulCompletion,
+		pgcode.UndefinedParameter,
+	)
+
+	h.tdb.Exec(t, `ALTER DATABASE defaultdb SET SECONDARY REGION "us-east2"`)
+	// Both regions of the database are now the primary or secondary region.
+	requireOutcomes(h.og.alterDatabaseDropRegion, pgcode.InvalidDatabaseDefinition)
+	h.run(h.og.alterDatabaseDropSecondaryRegion)
+	h.tdb.CheckQueryResults(t,
+		`SELECT count(*) FROM [SHOW REGIONS FROM DATABASE defaultdb] WHERE secondary`,
+		[][]string{{"0"}},
+	)
+
+	// us-east2 can't be dropped while it is the home region of a table, which
+	// is only validated once the transaction commits.
+	h.og.params.errorRate = 0
+	h.tdb.Exec(t, `CREATE TABLE table_w0_1 (a INT8 PRIMARY KEY) LOCALITY REGIONAL BY TABLE IN "us-east2"`)
+	for i := 0; i < 50 && h.commitOutcome != pgcode.Uncategorized; i++ {
+		h.run(h.og.alterDatabaseDropRegion)
+	}
+	require.Equal(t, pgcode.Uncategorized, h.commitOutcome)
+
+	const regions = `SELECT count(*) FROM [SHOW REGIONS FROM DATABASE defaultdb]`
+	h.tdb.Exec(t, `DROP TABLE table_w0_1`)
+	for i := 0; i < 50 && h.tdb.QueryStr(t, regions)[0][0] == "2"; i++ {
+		h.run(h.og.alterDatabaseDropRegion)
+	}
+	h.tdb.CheckQueryResults(t, regions, [][]string{{"1"}})
+	require.NoError(t, h.validate())
+}
+
+// TestAlterDatabaseSuperRegion sets the secondary region of a multi-region
+// database and adds, alters and drops its super regions.
+func TestAlterDatabaseSuperRegion(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	skip.UnderRace(t, "multi-region cluster is too heavy")
+
+	h, cleanup := newMultiRegionGeneratorTestHarness(t, 3, /* numRegions */
+		&operationGeneratorParams{errorRate: 50},
+		`SET CLUSTER SETTING sql.defaults.super_regions.enabled = 'on'`,
+		`ALTER DATABASE defaultdb PRIMARY REGION "us-east1"`,
+		`ALTER DATABASE defaultdb ADD REGION "us-east2"`,
+		`ALTER DATABASE defaultdb ADD REGION "us-east3"`,
+	)
+	defer cleanup()
+
+	requireOutcomes := func(
+		gen func(context.Context, pgx.Tx) (*opStmt, error), codes ...pgcode.Code,
+	) {
+		outcomes := h.outcomes(gen, 100)
+		for _, code := range codes {
+			require.Contains(t, outcomes, code)
+		}
+	}
+	// The super region statements of tdb run on whichever connection of its
+	// pool is free, which may have been opened before super regions were
+	// enabled.
+	const enableSuperRegions = `SET enable_super_regions = 'on'; `
+
+	requireOutcomes(h.og.alterDatabaseSecondaryRegion,
+		pgcode.SuccessfulCompletion,
+		pgcode.InvalidDatabaseDefinition,
+	)
+	requireOutcomes(h.og.alterDatabaseAddSuperRegion,
+		pgcode.SuccessfulCompletion,
+		pgcode.InvalidCatalogName,
+	)
+
+	// The primary and secondary regions must both be in, or both be out of,
+	// a super region.
+	h.tdb.Exec(t, `ALTER DATABASE defaultdb SET SECONDARY REGION "us-east2"`)
+	requireOutcomes(h.og.alterDatabaseAddSuperRegion,
+		pgcode.SuccessfulCompletion,
+		pgcode.InvalidDatabaseDefinition,
+	)
+	h.tdb.Exec(t, enableSuperRegions+
+		`ALTER DATABASE defaultdb ADD SUPER REGION "super_region_w0_1" VALUES "us-east1", "us-east2"`)
+	requireOutcomes(h.og.alterDatabaseAddSuperRegion,
+		pgcode.SuccessfulCompletion,
+		pgcode.Uncategorized,
+	)
+	requireOutcomes(h.og.alterDatabaseAlterSuperRegion,
+		pgcode.SuccessfulCompletion,
+		pgcode.UndefinedObject,
+		pgcode.InvalidDatabaseDefinition,
+	)
+	requireOutcomes(h.og.alterDatabaseDropSuperRegion,
+		pgcode.SuccessfulCompletion,
+		pgcode.Uncategorized,
+	)
+	// us-east3 isn't part of the super region of the primary region.
+	requireOutcomes(h.og.alterDatabaseSecondaryRegion,
+		pgcode.SuccessfulCompletion,
+		pgcode.InvalidDatabaseDefinition,
+	)
+
+	// Every super region must satisfy the survival goal of the database.
+	h.tdb.Exec(t, enableSuperRegions+
+		`ALTER DATABASE defaultdb ALTER SUPER REGION "super_region_w0_1" VALUES "us-east1", "us-east2", "us-east3"`)
+	h.tdb.Exec(t, `ALTER DATABASE defaultdb SURVIVE REGION FAILURE`)
+	requireOutcomes(h.og.alterDatabaseAlterSuperRegion,
+		pgcode.SuccessfulCompletion,
+		pgcode.InvalidParameterValue,
+	)
+
+	h.og.params.errorRate = 0
+	h.run(h.og.alterDatabaseDropSuperRegion)
+	h.tdb.CheckQueryResults(t,
+		`SELECT count(*) FROM [SHOW SUPER REGIONS FROM DATABASE defaultdb]`,
+		[][]string{{"0"}},
+	)
+	require.NoError(t, h.validate())
+}
@@ -12,6 +12,9 @@ package schemachange
 
 import (
 	"context"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/errors"
@@ -56,9 +59,12 @@ func init() {
 const (
 	// Non-DDL operations
 
-	insertRow  opType = iota // INSERT INTO <table> (<cols>) VALUES (<values>)
-	selectStmt               // SELECT..
-	validate                 // validate all table descriptors
+	insertRow             opType = iota // INSERT INTO <table> (<cols>) VALUES (<values>)
+	updateRow                           // UPDATE <table> SET <col> = <value> LIMIT <n>
+	deleteRow                           // DELETE FROM <table> LIMIT <n>
+	deleteCheckViolations               // DELETE FROM <table> WHERE NOT (<check constraint expression>)
+	selectStmt                          // SELECT..
+	validate                            // validate all table descriptors
 
 	// DDL operations
 
@@ -83,115 +89,141 @@ const (
 
 	// ALTER DATABASE ...
 
-	alterDatabaseAddRegion       // ALTER DATABASE <db> ADD REGION <region>
-	alterDatabasePrimaryRegion   // ALTER DATABASE <db> PRIMARY REGION <region>
-	alterDatabaseSurvivalGoal    // ALTER DATABASE <db> SURVIVE <failure_mode>
-	alterDatabaseAddSuperRegion  // ALTER DATABASE <db> ADD SUPER REGION <region> VALUES ...
-	alterDatabaseDropSuperRegion // ALTER DATABASE <db> DROP SUPER REGION <region>
+	alterDatabaseAddRegion           // ALTER DATABASE <db> ADD REGION <region>
+	alterDatabasePrimaryRegion       // ALTER DATABASE <db> PRIMARY REGION <region>
+	alterDatabaseSurvivalGoal        // ALTER DATABASE <db> SURVIVE <failure_mode>
+	alterDatabaseAddSuperRegion      // ALTER DATABASE <db> ADD SUPER REGION <region> VALUES ...
+	alterDatabaseDropSuperRegion     // ALTER DATABASE <db> DROP SUPER REGION <region>
+	alterDatabaseDropRegion          // ALTER DATABASE <db> DROP REGION <region>
+	alterDatabaseDropSecondaryRegion // ALTER DATABASE <db> DROP SECONDARY REGION
+	alterDatabaseSecondaryRegion     // ALTER DATABASE <db> SET SECONDARY REGION <region>
+	alterDatabaseAlterSuperRegion    // ALTER DATABASE <db> ALTER SUPER REGION <region> VALUES ...
+	alterDatabaseOwner               // ALTER DATABASE <db> OWNER TO <role>
+	alterDatabasePlacement           // ALTER DATABASE <db> PLACEMENT RESTRICTED|DEFAULT
 
 	// ALTER FUNCTION ...
+	alterFunctionOptions   // ALTER FUNCTION <function> [IMMUTABLE | STABLE | VOLATILE] [[NOT] LEAKPROOF]
 	alterFunctionRename    // ALTER FUNCTION <function> RENAME TO <name>
+	alterFunctionSetOwner  // ALTER FUNCTION <function> OWNER TO <role>
 	alterFunctionSetSchema // ALTER FUNCTION <function> SET SCHEMA <schema>
 
+	// ALTER INDEX ...
+
+	alterIndexPartitionBy // ALTER INDEX <table>@<index> PARTITION BY [LIST | RANGE] (<columns>) (<partitions>) | NOTHING
+	alterIndexVisible     // ALTER INDEX <table>@<index> [NOT] VISIBLE
+
+	// ALTER SCHEMA ...
+
+	alterSchemaOwner  // ALTER SCHEMA <schema> OWNER TO <role>
+	alterSchemaRename // ALTER SCHEMA <schema> RENAME TO <schema>
+
+	// ALTER SEQUENCE ...
+
+	alterSequence // ALTER SEQUENCE <sequence> <options>
+
 	// ALTER TABLE <table> ...
 
 	alterTableAddColumn               // ALTER TABLE <table> ADD [COLUMN] <column> <type>
 	alterTableAddConstraint           // ALTER TABLE <table> ADD CONSTRAINT <constraint> <def>
+	alterTableAddConstraintCheck      // ALTER TABLE <table> ADD CONSTRAINT <constraint> CHECK (<column> IS NOT NULL)
 	alterTableAddConstraintForeignKey // ALTER TABLE <table> ADD CONSTRAINT <constraint> FOREIGN KEY (<column>) REFERENCES <table> (<column>)
+	alterTableAddConstraintPrimaryKey // ALTER TABLE <table> ADD CONSTRAINT <constraint> PRIMARY KEY (<columns>)
 	alterTableAddConstraintUnique     // ALTER TABLE <table> ADD CONSTRAINT <constraint> UNIQUE (<column>)
 	alterTableAlterColumnType         // ALTER TABLE <table> ALTER [COLUMN] <column> [SET DATA] TYPE <type>
 	alterTableAlterPrimaryKey         // ALTER TABLE <table> ALTER PRIMARY KEY USING COLUMNS (<columns>)
+	alterTableAlterRowLevelTTL        // ALTER TABLE <table> SET (ttl_job_cron = <cron>, ttl_select_batch_size = <size>)
+	alterTableDisableRowLevelTTL      // ALTER TABLE <table> RESET (ttl)
 	alterTableDropColumn              // ALTER TABLE <table> DROP COLUMN <column>
 	alterTableDropColumnDefault       // ALTER TABLE <table> ALTER [COLUMN] <column> DROP DEFAULT
 	alterTableDropConstraint          // ALTER TABLE <table> DROP CONSTRAINT <constraint>
 	alterTableDropNotNull             // ALTER TABLE <table> ALTER [COLUMN] <column> DROP NOT NULL
+	alterTableDropOnUpdate            // ALTER TABLE <table> ALTER [COLUMN] <column> DROP ON UPDATE
 	alterTableDropStored              // ALTER TABLE <table> ALTER [COLUMN] <column> DROP STORED
+	alterTableEnableRowLevelTTL       // ALTER TABLE <table> SET (ttl_expire_after = <interval>)
 	alterTableLocality                // ALTER TABLE <table> LOCALITY <locality>
 	alterTableRenameColumn            // ALTER TABLE <table> RENAME [COLUMN] <column> TO <column>
+	alterTableRenameConstraint        // ALTER TABLE <table> RENAME CONSTRAINT <constraint> TO <constraint>
+	alterTableResetStorageParams      // ALTER TABLE <table> RESET (<param>, ...)
 	alterTableSetColumnDefault        // ALTER TABLE <table> ALTER [COLUMN] <column> SET DEFAULT <expr>
 	alterTableSetColumnNotNull        // ALTER TABLE <table> ALTER [COLUMN] <column> SET NOT NULL
+	alterTableSetOnUpdate             // ALTER TABLE <table> ALTER [COLUMN] <column> SET ON UPDATE <expr>
+	alterTableSetSchema               // ALTER TABLE <table> SET SCHEMA <schema>
+	alterTableSetStorageParams        // ALTER TABLE <table> SET (<param> = <value>, ...)
+	alterTableValidateConstraint      // ALTER TABLE <table> VALIDATE CONSTRAINT <constraint>
 
 	// ALTER TYPE ...
 
-	alterTypeDropValue // ALTER TYPE <type> DROP VALUE <value>
+	alterTypeAddValue    // ALTER TYPE <type> ADD VALUE <value>
+	alterTypeDropValue   // ALTER TYPE <type> DROP VALUE <value>
+	alterTypeRenameValue // ALTER TYPE <type> RENAME VALUE <value> TO <value>
 
 	// CREATE ...
 
+	createDatabase      // CREATE DATABASE <database>
 	createTypeEnum      // CREATE TYPE <type> ENUM AS <def>
 	createTypeComposite // CREATE TYPE <type> AS <def>
 	createIndex         // CREATE INDEX <index> ON <table> <def>
 	createSchema        // CREATE SCHEMA <schema>
 	createSequence      // CREATE SEQUENCE <sequence> <def>
+	createStats         // CREATE STATISTICS <name> [ON <columns>] FROM <table> [<options>]
 	createTable         // CREATE TABLE <table> <def>
 	createTableAs       // CREATE TABLE <table> AS <def>
-	createView          // CREATE VIEW <view> AS <def>
+	createView          // CREATE [MATERIALIZED] VIEW <view> AS <def>
 	createFunction      // CREATE FUNCTION <function> ...
+	createRole          // CREATE ROLE <role>
 
 	// COMMENT ON ...
 
-	commentOn // COMMENT ON [SCHEMA | TABLE | INDEX | COLUMN | CONSTRAINT] IS <comment>
+	commentOn // COMMENT ON [DATABASE | SCHEMA | TABLE | INDEX | COLUMN | CONSTRAINT | TYPE] IS <comment>
 
 	// DROP ...
 
+	dropDatabase // DROP DATABASE <database>
 	dropFunction // DROP FUNCTION <function>
 	dropIndex    // DROP INDEX <index>@<table>
+	dropOwnedBy  // DROP OWNED BY <role>
+	dropRole     // DROP ROLE <role>
 	dropSchema   // DROP SCHEMA <schema>
 	dropSequence // DROP SEQUENCE <sequence>
 	dropTable    // DROP TABLE <table>
+	dropType     // DROP TYPE <type>
 	dropView     // DROP VIEW <view>
 
+	// GRANT / REVOKE ...
+
+	grant  // GRANT <privileges> ON <object> TO <role>
+	revoke // REVOKE <privileges> ON <object> FROM <role>
+
+	// REASSIGN ...
+
+	reassignOwnedBy // REASSIGN OWNED BY <role> TO <role>
+
+	// REFRESH ...
+
+	refreshMaterializedView // REFRESH MATERIALIZED VIEW [CONCURRENTLY] <view>
+
 	// Unimplemented operations. TODO(sql-foundations): Audit and/or implement these operations.
-	// alterDatabaseOwner
-	// alterDatabasePlacement
 	// alterDatabaseSetZoneConfigExtension
 	// alterDefaultPrivileges
 	// alterFunctionDepExtension
-	// alterFunctionOptions
-	// alterFunctionSetOwner
 	// alterIndex
-	// alterIndexPartitionBy
-	// alterIndexVisible
 	// alterRole
 	// alterRoleSet
 	// alterSchema
-	// alterSchemaOwner
-	// alterSchemaRename
-	// alterSequence
 	// alterTableInjectStats
 	// alterTableOwner
 	// alterTablePartitionByTable
-	// alterTableRenameConstraint        // ALTER TABLE <table> RENAME CONSTRAINT <constraint> TO <constraint>
-	// alterTableResetStorageParams
 	// alterTableSetAudit
-	// alterTableSetOnUpdate
-	// alterTableSetSchema
-	// alterTableSetStorageParams
 	// alterTableSetVisible
-	// alterTableValidateConstraint
 	// alterType
-	// alterTypeAddValue
 	// alterTypeOwner
 	// alterTypeRename
-	// alterTypeRenameValue
 	// alterTypeSetSchema
-	// commentOnDatabase
-	// createDatabase
-	// createRole
-	// createStats
-	// createStatsOptions
 	// createType
-	// dropDatabase
-	// dropOwnedBy
-	// dropRole     // DROP ROLE <role>
-	// dropType     // DROP TYPE <type>
-	// grant
 	// grantRole
 	// grantTargetList
-	// reassignOwnedBy
-	// refreshMaterializedView
 	// renameDatabase
 	// reparentDatabase
-	// revoke
 	// revokeRole
 
 	// numOpTypes contains the total number of opType entries and is used to
@@ -202,105 +234,246 @@ const (
 
 var opFuncs = []func(*operationGenerator, context.Context, pgx.Tx) (*opStmt, error){
 	// Non-DDL
-	insertRow:  (*operationGenerator).insertRow,
-	selectStmt: (*operationGenerator).selectStmt,
-	validate:   (*operationGenerator).validate,
+	insertRow:             (*operationGenerator).insertRow,
+	updateRow:             (*operationGenerator).updateRow,
+	deleteRow:             (*operationGenerator).deleteRow,
+	deleteCheckViolations: (*operationGenerator).deleteCheckViolations,
+	selectStmt:            (*operationGenerator).selectStmt,
+	validate:              (*operationGenerator).validate,
 
 	// DDL Operations
 	alterDatabaseAddRegion:            (*operationGenerator).addRegion,
 	alterDatabaseAddSuperRegion:       (*operationGenerator).alterDatabaseAddSuperRegion,
+	alterDatabaseAlterSuperRegion:     (*operationGenerator).alterDatabaseAlterSuperRegion,
+	alterDatabaseDropRegion:           (*operationGenerator).alterDatabaseDropRegion,
+	alterDatabaseDropSecondaryRegion:  (*operationGenerator).alterDatabaseDropSecondaryRegion,
 	alterDatabaseDropSuperRegion:      (*operationGenerator).alterDatabaseDropSuperRegion,
+	alterDatabaseOwner:                (*operationGenerator).alterDatabaseOwner,
+	alterDatabasePlacement:            (*operationGenerator).alterDatabasePlacement,
 	alterDatabasePrimaryRegion:        (*operationGenerator).primaryRegion,
+	alterDatabaseSecondaryRegion:      (*operationGenerator).alterDatabaseSecondaryRegion,
 	alterDatabaseSurvivalGoal:         (*operationGenerator).survive,
+	alterFunctionOptions:              (*operationGenerator).alterFunctionOptions,
 	alterFunctionRename:               (*operationGenerator).alterFunctionRename,
+	alterFunctionSetOwner:             (*operationGenerator).alterFunctionSetOwner,
 	alterFunctionSetSchema:            (*operationGenerator).alterFunctionSetSchema,
+	alterIndexPartitionBy:             (*operationGenerator).alterIndexPartitionBy,
+	alterIndexVisible:                 (*operationGenerator).alterIndexVisible,
+	alterSchemaOwner:                  (*operationGenerator).alterSchemaOwner,
+	alterSchemaRename:                 (*operationGenerator).alterSchemaRename,
+	alterSequence:                     (*operationGenerator).alterSequence,
 	alterTableAddColumn:               (*operationGenerator).addColumn,
 	alterTableAddConstraint:           (*operationGenerator).addConstraint,
+	alterTableAddConstraintCheck:      (*operationGenerator).addCheckNotNullConstraint,
 	alterTableAddConstraintForeignKey: (*operationGenerator).addForeignKeyConstraint,
+	alterTableAddConstraintPrimaryKey: (*operationGenerator).addPrimaryKeyConstraint,
 	alterTableAddConstraintUnique:     (*operationGenerator).addUniqueConstraint,
 	alterTableAlterColumnType:         (*operationGenerator).setColumnType,
 	alterTableAlterPrimaryKey:         (*operationGenerator).alterTableAlterPrimaryKey,
+	alterTableAlterRowLevelTTL:        (*operationGenerator).alterTableAlterRowLevelTTL,
+	alterTableDisableRowLevelTTL:      (*operationGenerator).alterTableDisableRowLevelTTL,
 	alterTableDropColumn:              (*operationGenerator).dropColumn,
 	alterTableDropColumnDefault:       (*operationGenerator).dropColumnDefault,
 	alterTableDropConstraint:          (*operationGenerator).dropConstraint,
 	alterTableDropNotNull:             (*operationGenerator).dropColumnNotNull,
+	alterTableDropOnUpdate:            (*operationGenerator).dropColumnOnUpdate,
 	alterTableDropStored:              (*operationGenerator).dropColumnStored,
+	alterTableEnableRowLevelTTL:       (*operationGenerator).alterTableEnableRowLevelTTL,
 	alterTableLocality:                (*operationGenerator).alterTableLocality,
 	alterTableRenameColumn:            (*operationGenerator).renameColumn,
+	alterTableRenameConstraint:        (*operationGenerator).renameConstraint,
+	alterTableResetStorageParams:      (*operationGenerator).alterTableResetStorageParams,
 	alterTableSetColumnDefault:        (*operationGenerator).setColumnDefault,
 	alterTableSetColumnNotNull:        (*operationGenerator).setColumnNotNull,
+	alterTableSetOnUpdate:             (*operationGenerator).setColumnOnUpdate,
+	alterTableSetSchema:               (*operationGenerator).alterTableSetSchema,
+	alterTableSetStorageParams:        (*operationGenerator).alterTableSetStorageParams,
+	alterTableValidateConstraint:      (*operationGenerator).validateConstraint,
+	alterTypeAddValue:                 (*operationGenerator).addTypeValue,
 	alterTypeDropValue:                (*operationGenerator).alterTypeDropValue,
+	alterTypeRenameValue:              (*operationGenerator).alterTypeRenameValue,
 	commentOn:                         (*operationGenerator).commentOn,
+	createDatabase:                    (*operationGenerator).createDatabase,
 	createFunction:                    (*operationGenerator).createFunction,
 	createIndex:                       (*operationGenerator).createIndex,
+	createRole:                        (*operationGenerator).createRole,
 	createSchema:                      (*operationGenerator).createSchema,
 	createSequence:                    (*operationGenerator).createSequence,
+	createStats:                       (*operationGenerator).createStats,
 	createTable:                       (*operationGenerator).createTable,
 	createTableAs:                     (*operationGenerator).createTableAs,
 	createTypeEnum:                    (*operationGenerator).createEnum,
 	createTypeComposite:               (*operationGenerator).createCompositeType,
 	createView:                        (*operationGenerator).createView,
+	dropDatabase:                      (*operationGenerator).dropDatabase,
 	dropFunction:                      (*operationGenerator).dropFunction,
 	dropIndex:                         (*operationGenerator).dropIndex,
+	dropOwnedBy:                       (*operationGenerator).dropOwnedBy,
+	dropRole:                          (*operationGenerator).dropRole,
 	dropSchema:                        (*operationGenerator).dropSchema,
 	dropSequence:                      (*operationGenerator).dropSequence,
 	dropTable:                         (*operationGenerator).dropTable,
+	dropType:                          (*operationGenerator).dropType,
 	dropView:                          (*operationGenerator).dropView,
+	grant:                             (*operationGenerator).grant,
+	reassignOwnedBy:                   (*operationGenerator).reassignOwnedBy,
+	refreshMaterializedView:           (*operationGenerator).refreshMaterializedView,
 	renameIndex:                       (*operationGenerator).renameIndex,
 	renameSequence:                    (*operationGenerator).renameSequence,
 	renameTable:                       (*operationGenerator).renameTable,
 	renameView:                        (*operationGenerator).renameView,
+	revoke:                            (*operationGenerator).revoke,
 }
 
 var opWeights = []int{
 	// Non-DDL
-	insertRow:  10,
-	selectStmt: 10,
-	validate:   2, // validate twice more often
+	insertRow:             10,
+	updateRow:             5,
+	deleteRow:             2,
+	deleteCheckViolations: 2,
+	selectStmt:            10,
+	validate:              2, // validate twice more often
 
 	// DDL Operations
 	alterDatabaseAddRegion:            1,
 	alterDatabaseAddSuperRegion:       0, // Disabled and tracked with #111299
+	alterDatabaseAlterSuperRegion:     0, // Disabled and tracked with #111299
+	alterDatabaseDropRegion:           1,
+	alterDatabaseDropSecondaryRegion:  1,
 	alterDatabaseDropSuperRegion:      0, // Disabled and tracked with #111299
+	alterDatabaseOwner:                1,
+	alterDatabasePlacement:            1,
 	alterDatabasePrimaryRegion:        0, // Disabled and tracked with #83831
+	alterDatabaseSecondaryRegion:      1,
 	alterDatabaseSurvivalGoal:         0, // Disabled and tracked with #83831
+	alterFunctionOptions:              1,
 	alterFunctionRename:               1,
+	alterFunctionSetOwner:             1,
 	alterFunctionSetSchema:            1,
+	alterIndexPartitionBy:             1,
+	alterIndexVisible:                 1,
+	alterSchemaOwner:                  1,
+	alterSchemaRename:                 1,
+	alterSequence:                     1,
 	alterTableAddColumn:               1,
+	alterTableAddConstraintCheck:      1,
 	alterTableAddConstraintForeignKey: 1,
+	alterTableAddConstraintPrimaryKey: 1,
 	alterTableAddConstraintUnique:     0,
-	alterTableAlterColumnType:         0, // Disabled and tracked with #66662.
+	alterTableAlterColumnType:         1,
 	alterTableAlterPrimaryKey:         1,
+	alterTableAlterRowLevelTTL:        1,
+	alterTableDisableRowLevelTTL:      1,
 	alterTableDropColumn:              0,
 	alterTableDropColumnDefault:       1,
 	alterTableDropConstraint:          1,
 	alterTableDropNotNull:             1,
+	alterTableDropOnUpdate:            1,
 	alterTableDropStored:              1,
+	alterTableEnableRowLevelTTL:       1,
 	alterTableLocality:                1,
 	alterTableRenameColumn:            1,
+	alterTableRenameConstraint:        1,
+	alterTableResetStorageParams:      1,
 	alterTableSetColumnDefault:        1,
 	alterTableSetColumnNotNull:        1,
+	alterTableSetOnUpdate:             1,
+	alterTableSetSchema:               1,
+	alterTableSetStorageParams:        1,
+	alterTableValidateConstraint:      1,
+	alterTypeAddValue:                 1,
 	alterTypeDropValue:                1,
+	alterTypeRenameValue:              1,
 	commentOn:                         1,
+	createDatabase:                    1,
 	createFunction:                    1,
 	createIndex:                       1,
+	createRole:                        1,
 	createSchema:                      1,
 	createSequence:                    1,
+	createStats:                       1,
 	createTable:                       1,
 	createTableAs:                     1,
 	createTypeEnum:                    1,
 	createTypeComposite:               1,
 	createView:                        1,
+	dropDatabase:                      1,
 	dropFunction:                      1,
 	dropIndex:                         1,
+	dropOwnedBy:                       1,
+	dropRole:                          1,
 	dropSchema:                        1,
 	dropSequence:                      1,
 	dropTable:                         1,
+	dropType:                          1,
 	dropView:                          1,
+	grant:                             1,
+	reassignOwnedBy:                   1,
+	refreshMaterializedView:           1,
 	renameIndex:                       1,
 	renameSequence:                    1,
 	renameTable:                       1,
 	renameView:                        1,
+	revoke:                            1,
+}
+
+// implicitTxnOps are the operations whose statement must be the only
+// statement of its transaction. randOp never produces them. Instead, when a
+// worker using the legacy schema changer draws one of them, it generates the
+// statement and then runs it on its own, outside of an explicit transaction.
+var implicitTxnOps = []opType{
+	refreshMaterializedView,
+}
+
+// parseOpWeights parses the weight overrides given to the --op-weights flag,
+// as a comma-separated list of <operation>=<weight> pairs, where operations
+// are named after their opType.
+func parseOpWeights(spec string) (map[opType]int, error) {
+	overrides := make(map[opType]int)
+	if strings.TrimSpace(spec) == "" {
+		return overrides, nil
+	}
+	opTypesByName := make(map[string]opType, numOpTypes)
+	for op := opType(0); int(op) < numOpTypes; op++ {
+		opTypesByName[op.String()] = op
+	}
+	for _, pair := range strings.Split(spec, ",") {
+		name, weightStr, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return nil, errors.Newf("invalid operation weight %q, expected <operation>=<weight>", pair)
+		}
+		name = strings.TrimSpace(name)
+		op, ok := opTypesByName[name]
+		if !ok {
+			names := make([]string, 0, len(opTypesByName))
+			for name := range opTypesByName {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return nil, errors.WithHintf(
+				errors.Newf("unknown operation %q", name),
+				"valid operations are: %s", strings.Join(names, ", "))
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(weightStr))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid weight for operation %q", name)
+		}
+		if weight < 0 {
+			return nil, errors.Newf("weight for operation %q must not be negative, got %d", name, weight)
+		}
+		overrides[op] = weight
+	}
+	return overrides, nil
+}
+
+// opWeightsWithOverrides returns a copy of opWeights with the given weights
+// overridden.
+func opWeightsWithOverrides(overrides map[opType]int) []int {
+	weights := append([]int(nil), opWeights...)
+	for op, weight := range overrides {
+		weights[op] = weight
+	}
+	return weights
 }
 
 // This workload will maintain its own list of minimal supported versions for
@@ -309,19 +482,27 @@ var opWeights = []int{
 // list, but it's not sufficient for that reason.
 var opDeclarativeVersion = map[opType]clusterversion.Key{
 	alterTableAddColumn:               clusterversion.MinSupported,
+	alterTableAddConstraintCheck:      clusterversion.MinSupported,
 	alterTableAddConstraintForeignKey: clusterversion.MinSupported,
 	alterTableAddConstraintUnique:     clusterversion.MinSupported,
 	alterTableDropColumn:              clusterversion.MinSupported,
 	alterTableDropConstraint:          clusterversion.MinSupported,
 	alterTableDropNotNull:             clusterversion.MinSupported,
+	alterTableValidateConstraint:      clusterversion.MinSupported,
+	alterTypeAddValue:                 clusterversion.MinSupported,
 	alterTypeDropValue:                clusterversion.MinSupported,
+	alterTypeRenameValue:              clusterversion.MinSupported,
 	commentOn:                         clusterversion.MinSupported,
+	createDatabase:                    clusterversion.V24_1,
 	createIndex:                       clusterversion.MinSupported,
 	createSchema:                      clusterversion.MinSupported,
 	createSequence:                    clusterversion.MinSupported,
+	dropDatabase:                      clusterversion.MinSupported,
 	dropIndex:                         clusterversion.MinSupported,
+	dropOwnedBy:                       clusterversion.MinSupported,
 	dropSchema:                        clusterversion.MinSupported,
 	dropSequence:                      clusterversion.MinSupported,
 	dropTable:                         clusterversion.MinSupported,
+	dropType:                          clusterversion.MinSupported,
 	dropView:                          clusterversion.MinSupported,
 }
@@ -9,57 +9,104 @@ func _() {
 	// Re-run the stringer command to generate them again.
 	var x [1]struct{}
 	_ = x[insertRow-0]
-	_ = x[selectStmt-1]
-	_ = x[validate-2]
-	_ = x[renameIndex-3]
-	_ = x[renameSequence-4]
-	_ = x[renameTable-5]
-	_ = x[renameView-6]
-	_ = x[alterDatabaseAddRegion-7]
-	_ = x[alterDatabasePrimaryRegion-8]
-	_ = x[alterDatabaseSurvivalGoal-9]
-	_ = x[alterDatabaseAddSuperRegion-10]
-	_ = x[alterDatabaseDropSuperRegion-11]
-	_ = x[alterFunctionRename-12]
-	_ = x[alterFunctionSetSchema-13]
-	_ = x[alterTableAddColumn-14]
-	_ = x[alterTableAddConstraint-15]
-	_ = x[alterTableAddConstraintForeignKey-16]
-	_ = x[alterTableAddConstraintUnique-17]
-	_ = x[alterTableAlterColumnType-18]
-	_ = x[alterTableAlterPrimaryKey-19]
-	_ = x[alterTableDropColumn-20]
-	_ = x[alterTableDropColumnDefault-21]
-	_ = x[alterTableDropConstraint-22]
-	_ = x[alterTableDropNotNull-23]
-	_ = x[alterTableDropStored-24]
-	_ = x[alterTableLocality-25]
-	_ = x[alterTableRenameColumn-26]
-	_ = x[alterTableSetColumnDefault-27]
-	_ = x[alterTableSetColumnNotNull-28]
-	_ = x[alterTypeDropValue-29]
-	_ = x[createTypeEnum-30]
-	_ = x[createTypeComposite-31]
-	_ = x[createIndex-32]
-	_ = x[createSchema-33]
-	_ = x[createSequence-34]
-	_ = x[createTable-35]
-	_ = x[createTableAs-36]
-	_ = x[createView-37]
-	_ = x[createFunction-38]
-	_ = x[commentOn-39]
-	_ = x[dropFunction-40]
-	_ = x[dropIndex-41]
-	_ = x[dropSchema-42]
-	_ = x[dropSequence-43]
-	_ = x[dropTable-44]
-	_ = x[dropView-45]
+	_ = x[updateRow-1]
+	_ = x[deleteRow-2]
+	_ = x[deleteCheckViolations-3]
+	_ = x[selectStmt-4]
+	_ = x[validate-5]
+	_ = x[renameIndex-6]
+	_ = x[renameSequence-7]
+	_ = x[renameTable-8]
+	_ = x[renameView-9]
+	_ = x[alterDatabaseAddRegion-10]
+	_ = x[alterDatabasePrimaryRegion-11]
+	_ = x[alterDatabaseSurvivalGoal-12]
+	_ = x[alterDatabaseAddSuperRegion-13]
+	_ = x[alterDatabaseDropSuperRegion-14]
+	_ = x[alterDatabaseDropRegion-15]
+	_ = x[alterDatabaseDropSecondaryRegion-16]
+	_ = x[alterDatabaseSecondaryRegion-17]
+	_ = x[alterDatabaseAlterSuperRegion-18]
+	_ = x[alterDatabaseOwner-19]
+	_ = x[alterDatabasePlacement-20]
+	_ = x[alterFunctionOptions-21]
+	_ = x[alterFunctionRename-22]
+	_ = x[alterFunctionSetOwner-23]
+	_ = x[alterFunctionSetSchema-24]
+	_ = x[alterIndexPartitionBy-25]
+	_ = x[alterIndexVisible-26]
+	_ = x[alterSchemaOwner-27]
+	_ = x[alterSchemaRename-28]
+	_ = x[alterSequence-29]
+	_ = x[alterTableAddColumn-30]
+	_ = x[alterTableAddConstraint-31]
+	_ = x[alterTableAddConstraintCheck-32]
+	_ = x[alterTableAddConstraintForeignKey-33]
+	_ = x[alterTableAddConstraintPrimaryKey-34]
+	_ = x[alterTableAddConstraintUnique-35]
+	_ = x[alterTableAlterColumnType-36]
+	_ = x[alterTableAlterPrimaryKey-37]
+	_ = x[alterTableAlterRowLevelTTL-38]
+	_ = x[alterTableDisableRowLevelTTL-39]
+	_ = x[alterTableDropColumn-40]
+	_ = x[alterTableDropColumnDefault-41]
+	_ = x[alterTableDropConstraint-42]
+	_ = x[alterTableDropNotNull-43]
+	_ = x[alterTableDropOnUpdate-44]
+	_ = x[alterTableDropStored-45]
+	_ = x[alterTableEnableRowLevelTTL-46]
+	_ = x[alterTableLocality-47]
+	_ = x[alterTableRenameColumn-48]
+	_ = x[alterTableRenameConstraint-49]
+	_ = x[alterTableResetStorageParams-50]
+	_ = x[alterTableSetColumnDefault-51]
+	_ = x[alterTableSetColumnNotNull-52]
+	_ = x[alterTableSetOnUpdate-53]
+	_ = x[alterTableSetSchema-54]
+	_ = x[alterTableSetStorageParams-55]
+	_ = x[alterTableValidateConstraint-56]
+	_ = x[alterTypeAddValue-57]
+	_ = x[alterTypeDropValue-58]
+	_ = x[alterTypeRenameValue-59]
+	_ = x[createDatabase-60]
+	_ = x[createTypeEnum-61]
+	_ = x[createTypeComposite-62]
+	_ = x[createIndex-63]
+	_ = x[createSchema-64]
+	_ = x[createSequence-65]
+	_ = x[createStats-66]
+	_ = x[createTable-67]
+	_ = x[createTableAs-68]
+	_ = x[createView-69]
+	_ = x[createFunction-70]
+	_ = x[createRole-71]
+	_ = x[commentOn-72]
+	_ = x[dropDatabase-73]
+	_ = x[dropFunction-74]
+	_ = x[dropIndex-75]
+	_ = x[dropOwnedBy-76]
+	_ = x[dropRole-77]
+	_ = x[dropSchema-78]
+	_ = x[dropSequence-79]
+	_ = x[dropTable-80]
+	_ = x[dropType-81]
+	_ = x[dropView-82]
+	_ = x[grant-83]
+	_ = x[revoke-84]
+	_ = x[reassignOwnedBy-85]
+	_ = x[refreshMaterializedView-86]
 }
 
 func (i opType) String() string {
 	switch i {
 	case insertRow:
 		return "insertRow"
+	case updateRow:
+		return "updateRow"
+	case deleteRow:
+		return "deleteRow"
+	case deleteCheckViolations:
+		return "deleteCheckViolations"
 	case selectStmt:
 		return "selectStmt"
 	case validate:
@@ -82,22 +129,56 @@ func (i opType) String() string {
 		return "alterDatabaseAddSuperRegion"
 	case alterDatabaseDropSuperRegion:
 		return "alterDatabaseDropSuperRegion"
+	case alterDatabaseDropRegion:
+		return "alterDatabaseDropRegion"
+	case alterDatabaseDropSecondaryRegion:
+		return "alterDatabaseDropSecondaryRegion"
+	case alterDatabaseSecondaryRegion:
+		return "alterDatabaseSecondaryRegion"
+	case alterDatabaseAlterSuperRegion:
+		return "alterDatabaseAlterSuperRegion"
+	case alterDatabaseOwner:
+		return "alterDatabaseOwner"
+	case alterDatabasePlacement:
+		return "alterDatabasePlacement"
+	case alterFunctionOptions:
+		return "alterFunctionOptions"
 	case alterFunctionRename:
 		return "alterFunctionRename"
+	case alterFunctionSetOwner:
+		return "alterFunctionSetOwner"
 	case alterFunctionSetSchema:
 		return "alterFunctionSetSchema"
+	case alterIndexPartitionBy:
+		return "alterIndexPartitionBy"
+	case alterIndexVisible:
+		return "alterIndexVisible"
+	case alterSchemaOwner:
+		return "alterSchemaOwner"
+	case alterSchemaRename:
+		return "alterSchemaRename"
+	case alterSequence:
+		return "alterSequence"
 	case alterTableAddColumn:
 		return "alterTableAddColumn"
 	case alterTableAddConstraint:
 		return "alterTableAddConstraint"
+	case alterTableAddConstraintCheck:
+		return "alterTableAddConstraintCheck"
 	case alterTableAddConstraintForeignKey:
 		return "alterTableAddConstraintForeignKey"
+	case alterTableAddConstraintPrimaryKey:
+		return "alterTableAddConstraintPrimaryKey"
 	case alterTableAddConstraintUnique:
 		return "alterTableAddConstraintUnique"
 	case alterTableAlterColumnType:
 		return "alterTableAlterColumnType"
 	case alterTableAlterPrimaryKey:
 		return "alterTableAlterPrimaryKey"
+	case alterTableAlterRowLevelTTL:
+		return "alterTableAlterRowLevelTTL"
+	case alterTableDisableRowLevelTTL:
+		return "alterTableDisableRowLevelTTL"
 	case alterTableDropColumn:
 		return "alterTableDropColumn"
 	case alterTableDropColumnDefault:
@@ -106,18 +187,40 @@ func (i opType) String() string {
 		return "alterTableDropConstraint"
 	case alterTableDropNotNull:
 		return "alterTableDropNotNull"
+	case alterTableDropOnUpdate:
+		return "alterTableDropOnUpdate"
 	case alterTableDropStored:
 		return "alterTableDropStored"
+	case alterTableEnableRowLevelTTL:
+		return "alterTableEnableRowLevelTTL"
 	case alterTableLocality:
 		return "alterTableLocality"
 	case alterTableRenameColumn:
 		return "alterTableRenameColumn"
+	case alterTableRenameConstraint:
+		return "alterTableRenameConstraint"
+	case alterTableResetStorageParams:
+		return "alterTableResetStorageParams"
 	case alterTableSetColumnDefault:
 		return "alterTableSetColumnDefault"
 	case alterTableSetColumnNotNull:
 		return "alterTableSetColumnNotNull"
+	case alterTableSetOnUpdate:
+		return "alterTableSetOnUpdate"
+	case alterTableSetSchema:
+		return "alterTableSetSchema"
+	case alterTableSetStorageParams:
+		return "alterTableSetStorageParams"
+	case alterTableValidateConstraint:
+		return "alterTableValidateConstraint"
+	case alterTypeAddValue:
+		return "alterTypeAddValue"
 	case alterTypeDropValue:
 		return "alterTypeDropValue"
+	case alterTypeRenameValue:
+		return "alterTypeRenameValue"
+	case createDatabase:
+		return "createDatabase"
 	case createTypeEnum:
 		return "createTypeEnum"
 	case createTypeComposite:
@@ -128,6 +231,8 @@ func (i opType) String() string {
 		return "createSchema"
 	case createSequence:
 		return "createSequence"
+	case createStats:
+		return "createStats"
 	case createTable:
 		return "createTable"
 	case createTableAs:
@@ -136,20 +241,38 @@ func (i opType) String() string {
 		return "createView"
 	case createFunction:
 		return "createFunction"
+	case createRole:
+		return "createRole"
 	case commentOn:
 		return "commentOn"
+	case dropDatabase:
+		return "dropDatabase"
 	case dropFunction:
 		return "dropFunction"
 	case dropIndex:
 		return "dropIndex"
+	case dropOwnedBy:
+		return "dropOwnedBy"
+	case dropRole:
+		return "dropRole"
 	case dropSchema:
 		return "dropSchema"
 	case dropSequence:
 		return "dropSequence"
 	case dropTable:
 		return "dropTable"
+	case dropType:
+		return "dropType"
 	case dropView:
 		return "dropView"
+	case grant:
+		return "grant"
+	case revoke:
+		return "revoke"
+	case reassignOwnedBy:
+		return "reassignOwnedBy"
+	case refreshMaterializedView:
+		return "refreshMaterializedView"
 	default:
 		return "opType(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
@@ -0,0 +1,102 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package schemachange
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOpWeights(t *testing.T) {
+	for _, tc := range []struct {
+		spec     string
+		expected map[opType]int
+	}{
+		{spec: "", expected: map[opType]int{}},
+		{spec: "createIndex=5", expected: map[opType]int{createIndex: 5}},
+		{
+			spec:     "createIndex=5,dropTable=0",
+			expected: map[opType]int{createIndex: 5, dropTable: 0},
+		},
+		{
+			spec:     " createIndex = 5 , dropTable=0 ",
+			expected: map[opType]int{createIndex: 5, dropTable: 0},
+		},
+	} {
+		t.Run(tc.spec, func(t *testing.T) {
+			overrides, err := parseOpWeights(tc.spec)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, overrides)
+		})
+	}
+
+	for _, tc := range []struct {
+		spec string
+		err  string
+	}{
+		{spec: "createIndex", err: `invalid operation weight "createIndex"`},
+		{spec: "createIndex=many", err: `invalid weight for operation "createIndex"`},
+		{spec: "createIndex=-1", err: `weight for operation "createIndex" must not be negative`},
+		{spec: "createIndex=1,createIndexes=2", err: `unknown operation "createIndexes"`},
+	} {
+		t.Run(tc.spec, func(t *testing.T) {
+			_, err := parseOpWeights(tc.spec)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+
+	// The valid names are listed when an operation is unknown.
+	_, err := parseOpWeights("createIndexes=2")
+	require.Error(t, err)
+	hints := errors.FlattenHints(err)
+	for op := opType(0); int(op) < numOpTypes; op++ {
+		require.Contains(t, hints, op.String())
+	}
+}
+
+// TestOpWeightsOverrideSelection checks that overridden weights change how
+// often operations are drawn from a deck.
+func TestOpWeightsOverrideSelection(t *testing.T) {
+	const numDraws = 100000
+	draw := func(weights []int) map[opType]int {
+		d := newDeck(rand.New(rand.NewSource(0)), weights...)
+		counts := make(map[opType]int)
+		for i := 0; i < numDraws; i++ {
+			counts[opType(d.Int())]++
+		}
+		return counts
+	}
+
+	overrides, err := parseOpWeights("createIndex=100,dropTable=0")
+	require.NoError(t, err)
+	weights := opWeightsWithOverrides(overrides)
+	require.Equal(t, 100, weights[createIndex])
+	require.Equal(t, 0, weights[dropTable])
+	// The defaults themselves are left untouched.
+	require.NotEqual(t, 100, opWeights[createIndex])
+
+	defaultCounts := draw(opWeights)
+	overriddenCounts := draw(weights)
+	require.NotZero(t, defaultCounts[dropTable])
+	require.Zero(t, overriddenCounts[dropTable])
+
+	// createIndex should be drawn in proportion to its share of the weights.
+	var sum int
+	for _, weight := range weights {
+		sum += weight
+	}
+	expected := float64(numDraws) * float64(weights[createIndex]) / float64(sum)
+	require.InEpsilon(t, expected, float64(overriddenCounts[createIndex]), 0.05)
+	require.Greater(t, overriddenCounts[createIndex], 10*defaultCounts[createIndex])
+}
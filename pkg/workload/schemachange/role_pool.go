@@ -0,0 +1,64 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package schemachange
+
+import (
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// rolePool tracks the roles created by the workload, so that operations can
+// pick roles that are known to exist. It is shared by all workers, and only
+// reflects the changes of committed transactions: the roles created and
+// dropped by a transaction are buffered by its operationGenerator until it
+// commits.
+type rolePool struct {
+	mu struct {
+		syncutil.Mutex
+		roles map[string]struct{}
+	}
+}
+
+// newRolePool returns a pool containing the given roles.
+func newRolePool(roles ...string) *rolePool {
+	p := &rolePool{}
+	p.mu.roles = make(map[string]struct{}, len(roles))
+	for _, role := range roles {
+		p.mu.roles[role] = struct{}{}
+	}
+	return p
+}
+
+// apply records the roles created and dropped by a committed transaction.
+func (p *rolePool) apply(created, dropped []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, role := range created {
+		p.mu.roles[role] = struct{}{}
+	}
+	for _, role := range dropped {
+		delete(p.mu.roles, role)
+	}
+}
+
+// list returns the roles in the pool, sorted so that operations pick roles
+// deterministically.
+func (p *rolePool) list() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	roles := make([]string, 0, len(p.mu.roles))
+	for role := range p.mu.roles {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
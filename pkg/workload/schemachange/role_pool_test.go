@@ -0,0 +1,82 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package schemachange
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestRolePoolConcurrentApply checks that the pool stays consistent when
+// workers concurrently apply the roles created and dropped by their
+// transactions.
+func TestRolePoolConcurrentApply(t *testing.T) {
+	const numWorkers = 8
+	const numTxns = 100
+
+	p := newRolePool("role_existing")
+	var wg sync.WaitGroup
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < numTxns; i++ {
+				role := fmt.Sprintf("role_w%d_%d", w, i)
+				p.apply([]string{role}, nil)
+				// Every other role is dropped again by a later transaction.
+				if i%2 == 0 {
+					p.apply(nil, []string{role})
+				}
+				if !slices.Contains(p.list(), "role_existing") {
+					t.Errorf("role_existing missing from the pool")
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	expected := []string{"role_existing"}
+	for w := 0; w < numWorkers; w++ {
+		for i := 1; i < numTxns; i += 2 {
+			expected = append(expected, fmt.Sprintf("role_w%d_%d", w, i))
+		}
+	}
+	sort.Strings(expected)
+	require.Equal(t, expected, p.list())
+}
+
+// TestOperationGeneratorRoles checks that the roles created and dropped by a
+// transaction are visible to its operations, but only reach the pool once it
+// commits.
+func TestOperationGeneratorRoles(t *testing.T) {
+	pool := newRolePool("role_a", "role_b")
+	og := makeOperationGenerator(&operationGeneratorParams{roles: pool})
+
+	og.rolesCreatedInTxn = append(og.rolesCreatedInTxn, "role_c", "role_d")
+	og.rolesDroppedInTxn = append(og.rolesDroppedInTxn, "role_a", "role_d")
+	require.Equal(t, []string{"role_b", "role_c"}, og.roles())
+	require.Equal(t, []string{"role_a", "role_b"}, pool.list())
+
+	// A transaction that does not commit leaves the pool untouched.
+	og.resetTxnState()
+	require.Equal(t, []string{"role_a", "role_b"}, og.roles())
+
+	og.rolesCreatedInTxn = append(og.rolesCreatedInTxn, "role_c")
+	og.rolesDroppedInTxn = append(og.rolesDroppedInTxn, "role_a")
+	pool.apply(og.rolesCreatedInTxn, og.rolesDroppedInTxn)
+	og.resetTxnState()
+	require.Equal(t, []string{"role_b", "role_c"}, og.roles())
+}
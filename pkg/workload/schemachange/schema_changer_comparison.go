@@ -0,0 +1,174 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package schemachange
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// opsOutsideDatabase are the operations that change objects outside of the
+// database the workload runs in. They are not generated when comparing schema
+// changers, since running them a second time in the legacy database would
+// change the same objects again.
+var opsOutsideDatabase = []opType{
+	alterDatabaseAddRegion,
+	alterDatabaseAddSuperRegion,
+	alterDatabaseAlterSuperRegion,
+	alterDatabaseDropRegion,
+	alterDatabaseDropSecondaryRegion,
+	alterDatabaseDropSuperRegion,
+	alterDatabaseOwner,
+	alterDatabasePlacement,
+	alterDatabasePrimaryRegion,
+	alterDatabaseSecondaryRegion,
+	alterDatabaseSurvivalGoal,
+	createDatabase,
+	createRole,
+	dropDatabase,
+	dropRole,
+}
+
+// schemaChangerComparison re-runs the transactions of the workload with the
+// legacy schema changer in a second database, which starts out in the same
+// state as the one the workload runs in, and checks that every statement
+// returns the same error code and that both databases end up with the same
+// descriptors. It is used with --compare-schema-changers, which runs a single
+// worker so that both databases see the transactions in the same order.
+type schemaChangerComparison struct {
+	// db is the database the workload runs in, and legacyDB the database in
+	// which transactions are re-run through legacyConn.
+	db, legacyDB string
+	legacyConn   opLogConn
+}
+
+// makeSchemaChangerComparison returns a comparison between db and legacyDB,
+// after checking that they contain the same descriptors. legacyConn must be
+// connected to legacyDB.
+func makeSchemaChangerComparison(
+	ctx context.Context, legacyConn opLogConn, db, legacyDB string,
+) (*schemaChangerComparison, error) {
+	c := &schemaChangerComparison{
+		db:         db,
+		legacyDB:   legacyDB,
+		legacyConn: legacyConn,
+	}
+	if err := c.compareDescriptors(ctx); err != nil {
+		return nil, errors.Wrapf(err,
+			"database %s must start out in the same state as %s", legacyDB, db)
+	}
+	return c, nil
+}
+
+// compareTxn re-runs a transaction, as returned by opLogger.endTxn, with the
+// legacy schema changer, and compares its outcome and the resulting descriptors
+// with those of the original run.
+func (c *schemaChangerComparison) compareTxn(ctx context.Context, txn []opLogEntry) error {
+	if len(txn) == 0 {
+		return nil
+	}
+	end := txn[len(txn)-1]
+	for _, e := range txn {
+		// Statements that the declarative schema changer doesn't implement make
+		// the transaction roll back, so there is nothing to compare.
+		if end.Declarative && e.Outcome == pgcode.Uncategorized.String() {
+			return nil
+		}
+	}
+	legacyTxn := append([]opLogEntry(nil), txn...)
+	for i := range legacyTxn {
+		legacyTxn[i].Declarative = false
+	}
+	// The transaction is replayed with the errors predicted for the original
+	// run, and replayOpLogTxn fails if any outcome differs from the original.
+	if err := replayOpLogTxn(ctx, c.legacyConn, legacyTxn); err != nil {
+		return errors.Wrapf(err, "legacy schema changer diverged on transaction %d of worker %d",
+			end.Txn, end.WorkerID)
+	}
+	if end.Op == opLogRollback {
+		return nil
+	}
+	return errors.Wrapf(c.compareDescriptors(ctx),
+		"legacy schema changer diverged on transaction %d of worker %d", end.Txn, end.WorkerID)
+}
+
+// compareDescriptors returns an error with a diff if db and legacyDB don't
+// contain the same descriptors.
+func (c *schemaChangerComparison) compareDescriptors(ctx context.Context) error {
+	descs, err := dumpDescriptors(ctx, c.legacyConn, c.db)
+	if err != nil {
+		return err
+	}
+	legacyDescs, err := dumpDescriptors(ctx, c.legacyConn, c.legacyDB)
+	if err != nil {
+		return err
+	}
+	if descs == legacyDescs {
+		return nil
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(descs),
+		B:        difflib.SplitLines(legacyDescs),
+		FromFile: c.db,
+		ToFile:   c.legacyDB,
+		Context:  3,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to produce diff")
+	}
+	return errors.Newf("descriptors of %s and %s differ:\n%s", c.db, c.legacyDB, diff)
+}
+
+// dumpDescriptors returns the CREATE statements of the schemas, types,
+// relations and functions in a database, with references to the database
+// itself removed, so that the dumps of equivalent databases are identical.
+func dumpDescriptors(ctx context.Context, conn opLogConn, db string) (string, error) {
+	dbName := tree.NameString(db)
+	rows, err := conn.Query(ctx, strings.ReplaceAll(`
+SELECT create_statement
+  FROM (
+        SELECT 0 AS kind, schema_name AS name, create_statement
+          FROM $db.crdb_internal.create_schema_statements
+         WHERE database_name = $1
+        UNION ALL
+        SELECT 1, schema_name || '.' || descriptor_name, create_statement
+          FROM $db.crdb_internal.create_type_statements
+         WHERE database_name = $1
+        UNION ALL
+        SELECT 2, schema_name || '.' || descriptor_name, create_statement
+          FROM $db.crdb_internal.create_statements
+         WHERE database_name = $1 AND state = 'PUBLIC'
+        UNION ALL
+        SELECT 3, schema_name || '.' || function_name, create_statement
+          FROM $db.crdb_internal.create_function_statements
+         WHERE database_name = $1
+       )
+ ORDER BY kind, name, create_statement`, "$db", dbName), db)
+	if err != nil {
+		return "", err
+	}
+	stmts, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, stmt := range stmts {
+		b.WriteString(strings.ReplaceAll(stmt, dbName+".", ""))
+		b.WriteString(";\n")
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package schemachange
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSchemaChangerComparison runs a handful of schema changes with the
+// declarative schema changer, re-runs them with the legacy schema changer
+// through a schemaChangerComparison, and checks that both produce the same
+// descriptors.
+func TestSchemaChangerComparison(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	srv, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer srv.Stopper().Stop(ctx)
+
+	tdb := sqlutils.MakeSQLRunner(sqlDB)
+	tdb.Exec(t, `CREATE DATABASE sc`)
+	tdb.Exec(t, `CREATE DATABASE sc_legacy`)
+
+	pgURL, cleanup := sqlutils.PGUrl(
+		t, srv.ApplicationLayer().AdvSQLAddr(), t.Name(), url.User(username.RootUser),
+	)
+	defer cleanup()
+	var conns []*pgx.Conn
+	defer func() {
+		for _, conn := range conns {
+			require.NoError(t, conn.Close(ctx))
+		}
+	}()
+	connect := func(db string) *pgx.Conn {
+		dbURL := pgURL
+		dbURL.Path = db
+		conn, err := pgx.Connect(ctx, dbURL.String())
+		require.NoError(t, err)
+		conns = append(conns, conn)
+		return conn
+	}
+
+	conn := connect("sc")
+	_, err := conn.Exec(ctx, "SET use_declarative_schema_changer = 'unsafe_always'")
+	require.NoError(t, err)
+	comparison, err := makeSchemaChangerComparison(ctx, connect("sc_legacy"), "sc", "sc_legacy")
+	require.NoError(t, err)
+
+	// runTxn runs a statement in a transaction with the declarative schema
+	// changer, like a worker would, and returns the logged transaction.
+	opLog := &opLogger{}
+	runTxn := func(op opType, sql string, expected ...pgcode.Code) []opLogEntry {
+		og := makeOperationGenerator(&operationGeneratorParams{})
+		og.resetTxnState()
+		og.resetOpState(true /* useDeclarativeSchemaChanger */)
+		opLog.startTxn(true /* declarative */, "UTC")
+
+		tx, err := conn.Begin(ctx)
+		require.NoError(t, err)
+		stmt := makeOpStmtForSingleError(OpStmtDDL, sql, expected...)
+		opLog.logStmt(op, stmt)
+		if err := stmt.executeStmt(ctx, tx, og); err != nil {
+			require.Truef(t, errors.Is(err, errRunInTxnRbkSentinel), "%+v", err)
+			require.NoError(t, tx.Rollback(ctx))
+			return opLog.endTxn(opLogRollback, nil, nil, err)
+		}
+		err = tx.Commit(ctx)
+		require.NoError(t, err)
+		return opLog.endTxn(opLogCommit, og.expectedCommitErrors, og.potentialCommitErrors, err)
+	}
+
+	for _, tc := range []struct {
+		op       opType
+		sql      string
+		expected []pgcode.Code
+	}{
+		{op: createTable, sql: `CREATE TABLE t (a INT8 PRIMARY KEY, b INT8, c STRING)`},
+		{op: alterTableAddColumn, sql: `ALTER TABLE t ADD COLUMN d INT8 DEFAULT 7`},
+		{op: createIndex, sql: `CREATE INDEX t_b_idx ON t (b)`},
+		{op: alterTableAddConstraintUnique, sql: `ALTER TABLE t ADD CONSTRAINT t_c_key UNIQUE (c)`},
+		{op: createSchema, sql: `CREATE SCHEMA s`},
+		{op: createSequence, sql: `CREATE SEQUENCE s.seq`},
+		{op: alterTableDropColumn, sql: `ALTER TABLE t DROP COLUMN d`},
+		{op: dropIndex, sql: `DROP INDEX t@t_b_idx`},
+		{
+			op:       alterTableDropColumn,
+			sql:      `ALTER TABLE t DROP COLUMN missing`,
+			expected: []pgcode.Code{pgcode.UndefinedColumn},
+		},
+	} {
+		t.Run(tc.sql, func(t *testing.T) {
+			require.NoError(t, comparison.compareTxn(ctx, runTxn(tc.op, tc.sql, tc.expected...)))
+		})
+	}
+	tdb.CheckQueryResults(t, `SELECT column_name FROM [SHOW COLUMNS FROM sc_legacy.t]`,
+		[][]string{{"a"}, {"b"}, {"c"}},
+	)
+
+	// A schema change that doesn't happen in both databases is reported with
+	// a diff of their descriptors.
+	tdb.Exec(t, `CREATE TABLE sc_legacy.extra (a INT8 PRIMARY KEY)`)
+	err = comparison.compareTxn(ctx, runTxn(alterTableAddColumn, `ALTER TABLE t ADD COLUMN e INT8`))
+	require.ErrorContains(t, err, "descriptors of sc and sc_legacy differ")
+	require.ErrorContains(t, err, "+CREATE TABLE public.extra")
+
+	// Databases must start out with the same descriptors.
+	_, err = makeSchemaChangerComparison(ctx, connect("sc_legacy"), "sc", "sc_legacy")
+	require.ErrorContains(t, err, "must start out in the same state")
+}
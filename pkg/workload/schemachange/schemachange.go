@@ -19,12 +19,13 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"regexp"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
@@ -33,6 +34,7 @@ import (
 	"github.com/cockroachdb/errors"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/spf13/pflag"
@@ -52,6 +54,12 @@ import (
 // `bin/workload run schemachange --init --concurrency=2 --verbose=0 --max-ops-per-worker=1000`
 // will execute up to 1000 schema change operations per txn in two concurrent txns.
 //
+// Passing --soak-tables=N turns the workload into a steady-state load
+// generator: workers only create tables, indexes and enum types and insert
+// rows until the database has N tables, --soak-indexes secondary indexes and
+// --soak-types enum types. From then on, they only insert, update, delete and
+// select rows of the existing tables.
+//
 // TODO(peter): This is still work in progress, we need to
 // - support more than 1 database
 // - reference sequences in column defaults
@@ -68,6 +76,7 @@ const (
 	defaultSequenceOwnedByPct              = 25
 	defaultFkParentInvalidPct              = 5
 	defaultFkChildInvalidPct               = 5
+	defaultWideTablePct                    = 2
 	defaultDeclarativeSchemaChangerPct     = 75
 	defaultDeclarativeSchemaMaxStmtsPerTxn = 1
 )
@@ -76,6 +85,11 @@ type schemaChangeCounter struct {
 	// success and error keep track of the number of
 	// successful and erroneous schema transactions.
 	success, error prometheus.Counter
+	// opResults counts the statements executed for each operation, labeled
+	// with the name of its opType, whether it succeeded, failed with an
+	// expected or unexpected error, or succeeded when an error was expected,
+	// and its error code.
+	opResults *prometheus.CounterVec
 }
 
 type schemaChange struct {
@@ -90,17 +104,39 @@ type schemaChange struct {
 	sequenceOwnedByPct              int
 	logFilePath                     string
 	logFile                         *os.File
+	opLogPath                       string
+	opLogFile                       *os.File
+	opReplayPath                    string
+	compareSchemaChangers           bool
+	comparison                      *schemaChangerComparison
+	legacyConn                      *pgx.Conn
 	dumpLogsOnce                    *sync.Once
 	declarativeStatementsEnabled    atomic.Bool
 	workers                         []*schemaChangeWorker
 	fkParentInvalidPct              int
 	fkChildInvalidPct               int
+	wideTablePct                    int
+	opWeightsSpec                   string
+	opWeightOverrides               map[opType]int
 	declarativeSchemaChangerPct     int
 	declarativeSchemaMaxStmtsPerTxn int
+	soakTables                      int
+	soakIndexes                     int
+	soakTypes                       int
 	traceFilePath                   string
 	schemaWorkloadResultAnnotator   *schemaWorkloadResultAnnotator
 	reg                             *histogram.Registry
 	scCounter                       schemaChangeCounter
+
+	// soakSchemaBuilt is set once the schema built in soak mode has as many
+	// objects as requested.
+	soakSchemaBuilt atomic.Bool
+	// soakStartOnce ensures the switch to DML-only operation in soak mode is
+	// only logged once.
+	soakStartOnce sync.Once
+	// connTimeZones maps the connections of the pool to the time zone that
+	// was last set on them by a worker.
+	connTimeZones sync.Map
 }
 
 var schemaChangeMeta = workload.Meta{
@@ -124,18 +160,41 @@ var schemaChangeMeta = workload.Meta{
 			`Percentage of times that a sequence is owned by column upon creation.`)
 		s.flags.StringVar(&s.logFilePath, `txn-log`, "",
 			`If provided, transactions will be written to this file in JSON form`)
+		s.flags.StringVar(&s.opLogPath, `op-log`, "",
+			`If provided, every statement executed is written to this file as a JSON line, along with `+
+				`the errors predicted for it and its outcome, so that the run can be replayed with --op-replay`)
+		s.flags.StringVar(&s.opReplayPath, `op-replay`, "",
+			`If provided, the statements of an operation log written with --op-log are re-issued one `+
+				`transaction at a time instead of generating new ones, failing on any divergence. `+
+				`Use --max-ops=1 to exit once the log is replayed`)
+		s.flags.BoolVar(&s.compareSchemaChangers, `compare-schema-changers`, false,
+			`If set, every transaction is run again with the legacy schema changer in a copy of the `+
+				`database suffixed with _legacy, failing if the error codes or the resulting descriptors `+
+				`differ. Requires --concurrency=1`)
 		s.flags.StringVar(&s.traceFilePath, `trace-file`, "",
 			`The file to write OTeL traces to. Defaults to schemachange-workload.{timestamp}.otlp.ndjson.gz`)
 		s.flags.IntVar(&s.fkParentInvalidPct, `fk-parent-invalid-pct`, defaultFkParentInvalidPct,
 			`Percentage of times to choose an invalid parent column in a fk constraint.`)
 		s.flags.IntVar(&s.fkChildInvalidPct, `fk-child-invalid-pct`, defaultFkChildInvalidPct,
 			`Percentage of times to choose an invalid child column in a fk constraint.`)
+		s.flags.IntVar(&s.wideTablePct, `wide-table-pct`, defaultWideTablePct,
+			`Percentage of times that a table is created with dozens to hundreds of extra columns.`)
+		s.flags.StringVar(&s.opWeightsSpec, `op-weights`, "",
+			`Comma-separated list of <operation>=<weight> pairs overriding the default weights of `+
+				`operations, e.g. 'createIndex=5,dropTable=0'.`)
 		s.flags.IntVar(&s.declarativeSchemaChangerPct, `declarative-schema-changer-pct`,
 			defaultDeclarativeSchemaChangerPct,
 			`Percentage (between 0 and 100) of schema change statements handled by declarative schema changer, if supported.`)
 		s.flags.IntVar(&s.declarativeSchemaMaxStmtsPerTxn, `declarative-schema-changer-stmt-per-txn`,
 			defaultDeclarativeSchemaMaxStmtsPerTxn,
 			`Number of statements per-txn used by the declarative schema changer.`)
+		s.flags.IntVar(&s.soakTables, `soak-tables`, 0,
+			`If positive, run in soak mode: build a schema with at least this many tables, then only `+
+				`run DML against it for the remainder of the run.`)
+		s.flags.IntVar(&s.soakIndexes, `soak-indexes`, 0,
+			`Number of secondary indexes the schema built in soak mode has at least.`)
+		s.flags.IntVar(&s.soakTypes, `soak-types`, 0,
+			`Number of enum types the schema built in soak mode has at least.`)
 
 		s.connFlags = workload.NewConnFlags(&s.flags)
 		return s
@@ -165,6 +224,15 @@ func setupSchemaChangePromCounter(reg prometheus.Registerer) schemaChangeCounter
 				Help:      "The total number of unexpected failures.",
 			},
 		),
+		opResults: f.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: histogram.PrometheusNamespace,
+				Subsystem: schemaChangeMeta.Name,
+				Name:      "schema_change_op_results",
+				Help:      "The number of statements executed per operation, by result and error code.",
+			},
+			[]string{"op", "result", "code"},
+		),
 	}
 }
 
@@ -177,6 +245,43 @@ func (s *schemaChange) Flags() workload.Flags { return s.flags }
 // ConnFlags implements the ConnFlagser interface.
 func (s *schemaChange) ConnFlags() *workload.ConnFlags { return s.connFlags }
 
+// Hooks implements the Hookser interface.
+func (s *schemaChange) Hooks() workload.Hooks {
+	return workload.Hooks{
+		Validate: func() error {
+			overrides, err := parseOpWeights(s.opWeightsSpec)
+			if err != nil {
+				return errors.Wrap(err, "parsing --op-weights")
+			}
+			s.opWeightOverrides = overrides
+			runsInTxn := false
+			for op, weight := range opWeightsWithOverrides(overrides) {
+				if weight > 0 && !slices.Contains(implicitTxnOps, opType(op)) {
+					runsInTxn = true
+				}
+			}
+			if !runsInTxn {
+				return errors.New("--op-weights must give a positive weight to an operation that runs in a transaction")
+			}
+			if s.soakTables <= 0 && (s.soakIndexes > 0 || s.soakTypes > 0) {
+				return errors.New("--soak-indexes and --soak-types require --soak-tables")
+			}
+			if s.opLogPath != "" && s.opReplayPath != "" {
+				return errors.New("--op-log and --op-replay cannot be used together")
+			}
+			if s.compareSchemaChangers {
+				if s.opReplayPath != "" || s.dryRun {
+					return errors.New("--compare-schema-changers cannot be used with --op-replay or --dry-run")
+				}
+				if s.connFlags.Concurrency != 1 {
+					return errors.New("--compare-schema-changers requires --concurrency=1")
+				}
+			}
+			return nil
+		},
+	}
+}
+
 // Tables implements the workload.Generator interface.
 func (s *schemaChange) Tables() []workload.Table { return nil }
 
@@ -241,12 +346,38 @@ func (s *schemaChange) Ops(
 	// A separate weighting is constructed of only schema changes supported by the
 	// declarative schema changer. This will be used to make a per-worker deck
 	// that has equal weights, only for supported schema changes.
-	declarativeOpWeights := make([]int, len(opWeights))
-	for idx, weight := range opWeights {
+	// Apply the weights overridden with --op-weights.
+	weights := opWeightsWithOverrides(s.opWeightOverrides)
+	if s.compareSchemaChangers {
+		for _, op := range opsOutsideDatabase {
+			weights[op] = 0
+		}
+	}
+	// Indexes can only be partitioned by CCL binaries.
+	var isCCLBuild bool
+	if err := pool.Get().QueryRow(ctx, isCCLBuildQuery).Scan(&isCCLBuild); err != nil {
+		return workload.QueryLoad{}, err
+	}
+	if !isCCLBuild {
+		weights[alterIndexPartitionBy] = 0
+	}
+	declarativeOpWeights := make([]int, len(weights))
+	for idx, weight := range weights {
 		if _, ok := opDeclarativeVersion[opType(idx)]; ok {
 			declarativeOpWeights[idx] = weight
 		}
 	}
+	// In soak mode, the schema is built by operations that only add to it.
+	// Once it is built, only operations that do not change the schema are
+	// drawn.
+	soakBuildOpWeights := make([]int, len(weights))
+	for _, op := range []opType{createTable, createIndex, createTypeEnum, insertRow} {
+		soakBuildOpWeights[op] = weights[op]
+	}
+	dmlOpWeights := make([]int, len(weights))
+	for _, op := range []opType{insertRow, updateRow, deleteRow, deleteCheckViolations, selectStmt} {
+		dmlOpWeights[op] = weights[op]
+	}
 
 	ql := workload.QueryLoad{
 		Close: func(_ context.Context) error {
@@ -260,13 +391,21 @@ func (s *schemaChange) Ops(
 			pool.Close()
 			watchDogPool.Close()
 
-			closeErr := s.closeJSONLogFile()
+			closeErr := errors.CombineErrors(s.closeJSONLogFile(), s.closeOpLogFile())
+			if s.legacyConn != nil {
+				closeErr = errors.CombineErrors(closeErr, s.legacyConn.Close(ctx))
+			}
 			shutdownErr := tracerProvider.Shutdown(ctx)
 			s.schemaWorkloadResultAnnotator.logWorkloadStats(stdoutLog)
 			return errors.CombineErrors(closeErr, shutdownErr)
 		},
 	}
 
+	if s.opReplayPath != "" {
+		ql.WorkerFns = append(ql.WorkerFns, s.replayWorker(pool, stdoutLog))
+		return ql, nil
+	}
+
 	var artifactsLog *atomicLog
 	if s.logFilePath != "" {
 		err := s.initJSONLogFile(s.logFilePath)
@@ -275,8 +414,27 @@ func (s *schemaChange) Ops(
 		}
 		artifactsLog = makeAtomicLog(s.logFile)
 	}
+	var opLog *atomicLog
+	if s.opLogPath != "" {
+		f, err := os.OpenFile(s.opLogPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0660)
+		if err != nil {
+			return workload.QueryLoad{}, err
+		}
+		s.opLogFile = f
+		opLog = makeAtomicLog(f)
+	}
+	if s.compareSchemaChangers {
+		if err := s.initSchemaChangerComparison(ctx, pool, urls[0]); err != nil {
+			return workload.QueryLoad{}, err
+		}
+	}
 	s.dumpLogsOnce = &sync.Once{}
 
+	roles, err := s.initRolePool(ctx, pool)
+	if err != nil {
+		return workload.QueryLoad{}, err
+	}
+
 	for i := 0; i < s.connFlags.Concurrency; i++ {
 
 		// Different worker goroutines are not allowed to share RNGs. We use a
@@ -291,8 +449,10 @@ func (s *schemaChange) Ops(
 		if err != nil {
 			return workload.QueryLoad{}, err
 		}
-		ops := newDeck(workerRng, opWeights...)
+		ops := newDeck(workerRng, weights...)
 		declarativeOps := newDeck(workerRng, declarativeOpWeights...)
+		soakBuildOps := newDeck(workerRng, soakBuildOpWeights...)
+		dmlOps := newDeck(workerRng, dmlOpWeights...)
 
 		opGeneratorParams := operationGeneratorParams{
 			workerID:           i,
@@ -302,10 +462,16 @@ func (s *schemaChange) Ops(
 			rng:                workerRng,
 			ops:                ops,
 			declarativeOps:     declarativeOps,
+			soakBuildOps:       soakBuildOps,
+			dmlOps:             dmlOps,
 			maxSourceTables:    s.maxSourceTables,
 			sequenceOwnedByPct: s.sequenceOwnedByPct,
 			fkParentInvalidPct: s.fkParentInvalidPct,
 			fkChildInvalidPct:  s.fkChildInvalidPct,
+			wideTablePct:       s.wideTablePct,
+			roles:              roles,
+			concurrency:        s.connFlags.Concurrency,
+			opResults:          s.scCounter.opResults,
 		}
 
 		w := &schemaChangeWorker{
@@ -332,6 +498,11 @@ func (s *schemaChange) Ops(
 			tracer:              tracer,
 			scCounter:           &s.scCounter,
 		}
+		// Comparing schema changers replays the transactions collected by the
+		// operation logger, even if they aren't written anywhere.
+		if opLog != nil || s.comparison != nil {
+			w.opLog = &opLogger{log: opLog, workerID: i}
+		}
 
 		s.workers = append(s.workers, w)
 
@@ -340,6 +511,67 @@ func (s *schemaChange) Ops(
 	return ql, nil
 }
 
+// soakPhase is the phase of a workload running in soak mode.
+type soakPhase int
+
+const (
+	// soakDisabled is the phase of a workload that doesn't run in soak mode,
+	// which draws from all operations.
+	soakDisabled soakPhase = iota
+	// soakBuilding only draws operations that add to the schema.
+	soakBuilding
+	// soakRunning only draws operations that don't change the schema.
+	soakRunning
+)
+
+// soakPhase returns the phase of the workload. In soak mode, the schema is
+// being built until it has as many tables, secondary indexes and enum types
+// as requested, which is checked at the start of every transaction until it
+// does.
+func (w *schemaChangeWorker) soakPhase(ctx context.Context, conn *pgxpool.Conn) (soakPhase, error) {
+	s := w.workload
+	if s.soakTables <= 0 {
+		return soakDisabled, nil
+	}
+	if s.soakSchemaBuilt.Load() {
+		return soakRunning, nil
+	}
+	var tables, indexes, enums int
+	if err := conn.QueryRow(ctx, `
+SELECT (
+        SELECT count(*)
+          FROM pg_catalog.pg_class AS c
+          JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+         WHERE c.relkind = 'r' AND n.nspname NOT IN ('pg_catalog', 'pg_extension', 'information_schema', 'crdb_internal')
+       ),
+       (
+        SELECT count(*)
+          FROM pg_catalog.pg_index AS i
+          JOIN pg_catalog.pg_class AS c ON c.oid = i.indrelid
+          JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+         WHERE NOT i.indisprimary AND n.nspname NOT IN ('pg_catalog', 'pg_extension', 'information_schema', 'crdb_internal')
+       ),
+       (
+        SELECT count(*)
+          FROM pg_catalog.pg_type AS t
+          JOIN pg_catalog.pg_namespace AS n ON n.oid = t.typnamespace
+         WHERE t.typtype = 'e' AND n.nspname NOT IN ('pg_catalog', 'pg_extension', 'information_schema', 'crdb_internal')
+       )`).Scan(&tables, &indexes, &enums); err != nil {
+		return soakDisabled, errors.Wrap(err, "counting the objects of the soak schema")
+	}
+	if tables < s.soakTables || indexes < s.soakIndexes || enums < s.soakTypes {
+		return soakBuilding, nil
+	}
+	s.soakSchemaBuilt.Store(true)
+	s.soakStartOnce.Do(func() {
+		w.logger.stdoutLog.printLn(fmt.Sprintf(
+			"soak: schema built with %d tables, %d secondary indexes and %d enum types, only running DML from now on",
+			tables, indexes, enums,
+		))
+	})
+	return soakRunning, nil
+}
+
 // setClusterSettings configures any settings required for the workload ahead
 // of starting workers.
 func (s *schemaChange) setClusterSettings(ctx context.Context, pool *workload.MultiConnPool) error {
@@ -347,6 +579,34 @@ func (s *schemaChange) setClusterSettings(ctx context.Context, pool *workload.Mu
 	return errors.WithStack(err)
 }
 
+// initSchemaChangerComparison creates the database in which transactions are
+// re-run with the legacy schema changer for --compare-schema-changers, and
+// connects to it.
+func (s *schemaChange) initSchemaChangerComparison(
+	ctx context.Context, pool *workload.MultiConnPool, url string,
+) error {
+	var db string
+	if err := pool.Get().QueryRow(ctx, `SELECT current_database()`).Scan(&db); err != nil {
+		return err
+	}
+	legacyDB := db + "_legacy"
+	if _, err := pool.Get().Exec(ctx,
+		fmt.Sprintf(`CREATE DATABASE IF NOT EXISTS %s`, tree.NameString(legacyDB)),
+	); err != nil {
+		return err
+	}
+	conn, err := pgx.Connect(ctx, url)
+	if err != nil {
+		return err
+	}
+	s.legacyConn = conn
+	if _, err := conn.Exec(ctx, fmt.Sprintf(`SET database = %s`, tree.NameString(legacyDB))); err != nil {
+		return err
+	}
+	s.comparison, err = makeSchemaChangerComparison(ctx, conn, db, legacyDB)
+	return err
+}
+
 // initSeqName returns the smallest available sequence number to be
 // used to generate new unique names. Note that this assumes that no
 // other workload is being run at the same time.
@@ -367,10 +627,11 @@ SELECT max(regexp_extract(name, '[0-9]+$')::INT8)
 	           (SELECT schema_name FROM [SHOW SCHEMAS]) UNION
 						 (SELECT column_name FROM information_schema.columns) UNION
 						 (SELECT index_name FROM information_schema.statistics) UNION
-						 (SELECT function_name FROM [SHOW FUNCTIONS])
+						 (SELECT function_name FROM [SHOW FUNCTIONS]) UNION
+						 (SELECT username FROM system.users)
            ) AS obj (name)
        )
- WHERE name ~ '^(table|view|seq|enum|schema|udf)_w%[1]d_[0-9]+$'
+ WHERE name ~ '^(table|view|seq|enum|schema|udf|role)_w%[1]d_[0-9]+$'
     OR name ~ '^(col|index)[0-9]+_w%[1]d_[0-9]+$';
 `, workerID)
 	var maxID gosql.NullInt64
@@ -385,6 +646,22 @@ SELECT max(regexp_extract(name, '[0-9]+$')::INT8)
 	return seqNum, nil
 }
 
+// initRolePool returns a pool of the roles created by previous runs of the
+// workload.
+func (s *schemaChange) initRolePool(
+	ctx context.Context, pool *workload.MultiConnPool,
+) (*rolePool, error) {
+	rows, err := pool.Get().Query(ctx, `SELECT username FROM system.users WHERE username LIKE 'role\_%'`)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+	return newRolePool(roles...), nil
+}
+
 type schemaChangeWorker struct {
 	id                  int
 	workload            *schemaChange
@@ -396,10 +673,30 @@ type schemaChangeWorker struct {
 	opGen               *operationGenerator
 	isHoldingEntryLocks bool
 	logger              *logger
+	opLog               *opLogger
 	tracer              trace.Tracer
 	scCounter           *schemaChangeCounter
+	// timeZone is the time zone the transactions of the worker run in, one
+	// of sessionTimeZones.
+	timeZone string
 }
 
+// sessionTimeZones are the time zones workers run in, the first of which is
+// the one they start in. They include zones with non-hour offsets and zones
+// on either side of the date line.
+var sessionTimeZones = []string{
+	"UTC",
+	"America/New_York",
+	"Asia/Kolkata",
+	"Australia/Lord_Howe",
+	"Pacific/Kiritimati",
+	"Pacific/Pago_Pago",
+}
+
+// forceDeclarativeStatementsStmt enables schema changes that the declarative
+// schema changer doesn't handle by default.
+const forceDeclarativeStatementsStmt = `SET CLUSTER SETTING sql.schema.force_declarative_statements="+CREATE SCHEMA, +CREATE SEQUENCE"`
+
 var (
 	errRunInTxnFatalSentinel = errors.New("fatal error when running txn")
 	errRunInTxnRbkSentinel   = errors.New("txn needs to rollback")
@@ -452,12 +749,13 @@ func (w *schemaChangeWorker) runInTxn(
 	ctx context.Context,
 	tx pgx.Tx,
 	useDeclarativeSchemaChanger bool,
+	phase soakPhase,
 	workloadMetrics map[string]attribute.Value,
 ) error {
 	w.logger.startLog(w.id)
 	w.logger.writeLog("BEGIN")
 	opsNum := 1 + w.opGen.randIntn(w.maxOpsPerWorker)
-	if useDeclarativeSchemaChanger && opsNum > w.workload.declarativeSchemaMaxStmtsPerTxn {
+	if useDeclarativeSchemaChanger && phase != soakRunning && opsNum > w.workload.declarativeSchemaMaxStmtsPerTxn {
 		opsNum = w.workload.declarativeSchemaMaxStmtsPerTxn
 	}
 
@@ -474,7 +772,7 @@ func (w *schemaChangeWorker) runInTxn(
 			break
 		}
 
-		op, err := w.opGen.randOp(ctx, tx, useDeclarativeSchemaChanger)
+		op, err := w.opGen.randOp(ctx, tx, useDeclarativeSchemaChanger, phase)
 		if pgErr := new(pgconn.PgError); errors.As(err, &pgErr) &&
 			pgcode.MakeCode(pgErr.Code) == pgcode.SerializationFailure {
 			return errors.Mark(err, errRunInTxnRbkSentinel)
@@ -494,6 +792,7 @@ func (w *schemaChangeWorker) runInTxn(
 
 		w.logger.addExpectedErrors(op.expectedExecErrors, w.opGen.expectedCommitErrors)
 		w.logger.writeLogOp(op)
+		w.opLog.logStmt(w.opGen.opsInTxn[len(w.opGen.opsInTxn)-1], op)
 		if !w.dryRun {
 			start := timeutil.Now()
 			err := op.executeStmt(ctx, tx, w.opGen)
@@ -523,6 +822,78 @@ func (w *schemaChangeWorker) runInTxn(
 	return nil
 }
 
+// runInImplicitTxn generates a statement for op, one of implicitTxnOps, and
+// runs it on its own. The statement is generated in a transaction that is
+// rolled back before the statement runs, since it can't run in an explicit
+// transaction.
+func (w *schemaChangeWorker) runInImplicitTxn(
+	ctx context.Context, conn *pgxpool.Conn, op opType, timeZone string,
+) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return errors.Wrap(err, "cannot get a connection and begin a txn")
+	}
+	w.opGen.resetTxnState()
+	w.opGen.resetOpState(false /* useDeclarativeSchemaChanger */)
+	stmt, err := opFuncs[op](w.opGen, ctx, tx)
+	if rbkErr := tx.Rollback(ctx); rbkErr != nil {
+		err = errors.CombineErrors(err, rbkErr)
+	}
+	if pgErr := new(pgconn.PgError); errors.As(err, &pgErr) &&
+		pgcode.MakeCode(pgErr.Code) == pgcode.SerializationFailure {
+		return nil
+	} else if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, context.DeadlineExceeded) {
+		// There was nothing to run op on, or the deadline was hit while
+		// generating the statement.
+		return nil
+	} else if err != nil {
+		w.preErrorHook()
+		return errors.Mark(
+			w.WrapWithErrorState(
+				errors.Wrap(err, "***UNEXPECTED ERROR; Failed to generate a random operation")),
+			errRunInTxnFatalSentinel,
+		)
+	}
+	w.opGen.opsInTxn = append(w.opGen.opsInTxn, op)
+
+	defer w.releaseLocksIfHeld()
+	w.logger.startLog(w.id)
+	w.logger.addExpectedErrors(stmt.expectedExecErrors, w.opGen.expectedCommitErrors)
+	w.logger.writeLogOp(stmt)
+	w.opLog.startImplicitTxn(timeZone)
+	w.opLog.logStmt(op, stmt)
+	if w.dryRun {
+		w.logger.flushLog("")
+		w.opLog.endTxn(opLogRollback, nil, nil, nil)
+		return nil
+	}
+	start := timeutil.Now()
+	err = stmt.executeStmt(ctx, conn, w.opGen)
+	end := opLogCommit
+	if err != nil {
+		end = opLogRollback
+	}
+	txnEntries := w.opLog.endTxn(end, nil, nil, err)
+	if pgErr := new(pgconn.PgError); errors.As(err, &pgErr) &&
+		pgcode.MakeCode(pgErr.Code) == pgcode.SerializationFailure {
+		err = errors.Mark(err, errRunInTxnRbkSentinel)
+	}
+	switch {
+	case err == nil:
+		w.logger.flushLog("")
+		w.recordInHist(timeutil.Since(start), txnOk)
+		w.scCounter.success.Inc()
+	case errors.Is(err, errRunInTxnRbkSentinel):
+		w.logger.flushLogWithError(err)
+		w.recordInHist(timeutil.Since(start), txnRollback)
+	default:
+		w.logger.flushLogWithError(err)
+		w.preErrorHook()
+		return err
+	}
+	return w.compareSchemaChangers(ctx, txnEntries)
+}
+
 func (w *schemaChangeWorker) run(ctx context.Context) error {
 	connPool := w.pool.Get()
 	conn, err := connPool.Acquire(ctx)
@@ -530,7 +901,13 @@ func (w *schemaChangeWorker) run(ctx context.Context) error {
 		return errors.Wrap(err, "cannot get a connection")
 	}
 	defer conn.Release()
-	useDeclarativeSchemaChanger := w.opGen.randIntn(100) < w.workload.declarativeSchemaChangerPct
+	phase, err := w.soakPhase(ctx, conn)
+	if err != nil {
+		return err
+	}
+	// Tables and types are only created by the legacy schema changer.
+	useDeclarativeSchemaChanger := phase != soakBuilding &&
+		w.opGen.randIntn(100) < w.workload.declarativeSchemaChangerPct
 	if useDeclarativeSchemaChanger {
 		if _, err := conn.Exec(ctx, "SET use_declarative_schema_changer='unsafe_always';"); err != nil {
 			return err
@@ -541,6 +918,32 @@ func (w *schemaChangeWorker) run(ctx context.Context) error {
 		}
 	}
 
+	// Workers occasionally switch to a random time zone, so that values of
+	// TIMESTAMPTZ columns are converted differently depending on the session.
+	// The time zone is only set on connections that don't use it already.
+	if w.timeZone == "" {
+		w.timeZone = sessionTimeZones[0]
+	}
+	if w.opGen.randIntn(20) == 0 {
+		w.timeZone = sessionTimeZones[w.opGen.randIntn(len(sessionTimeZones))]
+	}
+	timeZone := w.timeZone
+	if connTimeZone, ok := w.workload.connTimeZones.Load(conn.Conn()); !ok || connTimeZone != timeZone {
+		if _, err := conn.Exec(ctx, fmt.Sprintf("SET TIME ZONE '%s'", timeZone)); err != nil {
+			return err
+		}
+		w.workload.connTimeZones.Store(conn.Conn(), timeZone)
+	}
+
+	// Statements that can't run in an explicit transaction are run on their
+	// own when drawn. The declarative schema changer doesn't implement any of
+	// them.
+	if !useDeclarativeSchemaChanger && phase == soakDisabled {
+		if op := opType(w.opGen.params.ops.Int()); slices.Contains(implicitTxnOps, op) {
+			return w.runInImplicitTxn(ctx, conn, op, timeZone)
+		}
+	}
+
 	tx, err := conn.Begin(ctx)
 	if err != nil {
 		return errors.Wrap(err, "cannot get a connection and begin a txn")
@@ -561,7 +964,7 @@ func (w *schemaChangeWorker) run(ctx context.Context) error {
 		if err != nil {
 			return errors.Wrap(err, "could not rollback before cluster setting")
 		}
-		_, err = conn.Exec(ctx, `SET CLUSTER SETTING sql.schema.force_declarative_statements="+CREATE SCHEMA, +CREATE SEQUENCE"`)
+		_, err = conn.Exec(ctx, forceDeclarativeStatementsStmt)
 		if err != nil {
 			return errors.Wrap(err, "cannot enable extra schema changes")
 		}
@@ -584,7 +987,8 @@ func (w *schemaChangeWorker) run(ctx context.Context) error {
 	defer watchDog.Stop()
 	start := timeutil.Now()
 	w.opGen.resetTxnState()
-	err = w.runInTxn(ctx, tx, useDeclarativeSchemaChanger, workloadMetrics)
+	w.opLog.startTxn(useDeclarativeSchemaChanger, timeZone)
+	err = w.runInTxn(ctx, tx, useDeclarativeSchemaChanger, phase, workloadMetrics)
 
 	if err != nil {
 		// Rollback in all cases to release the txn object and its conn pool. Wrap the original
@@ -600,6 +1004,7 @@ func (w *schemaChangeWorker) run(ctx context.Context) error {
 		}
 
 		w.logger.flushLogWithError(err)
+		txnEntries := w.opLog.endTxn(opLogRollback, nil, nil, err)
 		switch {
 		case errors.Is(err, errRunInTxnFatalSentinel):
 			w.preErrorHook()
@@ -607,14 +1012,16 @@ func (w *schemaChangeWorker) run(ctx context.Context) error {
 		case errors.Is(err, errRunInTxnRbkSentinel):
 			// Rollbacks are acceptable because all unexpected errors will be
 			// of errRunInTxnFatalSentinel.
-			return nil
+			return w.compareSchemaChangers(ctx, txnEntries)
 		default:
 			w.preErrorHook()
 			return errors.Wrapf(err, "***UNEXPECTED ERROR")
 		}
 	}
 	w.logger.writeLog("COMMIT")
-	if err = tx.Commit(ctx); err != nil {
+	err = tx.Commit(ctx)
+	txnEntries := w.opLog.endTxn(opLogCommit, w.opGen.expectedCommitErrors, w.opGen.potentialCommitErrors, err)
+	if err != nil {
 		// If the error not an instance of pgconn.PgError, then it is unexpected.
 		pgErr := new(pgconn.PgError)
 		if !errors.As(err, &pgErr) {
@@ -642,13 +1049,7 @@ func (w *schemaChangeWorker) run(ctx context.Context) error {
 
 		// If the error is an instance of pgcode.TransactionCommittedWithSchemaChangeFailure, then
 		// the underlying pgcode needs to be parsed from it.
-		if pgErr.Code == pgcode.TransactionCommittedWithSchemaChangeFailure.String() {
-			re := regexp.MustCompile(`\([A-Z0-9]{5}\)`)
-			underLyingErrorCode := re.FindString(pgErr.Error())
-			if underLyingErrorCode != "" {
-				pgErr.Code = underLyingErrorCode[1 : len(underLyingErrorCode)-1]
-			}
-		}
+		pgErr.Code = commitErrorCode(pgErr).String()
 
 		// Check for any expected errors.
 		if !w.opGen.expectedCommitErrors.contains(pgcode.MakeCode(pgErr.Code)) &&
@@ -666,7 +1067,7 @@ func (w *schemaChangeWorker) run(ctx context.Context) error {
 		// Error was anticipated, so it is acceptable.
 		w.recordInHist(timeutil.Since(start), txnCommitError)
 		w.logger.flushLog("COMMIT; Successfully got expected commit error")
-		return nil
+		return w.compareSchemaChangers(ctx, txnEntries)
 	}
 	if !w.opGen.expectedCommitErrors.empty() {
 		err := w.WrapWithErrorState(errors.Newf("***FAIL; Failed to receive a commit error when at least one commit error was expected"))
@@ -676,10 +1077,24 @@ func (w *schemaChangeWorker) run(ctx context.Context) error {
 	}
 
 	// If there were no errors while committing the txn.
+	w.opGen.txnCommitted()
 	w.logger.flushLog("")
 	w.recordInHist(timeutil.Since(start), txnOk)
 	workloadMetrics[txnCommitted] = attribute.BoolValue(true)
 	w.scCounter.success.Inc()
+	return w.compareSchemaChangers(ctx, txnEntries)
+}
+
+// compareSchemaChangers re-runs a transaction that ended as expected with the
+// legacy schema changer, if --compare-schema-changers is set.
+func (w *schemaChangeWorker) compareSchemaChangers(ctx context.Context, txn []opLogEntry) error {
+	if w.workload.comparison == nil {
+		return nil
+	}
+	if err := w.workload.comparison.compareTxn(ctx, txn); err != nil {
+		w.preErrorHook()
+		return errors.Mark(errors.Wrap(err, "***SCHEMA CHANGER DIVERGENCE"), errRunInTxnFatalSentinel)
+	}
 	return nil
 }
 
@@ -926,6 +1341,57 @@ func (s *schemaChange) initJSONLogFile(filePath string) error {
 	return nil
 }
 
+// closeOpLogFile closes s.opLogFile and is a noop if s.opLogFile is nil.
+func (s *schemaChange) closeOpLogFile() error {
+	if s.opLogFile == nil {
+		return nil
+	}
+
+	if err := s.opLogFile.Sync(); err != nil {
+		return err
+	}
+	err := s.opLogFile.Close()
+	s.opLogFile = nil
+	return err
+}
+
+// replayWorker returns a worker function replaying the operation log given
+// by --op-replay. The log is replayed once, after which the worker waits for
+// the workload to be stopped.
+func (s *schemaChange) replayWorker(
+	pool *workload.MultiConnPool, stdoutLog *atomicLog,
+) func(context.Context) error {
+	replayed := false
+	return func(ctx context.Context) error {
+		if replayed {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		replayed = true
+
+		f, err := os.Open(s.opReplayPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		conn, err := pool.Get().Acquire(ctx)
+		if err != nil {
+			return errors.Wrap(err, "cannot get a connection")
+		}
+		defer conn.Release()
+		if _, err := conn.Exec(ctx, forceDeclarativeStatementsStmt); err != nil {
+			return errors.Wrap(err, "cannot enable extra schema changes")
+		}
+
+		numTxns, err := replayOpLog(ctx, conn, f)
+		if err != nil {
+			return errors.Wrapf(err, "***UNEXPECTED ERROR; replaying %s", s.opReplayPath)
+		}
+		stdoutLog.printLn(fmt.Sprintf("replayed %d transactions from %s", numTxns, s.opReplayPath))
+		return nil
+	}
+}
+
 // closeJsonLogFile closes s.logFile and is a noop if s.logFile is nil.
 func (s *schemaChange) closeJSONLogFile() error {
 	if s.logFile == nil {
@@ -13,6 +13,7 @@ package schemachange
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -23,20 +24,25 @@ import (
 	"github.com/lib/pq/oid"
 )
 
-// txTypeResolver is a minimal type resolver to support writing enum values to
-// columns.
+// txTypeResolver is a minimal type resolver to support writing enum and
+// composite values to columns.
 type txTypeResolver struct {
 	tx pgx.Tx
 }
 
 // ResolveType implements the TypeReferenceResolver interface.
 // Note: If the name has an explicit schema, it will be resolved as
-// a user defined enum.
+// a user defined composite type if one exists, and as a user defined
+// enum otherwise.
 func (t txTypeResolver) ResolveType(
 	ctx context.Context, name *tree.UnresolvedObjectName,
 ) (*types.T, error) {
 
 	if name.HasExplicitSchema() {
+		typ, err := t.resolveCompositeType(ctx, name)
+		if err != nil || typ != nil {
+			return typ, err
+		}
 		rows, err := t.tx.Query(ctx, `
   SELECT enumlabel, enumsortorder, pgt.oid::int
     FROM pg_enum AS pge, pg_type AS pgt, pg_namespace AS pgn
@@ -112,6 +118,56 @@ ORDER BY enumsortorder`, name.Object(), name.Schema())
 	return types.OidToType[objectID], nil
 }
 
+// resolveCompositeType resolves a user defined composite type from the
+// statement that would create it. It returns a nil type if there is no
+// composite type with the given name.
+func (t txTypeResolver) resolveCompositeType(
+	ctx context.Context, name *tree.UnresolvedObjectName,
+) (*types.T, error) {
+	var objectID oid.Oid
+	var createStmt string
+	if err := t.tx.QueryRow(ctx, `
+  SELECT pgt.oid::int, cts.create_statement
+    FROM pg_type AS pgt, pg_namespace AS pgn, crdb_internal.create_type_statements AS cts
+   WHERE pgt.typnamespace = pgn.oid
+         AND typtype = 'c'
+         AND typname = $1
+         AND nspname = $2
+         AND cts.schema_name = nspname
+         AND cts.descriptor_name = typname`, name.Object(), name.Schema(),
+	).Scan(&objectID, &createStmt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	stmt, err := parser.ParseOne(createStmt)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing %q", createStmt)
+	}
+	createType, ok := stmt.AST.(*tree.CreateType)
+	if !ok {
+		return nil, errors.AssertionFailedf("unexpected statement %q", createStmt)
+	}
+	contents := make([]*types.T, len(createType.CompositeTypeList))
+	labels := make([]string, len(createType.CompositeTypeList))
+	for i, elem := range createType.CompositeTypeList {
+		if contents[i], err = tree.ResolveType(ctx, elem.Type, t); err != nil {
+			return nil, err
+		}
+		labels[i] = string(elem.Label)
+	}
+
+	n := types.UserDefinedTypeName{Name: name.Object()}
+	n.Schema = name.Schema()
+	n.ExplicitSchema = true
+	typ := *types.MakeLabeledTuple(contents, labels)
+	typ.InternalType.Oid = objectID
+	typ.TypeMeta = types.UserDefinedTypeMetadata{Name: &n}
+	return &typ, nil
+}
+
 func (t txTypeResolver) ResolveTypeByOID(ctx context.Context, oid oid.Oid) (*types.T, error) {
 	return nil, pgerror.Newf(pgcode.UndefinedObject, "type %d does not exist", oid)
 }